@@ -0,0 +1,87 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv/azurekv"
+)
+
+const (
+	cfgAzureWorkloadIdentity       = "azure-workload-identity"
+	cfgAzureTenantID               = "azure-tenant-id"
+	cfgAzureClientID               = "azure-client-id"
+	cfgAzureFederatedTokenFile     = "azure-federated-token-file"
+	cfgAzureFederatedTokenAudience = "azure-federated-token-audience"
+	cfgAzureServiceAccount         = "azure-service-account"
+)
+
+// azureFederatedTokenExpiration is the lifetime of the minted service account tokens, the shortest the API server allows.
+const azureFederatedTokenExpiration int64 = 600
+
+// azureCredentialsForConfig returns the Workload Identity credentials of the Azure backends. Without a
+// projected token file, a token with the audience of the federated credential is minted for the service
+// account with the TokenRequest API, so the Workload Identity webhook isn't needed.
+func azureCredentialsForConfig(cfg *viper.Viper) (azurekv.Credentials, error) {
+	credentials := azurekv.Credentials{
+		TenantID:  cfg.GetString(cfgAzureTenantID),
+		ClientID:  cfg.GetString(cfgAzureClientID),
+		TokenFile: cfg.GetString(cfgAzureFederatedTokenFile),
+	}
+
+	serviceAccount := cfg.GetString(cfgAzureServiceAccount)
+	if credentials.TokenFile != "" || serviceAccount == "" {
+		return credentials, nil
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return azurekv.Credentials{}, err
+	}
+
+	namespace := podNamespace()
+	audience := cfg.GetString(cfgAzureFederatedTokenAudience)
+	credentials.Assertion = func(ctx context.Context) (string, error) {
+		expiration := azureFederatedTokenExpiration
+		tokenRequest, err := client.CoreV1().ServiceAccounts(namespace).CreateToken(ctx, serviceAccount, &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				Audiences:         []string{audience},
+				ExpirationSeconds: &expiration,
+			},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return "", errors.Wrapf(err, "error requesting a token of service account %s/%s", namespace, serviceAccount)
+		}
+
+		return tokenRequest.Status.Token, nil
+	}
+
+	return credentials, nil
+}
+
+func init() {
+	configBoolVar(rootCmd, cfgAzureWorkloadIdentity, false, "Authenticate the Azure backends with Azure Workload Identity instead of client secrets")
+	configStringVar(rootCmd, cfgAzureTenantID, "", "The tenant of the Azure Workload Identity application, AZURE_TENANT_ID if empty")
+	configStringVar(rootCmd, cfgAzureClientID, "", "The client ID of the Azure Workload Identity application, AZURE_CLIENT_ID if empty")
+	configStringVar(rootCmd, cfgAzureFederatedTokenFile, "", "The projected service account token of Azure Workload Identity, AZURE_FEDERATED_TOKEN_FILE if empty")
+	configStringVar(rootCmd, cfgAzureFederatedTokenAudience, azurekv.DefaultFederatedTokenAudience, "The audience of the service account tokens minted for --"+cfgAzureServiceAccount)
+	configStringVar(rootCmd, cfgAzureServiceAccount, "", "The service account to mint Azure Workload Identity tokens for with the TokenRequest API, instead of reading a projected token")
+}
@@ -0,0 +1,121 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// reloadingCATransport rebuilds the transport of a target once its CA bundle changes,
+// so a Vault certificate issued by a rotated CA, e.g. by cert-manager, is trusted without a restart.
+type reloadingCATransport struct {
+	files []string
+	build func() (http.RoundTripper, *http.Transport, error)
+
+	mu   sync.Mutex
+	base http.RoundTripper
+	// the HTTP transport of base, whose connections are closed once it is rebuilt
+	transport *http.Transport
+	modTime   time.Time
+}
+
+// newReloadingCATransport watches the CA files, the transport of base is rebuilt with build once they change.
+func newReloadingCATransport(files []string, base http.RoundTripper, transport *http.Transport, build func() (http.RoundTripper, *http.Transport, error)) *reloadingCATransport {
+	t := &reloadingCATransport{files: files, build: build, base: base, transport: transport}
+	t.modTime, _ = t.modified()
+
+	return t
+}
+
+// modified returns the last modification time of the CA files, and the files of the CA directories.
+func (t *reloadingCATransport) modified() (time.Time, error) {
+	var modTime time.Time
+	for _, file := range t.files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "error checking %s", file)
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+		if !info.IsDir() {
+			continue
+		}
+
+		entries, err := os.ReadDir(file)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "error checking %s", file)
+		}
+		for _, entry := range entries {
+			info, err := os.Stat(filepath.Join(file, entry.Name()))
+			if err != nil {
+				return time.Time{}, errors.Wrapf(err, "error checking %s", file)
+			}
+			if info.ModTime().After(modTime) {
+				modTime = info.ModTime()
+			}
+		}
+	}
+
+	return modTime, nil
+}
+
+// current returns the transport of the CA files, rebuilding it if they changed.
+func (t *reloadingCATransport) current() http.RoundTripper {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	modTime, err := t.modified()
+	if err != nil || modTime.Equal(t.modTime) {
+		return t.base
+	}
+
+	// The bundle may be caught halfway through its rotation, keep using the previous one until it loads
+	base, transport, err := t.build()
+	if err != nil {
+		slog.Warn("error reloading vault CA certificate, using the previous one", "files", t.files, "error", err)
+		return t.base
+	}
+
+	t.transport.CloseIdleConnections()
+	t.base, t.transport, t.modTime = base, transport, modTime
+	slog.Info("reloaded vault CA certificate", "files", t.files)
+
+	return t.base
+}
+
+func (t *reloadingCATransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.current().RoundTrip(req)
+}
+
+// caFiles returns the CA certificate file and directory of the target, the ones to watch for rotation.
+func (t vaultTarget) caFiles() []string {
+	var files []string
+	config := t.tlsConfig()
+	for _, file := range []string{config.CACert, config.CAPath} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+
+	return files
+}
@@ -0,0 +1,60 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReloadingCATransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The bundle doesn't have the CA of the server yet
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.crt")
+	modTime := time.Now().Add(-time.Minute)
+	writeClientCert(t, caFile, filepath.Join(dir, "ca.key"), "previous", modTime)
+
+	cl, err := vaultTarget{Address: server.URL, CACert: caFile}.newClient()
+	require.NoError(t, err)
+	httpClient := cl.CloneConfig().HttpClient
+
+	_, err = httpClient.Get(server.URL)
+	require.Error(t, err)
+
+	// A half-written bundle keeps the previous transport
+	require.NoError(t, os.WriteFile(caFile, []byte("garbage"), 0o600))
+	_, err = httpClient.Get(server.URL)
+	require.Error(t, err)
+
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+	require.NoError(t, os.Chtimes(caFile, modTime.Add(time.Second), modTime.Add(time.Second)))
+	resp, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
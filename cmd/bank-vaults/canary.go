@@ -0,0 +1,202 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+)
+
+const (
+	cfgCanaryTargets     = "canary-targets"
+	cfgCanaryNamespace   = "canary-namespace"
+	cfgCanaryMountPrefix = "canary-mount-prefix"
+	cfgCanaryVerify      = "canary-verify"
+)
+
+// canaryMountSections are the config sections the canary mount prefix selects items of.
+var canaryMountSections = []string{"auth", "secrets"}
+
+// canaryCfg selects the subset of the config applied and verified before the rest of it.
+type canaryCfg struct {
+	// names of the targets applied first
+	targets []string
+	// Vault namespace of the targets applied first
+	namespace string
+	// path prefix of the auth methods and secrets engines applied first
+	mountPrefix string
+	// verify the canary matches the config before continuing
+	verify bool
+}
+
+func canaryForConfig(cfg *viper.Viper) canaryCfg {
+	return canaryCfg{
+		targets:     cfg.GetStringSlice(cfgCanaryTargets),
+		namespace:   cfg.GetString(cfgCanaryNamespace),
+		mountPrefix: strings.Trim(cfg.GetString(cfgCanaryMountPrefix), "/"),
+		verify:      cfg.GetBool(cfgCanaryVerify),
+	}
+}
+
+func (c canaryCfg) enabled() bool {
+	return c.selectsTargets() || c.mountPrefix != ""
+}
+
+func (c canaryCfg) selectsTargets() bool {
+	return len(c.targets) > 0 || c.namespace != ""
+}
+
+// isCanary tells whether the target is part of the canary, every target is if none are selected.
+func (c canaryCfg) isCanary(target configureTarget) bool {
+	if !c.selectsTargets() {
+		return true
+	}
+
+	return slices.Contains(c.targets, target.Name) || (c.namespace != "" && strings.Trim(target.Namespace, "/") == strings.Trim(c.namespace, "/"))
+}
+
+// config returns the part of the config applied to the canary targets: with a mount prefix only the
+// auth methods and secrets engines under it, without the purging of unmanaged config.
+func (c canaryCfg) config(data map[string]interface{}) map[string]interface{} {
+	if c.mountPrefix == "" {
+		return data
+	}
+
+	canary := map[string]interface{}{
+		"purgeUnmanagedConfig": map[string]interface{}{"enabled": false},
+	}
+	if retry, ok := data["retry"]; ok {
+		canary["retry"] = retry
+	}
+
+	for _, section := range canaryMountSections {
+		items, ok := data[section].([]interface{})
+		if !ok {
+			continue
+		}
+
+		var selected []interface{}
+		for _, item := range items {
+			fields := cast.ToStringMap(item)
+			path := cast.ToString(fields["path"])
+			if path == "" {
+				path = cast.ToString(fields["type"])
+			}
+			path = strings.Trim(path, "/")
+
+			if path == c.mountPrefix || strings.HasPrefix(path, c.mountPrefix+"/") {
+				selected = append(selected, item)
+			}
+		}
+
+		if len(selected) > 0 {
+			canary[section] = selected
+		}
+	}
+
+	return canary
+}
+
+// target returns the target the canary config is applied to. With a mount prefix it gets a fresh Vault
+// helper closed by the returned func: a helper merges every config into the one it applied before, so
+// the subset would replace the items at the same index of the full config and purge what they replaced.
+func (c canaryCfg) target(ctx context.Context, target configureTarget) (configureTarget, func(), error) {
+	if c.mountPrefix == "" || target.newVault == nil {
+		return target, func() {}, nil
+	}
+
+	v, err := target.newVault(ctx)
+	if err != nil {
+		return target, nil, errors.Wrap(err, "error creating vault helper of canary")
+	}
+	target.Vault = v
+
+	return target, v.Close, nil
+}
+
+// verifyCanary checks that the canary target matches the config it was just configured with.
+func verifyCanary(ctx context.Context, target configureTarget, data map[string]interface{}) error {
+	if skipDryRun("verify canary", "target", target.Name) {
+		return nil
+	}
+
+	drifts, err := target.Vault.Verify(ctx, data)
+	if err != nil {
+		return errors.Wrap(err, "error verifying canary")
+	}
+
+	if len(drifts) > 0 {
+		return errors.Errorf("canary drifted from the config after applying it: %s", drifts[0])
+	}
+
+	return nil
+}
+
+// applyWithCanary applies the config to the canary first and to the rest only if that succeeded.
+// fn is called with canary set for the canary pass.
+func applyWithCanary(targets []configureTarget, canary canaryCfg, parallel bool, fn func(target configureTarget, canary bool) error) error {
+	if !canary.enabled() {
+		return applyToTargets(targets, parallel, func(target configureTarget) error {
+			return fn(target, false)
+		})
+	}
+
+	var canaryTargets, rest []configureTarget
+	for _, target := range targets {
+		if canary.isCanary(target) {
+			canaryTargets = append(canaryTargets, target)
+		} else {
+			rest = append(rest, target)
+		}
+	}
+	if len(canaryTargets) == 0 {
+		return errors.New("no target matches the canary")
+	}
+
+	slog.Info("applying config to canary", "targets", len(canaryTargets), "mountPrefix", canary.mountPrefix)
+	err := applyToTargets(canaryTargets, parallel, func(target configureTarget) error {
+		return fn(target, true)
+	})
+	if err != nil {
+		return errors.Wrap(err, "canary failed, config not applied to the rest")
+	}
+
+	// The canary targets only got part of the config
+	if canary.mountPrefix != "" {
+		rest = targets
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+
+	slog.Info("canary succeeded, applying config to the rest", "targets", len(rest))
+
+	return applyToTargets(rest, parallel, func(target configureTarget) error {
+		return fn(target, false)
+	})
+}
+
+func init() {
+	configStringSliceVar(configureCmd, cfgCanaryTargets, nil, "Names of the targets to apply the config to first, the rest is only configured if they succeed")
+	configStringVar(configureCmd, cfgCanaryNamespace, "", "Apply the config first to the targets in this Vault namespace, the rest is only configured if they succeed")
+	configStringVar(configureCmd, cfgCanaryMountPrefix, "", "Apply first only the auth methods and secrets engines under this path prefix, the rest of the config is only applied if they succeed")
+	configBoolVar(configureCmd, cfgCanaryVerify, true, "Verify the canary matches the config before applying the rest, needs a token or the stored root token")
+}
@@ -0,0 +1,198 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+func TestApplyWithCanaryTargets(t *testing.T) {
+	targets := []configureTarget{{Name: "eu"}, {Name: "us", Namespace: "team-a/"}, {Name: "ap"}}
+
+	var applied []string
+	err := applyWithCanary(targets, canaryCfg{targets: []string{"ap"}, namespace: "team-a"}, false, func(target configureTarget, canary bool) error {
+		if canary {
+			applied = append(applied, "canary:"+target.Name)
+		} else {
+			applied = append(applied, target.Name)
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"canary:us", "canary:ap", "eu"}, applied)
+}
+
+func TestApplyWithCanaryFailure(t *testing.T) {
+	targets := []configureTarget{{Name: "eu"}, {Name: "us"}}
+
+	var applied []string
+	err := applyWithCanary(targets, canaryCfg{targets: []string{"eu"}}, false, func(target configureTarget, _ bool) error {
+		applied = append(applied, target.Name)
+		return errors.New("permission denied")
+	})
+
+	require.Error(t, err)
+	assert.Equal(t, []string{"eu"}, applied)
+
+	err = applyWithCanary(targets, canaryCfg{targets: []string{"ca"}}, false, func(configureTarget, bool) error { return nil })
+	assert.EqualError(t, err, "no target matches the canary")
+}
+
+func TestApplyWithCanaryMountPrefix(t *testing.T) {
+	targets := []configureTarget{{Name: "eu"}, {Name: "us"}}
+
+	var applied []string
+	err := applyWithCanary(targets, canaryCfg{targets: []string{"eu"}, mountPrefix: "team-a"}, false, func(target configureTarget, canary bool) error {
+		if canary {
+			applied = append(applied, "canary:"+target.Name)
+		} else {
+			applied = append(applied, target.Name)
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"canary:eu", "eu", "us"}, applied)
+}
+
+func TestCanaryConfig(t *testing.T) {
+	data := map[string]interface{}{
+		"purgeUnmanagedConfig": map[string]interface{}{"enabled": true},
+		"policies":             []interface{}{map[string]interface{}{"name": "admin"}},
+		"auth": []interface{}{
+			map[string]interface{}{"type": "kubernetes", "path": "/team-a/kubernetes/"},
+			map[string]interface{}{"type": "ldap"},
+		},
+		"secrets": []interface{}{
+			map[string]interface{}{"type": "kv", "path": "team-a"},
+			map[string]interface{}{"type": "kv", "path": "team-ab"},
+		},
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"purgeUnmanagedConfig": map[string]interface{}{"enabled": false},
+		"auth":                 []interface{}{map[string]interface{}{"type": "kubernetes", "path": "/team-a/kubernetes/"}},
+		"secrets":              []interface{}{map[string]interface{}{"type": "kv", "path": "team-a"}},
+	}, canaryCfg{mountPrefix: "team-a"}.config(data))
+
+	assert.Equal(t, data, canaryCfg{targets: []string{"eu"}}.config(data))
+}
+
+// fakeMountsVault serves the auth and mount tables of Vault from memory, recording the other requests.
+type fakeMountsVault struct {
+	mu       sync.Mutex
+	mounts   map[string]map[string]interface{}
+	requests []string
+}
+
+func (f *fakeMountsVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/")
+	f.requests = append(f.requests, r.Method+" "+path)
+	w.Header().Set("Content-Type", "application/json")
+
+	for _, table := range []string{"sys/auth", "sys/mounts"} {
+		switch {
+		case r.Method == http.MethodGet && path == table:
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": f.mounts[table]})
+			return
+		case strings.HasPrefix(path, table+"/") && !strings.Contains(path, "/tune"):
+			mount := strings.TrimPrefix(path, table+"/") + "/"
+			if r.Method == http.MethodDelete {
+				delete(f.mounts[table], mount)
+			} else {
+				var input struct{ Type string }
+				_ = json.NewDecoder(r.Body).Decode(&input)
+				f.mounts[table][mount] = map[string]interface{}{"type": input.Type}
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	if r.Method == http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[]}`))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestCanaryConfigureKeepsFullConfig(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeMountsVault{mounts: map[string]map[string]interface{}{
+		"sys/auth":   {"token/": map[string]interface{}{"type": "token"}},
+		"sys/mounts": {},
+	}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	apiConfig := api.DefaultConfig()
+	apiConfig.Address = srv.URL
+	cl, err := api.NewClient(apiConfig)
+	require.NoError(t, err)
+
+	newVault := func(ctx context.Context) (bankvaults.Vault, error) {
+		return bankvaults.New(ctx, mapKVStore{}, cl, bankvaults.Config{Token: "root"})
+	}
+	v, err := newVault(ctx)
+	require.NoError(t, err)
+	target := configureTarget{Name: "eu", Vault: v, newVault: newVault}
+
+	data := map[string]interface{}{
+		"purgeUnmanagedConfig": map[string]interface{}{"enabled": true},
+		"auth": []interface{}{
+			map[string]interface{}{"type": "approle", "roles": []interface{}{map[string]interface{}{"name": "ci", "policies": "ci"}}},
+			map[string]interface{}{"type": "userpass", "path": "team-a/userpass"},
+		},
+		"secrets": []interface{}{
+			map[string]interface{}{"type": "kv", "path": "secret"},
+			map[string]interface{}{"type": "kv", "path": "team-a/kv"},
+		},
+	}
+	canary := canaryCfg{mountPrefix: "team-a"}
+
+	require.NoError(t, target.Vault.Configure(ctx, data))
+
+	canaryTarget, closeCanary, err := canary.target(ctx, target)
+	require.NoError(t, err)
+	require.NoError(t, canaryTarget.Vault.Configure(ctx, canary.config(data)))
+	closeCanary()
+
+	require.NoError(t, target.Vault.Configure(ctx, data))
+
+	// The items of the canary don't replace the ones at the same index of the full config
+	assert.NotContains(t, fake.requests, "DELETE sys/auth/approle")
+	assert.NotContains(t, fake.requests, "DELETE sys/mounts/secret")
+	assert.NotContains(t, fake.requests, "PUT auth/team-a/userpass/role/ci")
+	assert.Len(t, fake.mounts["sys/auth"], 3)
+	assert.Len(t, fake.mounts["sys/mounts"], 2)
+}
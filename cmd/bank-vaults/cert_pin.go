@@ -0,0 +1,80 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// certPinner checks the certificate of the Vault server against pinned hashes, on top of the CA verification,
+// so a certificate issued by a compromised CA is rejected.
+type certPinner struct {
+	// SHA-256 hashes of the pinned certificates
+	certs [][]byte
+	// SHA-256 hashes of the pinned SubjectPublicKeyInfos, which survive certificate renewals with the same key
+	spkis [][]byte
+}
+
+// newCertPinner parses the pins: certificate pins are the hex SHA-256 fingerprint of the certificate,
+// like printed by 'openssl x509 -fingerprint -sha256', and SPKI pins are the base64 SHA-256 hash of the
+// public key, optionally prefixed with 'sha256//' like the pins of curl.
+func newCertPinner(certPins, spkiPins []string) (*certPinner, error) {
+	pinner := &certPinner{}
+
+	for _, pin := range certPins {
+		hash, err := hex.DecodeString(strings.ReplaceAll(strings.TrimSpace(pin), ":", ""))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, errors.Errorf("invalid certificate pin, expected a hex SHA-256 fingerprint: %s", pin)
+		}
+		pinner.certs = append(pinner.certs, hash)
+	}
+
+	for _, pin := range spkiPins {
+		hash, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(strings.TrimSpace(pin), "sha256//"))
+		if err != nil || len(hash) != sha256.Size {
+			return nil, errors.Errorf("invalid SPKI pin, expected a base64 SHA-256 hash: %s", pin)
+		}
+		pinner.spkis = append(pinner.spkis, hash)
+	}
+
+	return pinner, nil
+}
+
+// verifyConnection accepts the connection if the certificate of the server matches one of the pins.
+func (p *certPinner) verifyConnection(state tls.ConnectionState) error {
+	if len(state.PeerCertificates) == 0 {
+		return errors.New("vault server presented no certificate to check the pins against")
+	}
+	leaf := state.PeerCertificates[0]
+
+	certHash := sha256.Sum256(leaf.Raw)
+	spkiHash := sha256.Sum256(leaf.RawSubjectPublicKeyInfo)
+	matches := func(hash []byte) func([]byte) bool {
+		return func(pin []byte) bool { return slices.Equal(pin, hash) }
+	}
+
+	if slices.ContainsFunc(p.certs, matches(certHash[:])) || slices.ContainsFunc(p.spkis, matches(spkiHash[:])) {
+		return nil
+	}
+
+	return errors.Errorf("certificate of vault server %s matches none of the pins", state.ServerName)
+}
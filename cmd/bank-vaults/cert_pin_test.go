@@ -0,0 +1,72 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertPinning(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	caCert := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600))
+
+	certHash := sha256.Sum256(srv.Certificate().Raw)
+	spkiHash := sha256.Sum256(srv.Certificate().RawSubjectPublicKeyInfo)
+	otherHash := sha256.Sum256([]byte("other"))
+
+	get := func(target vaultTarget) error {
+		target.Address = srv.URL
+		target.CACert = caCert
+		config, err := target.apiConfig()
+		require.NoError(t, err)
+
+		resp, err := config.HttpClient.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		return err
+	}
+
+	assert.NoError(t, get(vaultTarget{PinnedCerts: []string{hex.EncodeToString(otherHash[:]), hex.EncodeToString(certHash[:])}}))
+	assert.NoError(t, get(vaultTarget{PinnedSPKIs: []string{"sha256//" + base64.StdEncoding.EncodeToString(spkiHash[:])}}))
+
+	err := get(vaultTarget{PinnedCerts: []string{hex.EncodeToString(otherHash[:])}, PinnedSPKIs: []string{base64.StdEncoding.EncodeToString(otherHash[:])}})
+	assert.ErrorContains(t, err, "matches none of the pins")
+}
+
+func TestNewCertPinnerRejectsInvalidPins(t *testing.T) {
+	_, err := newCertPinner([]string{"AB:CD"}, nil)
+	assert.Error(t, err)
+
+	_, err = newCertPinner(nil, []string{"not base64"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,181 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+)
+
+// reloadingClientCert presents the client certificate of the files at each TLS handshake,
+// reloading them once they change, so rotated certificates are used without a restart.
+type reloadingClientCert struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// modified returns the last modification time of the certificate and key files.
+func (c *reloadingClientCert) modified() (time.Time, error) {
+	var modTime time.Time
+	for _, file := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, errors.Wrapf(err, "error checking %s", file)
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+	}
+
+	return modTime, nil
+}
+
+// changed tells whether the files changed since the certificate was loaded.
+func (c *reloadingClientCert) changed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modTime, err := c.modified()
+
+	return err == nil && c.cert != nil && !modTime.Equal(c.modTime)
+}
+
+func (c *reloadingClientCert) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modTime, err := c.modified()
+	if err == nil && c.cert != nil && modTime.Equal(c.modTime) {
+		return c.cert, nil
+	}
+
+	if err == nil {
+		var cert tls.Certificate
+		cert, err = tls.LoadX509KeyPair(c.certFile, c.keyFile)
+		if err == nil {
+			c.cert, c.modTime = &cert, modTime
+			slog.Debug("loaded vault client certificate", "cert", c.certFile)
+
+			return c.cert, nil
+		}
+	}
+
+	// The certificate and key may be caught halfway through their rotation, keep using the previous ones
+	if c.cert != nil {
+		slog.Warn("error reloading vault client certificate, using the previous one", "cert", c.certFile, "error", err)
+		return c.cert, nil
+	}
+
+	return nil, errors.Wrap(err, "error loading vault client certificate")
+}
+
+// clientCertTransport closes the idle connections once the client certificate changed,
+// so the next requests handshake with the new certificate.
+type clientCertTransport struct {
+	base *http.Transport
+	cert *reloadingClientCert
+}
+
+func (t *clientCertTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.cert.changed() {
+		t.base.CloseIdleConnections()
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// instanceCertTransport sends the requests to the instances of a target with their own client certificates.
+type instanceCertTransport struct {
+	base http.RoundTripper
+	// transports of the instances by host
+	instances map[string]http.RoundTripper
+}
+
+func (t *instanceCertTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if transport, ok := t.instances[req.URL.Host]; ok {
+		return transport.RoundTrip(req)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// newClientCertTransport makes the transport reload its certificate files on rotation.
+func newClientCertTransport(transport *http.Transport, certFile, keyFile string) *clientCertTransport {
+	cert := &reloadingClientCert{certFile: certFile, keyFile: keyFile}
+	transport.TLSClientConfig.GetClientCertificate = cert.GetClientCertificate
+
+	return &clientCertTransport{base: transport, cert: cert}
+}
+
+// withReloadingClientCert makes the client of the config reload its certificate files on rotation,
+// and use the certificates of the instances with their addresses.
+func (t vaultTarget) withReloadingClientCert(config *api.Config) error {
+	transport, ok := config.HttpClient.Transport.(*http.Transport)
+	if !ok || transport.TLSClientConfig == nil {
+		if len(t.Instances) > 0 {
+			return errors.New("instance client certificates need a TLS transport")
+		}
+
+		return nil
+	}
+
+	// Cloned before the certificate of the target is set on it
+	instances := map[string]http.RoundTripper{}
+	for _, instance := range t.Instances {
+		if instance.ClientCert == "" || instance.ClientKey == "" {
+			return errors.Errorf("instance %s needs both a client certificate and key", instance.Address)
+		}
+
+		u, err := url.Parse(instance.Address)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing instance address %s", instance.Address)
+		}
+		if !slices.ContainsFunc(t.addresses(), func(address string) bool {
+			a, err := url.Parse(address)
+			return err == nil && a.Host == u.Host
+		}) {
+			return errors.Errorf("instance %s is not an address of the target", instance.Address)
+		}
+
+		instances[u.Host] = newClientCertTransport(transport.Clone(), instance.ClientCert, instance.ClientKey)
+	}
+
+	certFile, keyFile := t.ClientCert, t.ClientKey
+	if certFile == "" && keyFile == "" {
+		certFile, keyFile = os.Getenv(api.EnvVaultClientCert), os.Getenv(api.EnvVaultClientKey)
+	}
+	if certFile != "" && keyFile != "" {
+		config.HttpClient.Transport = newClientCertTransport(transport, certFile, keyFile)
+	}
+
+	if len(instances) > 0 {
+		config.HttpClient.Transport = &instanceCertTransport{base: config.HttpClient.Transport, instances: instances}
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeClientCert writes a self-signed certificate and its key, dated at modTime.
+func writeClientCert(t *testing.T, certFile, keyFile, commonName string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+}
+
+func commonNameOf(t *testing.T, c *reloadingClientCert) string {
+	t.Helper()
+
+	cert, err := c.GetClientCertificate(nil)
+	require.NoError(t, err)
+
+	parsed, err := x509.ParseCertificate(cert.Certificate[0])
+	require.NoError(t, err)
+
+	return parsed.Subject.CommonName
+}
+
+func TestReloadingClientCert(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Minute)
+
+	writeClientCert(t, certFile, keyFile, "first", modTime)
+
+	c := &reloadingClientCert{certFile: certFile, keyFile: keyFile}
+	assert.Equal(t, "first", commonNameOf(t, c))
+	assert.False(t, c.changed())
+
+	writeClientCert(t, certFile, keyFile, "rotated", modTime.Add(time.Second))
+	assert.True(t, c.changed())
+	assert.Equal(t, "rotated", commonNameOf(t, c))
+	assert.False(t, c.changed())
+
+	// A half-written rotation keeps the previous certificate
+	require.NoError(t, os.WriteFile(keyFile, []byte("garbage"), 0o600))
+	assert.Equal(t, "rotated", commonNameOf(t, c))
+}
+
+func TestReloadingClientCertMissingFiles(t *testing.T) {
+	dir := t.TempDir()
+	c := &reloadingClientCert{certFile: filepath.Join(dir, "tls.crt"), keyFile: filepath.Join(dir, "tls.key")}
+
+	_, err := c.GetClientCertificate(nil)
+	assert.Error(t, err)
+}
+
+func TestInstanceClientCerts(t *testing.T) {
+	target := vaultTarget{
+		Address:    "https://vault-0:8200,https://vault-1:8200",
+		ClientCert: "default.crt",
+		ClientKey:  "default.key",
+		Instances:  []vaultInstance{{Address: "https://vault-1:8200", ClientCert: "vault-1.crt", ClientKey: "vault-1.key"}},
+	}
+
+	config := api.DefaultConfig()
+	require.NoError(t, target.withReloadingClientCert(config))
+
+	transport, ok := config.HttpClient.Transport.(*instanceCertTransport)
+	require.True(t, ok)
+	assert.Equal(t, "default.crt", transport.base.(*clientCertTransport).cert.certFile)
+	require.Contains(t, transport.instances, "vault-1:8200")
+	assert.Equal(t, "vault-1.crt", transport.instances["vault-1:8200"].(*clientCertTransport).cert.certFile)
+	assert.NotSame(t, transport.base.(*clientCertTransport).base, transport.instances["vault-1:8200"].(*clientCertTransport).base)
+
+	target.Instances[0].Address = "https://vault-2:8200"
+	assert.EqualError(t, target.withReloadingClientCert(api.DefaultConfig()), "instance https://vault-2:8200 is not an address of the target")
+
+	target.Instances[0] = vaultInstance{Address: "https://vault-1:8200", ClientCert: "vault-1.crt"}
+	assert.EqualError(t, target.withReloadingClientCert(api.DefaultConfig()), "instance https://vault-1:8200 needs both a client certificate and key")
+}
@@ -16,18 +16,28 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
 	"os"
 
 	"emperror.dev/errors"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	vaultpkg "github.com/bank-vaults/vault-sdk/vault"
 	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	internalVault "github.com/bank-vaults/bank-vaults/internal/vault"
 	"github.com/bank-vaults/bank-vaults/pkg/kv"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/alibabakms"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/alibabaoss"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/awskms"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/azurekv"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/csi"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/dev"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/envelope"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/file"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/gckms"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/gcs"
@@ -38,18 +48,85 @@ import (
 	"github.com/bank-vaults/bank-vaults/pkg/kv/ocikms"
 	"github.com/bank-vaults/bank-vaults/pkg/kv/s3"
 	kvvault "github.com/bank-vaults/bank-vaults/pkg/kv/vault"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/wrapped"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
 )
 
-func vaultConfigForConfig(c *viper.Viper) internalVault.Config {
-	return internalVault.Config{
+func vaultConfigForConfig(c *viper.Viper) bankvaults.Config {
+	return bankvaults.Config{
 		SecretShares:    c.GetInt(cfgSecretShares),
 		SecretThreshold: c.GetInt(cfgSecretThreshold),
 
-		InitRootToken:  c.GetString(cfgInitRootToken),
-		StoreRootToken: c.GetBool(cfgStoreRootToken),
+		InitRootToken:   c.GetString(cfgInitRootToken),
+		StoreRootToken:  c.GetBool(cfgStoreRootToken) && !c.GetBool(cfgRevokeRootToken),
+		RevokeRootToken: c.GetBool(cfgRevokeRootToken),
+		// Minting a token is a write, a dry run keeps using the root token
+		ConfigurerToken: c.GetBool(cfgConfigurerToken) && !c.GetBool(cfgDryRun),
 
 		PreFlightChecks: c.GetBool(cfgPreFlightChecks),
+
+		SkipUnchanged:   c.GetBool(cfgSkipUnchanged),
+		ContinueOnError: c.GetBool(cfgContinueOnError),
+		Concurrency:     c.GetInt(cfgConcurrency),
+
+		Notifier: notifierForConfig(c),
+		Hooks:    hooksForConfig(c),
+
+		RedactFields: c.GetStringSlice(cfgLogRedactFields),
+
+		Retry: bankvaults.RetryPolicy{
+			Attempts:   c.GetInt(cfgRetryAttempts),
+			MinBackoff: c.GetDuration(cfgRetryMinBackoff),
+			MaxBackoff: c.GetDuration(cfgRetryMaxBackoff),
+			Timeout:    c.GetDuration(cfgRetryTimeout),
+		},
+		RetryBudget: vaultRetryBudget,
+	}
+}
+
+// k8sStoreForConfig returns the kv store of the K8S Secret layout configured by flags.
+func k8sStoreForConfig(cfg *viper.Viper) (kv.Service, error) {
+	ownerReferences, err := k8sOwnerReferencesForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return k8s.NewWithOptions(
+		cfg.GetString(cfgK8SNamespace),
+		cfg.GetString(cfgK8SSecret),
+		k8s.Options{
+			Labels:          cfg.GetStringMapString(cfgK8SLabels),
+			Annotations:     cfg.GetStringMapString(cfgK8SAnnotations),
+			Type:            corev1.SecretType(cfg.GetString(cfgK8SType)),
+			KeyTemplate:     cfg.GetString(cfgK8SKeyTemplate),
+			Include:         cfg.GetStringSlice(cfgK8SInclude),
+			Exclude:         cfg.GetStringSlice(cfgK8SExclude),
+			OwnerReferences: ownerReferences,
+			FieldManager:    cfg.GetString(cfgK8SFieldManager),
+		},
+	)
+}
+
+// k8sOwnerReferencesForConfig returns the owner references of the K8S Secrets, or nil to read them
+// from K8S_OWNER_REFERENCE. That one points to an object next to Vault, Kubernetes ignores owner references
+// across namespaces, so the Secrets stored in another namespace (e.g. a locked-down ops one) get no owner.
+func k8sOwnerReferencesForConfig(cfg *viper.Viper) ([]metav1.OwnerReference, error) {
+	if ownerReferenceJSON := cfg.GetString(cfgK8SOwnerReference); ownerReferenceJSON != "" {
+		var ownerReference metav1.OwnerReference
+		if err := json.Unmarshal([]byte(ownerReferenceJSON), &ownerReference); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling --%s", cfgK8SOwnerReference)
+		}
+
+		return []metav1.OwnerReference{ownerReference}, nil
+	}
+
+	if namespace := cfg.GetString(cfgK8SNamespace); namespace != "" && namespace != podNamespace() && os.Getenv(k8s.EnvK8SOwnerReference) != "" {
+		slog.Info(fmt.Sprintf("not setting the owner reference of %s on the secrets stored in namespace %s", k8s.EnvK8SOwnerReference, namespace))
+
+		return []metav1.OwnerReference{}, nil
 	}
+
+	return nil, nil
 }
 
 // all returns true if all values of a string slice are equal to target value
@@ -78,24 +155,89 @@ func correctValues(flags, choices []string) bool {
 	return true
 }
 
-func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error) {
+// kvStoreForConfig returns the key store of the mode, instrumented with operation metrics,
+// storing values in envelopes and wrapping the init output if enabled, with the wrapping
+// store to refresh, nil if the init output isn't wrapped.
+// Its writes are only logged in dry-run mode.
+func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, *wrapped.Store, error) {
+	store, err := kvBackendForConfig(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.GetBool(cfgKVEnvelope) {
+		store = envelope.NewWithOptions(store, cfg.GetString(cfgMode), []byte(cfg.GetString(cfgKVEnvelopeMACKey)), envelope.Options{
+			RejectLegacy: cfg.GetBool(cfgKVEnvelopeRejectLegacy),
+		})
+	}
+
+	var wrappedInitOutput *wrapped.Store
+	if cfg.GetBool(cfgWrapInitOutput) {
+		wrappedInitOutput, err = wrapInitOutput(cfg, store)
+		if err != nil {
+			return nil, nil, err
+		}
+		store = wrappedInitOutput
+	}
+
+	if cfg.GetString(cfgTokenSyncSecret) != "" {
+		store, err = tokenSyncStoreForConfig(cfg, store)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if cfg.GetBool(cfgDryRun) {
+		store = &dryRunKVStore{Service: store}
+	}
+
+	return newInstrumentedKVStore(cfg.GetString(cfgMode), store), wrappedInitOutput, nil
+}
+
+func kvBackendForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error) {
+	fips := cfg.GetBool(cfgFIPS)
+	if fips {
+		if err := checkFIPSMode(cfg.GetString(cfgMode)); err != nil {
+			return nil, err
+		}
+	}
+
+	proxy, err := kvProxyForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var proxyClient *http.Client
+	if proxy != nil {
+		proxyClient = proxy.HTTPClient()
+	}
+
 	switch mode := cfg.GetString(cfgMode); mode {
 	case cfgModeValueGoogleCloudKMSGCS:
-		gcs, err := gcs.New(
+		googleOptions, err := gckms.ClientOptionsWithProxy(ctx, gckms.Credentials{
+			CredentialsFile:           cfg.GetString(cfgGoogleCredentialsFile),
+			ImpersonateServiceAccount: cfg.GetString(cfgGoogleImpersonateServiceAccount),
+		}, proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "error loading google cloud credentials")
+		}
+
+		gcs, err := gcs.NewWithClientOptions(
 			ctx,
 			cfg.GetString(cfgGoogleCloudStorageBucket),
 			cfg.GetString(cfgGoogleCloudStoragePrefix),
+			googleOptions...,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating google cloud storage kv store")
 		}
 
-		kms, err := gckms.New(ctx,
+		kms, err := gckms.NewWithClientOptions(ctx,
 			gcs,
 			cfg.GetString(cfgGoogleCloudKMSProject),
 			cfg.GetString(cfgGoogleCloudKMSLocation),
 			cfg.GetString(cfgGoogleCloudKMSKeyRing),
 			cfg.GetString(cfgGoogleCloudKMSCryptoKey),
+			googleOptions...,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating google cloud kms kv store")
@@ -113,6 +255,15 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 		kmsRegions := cfg.GetStringSlice(cfgAWSKMSRegion)
 		kmsKeyIDs := cfg.GetStringSlice(cfgAWSKMSKeyID)
 		kmsKeyEncryptionContext := cfg.GetStringMapString(cfgAWSKMSEncryptionContext)
+		credentials := awskms.Credentials{
+			RoleARN:              cfg.GetString(cfgAWSRoleARN),
+			WebIdentityTokenFile: cfg.GetString(cfgAWSWebIdentityTokenFile),
+			RoleSessionName:      cfg.GetString(cfgAWSRoleSessionName),
+		}
+		var awsOptions []func(*awsconfig.LoadOptions) error
+		if proxy != nil {
+			awsOptions = append(awsOptions, awskms.WithProxy(*proxy))
+		}
 
 		// Try to use the standard AWS region
 		// setting if not provided for KMS/S3
@@ -159,9 +310,14 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 			} else {
 				kmsKeyID = ""
 			}
-			s3Service, err := s3.New(
+			// An empty region is resolved by the AWS SDK, e.g. from the environment of the pod
+			s3Config, err := awskms.LoadConfig(ctx, s3Regions[i], credentials, awsOptions...)
+			if err != nil {
+				return nil, errors.Wrap(err, "error loading AWS S3 config")
+			}
+			s3Service, err := s3.NewWithConfig(
 				ctx,
-				s3Regions[i],
+				s3Config,
 				s3Buckets[i],
 				s3Prefix,
 				s3SSEAlgos[i],
@@ -172,7 +328,11 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 			}
 
 			if s3SSEAlgos[i] == "" {
-				kmsService, err := awskms.New(ctx, s3Service, kmsRegions[i], kmsKeyIDs[i], kmsKeyEncryptionContext)
+				kmsConfig, err := awskms.LoadConfig(ctx, kmsRegions[i], credentials, awsOptions...)
+				if err != nil {
+					return nil, errors.Wrap(err, "error loading AWS KMS config")
+				}
+				kmsService, err := awskms.NewWithConfig(ctx, kmsConfig, s3Service, kmsKeyIDs[i], kmsKeyEncryptionContext)
 				if err != nil {
 					return nil, errors.Wrap(err, "error creating AWS KMS kv store")
 				}
@@ -185,7 +345,20 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 		return multi.New(services), nil
 
 	case cfgModeValueAzureKeyVault:
-		akv, err := azurekv.New(cfg.GetString(cfgAzureKeyVaultName), cfg.GetString(cfgAzureKeyVaultPrefix))
+		var credentials *azurekv.Credentials
+		if cfg.GetBool(cfgAzureWorkloadIdentity) {
+			workloadIdentity, err := azureCredentialsForConfig(cfg)
+			if err != nil {
+				return nil, errors.Wrap(err, "error loading Azure Workload Identity credentials")
+			}
+			credentials = &workloadIdentity
+		}
+		var clientOptions azcore.ClientOptions
+		if proxy != nil {
+			clientOptions = azurekv.ClientOptions(*proxy)
+		}
+
+		akv, err := azurekv.NewWithClientOptions(cfg.GetString(cfgAzureKeyVaultName), cfg.GetString(cfgAzureKeyVaultPrefix), credentials, clientOptions)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating Azure Key Vault kv store")
 		}
@@ -193,18 +366,20 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 		return akv, nil
 
 	case cfgModeValueOCI:
-		ociOs, err := oci.New(
+		ociOs, err := oci.NewWithHTTPClient(
 			cfg.GetString(cfgOciBucketNamespace),
 			cfg.GetString(cfgOciBucketName),
 			cfg.GetString(cfgOciBucketPrefix),
+			proxyClient,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating oracle object storage kv store")
 		}
 
-		ociKms, err := ocikms.New(ociOs,
+		ociKms, err := ocikms.NewWithHTTPClient(ociOs,
 			cfg.GetString(cfgOciKeyOCID),
 			cfg.GetString(cfgOciCryptographicEndpoint),
+			proxyClient,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating oracle kms kv store")
@@ -226,23 +401,25 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 			return nil, errors.Errorf("Alibaba OSS bucket should be specified")
 		}
 
-		oss, err := alibabaoss.New(
+		oss, err := alibabaoss.NewWithHTTPClient(
 			cfg.GetString(cfgAlibabaOSSEndpoint),
 			accessKeyID,
 			accessKeySecret,
 			bucket,
 			cfg.GetString(cfgAlibabaOSSPrefix),
+			proxyClient,
 		)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating Alibaba OSS kv store")
 		}
 
-		kms, err := alibabakms.New(
+		kms, err := alibabakms.NewWithHTTPClient(
 			cfg.GetString(cfgAlibabaKMSRegion),
 			accessKeyID,
 			accessKeySecret,
 			cfg.GetString(cfgAlibabaKMSKeyID),
-			oss)
+			oss,
+			proxyClient)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating Alibaba KMS kv store")
 		}
@@ -250,13 +427,25 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 		return kms, nil
 
 	case cfgModeValueVault:
-		vault, err := kvvault.New(
-			cfg.GetString(cfgVaultAddress),
+		vaultTarget := kvVaultTargetForConfig(cfg)
+		vaultConfig, err := vaultTarget.apiConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating Vault kv store config")
+		}
+
+		var vaultOptions []vaultpkg.ClientOption
+		if vaultTarget.Namespace != "" {
+			vaultOptions = append(vaultOptions, vaultpkg.VaultNamespace(vaultTarget.Namespace))
+		}
+
+		vault, err := kvvault.NewWithConfig(
+			vaultConfig,
 			cfg.GetString(cfgVaultUnsealKeysPath),
 			cfg.GetString(cfgVaultRole),
 			cfg.GetString(cfgVaultAuthPath),
 			cfg.GetString(cfgVaultTokenPath),
-			cfg.GetString(cfgVaultToken))
+			cfg.GetString(cfgVaultToken),
+			vaultOptions...)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating Vault kv store")
 		}
@@ -264,11 +453,7 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 		return vault, nil
 
 	case cfgModeValueK8S:
-		k8s, err := k8s.New(
-			cfg.GetString(cfgK8SNamespace),
-			cfg.GetString(cfgK8SSecret),
-			cfg.GetStringMapString(cfgK8SLabels),
-		)
+		k8s, err := k8sStoreForConfig(cfg)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating K8S Secret kv store")
 		}
@@ -277,11 +462,7 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 
 	// BANK_VAULTS_HSM_PIN=banzai bank-vaults unseal --init --mode hsm-k8s --k8s-secret-name hsm --k8s-secret-namespace default --hsm-slot-id 0
 	case cfgModeValueHSMK8S:
-		k8s, err := k8s.New(
-			cfg.GetString(cfgK8SNamespace),
-			cfg.GetString(cfgK8SSecret),
-			cfg.GetStringMapString(cfgK8SLabels),
-		)
+		k8s, err := k8sStoreForConfig(cfg)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating K8S Secret with kv store")
 		}
@@ -292,6 +473,7 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 			TokenLabel: cfg.GetString(cfgHSMTokenLabel),
 			Pin:        cfg.GetString(cfgHSMPin),
 			KeyLabel:   cfg.GetString(cfgHSMKeyLabel),
+			FIPS:       fips,
 		}, k8s)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating HSM kv store")
@@ -307,6 +489,7 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 			TokenLabel: cfg.GetString(cfgHSMTokenLabel),
 			Pin:        cfg.GetString(cfgHSMPin),
 			KeyLabel:   cfg.GetString(cfgHSMKeyLabel),
+			FIPS:       fips,
 		}, nil)
 		if err != nil {
 			return nil, errors.Wrap(err, "error creating HSM kv store")
@@ -330,6 +513,14 @@ func kvStoreForConfig(ctx context.Context, cfg *viper.Viper) (kv.Service, error)
 
 		return file, nil
 
+	case cfgModeValueCSI:
+		csi, err := csi.New(cfg.GetString(cfgCSIPath), cfg.GetStringMapString(cfgCSIObjects))
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating Secrets Store CSI kv store")
+		}
+
+		return csi, nil
+
 	default:
 		return nil, errors.Errorf("unsupported backend mode: '%s'", mode)
 	}
@@ -0,0 +1,73 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv/k8s"
+)
+
+func TestK8sOwnerReferencesForConfig(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "vault")
+	t.Setenv(k8s.EnvK8SOwnerReference, `{"apiVersion":"vault.banzaicloud.com/v1alpha1","kind":"Vault","name":"vault","uid":"1234"}`)
+
+	cfg := viper.New()
+	cfg.Set(cfgK8SNamespace, "vault")
+	ownerReferences, err := k8sOwnerReferencesForConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, ownerReferences, "the owner reference of the environment applies in the namespace of the pod")
+
+	cfg.Set(cfgK8SNamespace, "vault-ops")
+	ownerReferences, err = k8sOwnerReferencesForConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []metav1.OwnerReference{}, ownerReferences)
+
+	cfg.Set(cfgK8SOwnerReference, `{"apiVersion":"v1","kind":"ConfigMap","name":"vault-unseal-owner","uid":"5678"}`)
+	ownerReferences, err = k8sOwnerReferencesForConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []metav1.OwnerReference{{APIVersion: "v1", Kind: "ConfigMap", Name: "vault-unseal-owner", UID: "5678"}}, ownerReferences)
+
+	cfg.Set(cfgK8SOwnerReference, "{")
+	_, err = k8sOwnerReferencesForConfig(cfg)
+	assert.Error(t, err)
+}
+
+func TestKVStoreForConfigWrappedInitOutput(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set(cfgMode, cfgModeValueFile)
+	cfg.Set(cfgFilePath, t.TempDir())
+
+	store, wrappedInitOutput, err := kvStoreForConfig(t.Context(), cfg)
+	require.NoError(t, err)
+	assert.NotNil(t, store)
+	assert.Nil(t, wrappedInitOutput)
+
+	// Each key store has its own wrapping store
+	cfg.Set(cfgWrapInitOutput, true)
+	cfg.Set(cfgVaultAddress, "http://127.0.0.1:8200")
+	cfg.Set(cfgVaultToken, "token")
+	_, first, err := kvStoreForConfig(t.Context(), cfg)
+	require.NoError(t, err)
+	_, second, err := kvStoreForConfig(t.Context(), cfg)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.NotSame(t, first, second)
+}
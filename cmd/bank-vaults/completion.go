@@ -60,7 +60,8 @@ $ bank-vaults completion fish > ~/.config/fish/completions/bank-vaults.fish
 	Run: func(cmd *cobra.Command, args []string) {
 		switch args[0] {
 		case "bash":
-			_ = cmd.Root().GenBashCompletion(os.Stdout)
+			// V2 completes the values of the flags as well
+			_ = cmd.Root().GenBashCompletionV2(os.Stdout, true)
 		case "zsh":
 			_ = cmd.Root().GenZshCompletion(os.Stdout)
 		case "fish":
@@ -71,6 +72,81 @@ $ bank-vaults completion fish > ~/.config/fish/completions/bank-vaults.fish
 	},
 }
 
+// flagValueCompletions are the values the enum flags are completed with.
+var flagValueCompletions = map[string][]string{
+	cfgMode: {
+		cfgModeValueGoogleCloudKMSGCS,
+		cfgModeValueAWSKMS3,
+		cfgModeValueAzureKeyVault,
+		cfgModeValueAlibabaKMSOSS,
+		cfgModeValueVault,
+		cfgModeValueOCI,
+		cfgModeValueK8S,
+		cfgModeValueHSMK8S,
+		cfgModeValueHSM,
+		cfgModeValueDev,
+		cfgModeValueFile,
+		cfgModeValueCSI,
+	},
+	cfgLogLevel:     {"debug", "info", "warn", "error"},
+	cfgLogFormat:    {cfgLogFormatValueText, cfgLogFormatValueJSON},
+	cfgRenderFormat: {cfgRenderFormatValueYAML, cfgRenderFormatValueJSON},
+	cfgLogStrict:    {cfgLogStrictValueRedact, cfgLogStrictValueAbort},
+}
+
+// filenameFlags are the flags completed with file names.
+var filenameFlags = []string{
+	cfgVaultConfigFile,
+	cfgTargetsFile,
+	cfgConfigSignatureKey,
+	cfgOverlays,
+	cfgLicenseFile,
+	cfgAWSWebIdentityTokenFile,
+	cfgHSMModulePath,
+	cfgMetricsTLSCertFile,
+	cfgMetricsTLSKeyFile,
+	cfgTargetCACert,
+	cfgTargetClientCert,
+	cfgTargetClientKey,
+	cfgVaultCACert,
+	cfgVaultClientCert,
+	cfgVaultClientKey,
+	cfgTokenFile,
+	cfgKubernetesAuthTokenFile,
+	cfgAppRoleRoleIDFile,
+	cfgAppRoleSecretIDFile,
+	cfgInitContainerCompletionFile,
+	cfgInitContainerConfigFile,
+	cfgInitContainerTokenFile,
+	cfgRenderOverlays,
+	cfgConfigurerPolicyOverlays,
+}
+
+// registerFlagCompletions registers the completions of the flag values on the commands defining the flags.
+func registerFlagCompletions(cmd *cobra.Command) {
+	for name, values := range flagValueCompletions {
+		if cmd.PersistentFlags().Lookup(name) != nil {
+			_ = cmd.RegisterFlagCompletionFunc(name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+		}
+	}
+
+	for _, name := range filenameFlags {
+		if cmd.PersistentFlags().Lookup(name) != nil {
+			_ = cmd.MarkPersistentFlagFilename(name)
+		}
+	}
+
+	for _, name := range []string{cfgFilePath, cfgCSIPath} {
+		if cmd.PersistentFlags().Lookup(name) != nil {
+			_ = cmd.MarkPersistentFlagDirname(name)
+		}
+	}
+
+	for _, subCmd := range cmd.Commands() {
+		registerFlagCompletions(subCmd)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(completionCmd)
 }
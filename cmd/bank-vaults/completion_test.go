@@ -0,0 +1,37 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFlagCompletions(t *testing.T) {
+	registerFlagCompletions(rootCmd)
+
+	complete, ok := rootCmd.GetFlagCompletionFunc(cfgMode)
+	require.True(t, ok)
+	values, directive := complete(rootCmd, nil, "")
+	assert.Contains(t, values, cfgModeValueAWSKMS3)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	// Defined on the configure command
+	assert.Contains(t, configureCmd.PersistentFlags().Lookup(cfgVaultConfigFile).Annotations, cobra.BashCompFilenameExt)
+	assert.Contains(t, rootCmd.PersistentFlags().Lookup(cfgFilePath).Annotations, cobra.BashCompSubdirsInDir)
+}
@@ -0,0 +1,244 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+)
+
+const (
+	cfgConfigResource          = "config-resource"
+	cfgConfigResourceGVR       = "config-resource-gvr"
+	cfgConfigResourceNamespace = "config-resource-namespace"
+)
+
+// Phases of the status of the config resources.
+const (
+	configResourcePhaseApplied = "Applied"
+	configResourcePhaseFailed  = "Failed"
+)
+
+// configResourceResync is how often the informer lists the config resources again, only the ones
+// with a new generation are applied.
+const configResourceResync = 10 * time.Minute
+
+// configResourceSource reads the configs from namespaced custom resources whose spec mirrors the external
+// config, e.g. VaultConfigs, and writes how applying them went to their status.
+type configResourceSource struct {
+	client       dynamic.Interface
+	gvr          schema.GroupVersionResource
+	namespace    string
+	fieldManager string
+
+	mu sync.Mutex
+	// generation last queued, by resource
+	generations map[types.UID]int64
+}
+
+// configResource is the custom resource a config was read from.
+type configResource struct {
+	source     *configResourceSource
+	name       string
+	generation int64
+}
+
+func newConfigResourceSource(client dynamic.Interface, gvr schema.GroupVersionResource, namespace, fieldManager string) *configResourceSource {
+	return &configResourceSource{
+		client:       client,
+		gvr:          gvr,
+		namespace:    namespace,
+		fieldManager: fieldManager,
+		generations:  map[types.UID]int64{},
+	}
+}
+
+func configResourceSourceForConfig(cfg *viper.Viper) (*configResourceSource, error) {
+	gvr, _ := schema.ParseResourceArg(cfg.GetString(cfgConfigResourceGVR))
+	if gvr == nil {
+		return nil, errors.Errorf("invalid --%s, expected resource.version.group: %s", cfgConfigResourceGVR, cfg.GetString(cfgConfigResourceGVR))
+	}
+
+	config, err := newK8sConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating k8s dynamic client")
+	}
+
+	namespace := cfg.GetString(cfgConfigResourceNamespace)
+	if namespace == "" {
+		namespace = podNamespace()
+	}
+
+	return newConfigResourceSource(client, *gvr, namespace, cfg.GetString(cfgK8SFieldManager)), nil
+}
+
+func (s *configResourceSource) resources() dynamic.ResourceInterface {
+	return s.client.Resource(s.gvr).Namespace(s.namespace)
+}
+
+// configFile returns the config of a resource, its spec.
+func (s *configResourceSource) configFile(obj *unstructured.Unstructured) (*configFile, error) {
+	spec, _, err := unstructured.NestedMap(obj.Object, "spec")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading the spec of %s/%s", s.namespace, obj.GetName())
+	}
+
+	return &configFile{
+		Path: fmt.Sprintf("%s/%s/%s", s.gvr.Resource, s.namespace, obj.GetName()),
+		Data: spec,
+		resource: &configResource{
+			source:     s,
+			name:       obj.GetName(),
+			generation: obj.GetGeneration(),
+		},
+	}, nil
+}
+
+// list queues the configs of all the resources once.
+func (s *configResourceSource) list(ctx context.Context, configurations chan<- *configFile) error {
+	list, err := s.resources().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "error listing %s in namespace %s", s.gvr.Resource, s.namespace)
+	}
+
+	for i := range list.Items {
+		s.queue(ctx, &list.Items[i], configurations)
+	}
+
+	return nil
+}
+
+// watch queues the config of a resource whenever its spec changes, until the context is done.
+func (s *configResourceSource) watch(ctx context.Context, configurations chan<- *configFile) {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(s.client, configResourceResync, s.namespace, nil)
+	informer := factory.ForResource(s.gvr).Informer()
+
+	queue := func(obj interface{}) {
+		if obj, ok := obj.(*unstructured.Unstructured); ok {
+			s.queue(ctx, obj, configurations)
+		}
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    queue,
+		UpdateFunc: func(_, obj interface{}) { queue(obj) },
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error watching %s: %s", s.gvr.Resource, err.Error()))
+		return
+	}
+
+	slog.Info(fmt.Sprintf("watching %s in namespace %s for changes", s.gvr.Resource, s.namespace))
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	factory.Shutdown()
+}
+
+func (s *configResourceSource) queue(ctx context.Context, obj *unstructured.Unstructured, configurations chan<- *configFile) {
+	// The status updates and the resyncs don't change the generation
+	s.mu.Lock()
+	if generation, ok := s.generations[obj.GetUID()]; ok && generation == obj.GetGeneration() {
+		s.mu.Unlock()
+		return
+	}
+	s.generations[obj.GetUID()] = obj.GetGeneration()
+	s.mu.Unlock()
+
+	config, err := s.configFile(obj)
+	if err != nil {
+		slog.Error(err.Error())
+		(&configResource{source: s, name: obj.GetName(), generation: obj.GetGeneration()}).updateStatus(ctx, err)
+
+		return
+	}
+
+	select {
+	case configurations <- config:
+	case <-ctx.Done():
+	}
+}
+
+// reload returns the current config of the resource, to retry a failed one without reverting a newer one.
+func (r *configResource) reload(ctx context.Context) (*configFile, error) {
+	obj, err := r.source.resources().Get(ctx, r.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting %s %s/%s", r.source.gvr.Resource, r.source.namespace, r.name)
+	}
+
+	return r.source.configFile(obj)
+}
+
+// updateStatus writes how applying the config went to the status of the resource, unless it changed since.
+func (r *configResource) updateStatus(ctx context.Context, applyErr error) {
+	status := map[string]interface{}{
+		"observedGeneration": r.generation,
+		"phase":              configResourcePhaseApplied,
+		"message":            "",
+		"lastAppliedTime":    time.Now().UTC().Format(time.RFC3339),
+	}
+	if applyErr != nil {
+		// The status is readable by everyone reading the resource
+		message, _ := secmem.Redact(applyErr.Error())
+		status["phase"] = configResourcePhaseFailed
+		status["message"] = message
+	}
+
+	resources := r.source.resources()
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		obj, err := resources.Get(ctx, r.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if obj.GetGeneration() != r.generation {
+			return nil
+		}
+
+		if err := unstructured.SetNestedField(obj.Object, status, "status"); err != nil {
+			return err
+		}
+		_, err = resources.UpdateStatus(ctx, obj, metav1.UpdateOptions{FieldManager: r.source.fieldManager})
+
+		return err
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("error updating the status of %s %s/%s: %s", r.source.gvr.Resource, r.source.namespace, r.name, err.Error()))
+	}
+}
+
+func init() {
+	configBoolVar(configureCmd, cfgConfigResource, false, "Read the configs from namespaced custom resources whose spec mirrors the external config instead of files, and write the apply results to their status")
+	configStringVar(configureCmd, cfgConfigResourceGVR, "vaultconfigs.v1alpha1.bank-vaults.dev", "The custom resource of the configs, as resource.version.group")
+	configStringVar(configureCmd, cfgConfigResourceNamespace, "", "The namespace of the config custom resources, defaults to POD_NAMESPACE or the namespace of the pod")
+}
@@ -0,0 +1,108 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var testConfigResourceGVR = schema.GroupVersionResource{Group: "bank-vaults.dev", Version: "v1alpha1", Resource: "vaultconfigs"}
+
+func newTestConfigResource(name string, generation int64, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "bank-vaults.dev/v1alpha1",
+		"kind":       "VaultConfig",
+		"metadata": map[string]interface{}{
+			"name":       name,
+			"namespace":  "vault",
+			"uid":        name + "-uid",
+			"generation": generation,
+		},
+		"spec": spec,
+	}}
+}
+
+func newTestConfigResourceSource(objects ...runtime.Object) *configResourceSource {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		testConfigResourceGVR: "VaultConfigList",
+	}, objects...)
+
+	return newConfigResourceSource(client, testConfigResourceGVR, "vault", "bank-vaults")
+}
+
+func TestConfigResourceSourceList(t *testing.T) {
+	spec := map[string]interface{}{"policies": []interface{}{map[string]interface{}{"name": "reader"}}}
+	source := newTestConfigResourceSource(newTestConfigResource("reader", 1, spec))
+
+	configurations := make(chan *configFile, 2)
+	require.NoError(t, source.list(context.Background(), configurations))
+	require.Len(t, configurations, 1)
+
+	config := <-configurations
+	assert.Equal(t, "vaultconfigs/vault/reader", config.Path)
+	assert.Equal(t, spec, config.Data)
+	assert.Equal(t, int64(1), config.resource.generation)
+
+	// An unchanged generation, e.g. after a status update, isn't applied again
+	require.NoError(t, source.list(context.Background(), configurations))
+	assert.Empty(t, configurations)
+}
+
+func TestConfigResourceUpdateStatus(t *testing.T) {
+	source := newTestConfigResourceSource(newTestConfigResource("reader", 2, map[string]interface{}{}))
+	ctx := context.Background()
+
+	status := func() map[string]interface{} {
+		obj, err := source.resources().Get(ctx, "reader", metav1.GetOptions{})
+		require.NoError(t, err)
+		status, _, err := unstructured.NestedMap(obj.Object, "status")
+		require.NoError(t, err)
+
+		return status
+	}
+
+	(&configResource{source: source, name: "reader", generation: 2}).updateStatus(ctx, assert.AnError)
+	assert.Equal(t, configResourcePhaseFailed, status()["phase"])
+	assert.Equal(t, assert.AnError.Error(), status()["message"])
+	assert.Equal(t, int64(2), status()["observedGeneration"])
+
+	(&configResource{source: source, name: "reader", generation: 2}).updateStatus(ctx, nil)
+	assert.Equal(t, configResourcePhaseApplied, status()["phase"])
+	assert.Empty(t, status()["message"])
+
+	// The status of a resource changed since tells about the new generation
+	(&configResource{source: source, name: "reader", generation: 1}).updateStatus(ctx, assert.AnError)
+	assert.Equal(t, configResourcePhaseApplied, status()["phase"])
+}
+
+func TestConfigResourceReload(t *testing.T) {
+	source := newTestConfigResourceSource(newTestConfigResource("reader", 3, map[string]interface{}{"auth": []interface{}{}}))
+
+	config, err := (&configResource{source: source, name: "reader", generation: 2}).reload(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), config.resource.generation)
+
+	_, err = (&configResource{source: source, name: "deleted"}).reload(context.Background())
+	assert.Error(t, err)
+}
@@ -0,0 +1,162 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+)
+
+const (
+	cfgConfigSignatureKey    = "config-signature-key"
+	cfgConfigSignatureSuffix = "config-signature-suffix"
+)
+
+// configSignature verifies the detached signatures of the config files, nil if they are not verified.
+var configSignature *signatureVerifier
+
+// signatureVerifier verifies detached signatures made by cosign (sign-blob) or GPG.
+type signatureVerifier struct {
+	// public key of cosign, nil for GPG
+	publicKey crypto.PublicKey
+	// keyring of GPG, nil for cosign
+	keyring openpgp.EntityList
+	// the signature of a file is read from the file name with this suffix
+	suffix string
+}
+
+// newSignatureVerifier loads the public key of a verifier: a PEM public key for cosign
+// signatures, or a GPG public key (armored or not) for GPG signatures.
+func newSignatureVerifier(keyFile, suffix string) (*signatureVerifier, error) {
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading config signature key")
+	}
+
+	if block, _ := pem.Decode(key); block != nil && block.Type == "PUBLIC KEY" {
+		publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing config signature key")
+		}
+
+		switch publicKey.(type) {
+		case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		default:
+			return nil, errors.Errorf("unsupported config signature key type: %T", publicKey)
+		}
+
+		return &signatureVerifier{publicKey: publicKey, suffix: suffix}, nil
+	}
+
+	var keyring openpgp.EntityList
+	if block, err := armor.Decode(bytes.NewReader(key)); err == nil {
+		keyring, err = openpgp.ReadKeyRing(block.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing config signature GPG key")
+		}
+	} else {
+		keyring, err = openpgp.ReadKeyRing(bytes.NewReader(key))
+		if err != nil {
+			return nil, errors.Wrap(err, "config signature key is neither a PEM public key nor a GPG key")
+		}
+	}
+
+	return &signatureVerifier{keyring: keyring, suffix: suffix}, nil
+}
+
+// verifyFile verifies the content of the file with the signature next to it.
+func (v *signatureVerifier) verifyFile(file string, content []byte) error {
+	signature, err := os.ReadFile(file + v.suffix)
+	if err != nil {
+		return errors.Wrapf(err, "error reading signature of %s", file)
+	}
+
+	if err := v.verify(content, signature); err != nil {
+		return errors.Wrapf(err, "invalid signature of %s", file)
+	}
+
+	return nil
+}
+
+func (v *signatureVerifier) verify(content, signature []byte) error {
+	if v.keyring != nil {
+		var err error
+		if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("-----BEGIN PGP SIGNATURE-----")) {
+			_, err = openpgp.CheckArmoredDetachedSignature(v.keyring, bytes.NewReader(content), bytes.NewReader(signature), nil)
+		} else {
+			_, err = openpgp.CheckDetachedSignature(v.keyring, bytes.NewReader(content), bytes.NewReader(signature), nil)
+		}
+
+		return err
+	}
+
+	// cosign writes the signature base64 encoded
+	signature, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(signature)))
+	if err != nil {
+		return errors.Wrap(err, "error decoding cosign signature")
+	}
+
+	digest := sha256.Sum256(content)
+
+	switch publicKey := v.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(publicKey, digest[:], signature) {
+			return errors.New("signature verification failed")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(publicKey, content, signature) {
+			return errors.New("signature verification failed")
+		}
+	case *rsa.PublicKey:
+		if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature); err != nil {
+			return errors.Wrap(err, "signature verification failed")
+		}
+	}
+
+	return nil
+}
+
+// setupConfigSignature enables the verification of the config files if a key is configured.
+func setupConfigSignature() error {
+	keyFile := c.GetString(cfgConfigSignatureKey)
+	if keyFile == "" {
+		return nil
+	}
+
+	verifier, err := newSignatureVerifier(keyFile, c.GetString(cfgConfigSignatureSuffix))
+	if err != nil {
+		return err
+	}
+	configSignature = verifier
+
+	return nil
+}
+
+func init() {
+	configStringVar(rootCmd, cfgConfigSignatureKey, "", "Public key verifying the detached signatures of the config and overlay files before they are applied: a PEM public key for cosign, or a GPG public key")
+	configStringVar(rootCmd, cfgConfigSignatureSuffix, ".sig", "Suffix of the file name of the detached signature of a config file")
+}
@@ -0,0 +1,89 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigSignatureCosign(t *testing.T) {
+	dir := t.TempDir()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	keyFile := filepath.Join(dir, "cosign.pub")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey}), 0o600))
+
+	config := []byte("policies: []\n")
+	digest := sha256.Sum256(config)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	configFile := filepath.Join(dir, "vault-config.yml")
+	require.NoError(t, os.WriteFile(configFile, config, 0o600))
+	require.NoError(t, os.WriteFile(configFile+".sig", []byte(base64.StdEncoding.EncodeToString(signature)), 0o600))
+
+	verifier, err := newSignatureVerifier(keyFile, ".sig")
+	require.NoError(t, err)
+
+	assert.NoError(t, verifier.verifyFile(configFile, config))
+	assert.Error(t, verifier.verifyFile(configFile, []byte("policies: [{name: evil}]\n")))
+
+	require.NoError(t, os.WriteFile(configFile+".sig", signature, 0o600))
+	assert.ErrorContains(t, verifier.verifyFile(configFile, config), "error decoding cosign signature")
+
+	require.NoError(t, os.Remove(configFile+".sig"))
+	assert.Error(t, verifier.verifyFile(configFile, config), "an unsigned config is rejected")
+}
+
+func TestConfigSignatureGPG(t *testing.T) {
+	dir := t.TempDir()
+
+	entity, err := openpgp.NewEntity("bank-vaults", "", "bank-vaults@example.com", nil)
+	require.NoError(t, err)
+	var publicKey bytes.Buffer
+	require.NoError(t, entity.Serialize(&publicKey))
+	keyFile := filepath.Join(dir, "key.gpg")
+	require.NoError(t, os.WriteFile(keyFile, publicKey.Bytes(), 0o600))
+
+	config := []byte("policies: []\n")
+	var signature bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&signature, entity, bytes.NewReader(config), nil))
+
+	configFile := filepath.Join(dir, "vault-config.yml")
+	require.NoError(t, os.WriteFile(configFile+".asc", signature.Bytes(), 0o600))
+
+	verifier, err := newSignatureVerifier(keyFile, ".asc")
+	require.NoError(t, err)
+
+	assert.NoError(t, verifier.verifyFile(configFile, config))
+	assert.Error(t, verifier.verifyFile(configFile, []byte("policies: [{name: evil}]\n")))
+}
@@ -21,28 +21,62 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"emperror.dev/errors"
 	"github.com/bank-vaults/vault-sdk/utils/templater"
-	"github.com/bank-vaults/vault-sdk/vault"
 	"github.com/fsnotify/fsnotify"
+	"github.com/hashicorp/vault/api"
 	"github.com/jpillora/backoff"
 	"github.com/ramizpolic/multiparser"
 	"github.com/ramizpolic/multiparser/parser"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
-	internalVault "github.com/bank-vaults/bank-vaults/internal/vault"
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
 )
 
 const (
 	cfgVaultConfigFile = "vault-config-file"
 	cfgFatal           = "fatal"
 	cfgDisableMetrics  = "disable-metrics"
+	cfgSkipUnchanged   = "skip-unchanged"
+	cfgReportOutput    = "report-output"
+	cfgContinueOnError = "continue-on-error"
+	cfgConcurrency     = "concurrency"
+
+	cfgManageToken         = "manage-token"
+	cfgManageTokenInterval = "manage-token-interval"
+
+	cfgAuditTrail          = "audit-trail"
+	cfgAuditTrailVaultPath = "audit-trail-vault-path"
+
+	cfgRetryAttempts   = "retry-attempts"
+	cfgRetryMinBackoff = "retry-min-backoff"
+	cfgRetryMaxBackoff = "retry-max-backoff"
+	cfgRetryTimeout    = "retry-timeout"
+)
+
+const (
+	cfgAuditTrailValueKV    = "kv"
+	cfgAuditTrailValueVault = "vault"
+)
+
+// Exit codes of configure --once.
+const (
+	configureExitSuccess = 0
+	configureExitError   = 1
+	// only config items skipped in --continue-on-error mode failed
+	configureExitPartial = 3
 )
 
 type configFile struct {
 	Path string
 	Data map[string]interface{}
+	// the custom resource the config was read from, nil for files
+	resource *configResource
 }
 
 var configureCmd = &cobra.Command{
@@ -50,8 +84,18 @@ var configureCmd = &cobra.Command{
 	Short: "Configures a Vault based on a YAML/JSON configuration file",
 	Long: `This configuration is an extension to what is available through the Vault configuration:
 			https://www.vaultproject.io/docs/configuration/index.html. With this it is possible to
-			configure secret engines, auth methods, etc...`,
+			configure secret engines, auth methods, etc...
+
+			With --once the config is applied a single time, the exit code is 0 on success,
+			1 on failure and 3 if only config items skipped by --continue-on-error failed.`,
 	Run: func(cmd *cobra.Command, _ []string) {
+		exitCode := 0
+		defer func() {
+			if exitCode != 0 {
+				os.Exit(exitCode)
+			}
+		}()
+
 		var unsealConfig unsealCfg
 		ctx, cancel := context.WithCancel(cmd.Context())
 		defer cancel()
@@ -60,27 +104,107 @@ var configureCmd = &cobra.Command{
 		unsealConfig.unsealPeriod = c.GetDuration(cfgUnsealPeriod)
 		vaultConfigFiles := c.GetStringSlice(cfgVaultConfigFile)
 		disableMetrics := c.GetBool(cfgDisableMetrics)
+		reportOutput := c.GetString(cfgReportOutput)
+		canaryConfig := canaryForConfig(c)
+
+		if c.GetBool(cfgTracing) {
+			shutdownTracing, err := setupTracing(ctx)
+			if err != nil {
+				slog.Error(fmt.Sprintf("error setting up tracing: %s", err.Error()))
+				os.Exit(1)
+			}
+			defer func() {
+				if err := shutdownTracing(context.Background()); err != nil {
+					slog.Error(fmt.Sprintf("error flushing traces: %s", err.Error()))
+				}
+			}()
+		}
 
-		store, err := kvStoreForConfig(ctx, c)
+		store, _, err := kvStoreForConfig(ctx, c)
 		if err != nil {
 			slog.Error(fmt.Sprintf("error creating kv store: %s", err.Error()))
 			os.Exit(1)
 		}
 
-		cl, err := vault.NewRawClient()
+		// Create parsers
+		parser, err := multiparser.New(parser.JSON, parser.YAML)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error file parsers: %v", err))
+			os.Exit(1)
+		}
+
+		rollout, err := rolloutGateForConfig(c)
 		if err != nil {
-			slog.Error(fmt.Sprintf("error connecting to vault: %s", err.Error()))
+			slog.Error(fmt.Sprintf("error creating rollout gate: %s", err.Error()))
 			os.Exit(1)
 		}
 
-		v, err := internalVault.New(ctx, store, cl, vaultConfigForConfig(c))
+		targets, err := configureTargetsForConfig(ctx, c, parser, store)
 		if err != nil {
-			slog.Error(fmt.Sprintf("error creating vault helper: %s", err.Error()))
+			slog.Error(fmt.Sprintf("error creating vault targets: %s", err.Error()))
 			os.Exit(1)
 		}
+		defer func() {
+			for _, target := range targets {
+				target.Vault.Close()
+			}
+		}()
+
+		if c.GetBool(cfgVerify) {
+			exitCode = verifyExitConverged
+			for _, target := range targets {
+				if code := verifyConfigurations(ctx, target, parser, vaultConfigFiles); code != verifyExitConverged && exitCode != verifyExitError {
+					exitCode = code
+				}
+			}
+
+			return
+		}
 
+		if c.GetBool(cfgManageToken) {
+			// The managers revoke their tokens when the command returns, before the deferred exit
+			managerCtx, stopManagers := context.WithCancel(ctx)
+			var managers sync.WaitGroup
+			defer func() {
+				stopManagers()
+				managers.Wait()
+			}()
+
+			interval := c.GetDuration(cfgManageTokenInterval)
+			for _, target := range targets {
+				managers.Add(1)
+				go func() {
+					defer managers.Done()
+					defer recoverScrubbed()
+					target.Vault.ManageToken(managerCtx, interval)
+				}()
+			}
+		}
+
+		vaults := make([]bankvaults.Vault, 0, len(targets))
+		for _, target := range targets {
+			vaults = append(vaults, target.Vault)
+		}
+		// The configurer idles until a config changes, only a run taking too long counts as wedged
+		health := newHealthChecker(store, vaults, c.GetDuration(cfgHealthLoopTimeout), true)
+		health.maxConsecutiveFailures = c.GetInt(cfgMaxConsecutiveFailures)
+		health.serve(ctx, c)
+
+		// The targets are told apart by the target label, the address only identifies a single one
+		var metricsAddress string
+		if len(targets) == 1 {
+			metricsAddress = targets[0].Address
+		}
+		configOutcomes.setWindow(c.GetDuration(cfgMetricsSLOWindow))
+		metrics := prometheusExporter{
+			Targets: targets,
+			Mode:    "configure",
+			Server:  metricsServerForConfig(c),
+			Labels:  instanceLabelsForConfig(c, metricsAddress),
+
+			LeaderElection: c.GetBool(cfgLeaderElection),
+		}
 		if !disableMetrics {
-			metrics := prometheusExporter{Vault: v, Mode: "configure"}
 			go func() {
 				err := metrics.Run()
 				if err != nil {
@@ -90,29 +214,55 @@ var configureCmd = &cobra.Command{
 			}()
 		}
 
-		// Create parsers
-		parser, err := multiparser.New(parser.JSON, parser.YAML)
-		if err != nil {
-			slog.Error(fmt.Sprintf("error file parsers: %v", err))
-			os.Exit(1)
-		}
+		var configurations chan *configFile
+		if c.GetBool(cfgConfigResource) {
+			resources, err := configResourceSourceForConfig(c)
+			if err != nil {
+				slog.Error(fmt.Sprintf("error creating config resource source: %s", err.Error()))
+				os.Exit(1)
+			}
+
+			configurations = make(chan *configFile, 1)
+			if runOnce {
+				go func() {
+					defer close(configurations)
+					if err := resources.list(ctx, configurations); err != nil {
+						slog.Error(err.Error())
+						os.Exit(1)
+					}
+				}()
+			} else {
+				go resources.watch(ctx, configurations)
+			}
+		} else {
+			configurations = make(chan *configFile, len(vaultConfigFiles))
+			for i, vaultConfigFile := range vaultConfigFiles {
+				vaultConfigFiles[i] = filepath.Clean(vaultConfigFile)
+				configurations <- parseConfiguration(parser, vaultConfigFile)
+			}
 
-		configurations := make(chan *configFile, len(vaultConfigFiles))
-		for i, vaultConfigFile := range vaultConfigFiles {
-			vaultConfigFiles[i] = filepath.Clean(vaultConfigFile)
-			configurations <- parseConfiguration(parser, vaultConfigFile)
+			if !runOnce {
+				go func() {
+					err := watchConfigurations(parser, vaultConfigFiles, configurations)
+					if err != nil {
+						slog.Error(fmt.Sprintf("error watching configuration: %v", err))
+						os.Exit(1)
+					}
+				}()
+			} else {
+				close(configurations)
+			}
 		}
 
+		// Changes made out-of-band are reverted right away, instead of at the next change of the config
+		var vaultEvents *vaultEventTrigger
 		if !runOnce {
-			go func() {
-				err := watchConfigurations(parser, vaultConfigFiles, configurations)
-				if err != nil {
-					slog.Error(fmt.Sprintf("error watching configuration: %v", err))
-					os.Exit(1)
-				}
-			}()
-		} else {
-			close(configurations)
+			vaultEvents, err = vaultEventTriggerForConfig(c, parser)
+			if err != nil {
+				slog.Error(fmt.Sprintf("error subscribing to vault events: %s", err.Error()))
+				os.Exit(1)
+			}
+			vaultEvents.watch(ctx, configurations)
 		}
 
 		// Handle backoff for configuration errors
@@ -123,61 +273,239 @@ var configureCmd = &cobra.Command{
 			Jitter: false,
 		}
 
-		for config := range configurations {
-			slog.Info(fmt.Sprintf("applying config file: %s", config.Path))
-			func() {
-				for {
-					slog.Info("checking if vault is sealed...")
-					sealed, err := v.Sealed()
-					if err != nil {
-						slog.Error(fmt.Sprintf("error checking if vault is sealed: %s, waiting %s before trying again...", err.Error(), unsealConfig.unsealPeriod))
-						time.Sleep(unsealConfig.unsealPeriod)
+		stopStatsd, err := startStatsdSink(ctx, c, &metrics)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error creating statsd sink: %s", err.Error()))
+			os.Exit(1)
+		}
 
-						continue
+		// Sends the last metrics before exiting, a one-shot run may be gone before it could be scraped
+		flushMetrics := func() {
+			stopStatsd()
+			if !runOnce {
+				return
+			}
+			if err := pushMetrics(ctx, c, &metrics); err != nil {
+				slog.Error(fmt.Sprintf("error pushing metrics: %s", err.Error()))
+			}
+		}
+
+		configure := func(ctx context.Context, target configureTarget, config *configFile, canary bool) error {
+			for {
+				slog.Info("checking if vault is sealed...", "target", target.Name)
+				sealed, err := target.Vault.Sealed()
+				if err != nil {
+					slog.Error("error checking if vault is sealed, waiting before trying again...", "target", target.Name, "error", err, "period", unsealConfig.unsealPeriod)
+					if err := sleepContext(ctx, unsealConfig.unsealPeriod); err != nil {
+						return err
 					}
 
-					// If vault is sealed, we stop here and wait another unsealPeriod
-					if sealed {
-						slog.Info(fmt.Sprintf("vault is sealed, waiting %s before trying again...", unsealConfig.unsealPeriod))
-						time.Sleep(unsealConfig.unsealPeriod)
+					continue
+				}
 
-						continue
+				// If vault is sealed, we stop here and wait another unsealPeriod
+				if sealed {
+					// Waiting for Vault to be unsealed is not a wedged run
+					health.heartbeat()
+					slog.Info("vault is sealed, waiting before trying again...", "target", target.Name, "period", unsealConfig.unsealPeriod)
+					if err := sleepContext(ctx, unsealConfig.unsealPeriod); err != nil {
+						return err
 					}
-					slog.Info("vault is unsealed, configuring...")
 
-					if err = v.Configure(ctx, config.Data); err != nil {
-						slog.Error(fmt.Sprintf("error configuring vault: %s", err.Error()))
-						if errorFatal {
-							os.Exit(1)
+					continue
+				}
+
+				if rollout != nil {
+					reason, err := rollout.pauseReason(ctx, target)
+					if err != nil || reason != "" {
+						// Waiting for a rollout to settle is not a wedged run either
+						health.heartbeat()
+						if err != nil {
+							slog.Error("error checking the vault rollout, waiting before trying again...", "target", target.Name, "error", err, "period", unsealConfig.unsealPeriod)
+						} else {
+							slog.Info("vault rollout in progress, waiting before trying again...", "target", target.Name, "reason", reason, "period", unsealConfig.unsealPeriod)
+						}
+						if err := sleepContext(ctx, unsealConfig.unsealPeriod); err != nil {
+							return err
 						}
 
-						failedConfigurationsCount++
-						// Failed configuration handler - Increase the backoff sleep
-						go handleConfigurationError(parser, config.Path, configurations, b.Duration())
+						continue
+					}
+				}
+				slog.Info("vault is unsealed, configuring...", "target", target.Name)
 
-						return
+				data, err := applyOverlays(parser, config.Data, target.Overlays)
+				if err != nil {
+					recordTargetConfiguration(target.Name, err)
+					return err
+				}
+				if canary {
+					data = canaryConfig.config(data)
+
+					var closeCanary func()
+					target, closeCanary, err = canaryConfig.target(ctx, target)
+					if err != nil {
+						recordTargetConfiguration(target.Name, err)
+						return err
 					}
+					defer closeCanary()
+				}
 
-					// On *any* successful configuration reset the backoff
-					b.Reset()
-					successfulConfigurationsCount++
-					slog.Info("successfully configured vault")
+				err = target.Vault.Configure(ctx, data)
+				if rErr := writeReport(ctx, reportOutput, store, target.Name, config.Path, target.Vault.Report()); rErr != nil {
+					slog.Error("error writing apply report", "target", target.Name, "error", rErr)
+				}
+				recordTargetConfiguration(target.Name, err)
+				recordSectionMetrics(target.Name, target.Vault.Report())
+				recordTokenRenewal(target.Name, target.Vault.Report())
+				recordErrorCategories(target.Name, target.Vault.Report())
+				if err == nil {
+					recordConfigIdentity(target.Name, target.Vault.Report())
+				}
+				if err == nil && canary && canaryConfig.verify {
+					err = verifyCanary(ctx, target, data)
+				}
 
-					return
+				return err
+			}
+		}
+
+		apply := func(ctx context.Context) error {
+			for {
+				var config *configFile
+				select {
+				case <-ctx.Done():
+					return nil
+				case next, ok := <-configurations:
+					if !ok {
+						return nil
+					}
+					config = next
 				}
-			}()
+
+				slog.Info("applying config file", "file", config.Path)
+				health.heartbeat()
+
+				vaultEvents.runStarted()
+				err := applyWithCanary(targets, canaryConfig, c.GetBool(cfgTargetsParallel), func(target configureTarget, canary bool) error {
+					return configure(ctx, target, config, canary)
+				})
+				vaultEvents.runFinished(config)
+				health.iterationDone(err)
+				if err != nil && ctx.Err() != nil {
+					slog.Warn("configure run aborted by shutdown", "error", err)
+					if runOnce {
+						exitCode = configureExitError
+					}
+
+					return nil
+				}
+				if config.resource != nil {
+					config.resource.updateStatus(ctx, err)
+				}
+				if err != nil {
+					slog.Error(fmt.Sprintf("error configuring vault: %s", err.Error()))
+					failedConfigurationsCount++
+					if errorFatal {
+						return err
+					}
+					if health.failedTooOften() {
+						return errors.Errorf("configuring vault failed %d times in a row", health.maxConsecutiveFailures)
+					}
+
+					// Nothing retries a one-shot run, its exit code tells how it went
+					if runOnce {
+						// A failed config file outweighs partially applied ones
+						if exitCode != configureExitError {
+							exitCode = configureExitCode(err)
+						}
+
+						continue
+					}
+
+					// Failed configuration handler - Increase the backoff sleep
+					go handleConfigurationError(ctx, parser, config, configurations, b.Duration())
+
+					continue
+				}
+
+				// On *any* successful configuration reset the backoff
+				b.Reset()
+				successfulConfigurationsCount++
+				slog.Info("successfully configured vault")
+			}
 		}
+
+		if c.GetBool(cfgLeaderElection) {
+			err = runWithLeaderElection(ctx, c, defaultConfigureLeaseName, apply)
+		} else {
+			err = apply(ctx)
+		}
+		if err != nil {
+			slog.Error(err.Error())
+			// Exit after the deferred shutdown of tracing
+			exitCode = configureExitError
+		}
+		flushMetrics()
 	},
 }
 
-func handleConfigurationError(parser multiparser.Parser, vaultConfigFile string, configurations chan<- *configFile, sleepTime time.Duration) {
+// configureExitCode returns the exit code of a one-shot run: partial failure if every target
+// only failed because of skipped config items, error otherwise.
+func configureExitCode(err error) int {
+	if err == nil {
+		return configureExitSuccess
+	}
+
+	for _, err := range errors.GetErrors(err) {
+		if !bankvaults.IsPartialFailure(err) {
+			return configureExitError
+		}
+	}
+
+	return configureExitPartial
+}
+
+func auditTrailForConfig(cfg *viper.Viper, store kv.Service, cl *api.Client) (bankvaults.AuditTrail, error) {
+	switch auditTrail := cfg.GetString(cfgAuditTrail); auditTrail {
+	case "":
+		return nil, nil
+	case cfgAuditTrailValueKV:
+		return bankvaults.NewKVAuditTrail(store), nil
+	case cfgAuditTrailValueVault:
+		path := cfg.GetString(cfgAuditTrailVaultPath)
+		if path == "" {
+			return nil, errors.Errorf("--%s must be set for the '%s' audit trail", cfgAuditTrailVaultPath, cfgAuditTrailValueVault)
+		}
+
+		return bankvaults.NewVaultAuditTrail(cl, path), nil
+	default:
+		return nil, errors.Errorf("unsupported audit trail: '%s'", auditTrail)
+	}
+}
+
+func handleConfigurationError(ctx context.Context, parser multiparser.Parser, config *configFile, configurations chan<- *configFile, sleepTime time.Duration) {
 	// This handler will sleep for a exponential backoff amount of time and re-inject the failed configuration into the
 	// configurations channel to be re-applied to vault
 	// Eventually consistent model - all recoverable errors (5xx and configs that depend on other configs) will be eventually fixed
 	// non recoverable errors will be retried and keep failing every MAX BACKOFF seconds, increasing the error counters ont he vault-configurator pod.
-	slog.Info(fmt.Sprintf("Failed applying configuration file: %s , sleeping for %s before trying again", vaultConfigFile, sleepTime))
-	time.Sleep(sleepTime)
-	configurations <- parseConfiguration(parser, vaultConfigFile)
+	slog.Info(fmt.Sprintf("Failed applying configuration file: %s , sleeping for %s before trying again", config.Path, sleepTime))
+	if err := sleepContext(ctx, sleepTime); err != nil {
+		return
+	}
+
+	if config.resource == nil {
+		configurations <- parseConfiguration(parser, config.Path)
+		return
+	}
+
+	// The resource may have changed or be gone since
+	reloaded, err := config.resource.reload(ctx)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error reloading %s, not retrying it: %s", config.Path, err.Error()))
+		return
+	}
+	configurations <- reloaded
 }
 
 func watchConfigurations(parser multiparser.Parser, vaultConfigFiles []string, configurations chan<- *configFile) error {
@@ -238,32 +566,46 @@ func watchConfigurations(parser multiparser.Parser, vaultConfigFiles []string, c
 }
 
 func parseConfiguration(parser multiparser.Parser, vaultConfigFile string) *configFile {
+	config, err := readConfiguration(parser, vaultConfigFile)
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+
+	return config
+}
+
+// readConfiguration reads, verifies the signature of if enabled, templates and parses a config file.
+func readConfiguration(parser multiparser.Parser, vaultConfigFile string) (*configFile, error) {
 	// Read file
 	vaultConfig, err := os.ReadFile(vaultConfigFile)
 	if err != nil {
-		slog.Error(fmt.Sprintf("error reading vault config template: %s", err.Error()))
-		os.Exit(1)
+		return nil, errors.Wrap(err, "error reading vault config template")
+	}
+
+	if configSignature != nil {
+		if err := configSignature.verifyFile(vaultConfigFile, vaultConfig); err != nil {
+			return nil, err
+		}
 	}
 
 	// Replace env templating data
 	templater := templater.NewTemplater(templater.DefaultLeftDelimiter, templater.DefaultRightDelimiter)
 	buffer, err := templater.EnvTemplate(string(vaultConfig))
 	if err != nil {
-		slog.Error(fmt.Sprintf("error executing vault config template: %s", err.Error()))
-		os.Exit(1)
+		return nil, errors.Wrap(err, "error executing vault config template")
 	}
 
 	// Load raw data into map
 	var data map[string]interface{}
 	if err := parser.Parse(buffer.Bytes(), &data); err != nil {
-		slog.Error(fmt.Sprintf("error parsing vault config file: %v", err))
-		os.Exit(1)
+		return nil, errors.Wrap(err, "error parsing vault config file")
 	}
 
 	return &configFile{
 		Path: vaultConfigFile,
 		Data: data,
-	}
+	}, nil
 }
 
 func stringInSlice(list []string, match string) bool {
@@ -277,8 +619,21 @@ func stringInSlice(list []string, match string) bool {
 
 func init() {
 	configBoolVar(configureCmd, cfgFatal, false, "Make configuration errors fatal to the configurator")
-	configStringSliceVar(configureCmd, cfgVaultConfigFile, []string{internalVault.DefaultConfigFile}, "The filename of the YAML/JSON Vault configuration")
+	configBoolVar(configureCmd, cfgContinueOnError, false, "Skip failing config items, apply the rest of the config and report the failures at the end")
+	configIntVar(configureCmd, cfgConcurrency, 1, "How many policies, auth roles and startup secrets are applied at once")
+	configStringSliceVar(configureCmd, cfgVaultConfigFile, []string{bankvaults.DefaultConfigFile}, "The filename of the YAML/JSON Vault configuration")
 	configBoolVar(configureCmd, cfgDisableMetrics, false, "Disable configurer metrics")
+	configStringVar(configureCmd, cfgReportOutput, "", "Where to write the JSON report of each configure run: 'stdout', 'kv' (the configured key store) or a file path")
+	configStringVar(configureCmd, cfgAuditTrail, "", fmt.Sprintf("Record every write performed in an append-only audit trail stored in '%s' (the configured key store) or '%s' (a Vault KV path)", cfgAuditTrailValueKV, cfgAuditTrailValueVault))
+	configStringVar(configureCmd, cfgAuditTrailVaultPath, "", "The Vault KV path to store the audit trail in, e.g. 'secret/data/bank-vaults/audit'")
+	configBoolVar(configureCmd, cfgVerify, false, "Only verify if Vault matches the configuration and exit with 0 if it does, 2 if it drifted and 1 on errors")
+	configIntVar(configureCmd, cfgRetryAttempts, bankvaults.DefaultRetryPolicy.Attempts, "Maximum number of attempts of failing requests, 0 retries until the backoff reaches its maximum")
+	configDurationVar(configureCmd, cfgRetryMinBackoff, bankvaults.DefaultRetryPolicy.MinBackoff, "Minimum backoff between retries of failing requests")
+	configDurationVar(configureCmd, cfgRetryMaxBackoff, bankvaults.DefaultRetryPolicy.MaxBackoff, "Maximum backoff between retries of failing requests")
+	configDurationVar(configureCmd, cfgRetryTimeout, bankvaults.DefaultRetryPolicy.Timeout, "Overall time limit of the retries of a failing request, 0 means no limit")
+	configBoolVar(configureCmd, cfgManageToken, false, "Keep the token configure logs in with between the runs: renew it before it expires, log in again if renewing fails and revoke it on shutdown (except the stored configurer token)")
+	configDurationVar(configureCmd, cfgManageTokenInterval, time.Minute, "How often the managed token is checked")
+	configBoolVar(configureCmd, cfgSkipUnchanged, false, "Skip applying a config if neither it, the Secrets it references nor the Vault mount table changed since the last successful apply")
 
 	rootCmd.AddCommand(configureCmd)
 }
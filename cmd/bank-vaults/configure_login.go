@@ -0,0 +1,186 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const cfgTokenFile = "token-file"
+
+const (
+	cfgKubernetesAuthRole      = "kubernetes-auth-role"
+	cfgKubernetesAuthPath      = "kubernetes-auth-path"
+	cfgKubernetesAuthTokenFile = "kubernetes-auth-token-file"
+)
+
+const (
+	cfgAppRolePath                 = "approle-path"
+	cfgAppRoleRoleID               = "approle-role-id"
+	cfgAppRoleRoleIDFile           = "approle-role-id-file"
+	cfgAppRoleSecretID             = "approle-secret-id"
+	cfgAppRoleSecretIDFile         = "approle-secret-id-file"
+	cfgAppRoleSecret               = "approle-secret"
+	cfgAppRoleRotateSecretIDOfRole = "approle-rotate-secret-id-of-role"
+)
+
+const (
+	cfgCertAuth     = "cert-auth"
+	cfgCertAuthPath = "cert-auth-path"
+	cfgCertAuthRole = "cert-auth-role"
+)
+
+// Keys of the Kubernetes Secret holding the AppRole credentials.
+const (
+	appRoleSecretRoleIDKey   = "role_id"
+	appRoleSecretSecretIDKey = "secret_id"
+)
+
+// kubernetesAuthForConfig returns the Kubernetes auth login of the configurer, the role of a target overrides the flag.
+func kubernetesAuthForConfig(cfg *viper.Viper, target clusterTarget) bankvaults.KubernetesAuth {
+	auth := bankvaults.KubernetesAuth{
+		Role:      cfg.GetString(cfgKubernetesAuthRole),
+		Path:      cfg.GetString(cfgKubernetesAuthPath),
+		TokenFile: cfg.GetString(cfgKubernetesAuthTokenFile),
+	}
+
+	if target.KubernetesAuthRole != "" {
+		auth.Role = target.KubernetesAuthRole
+	}
+	if target.KubernetesAuthPath != "" {
+		auth.Path = target.KubernetesAuthPath
+	}
+
+	return auth
+}
+
+// appRoleCredentials reads the AppRole credentials from a Kubernetes Secret, files or the flags
+// (and so the BANK_VAULTS_APPROLE_* environment variables), in this order of precedence.
+type appRoleCredentials struct {
+	roleID       string
+	roleIDFile   string
+	secretID     string
+	secretIDFile string
+	secret       string
+
+	secrets *k8sSecretResolver
+}
+
+func (a *appRoleCredentials) Credentials(ctx context.Context) (string, string, error) {
+	roleID, err := a.credential(ctx, a.roleID, a.roleIDFile, appRoleSecretRoleIDKey)
+	if err != nil {
+		return "", "", errors.Wrap(err, "error reading role_id")
+	}
+
+	secretID, err := a.credential(ctx, a.secretID, a.secretIDFile, appRoleSecretSecretIDKey)
+	if err != nil {
+		return "", "", errors.Wrap(err, "error reading secret_id")
+	}
+
+	return roleID, secretID, nil
+}
+
+func (a *appRoleCredentials) credential(ctx context.Context, value, file, secretKey string) (string, error) {
+	if a.secret != "" {
+		return a.secrets.SecretValue(ctx, "", a.secret, secretKey)
+	}
+
+	if file != "" {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", errors.Wrapf(err, "error reading %s", file)
+		}
+
+		return strings.TrimSpace(string(content)), nil
+	}
+
+	return value, nil
+}
+
+func (a *appRoleCredentials) StoreSecretID(ctx context.Context, secretID string) error {
+	if a.secret != "" {
+		return a.secrets.updateSecretValue(ctx, "", a.secret, appRoleSecretSecretIDKey, secretID)
+	}
+
+	if a.secretIDFile != "" {
+		return errors.Wrapf(os.WriteFile(a.secretIDFile, []byte(secretID), 0o600), "error writing %s", a.secretIDFile)
+	}
+
+	return errors.Errorf("a secret_id set by flag or environment can't be rotated, use --%s or --%s", cfgAppRoleSecretIDFile, cfgAppRoleSecret)
+}
+
+// appRoleAuthForConfig returns the AppRole auth login of the configurer, disabled if no role_id is configured.
+func appRoleAuthForConfig(cfg *viper.Viper, secrets *k8sSecretResolver) bankvaults.AppRoleAuth {
+	credentials := &appRoleCredentials{
+		roleID:       cfg.GetString(cfgAppRoleRoleID),
+		roleIDFile:   cfg.GetString(cfgAppRoleRoleIDFile),
+		secretID:     cfg.GetString(cfgAppRoleSecretID),
+		secretIDFile: cfg.GetString(cfgAppRoleSecretIDFile),
+		secret:       cfg.GetString(cfgAppRoleSecret),
+		secrets:      secrets,
+	}
+	if credentials.roleID == "" && credentials.roleIDFile == "" && credentials.secret == "" {
+		return bankvaults.AppRoleAuth{}
+	}
+
+	auth := bankvaults.AppRoleAuth{
+		Credentials: credentials,
+		Path:        cfg.GetString(cfgAppRolePath),
+	}
+
+	if role := cfg.GetString(cfgAppRoleRotateSecretIDOfRole); role != "" && !skipDryRun("rotate the approle secret_id", "role", role) {
+		auth.RotateSecretIDRole = role
+	}
+
+	return auth
+}
+
+// certAuthForConfig returns the TLS certificate auth login of the configurer, which presents the
+// client certificate of the target, reloaded once rotated.
+func certAuthForConfig(cfg *viper.Viper) bankvaults.CertAuth {
+	return bankvaults.CertAuth{
+		Enabled: cfg.GetBool(cfgCertAuth),
+		Path:    cfg.GetString(cfgCertAuthPath),
+		Role:    cfg.GetString(cfgCertAuthRole),
+	}
+}
+
+func init() {
+	configStringVar(configureCmd, cfgTokenFile, "", "File holding the token to configure Vault with instead of the root token, e.g. the sink of a Vault Agent, re-read at every run")
+
+	configStringVar(configureCmd, cfgKubernetesAuthRole, "", "Log in with the Kubernetes auth method as this role instead of using the root token")
+	configStringVar(configureCmd, cfgKubernetesAuthPath, bankvaults.DefaultKubernetesAuthPath, "Mount path of the Kubernetes auth method to log in with")
+	configStringVar(configureCmd, cfgKubernetesAuthTokenFile, bankvaults.DefaultServiceAccountTokenFile, "Projected service account token to log in with the Kubernetes auth method")
+
+	configStringVar(configureCmd, cfgAppRolePath, bankvaults.DefaultAppRoleAuthPath, "Mount path of the AppRole auth method to log in with")
+	configStringVar(configureCmd, cfgAppRoleRoleID, "", "Log in with the AppRole auth method with this role_id instead of using the root token")
+	configStringVar(configureCmd, cfgAppRoleRoleIDFile, "", "File holding the role_id to log in with the AppRole auth method")
+	configStringVar(configureCmd, cfgAppRoleSecretID, "", "The secret_id to log in with the AppRole auth method")
+	configStringVar(configureCmd, cfgAppRoleSecretIDFile, "", "File holding the secret_id to log in with the AppRole auth method, re-read at every login")
+	configStringVar(configureCmd, cfgAppRoleSecret, "", "Kubernetes Secret holding the role_id and secret_id keys to log in with the AppRole auth method, re-read at every login")
+	configStringVar(configureCmd, cfgAppRoleRotateSecretIDOfRole, "", "Generate a new secret_id of this AppRole role after every login, store it in the secret_id file or Secret and destroy the previous one")
+
+	configBoolVar(configureCmd, cfgCertAuth, false, fmt.Sprintf("Log in with the TLS certificate auth method presenting the --%s and --%s certificate instead of using the root token", cfgTargetClientCert, cfgTargetClientKey))
+	configStringVar(configureCmd, cfgCertAuthPath, bankvaults.DefaultCertAuthPath, "Mount path of the TLS certificate auth method to log in with")
+	configStringVar(configureCmd, cfgCertAuthRole, "", "Certificate role to log in as, all roles matching the client certificate are tried if empty")
+}
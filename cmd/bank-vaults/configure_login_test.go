@@ -0,0 +1,59 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppRoleCredentialsFromFiles(t *testing.T) {
+	dir := t.TempDir()
+	roleIDFile := filepath.Join(dir, "role-id")
+	secretIDFile := filepath.Join(dir, "secret-id")
+	require.NoError(t, os.WriteFile(roleIDFile, []byte("role-id\n"), 0o600))
+	require.NoError(t, os.WriteFile(secretIDFile, []byte("old-secret-id\n"), 0o600))
+
+	credentials := &appRoleCredentials{roleID: "ignored", roleIDFile: roleIDFile, secretIDFile: secretIDFile}
+
+	ctx := context.Background()
+	roleID, secretID, err := credentials.Credentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "role-id", roleID)
+	assert.Equal(t, "old-secret-id", secretID)
+
+	require.NoError(t, credentials.StoreSecretID(ctx, "new-secret-id"))
+
+	_, secretID, err = credentials.Credentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "new-secret-id", secretID)
+}
+
+func TestAppRoleCredentialsFromFlagsCantBeRotated(t *testing.T) {
+	credentials := &appRoleCredentials{roleID: "role-id", secretID: "secret-id"}
+
+	ctx := context.Background()
+	roleID, secretID, err := credentials.Credentials(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "role-id", roleID)
+	assert.Equal(t, "secret-id", secretID)
+
+	assert.Error(t, credentials.StoreSecretID(ctx, "new-secret-id"))
+}
@@ -0,0 +1,63 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+func TestConfigureExitCode(t *testing.T) {
+	assert.Equal(t, configureExitSuccess, configureExitCode(nil))
+	assert.Equal(t, configureExitError, configureExitCode(errors.New("permission denied")))
+
+	// A policy write fails in continue-on-error mode, the rest of the config is applied
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/v1/sys/policies/acl/broken":
+			http.Error(w, `{"errors":["invalid policy"]}`, http.StatusBadRequest)
+		case r.Method == http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	v, err := bankvaults.New(context.Background(), nil, client, bankvaults.Config{Token: "token", ContinueOnError: true})
+	require.NoError(t, err)
+	defer v.Close()
+
+	err = v.Configure(context.Background(), map[string]interface{}{
+		"policies": []interface{}{map[string]interface{}{"name": "broken", "rules": `path "secret/*" { capabilities = ["read"] }`}},
+	})
+	require.Error(t, err)
+	assert.Equal(t, configureExitPartial, configureExitCode(err))
+	assert.Equal(t, configureExitError, configureExitCode(errors.Combine(err, errors.New("connection refused"))))
+}
@@ -0,0 +1,97 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/spf13/cobra"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgConfigurerPolicyOverlays = "configurer-policy-overlays"
+	cfgConfigurerPolicyLicense  = "configurer-policy-license"
+)
+
+var configurerPolicyCmd = &cobra.Command{
+	Use:   "configurer-policy [config files]",
+	Short: "Print the least-privilege policy configure needs to apply the external configuration",
+	Long: `This command reads the external configuration files like configure does and prints the
+minimal ACL policy the token of configure needs to apply them: reading the mount tables, managing
+the configured audit devices, auth methods, secret engines, plugins and policies, and writing the
+startup secrets. Wildcard paths are only granted if purging unmanaged config needs them.
+
+The policy covers all the given files, it is the one of the configurer token. Writing it to Vault and
+logging in with a token of it moves configure off the root token. Reads the default config file if no
+files are given.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{bankvaults.DefaultConfigFile}
+		}
+
+		parser, err := multiparser.New(parser.JSON, parser.YAML)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error file parsers: %v", err))
+			os.Exit(1)
+		}
+
+		overlays := c.GetStringSlice(cfgConfigurerPolicyOverlays)
+		if err := printConfigurerPolicy(os.Stdout, parser, overlays, c.GetBool(cfgConfigurerPolicyLicense), args); err != nil {
+			slog.Error(fmt.Sprintf("error generating configurer policy: %s", err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+// printConfigurerPolicy prints the policy configure needs to apply the config files after templating and overlays.
+func printConfigurerPolicy(w io.Writer, parser multiparser.Parser, overlays []string, license bool, vaultConfigFiles []string) error {
+	configs := make([]map[string]interface{}, 0, len(vaultConfigFiles))
+	for _, vaultConfigFile := range vaultConfigFiles {
+		config, err := readConfiguration(parser, vaultConfigFile)
+		if err != nil {
+			return errors.Wrapf(err, "error reading %s", vaultConfigFile)
+		}
+
+		data, err := applyOverlays(parser, config.Data, overlays)
+		if err != nil {
+			return errors.Wrapf(err, "error applying overlays to %s", vaultConfigFile)
+		}
+		configs = append(configs, data)
+	}
+
+	policy, err := bankvaults.ConfigurerPolicy(configs, license)
+	if err != nil {
+		return errors.Wrap(err, "error decoding config")
+	}
+
+	fmt.Fprintf(w, "# %s\n%s", bankvaults.ConfigurerPolicyName, policy)
+
+	return nil
+}
+
+func init() {
+	configStringSliceVar(configurerPolicyCmd, cfgConfigurerPolicyOverlays, []string{}, "Overlay files applied in order to the configs, like --"+cfgOverlays+" of configure")
+	configBoolVar(configurerPolicyCmd, cfgConfigurerPolicyLicense, false, "Grant managing the license, needed if configure runs with --"+cfgLicense+", --"+cfgLicenseFile+" or --"+cfgLicenseKVKey)
+
+	rootCmd.AddCommand(configurerPolicyCmd)
+}
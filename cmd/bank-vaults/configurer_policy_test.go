@@ -0,0 +1,82 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintConfigurerPolicy(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "vault-config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+policies:
+  - name: allow_secrets
+    rules: path "secret/*" { capabilities = ["read"] }
+secrets:
+  - type: kv
+    path: secret
+`), 0o600))
+	otherConfigFile := filepath.Join(dir, "auth.yml")
+	require.NoError(t, os.WriteFile(otherConfigFile, []byte(`
+auth:
+  - type: kubernetes
+`), 0o600))
+	overlayFile := filepath.Join(dir, "prod.yml")
+	require.NoError(t, os.WriteFile(overlayFile, []byte(`
+startupSecrets:
+  - type: kv
+    path: secret/data/app
+    data:
+      data:
+        key: value
+`), 0o600))
+
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, printConfigurerPolicy(&out, parser, []string{overlayFile}, false, []string{configFile, otherConfigFile}))
+
+	policy := out.String()
+	assert.Contains(t, policy, "# bank-vaults-configurer\n")
+	assert.Contains(t, policy, `path "sys/policies/acl/allow_secrets"`)
+	assert.Contains(t, policy, `path "sys/mounts/secret"`)
+	assert.Contains(t, policy, `path "sys/auth/kubernetes"`)
+	assert.Contains(t, policy, `path "secret/data/app"`)
+	assert.NotContains(t, policy, `path "sys/mounts/*"`)
+	assert.NotContains(t, policy, `path "sys/license"`)
+}
+
+func TestPrintConfigurerPolicyInvalidConfig(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "vault-config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte("polices: []\n"), 0o600))
+
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.Error(t, printConfigurerPolicy(&out, parser, nil, false, []string{configFile}))
+	assert.Empty(t, out.String())
+}
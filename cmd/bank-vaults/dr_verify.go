@@ -0,0 +1,305 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/spf13/cobra"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/file"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgDRVerifyVaultAddress = "dr-verify-vault-addr"
+	cfgDRVerifyScratchDir   = "dr-verify-scratch-dir"
+	cfgDRVerifyOutput       = "dr-verify-output"
+)
+
+// Names of the checks of the disaster-recovery report.
+const (
+	drCheckDecrypt      = "decrypt"
+	drCheckScratch      = "scratch-restore"
+	drCheckUnseal       = "unseal"
+	drCheckGenerateRoot = "generate-root"
+)
+
+var drVerifyCmd = &cobra.Command{
+	Use:   "dr-verify",
+	Short: "Verify that the stored unseal keys can recover Vault",
+	Long: `This command reads and decrypts the unseal keys, recovery keys and root token from
+the key store, restores them into a scratch directory and checks that enough of
+them are left to reach the secret threshold.
+
+With --dr-verify-vault-addr it also unseals that Vault with the restored keys and
+generates a root token with them, it must be a throwaway instance started from a
+copy of the storage of the cluster (e.g. restored with 'snapshot restore'), never
+the cluster itself. The root token and the token created with it are revoked
+afterwards. The TLS settings of the --target-* flags are used to connect to it.
+
+The JSON pass/fail report is printed to the standard output or --dr-verify-output,
+the command exits with 1 if a check failed, so DR readiness can be tested by a CronJob.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		ctx := cmd.Context()
+
+		store, _, err := kvStoreForConfig(ctx, c)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error creating kv store: %s", err.Error()))
+			os.Exit(1)
+		}
+
+		var cl *api.Client
+		if address := c.GetString(cfgDRVerifyVaultAddress); address != "" {
+			target := targetForConfig(c)
+			target.Address = address
+			cl, err = target.newClient()
+			if err != nil {
+				slog.Error(fmt.Sprintf("error connecting to vault: %s", err.Error()))
+				os.Exit(1)
+			}
+		}
+
+		scratchDir, err := os.MkdirTemp(c.GetString(cfgDRVerifyScratchDir), "bank-vaults-dr-")
+		if err != nil {
+			slog.Error(fmt.Sprintf("error creating scratch directory: %s", err.Error()))
+			os.Exit(1)
+		}
+
+		report := verifyDR(ctx, store, scratchDir, cl, c.GetInt(cfgSecretShares), c.GetInt(cfgSecretThreshold))
+		// The scratch directory holds the decrypted keys
+		if err := os.RemoveAll(scratchDir); err != nil {
+			slog.Error(fmt.Sprintf("error removing scratch directory %s: %s", scratchDir, err.Error()))
+		}
+
+		if err := writeDRReport(report, c.GetString(cfgDRVerifyOutput)); err != nil {
+			slog.Error(fmt.Sprintf("error writing disaster-recovery report: %s", err.Error()))
+			os.Exit(1)
+		}
+
+		if !report.Passed {
+			slog.Error("disaster-recovery verification failed")
+			os.Exit(1)
+		}
+
+		slog.Info("disaster-recovery verification passed")
+	},
+}
+
+// drReport is the outcome of a disaster-recovery verification.
+type drReport struct {
+	Time   time.Time `json:"time"`
+	Passed bool      `json:"passed"`
+	Checks []drCheck `json:"checks"`
+}
+
+// drCheck is one of the checks of a disaster-recovery verification.
+type drCheck struct {
+	Name    string `json:"name"`
+	Passed  bool   `json:"passed"`
+	Details string `json:"details,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (r *drReport) add(name, details string, err error) {
+	check := drCheck{Name: name, Passed: err == nil, Details: details}
+	if err != nil {
+		check.Error = err.Error()
+		r.Passed = false
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+// verifyDR decrypts the init output stored in the key store, restores it into the scratch directory and,
+// given a client of a throwaway Vault, proves that the restored keys unseal it and generate a root token.
+// The checks after a failed one are skipped.
+func verifyDR(ctx context.Context, store kv.Service, scratchDir string, cl *api.Client, shares, threshold int) *drReport {
+	report := &drReport{Time: time.Now().UTC(), Passed: true}
+
+	values, details, err := readInitOutput(ctx, store, shares, threshold)
+	report.add(drCheckDecrypt, details, err)
+	if err != nil {
+		return report
+	}
+
+	scratch, err := restoreInitOutput(ctx, scratchDir, values)
+	report.add(drCheckScratch, fmt.Sprintf("restored %d values into %s", len(values), scratchDir), err)
+	if err != nil || cl == nil {
+		return report
+	}
+
+	v, err := bankvaults.New(ctx, scratch, cl, bankvaults.Config{
+		SecretShares:    shares,
+		SecretThreshold: threshold,
+		RevokeRootToken: true,
+	})
+	if err != nil {
+		report.add(drCheckUnseal, "", errors.Wrap(err, "error creating vault helper"))
+		return report
+	}
+	defer v.Close()
+
+	details, err = unsealDRVault(ctx, v, cl)
+	report.add(drCheckUnseal, details, err)
+	if err != nil {
+		return report
+	}
+
+	report.add(drCheckGenerateRoot, "generated a root token and created a token with it", generateDRRootToken(ctx, v, cl))
+
+	return report
+}
+
+// readInitOutput reads and decrypts the unseal keys, recovery keys and root token stored in the key store,
+// failing if neither the unseal nor the recovery keys reach the threshold.
+func readInitOutput(ctx context.Context, store kv.Service, shares, threshold int) (map[string][]byte, string, error) {
+	values := map[string][]byte{}
+	var unsealKeys, recoveryKeys int
+	for _, key := range bankvaults.InitOutputKeys(bankvaults.Config{SecretShares: shares}) {
+		value, err := store.Get(ctx, key)
+		if kv.IsNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "error reading '%s'", key)
+		}
+		if len(value) == 0 {
+			return nil, "", errors.Errorf("'%s' is empty", key)
+		}
+
+		values[key] = value
+		switch {
+		case strings.HasPrefix(key, "vault-unseal-"):
+			unsealKeys++
+		case strings.HasPrefix(key, "vault-recovery-"):
+			recoveryKeys++
+		}
+	}
+
+	_, rootToken := values["vault-root"]
+	details := fmt.Sprintf("%d unseal keys, %d recovery keys, root token stored: %t", unsealKeys, recoveryKeys, rootToken)
+	if unsealKeys < threshold && recoveryKeys < threshold {
+		return nil, details, errors.Errorf("fewer unseal and recovery keys stored than the threshold of %d", threshold)
+	}
+
+	return values, details, nil
+}
+
+// restoreInitOutput writes the values into a file key store in the scratch directory and reads them back.
+func restoreInitOutput(ctx context.Context, scratchDir string, values map[string][]byte) (kv.Service, error) {
+	scratch, err := file.New(scratchDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating scratch kv store")
+	}
+
+	for key, value := range values {
+		if err := scratch.Set(ctx, key, value); err != nil {
+			return nil, errors.Wrapf(err, "error restoring '%s'", key)
+		}
+		restored, err := scratch.Get(ctx, key)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading restored '%s'", key)
+		}
+		if !bytes.Equal(restored, value) {
+			return nil, errors.Errorf("restored '%s' differs from the stored one", key)
+		}
+	}
+
+	return scratch, nil
+}
+
+// unsealDRVault unseals the throwaway Vault with the restored keys, Vault unsealed by its KMS
+// with recovery keys passes without it.
+func unsealDRVault(ctx context.Context, v bankvaults.Vault, cl *api.Client) (string, error) {
+	status, err := cl.Sys().SealStatusWithContext(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "error getting seal status")
+	}
+	if !status.Initialized {
+		return "", errors.New("vault isn't initialized, start it from a copy of the storage of the cluster")
+	}
+	if status.RecoverySeal {
+		if status.Sealed {
+			return "", errors.New("vault with recovery keys is sealed, its KMS didn't unseal it")
+		}
+		return "unsealed by its KMS, the recovery keys are proven by generating a root token", nil
+	}
+	if !status.Sealed {
+		return "", errors.New("vault is unsealed already, the unseal keys can't be proven with it")
+	}
+
+	if err := v.Unseal(ctx); err != nil {
+		return "", err
+	}
+
+	return "unsealed with the restored unseal keys", nil
+}
+
+// generateDRRootToken generates a root token with the restored keys and creates a short-lived token with it,
+// revoking both.
+func generateDRRootToken(ctx context.Context, v bankvaults.Vault, cl *api.Client) error {
+	token, err := v.CreateToken(ctx, []string{"default"}, time.Minute)
+	if err != nil {
+		return err
+	}
+
+	tokenClient, err := cl.Clone()
+	if err != nil {
+		return errors.Wrap(err, "error cloning client")
+	}
+	tokenClient.SetToken(token)
+	if err := tokenClient.Auth().Token().RevokeSelfWithContext(ctx, ""); err != nil {
+		return errors.Wrap(err, "error revoking the created token")
+	}
+
+	return nil
+}
+
+// writeDRReport writes the report as JSON to the file, or to the standard output if empty.
+func writeDRReport(report *drReport, path string) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+func init() {
+	configStringVar(drVerifyCmd, cfgDRVerifyVaultAddress, "", "Address of a throwaway Vault started from a copy of the storage of the cluster, to unseal and generate a root token with the restored keys")
+	configStringVar(drVerifyCmd, cfgDRVerifyScratchDir, "", "Directory the scratch directory the keys are restored into is created in, the default temporary directory if empty")
+	configStringVar(drVerifyCmd, cfgDRVerifyOutput, "", "File to write the JSON report to, the standard output if empty")
+
+	rootCmd.AddCommand(drVerifyCmd)
+}
@@ -0,0 +1,150 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drVault fakes a sealed Vault unsealed and generating root tokens with two unseal keys.
+type drVault struct {
+	t        *testing.T
+	mu       sync.Mutex
+	sealed   bool
+	progress int
+	revoked  []string
+}
+
+func (d *drVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	const otp = "otp-otp-otp"
+	const rootToken = "hvs.rootabc"
+
+	var response interface{}
+	switch r.URL.Path {
+	case "/v1/sys/seal-status":
+		response = map[string]interface{}{"initialized": true, "sealed": d.sealed, "t": 2, "n": 3}
+	case "/v1/sys/unseal":
+		d.progress++
+		d.sealed = d.progress < 2
+		response = map[string]interface{}{"initialized": true, "sealed": d.sealed, "progress": d.progress % 2, "t": 2, "n": 3}
+	case "/v1/sys/generate-root/attempt":
+		d.progress = 0
+		response = map[string]interface{}{"nonce": "nonce", "required": 2, "otp": otp, "otp_length": len(otp)}
+	case "/v1/sys/generate-root/update":
+		d.progress++
+		encoded := make([]byte, len(otp))
+		for i := range encoded {
+			encoded[i] = rootToken[i] ^ otp[i]
+		}
+		response = map[string]interface{}{"complete": d.progress == 2, "encoded_root_token": base64.RawStdEncoding.EncodeToString(encoded)}
+	case "/v1/auth/token/create-orphan":
+		assert.Equal(d.t, rootToken, r.Header.Get("X-Vault-Token"))
+		response = map[string]interface{}{"auth": map[string]interface{}{"client_token": "hvs.created"}}
+	case "/v1/auth/token/revoke-self":
+		d.revoked = append(d.revoked, r.Header.Get("X-Vault-Token"))
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response) //nolint:errcheck
+}
+
+func drStore() mapKVStore {
+	return mapKVStore{
+		"vault-unseal-0": []byte("key-0"),
+		"vault-unseal-1": []byte("key-1"),
+		"vault-unseal-2": []byte("key-2"),
+	}
+}
+
+func TestVerifyDR(t *testing.T) {
+	fake := &drVault{t: t, sealed: true}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	cl, err := vaultTarget{Address: srv.URL}.newClient()
+	require.NoError(t, err)
+
+	scratchDir := t.TempDir()
+	report := verifyDR(context.Background(), drStore(), scratchDir, cl, 3, 2)
+
+	assert.True(t, report.Passed, "%+v", report.Checks)
+	require.Len(t, report.Checks, 4)
+	assert.Equal(t, drCheckDecrypt, report.Checks[0].Name)
+	assert.Equal(t, "3 unseal keys, 0 recovery keys, root token stored: false", report.Checks[0].Details)
+	assert.Equal(t, drCheckGenerateRoot, report.Checks[3].Name)
+	assert.False(t, fake.sealed)
+	assert.ElementsMatch(t, []string{"hvs.rootabc", "hvs.created"}, fake.revoked, "the generated tokens are revoked")
+
+	restored, err := os.ReadFile(filepath.Join(scratchDir, "vault-unseal-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", string(restored))
+}
+
+func TestVerifyDRFailures(t *testing.T) {
+	store := drStore()
+	delete(store, "vault-unseal-1")
+	delete(store, "vault-unseal-2")
+
+	report := verifyDR(context.Background(), store, t.TempDir(), nil, 3, 2)
+	assert.False(t, report.Passed)
+	require.Len(t, report.Checks, 1, "the checks after a failed one are skipped")
+	assert.Contains(t, report.Checks[0].Error, "threshold of 2")
+
+	// Unsealed already, so the unseal keys aren't proven
+	srv := httptest.NewServer(&drVault{t: t})
+	defer srv.Close()
+	cl, err := vaultTarget{Address: srv.URL}.newClient()
+	require.NoError(t, err)
+
+	report = verifyDR(context.Background(), drStore(), t.TempDir(), cl, 3, 2)
+	assert.False(t, report.Passed)
+	require.Len(t, report.Checks, 3)
+	assert.True(t, report.Checks[1].Passed)
+	assert.Contains(t, report.Checks[2].Error, "unsealed already")
+}
+
+func TestWriteDRReport(t *testing.T) {
+	report := &drReport{Passed: true}
+	report.add(drCheckDecrypt, "3 unseal keys", nil)
+
+	output := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, writeDRReport(report, output))
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var written drReport
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, report.Checks, written.Checks)
+	assert.True(t, written.Passed)
+}
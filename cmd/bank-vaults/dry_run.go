@@ -0,0 +1,104 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+const cfgDryRun = "dry-run"
+
+// dryRun is set by --dry-run: the Vault writes and key store writes are only logged.
+var dryRun bool
+
+// skipDryRun logs an action instead of performing it in dry-run mode.
+func skipDryRun(action string, args ...interface{}) bool {
+	if !dryRun {
+		return false
+	}
+
+	slog.Info("dry run: would "+action, args...)
+
+	return true
+}
+
+// dryRunTransport passes the reads and logins through to Vault and only logs the other requests,
+// answering them with an empty JSON object as if they succeeded. Generating a root token fails.
+type dryRunTransport struct {
+	base http.RoundTripper
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadOnlyRequest(req) {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	// A faked generate-root answer would be taken for a token, a dry run needs a token of its own
+	if path := strings.TrimPrefix(req.URL.Path, "/v1/"); strings.HasPrefix(path, "sys/generate-root/") {
+		return nil, errors.Errorf("dry run: %s %s would generate a root token, a dry run needs a token", req.Method, path)
+	}
+	slog.Info("dry run: would call vault", "method", req.Method, "path", req.URL.Path)
+
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+// isReadOnlyRequest tells whether a request leaves Vault unchanged. Logins are let through as well,
+// so the config can be read with the token of the configurer.
+func isReadOnlyRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, "LIST":
+		return true
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v1/")
+
+	return strings.HasPrefix(path, "auth/") && (strings.HasSuffix(path, "/login") || strings.Contains(path, "/login/"))
+}
+
+// dryRunKVStore reads from the key store, but only logs the writes.
+type dryRunKVStore struct {
+	kv.Service
+}
+
+func (s *dryRunKVStore) Set(_ context.Context, key string, _ []byte) error {
+	slog.Info("dry run: would write key store", "key", key)
+
+	return nil
+}
+
+func init() {
+	configBoolVar(rootCmd, cfgDryRun, false, "Only log the Vault API calls and key store writes init, unseal and configure would make, without performing them")
+}
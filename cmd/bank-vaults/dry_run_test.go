@@ -0,0 +1,75 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func TestDryRunTransport(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"auth":{"client_token":"token"},"data":{}}`)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.HttpClient.Transport = &dryRunTransport{base: config.HttpClient.Transport}
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("auth/kubernetes/login", map[string]interface{}{"role": "configurer"})
+	require.NoError(t, err)
+	_, err = client.Logical().Read("sys/mounts")
+	require.NoError(t, err)
+	_, err = client.Logical().Write("sys/mounts/secret", map[string]interface{}{"type": "kv"})
+	require.NoError(t, err)
+	_, err = client.Logical().Delete("sys/policies/acl/reader")
+	require.NoError(t, err)
+
+	_, err = client.Sys().GenerateRootInit("", "")
+	assert.ErrorContains(t, err, "a dry run needs a token")
+
+	assert.Equal(t, []string{"PUT /v1/auth/kubernetes/login", "GET /v1/sys/mounts"}, requests)
+}
+
+type recordingKVStore struct {
+	kv.Service
+	writes int
+}
+
+func (s *recordingKVStore) Set(context.Context, string, []byte) error {
+	s.writes++
+	return nil
+}
+
+func TestDryRunKVStore(t *testing.T) {
+	store := &recordingKVStore{}
+
+	require.NoError(t, (&dryRunKVStore{Service: store}).Set(context.Background(), "vault-root", []byte("token")))
+	assert.Zero(t, store.writes)
+}
@@ -0,0 +1,133 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"emperror.dev/errors"
+)
+
+// failoverTransport sends the requests to the active one of several addresses of a Vault cluster,
+// failing over to the next address when it is unreachable. The requests standby nodes redirect to an
+// unreachable active node (e.g. with a wrong api_addr) are sent to the next address as well.
+type failoverTransport struct {
+	base      http.RoundTripper
+	addresses []*url.URL
+
+	mu     sync.Mutex
+	active int
+}
+
+func newFailoverTransport(base http.RoundTripper, addresses []string) (*failoverTransport, error) {
+	t := &failoverTransport{base: base}
+	for _, address := range addresses {
+		u, err := url.Parse(address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error parsing vault address %s", address)
+		}
+		t.addresses = append(t.addresses, u)
+	}
+
+	return t, nil
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The body is sent again to the next address
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading request body")
+		}
+	}
+
+	// Redirects of standby nodes are followed as they are, unless they turn out to be unreachable
+	redirected := !slices.ContainsFunc(t.addresses, func(u *url.URL) bool { return u.Host == req.URL.Host })
+
+	attempts := len(t.addresses)
+	if redirected {
+		attempts++
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		active := t.activeAddress()
+		target := req.URL
+		if !redirected || attempt > 0 {
+			target = t.addresses[active]
+		}
+
+		attemptReq := req.Clone(req.Context())
+		attemptReq.URL.Scheme, attemptReq.URL.Host = target.Scheme, target.Host
+		attemptReq.Host = ""
+		if body != nil {
+			attemptReq.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err == nil || !isUnreachable(err) {
+			return resp, err
+		}
+		lastErr = err
+
+		// A redirect failing doesn't mean the active address is down
+		if redirected && attempt == 0 {
+			slog.Warn("vault address redirected to is unreachable, failing over", "address", target.Host, "error", err)
+			continue
+		}
+
+		next := t.failover(active)
+		slog.Warn("vault address is unreachable, failing over", "address", target.Host, "next", t.addresses[next].Host, "error", err)
+	}
+
+	return nil, lastErr
+}
+
+func (t *failoverTransport) activeAddress() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.active
+}
+
+// failover moves on from the failed address, unless another request did already.
+func (t *failoverTransport) failover(failed int) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.active == failed {
+		t.active = (failed + 1) % len(t.addresses)
+	}
+
+	return t.active
+}
+
+// isUnreachable tells whether the request failed because the address couldn't be connected to,
+// in which case it didn't reach Vault and can be sent to another address.
+func isUnreachable(err error) bool {
+	var opErr *net.OpError
+
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
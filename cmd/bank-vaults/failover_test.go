@@ -0,0 +1,52 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverClient(t *testing.T) {
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	var bodies []string
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer up.Close()
+
+	target := vaultTarget{Address: down.URL + ", " + up.URL}
+	_, err := target.newClient()
+	require.Error(t, err, "only configure fails over")
+
+	client, err := target.newFailoverClient()
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("sys/policies/acl/reader", map[string]interface{}{"policy": "path"})
+	require.NoError(t, err)
+	_, err = client.Logical().Write("sys/policies/acl/writer", map[string]interface{}{"policy": "path"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`{"policy":"path"}`, `{"policy":"path"}`}, bodies)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/fips140"
+	"slices"
+
+	"emperror.dev/errors"
+)
+
+const cfgFIPS = "fips"
+
+// fipsNonCompliantModes are the modes whose key store doesn't encrypt with FIPS-validated primitives:
+// the plaintext stores, and Alibaba Cloud KMS which isn't validated in every region.
+var fipsNonCompliantModes = []string{
+	cfgModeValueK8S,
+	cfgModeValueDev,
+	cfgModeValueFile,
+	cfgModeValueCSI,
+	cfgModeValueAlibabaKMSOSS,
+}
+
+// fipsCryptoEnabled tells whether the Go crypto runs in FIPS 140-3 mode (GODEBUG=fips140=on)
+// or the binary is built with the boringcrypto module.
+func fipsCryptoEnabled() bool {
+	return fips140.Enabled() || boringCryptoEnabled()
+}
+
+// checkFIPSCrypto fails if the crypto of the binary is not FIPS compliant.
+func checkFIPSCrypto(fipsCryptoEnabled bool) error {
+	if !fipsCryptoEnabled {
+		return errors.New("FIPS mode requires GODEBUG=fips140=on or a binary built with GOEXPERIMENT=boringcrypto")
+	}
+
+	return nil
+}
+
+// checkFIPSMode fails if the key store of the mode doesn't encrypt with FIPS-validated primitives.
+func checkFIPSMode(mode string) error {
+	if slices.Contains(fipsNonCompliantModes, mode) {
+		return errors.Errorf("mode '%s' is not allowed in FIPS mode, its key store doesn't use FIPS-validated encryption", mode)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFIPS(t *testing.T) {
+	assert.Error(t, checkFIPSCrypto(false))
+	assert.NoError(t, checkFIPSCrypto(true))
+
+	for _, mode := range []string{cfgModeValueK8S, cfgModeValueDev, cfgModeValueFile, cfgModeValueAlibabaKMSOSS} {
+		assert.Error(t, checkFIPSMode(mode), mode)
+	}
+	for _, mode := range []string{cfgModeValueAWSKMS3, cfgModeValueGoogleCloudKMSGCS, cfgModeValueAzureKeyVault, cfgModeValueOCI, cfgModeValueVault, cfgModeValueHSM, cfgModeValueHSMK8S} {
+		assert.NoError(t, checkFIPSMode(mode), mode)
+	}
+}
+
+func TestKVStoreRejectsNonCompliantModeInFIPSMode(t *testing.T) {
+	cfg := viper.New()
+	cfg.Set(cfgMode, cfgModeValueDev)
+	cfg.Set(cfgFIPS, true)
+
+	_, _, err := kvStoreForConfig(t.Context(), cfg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed in FIPS mode")
+}
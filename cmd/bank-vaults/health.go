@@ -0,0 +1,196 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgHealthAddress          = "health-address"
+	cfgHealthLoopTimeout      = "health-loop-timeout"
+	cfgMaxConsecutiveFailures = "max-consecutive-failures"
+)
+
+const (
+	// healthProbeKey is read from the key store to check that it is reachable, it doesn't need to exist
+	healthProbeKey = "bank-vaults-health-probe"
+
+	healthCheckTimeout = 5 * time.Second
+)
+
+// healthChecker serves the liveness and readiness of the process for Kubernetes probes.
+type healthChecker struct {
+	store  kv.Service
+	vaults []bankvaults.Vault
+	// how long the loop may not finish an iteration before the process counts as wedged, 0 disables the check
+	loopTimeout time.Duration
+	// the loop idles until there is work, like the configurer waiting for config changes,
+	// so only an iteration running longer than loopTimeout counts as wedged
+	idles bool
+	// the loop gives up after this many consecutive failed iterations, 0 disables the limit
+	maxConsecutiveFailures int
+
+	lastIteration       atomic.Int64
+	lastSuccess         atomic.Int64
+	busySince           atomic.Int64
+	consecutiveFailures atomic.Int64
+
+	// systemd is told the service is ready after the first iteration
+	readyOnce sync.Once
+}
+
+func newHealthChecker(store kv.Service, vaults []bankvaults.Vault, loopTimeout time.Duration, idles bool) *healthChecker {
+	h := &healthChecker{store: store, vaults: vaults, loopTimeout: loopTimeout, idles: idles}
+	h.lastIteration.Store(time.Now().UnixNano())
+
+	return h
+}
+
+// heartbeat records that a loop iteration is running and making progress.
+func (h *healthChecker) heartbeat() {
+	h.busySince.Store(time.Now().UnixNano())
+}
+
+// iterationDone records a finished loop iteration and whether it succeeded.
+func (h *healthChecker) iterationDone(err error) {
+	now := time.Now().UnixNano()
+	h.lastIteration.Store(now)
+	h.busySince.Store(0)
+	if err == nil {
+		h.lastSuccess.Store(now)
+		h.consecutiveFailures.Store(0)
+	} else {
+		h.consecutiveFailures.Add(1)
+	}
+
+	h.readyOnce.Do(func() { notifySystemd("READY=1") })
+}
+
+// failedTooOften reports whether the loop failed --max-consecutive-failures times in a row, so the
+// process should exit and let Kubernetes restart it instead of retrying a poisoned state forever.
+func (h *healthChecker) failedTooOften() bool {
+	return h.maxConsecutiveFailures > 0 && h.consecutiveFailures.Load() >= int64(h.maxConsecutiveFailures)
+}
+
+// live reports whether the loop is still making progress.
+func (h *healthChecker) live() error {
+	if h.loopTimeout <= 0 {
+		return nil
+	}
+
+	if h.idles {
+		if busySince := h.busySince.Load(); busySince != 0 {
+			if since := time.Since(time.Unix(0, busySince)); since > h.loopTimeout {
+				return errors.Errorf("loop iteration running for %s", since.Round(time.Second))
+			}
+		}
+
+		return nil
+	}
+
+	if since := time.Since(time.Unix(0, h.lastIteration.Load())); since > h.loopTimeout {
+		return errors.Errorf("no loop iteration finished for %s", since.Round(time.Second))
+	}
+
+	return nil
+}
+
+// ready reports whether the key store and Vault are reachable and the last iteration succeeded recently enough.
+func (h *healthChecker) ready(ctx context.Context) []string {
+	var failures []string
+
+	if _, err := h.store.Get(ctx, healthProbeKey); err != nil && !kv.IsNotFoundError(err) {
+		failures = append(failures, fmt.Sprintf("kv: %s", err))
+	}
+
+	for _, v := range h.vaults {
+		if _, err := v.Sealed(); err != nil {
+			failures = append(failures, fmt.Sprintf("vault: %s", err))
+		}
+	}
+
+	lastSuccess := h.lastSuccess.Load()
+	switch {
+	case lastSuccess == 0:
+		failures = append(failures, "loop: no successful iteration yet")
+	case h.loopTimeout > 0 && !h.idles && time.Since(time.Unix(0, lastSuccess)) > h.loopTimeout:
+		failures = append(failures, fmt.Sprintf("loop: last successful iteration at %s", time.Unix(0, lastSuccess).UTC().Format(time.RFC3339)))
+	}
+
+	return failures
+}
+
+func (h *healthChecker) handleLive(w http.ResponseWriter, _ *http.Request) {
+	if err := h.live(); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	fmt.Fprintln(w, "ok")
+}
+
+func (h *healthChecker) handleReady(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), healthCheckTimeout)
+	defer cancel()
+
+	if failures := h.ready(ctx); len(failures) > 0 {
+		http.Error(w, strings.Join(failures, "\n"), http.StatusServiceUnavailable)
+		return
+	}
+
+	fmt.Fprintln(w, "ok")
+}
+
+// serve starts the /healthz and /readyz endpoints in the background, if an address is configured,
+// and the pings of the systemd watchdog, if enabled.
+func (h *healthChecker) serve(ctx context.Context, cfg *viper.Viper) {
+	go h.watchSystemd(ctx)
+
+	address := cfg.GetString(cfgHealthAddress)
+	if address == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.handleLive)
+	mux.HandleFunc("/readyz", h.handleReady)
+
+	slog.Info(fmt.Sprintf("health endpoints enabled: %s/healthz, %s/readyz", address, address))
+	go func() {
+		if err := http.ListenAndServe(address, mux); err != nil { //nolint:gosec
+			slog.Error(fmt.Sprintf("error serving health endpoints: %s", err.Error()))
+		}
+	}()
+}
+
+func init() {
+	configStringVar(rootCmd, cfgHealthAddress, "", "Address to serve the /healthz and /readyz endpoints on, disabled if empty")
+	configIntVar(rootCmd, cfgMaxConsecutiveFailures, 0, "Exit with an error after this many consecutive failed unseal or configure iterations, 0 retries forever")
+	configDurationVar(rootCmd, cfgHealthLoopTimeout, 5*time.Minute, "How long the unseal loop may not finish an iteration, or a configure run may take, before /healthz fails, 0 disables the check")
+}
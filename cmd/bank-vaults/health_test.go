@@ -0,0 +1,88 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+// emptyStore is a key store without any keys.
+type emptyStore struct{}
+
+func (emptyStore) Get(_ context.Context, key string) ([]byte, error) {
+	return nil, kv.NewNotFoundError("key not found: %s", key)
+}
+
+func (emptyStore) Set(context.Context, string, []byte) error {
+	return nil
+}
+
+func TestHealthCheckerLoop(t *testing.T) {
+	h := newHealthChecker(emptyStore{}, nil, 20*time.Millisecond, false)
+	assert.NoError(t, h.live())
+
+	time.Sleep(40 * time.Millisecond)
+	assert.Error(t, h.live(), "no iteration finished within the loop timeout")
+
+	h.iterationDone(nil)
+	assert.NoError(t, h.live())
+}
+
+func TestHealthCheckerIdlingLoop(t *testing.T) {
+	h := newHealthChecker(emptyStore{}, nil, 20*time.Millisecond, true)
+
+	time.Sleep(40 * time.Millisecond)
+	assert.NoError(t, h.live(), "idling is not wedged")
+
+	h.heartbeat()
+	time.Sleep(40 * time.Millisecond)
+	assert.Error(t, h.live(), "an iteration running longer than the loop timeout is wedged")
+
+	h.iterationDone(nil)
+	assert.NoError(t, h.live())
+}
+
+func TestHealthCheckerConsecutiveFailures(t *testing.T) {
+	h := newHealthChecker(emptyStore{}, nil, 0, false)
+	h.iterationDone(errors.New("sealed"))
+	assert.False(t, h.failedTooOften(), "no limit by default")
+
+	h.maxConsecutiveFailures = 2
+	h.iterationDone(nil)
+	h.iterationDone(errors.New("sealed"))
+	assert.False(t, h.failedTooOften())
+
+	h.iterationDone(errors.New("sealed"))
+	assert.True(t, h.failedTooOften())
+
+	h.iterationDone(nil)
+	assert.False(t, h.failedTooOften(), "a success resets the failures")
+}
+
+func TestHealthCheckerReady(t *testing.T) {
+	h := newHealthChecker(emptyStore{}, []bankvaults.Vault{fakeVault{}}, time.Minute, true)
+	assert.Equal(t, []string{"loop: no successful iteration yet"}, h.ready(context.Background()))
+
+	h.iterationDone(nil)
+	assert.Empty(t, h.ready(context.Background()))
+}
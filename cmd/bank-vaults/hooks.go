@@ -0,0 +1,72 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/viper"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgPreApplyHook  = "pre-apply-hook"
+	cfgPostApplyHook = "post-apply-hook"
+	cfgPurgeHook     = "purge-hook"
+	cfgHookHeaders   = "hook-headers"
+	cfgHookTimeout   = "hook-timeout"
+)
+
+// hooksForConfig returns the hooks configured by the hook flags. A dry run makes no changes
+// to announce, so it runs no hooks.
+func hooksForConfig(cfg *viper.Viper) bankvaults.Hooks {
+	if cfg.GetBool(cfgDryRun) {
+		return bankvaults.Hooks{}
+	}
+
+	headers := cfg.GetStringMapString(cfgHookHeaders)
+
+	return bankvaults.Hooks{
+		PreApply:  hooksForSpecs(cfg.GetStringSlice(cfgPreApplyHook), headers),
+		PostApply: hooksForSpecs(cfg.GetStringSlice(cfgPostApplyHook), headers),
+		Purge:     hooksForSpecs(cfg.GetStringSlice(cfgPurgeHook), headers),
+		Timeout:   cfg.GetDuration(cfgHookTimeout),
+	}
+}
+
+// hooksForSpecs returns a hook for each spec: HTTP(S) URLs are posted to, anything else is a command
+// run with its space separated arguments.
+func hooksForSpecs(specs []string, headers map[string]string) []bankvaults.Hook {
+	var hooks []bankvaults.Hook
+	for _, spec := range specs {
+		switch {
+		case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+			hooks = append(hooks, bankvaults.NewHTTPHook(spec, headers))
+		case strings.TrimSpace(spec) != "":
+			hooks = append(hooks, bankvaults.NewExecHook(strings.Fields(spec)...))
+		}
+	}
+
+	return hooks
+}
+
+func init() {
+	configStringSliceVar(configureCmd, cfgPreApplyHook, nil, "Commands or HTTP(S) URLs run before each configure run with the JSON event on stdin or as the request body, a failing one aborts the run")
+	configStringSliceVar(configureCmd, cfgPostApplyHook, nil, "Commands or HTTP(S) URLs run after each configure run with the JSON event holding the apply report")
+	configStringSliceVar(configureCmd, cfgPurgeHook, nil, "Commands or HTTP(S) URLs run after each unmanaged resource is purged, with the JSON event holding its section and path")
+	configStringMapVar(configureCmd, cfgHookHeaders, map[string]string{}, "Additional HTTP headers to send with the requests of the HTTP hooks")
+	configDurationVar(configureCmd, cfgHookTimeout, bankvaults.DefaultHookTimeout, "Time limit of each hook")
+}
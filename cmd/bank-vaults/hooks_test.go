@@ -0,0 +1,58 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+func TestHooksForConfig(t *testing.T) {
+	var posted int
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ops", r.Header.Get("X-Team"))
+		posted++
+	}))
+	defer srv.Close()
+
+	cfg := viper.New()
+	cfg.Set(cfgPreApplyHook, []string{"sh -c true", " "})
+	cfg.Set(cfgPostApplyHook, []string{srv.URL})
+	cfg.Set(cfgHookHeaders, map[string]string{"X-Team": "ops"})
+	cfg.Set(cfgHookTimeout, time.Second)
+
+	hooks := hooksForConfig(cfg)
+	require.Len(t, hooks.PreApply, 1, "blank specs are ignored")
+	require.Len(t, hooks.PostApply, 1)
+	assert.Empty(t, hooks.Purge)
+	assert.Equal(t, time.Second, hooks.Timeout)
+
+	ctx := context.Background()
+	require.NoError(t, hooks.PreApply[0].Run(ctx, bankvaults.HookEvent{Stage: bankvaults.HookStagePreApply}))
+	require.NoError(t, hooks.PostApply[0].Run(ctx, bankvaults.HookEvent{Stage: bankvaults.HookStagePostApply}))
+	assert.Equal(t, 1, posted)
+
+	cfg.Set(cfgDryRun, true)
+	assert.Equal(t, bankvaults.Hooks{}, hooksForConfig(cfg), "a dry run runs no hooks")
+}
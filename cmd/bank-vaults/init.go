@@ -20,15 +20,16 @@ import (
 	"log/slog"
 	"os"
 
-	"github.com/bank-vaults/vault-sdk/vault"
 	"github.com/spf13/cobra"
 
-	internalVault "github.com/bank-vaults/bank-vaults/internal/vault"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
 )
 
 const (
 	cfgInitRootToken   = "init-root-token"
 	cfgStoreRootToken  = "store-root-token"
+	cfgRevokeRootToken = "revoke-root-token"
+	cfgConfigurerToken = "configurer-token"
 	cfgPreFlightChecks = "pre-flight-checks"
 )
 
@@ -43,35 +44,41 @@ It will not unseal the Vault instance after initializing.`,
 	Run: func(cmd *cobra.Command, _ []string) {
 		ctx, cancel := context.WithCancel(cmd.Context())
 		defer cancel()
-		store, err := kvStoreForConfig(ctx, c)
+		store, _, err := kvStoreForConfig(ctx, c)
 		if err != nil {
 			slog.Error(fmt.Sprintf("error creating kv store: %s", err.Error()))
 			os.Exit(1)
 		}
 
-		cl, err := vault.NewRawClient()
+		cl, err := targetForConfig(c).newClient()
 		if err != nil {
 			slog.Error(fmt.Sprintf("error connecting to vault: %s", err.Error()))
 			os.Exit(1)
 		}
 
-		v, err := internalVault.New(ctx, store, cl, vaultConfigForConfig(c))
+		v, err := bankvaults.New(ctx, store, cl, vaultConfigForConfig(c))
 		if err != nil {
 			slog.Error(fmt.Sprintf("error creating vault helper: %s", err.Error()))
 			os.Exit(1)
 		}
+		defer v.Close()
 
-		if err = v.Init(ctx); err != nil {
+		if err = initVault(ctx, v); err != nil {
 			slog.Error(fmt.Sprintf("error initializing vault: %s", err.Error()))
 			os.Exit(1)
 		}
+
+		// Nothing configures with the root token of init, with auto-unseal it is revoked right away
+		v.RevokeInitRootToken(ctx)
 	},
 }
 
 func init() {
 	configStringVar(initCmd, cfgInitRootToken, "", "root token for the new vault cluster")
 	configBoolVar(rootCmd, cfgStoreRootToken, true, "should the root token be stored in the key store")
-	configBoolVar(rootCmd, cfgPreFlightChecks, true, "should the key store be tested first to validate access rights")
+	configBoolVar(rootCmd, cfgRevokeRootToken, false, "Never store the root token: the one of init is revoked after its first use, and root tokens generated from the unseal keys after each use (overrides --"+cfgStoreRootToken+")")
+	configBoolVar(rootCmd, cfgConfigurerToken, false, "Mint a token scoped to the config at init and configure with it instead of the root token, it is minted again when the config needs more")
+	configBoolVar(rootCmd, cfgPreFlightChecks, true, "should the key store be tested first to validate access rights and that it decrypts what it encrypts")
 
 	rootCmd.AddCommand(initCmd)
 }
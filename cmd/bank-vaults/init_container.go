@@ -0,0 +1,211 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/spf13/cobra"
+
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgInitContainerCompletionFile = "init-container-completion-file"
+	cfgInitContainerUnseal         = "init-container-unseal"
+	cfgInitContainerConfigFile     = "init-container-config-file"
+	cfgInitContainerTokenFile      = "init-container-token-file"
+	cfgInitContainerTokenPolicies  = "init-container-token-policies"
+	cfgInitContainerTokenTTL       = "init-container-token-ttl"
+)
+
+type initContainerCfg struct {
+	completionFile string
+	unseal         bool
+	configFiles    []string
+	tokenFile      string
+	tokenPolicies  []string
+	tokenTTL       time.Duration
+}
+
+var initContainerCmd = &cobra.Command{
+	Use:   "init-container",
+	Short: "Initializes Vault once from an initContainer",
+	Long: `This command initializes the target Vault instance, optionally unseals and configures it,
+writes a token with the given policies and a completion file to a shared volume, then exits.
+
+The main containers can wait for the completion file and use the written token, without
+running bank-vaults themselves. Nothing is done if the completion file already exists.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		ctx, cancel := context.WithCancel(cmd.Context())
+		defer cancel()
+
+		initContainerConfig := initContainerCfg{
+			completionFile: c.GetString(cfgInitContainerCompletionFile),
+			unseal:         c.GetBool(cfgInitContainerUnseal),
+			configFiles:    c.GetStringSlice(cfgInitContainerConfigFile),
+			tokenFile:      c.GetString(cfgInitContainerTokenFile),
+			tokenPolicies:  c.GetStringSlice(cfgInitContainerTokenPolicies),
+			tokenTTL:       c.GetDuration(cfgInitContainerTokenTTL),
+		}
+		if initContainerConfig.completionFile == "" {
+			slog.Error(fmt.Sprintf("--%s must be set", cfgInitContainerCompletionFile))
+			os.Exit(1)
+		}
+		if initContainerConfig.tokenFile != "" && len(initContainerConfig.tokenPolicies) == 0 {
+			slog.Error(fmt.Sprintf("--%s must be set to write a token", cfgInitContainerTokenPolicies))
+			os.Exit(1)
+		}
+
+		store, _, err := kvStoreForConfig(ctx, c)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error creating kv store: %s", err.Error()))
+			os.Exit(1)
+		}
+
+		cl, err := targetForConfig(c).newClient()
+		if err != nil {
+			slog.Error(fmt.Sprintf("error connecting to vault: %s", err.Error()))
+			os.Exit(1)
+		}
+
+		v, err := bankvaults.New(ctx, store, cl, vaultConfigForConfig(c))
+		if err != nil {
+			slog.Error(fmt.Sprintf("error creating vault helper: %s", err.Error()))
+			os.Exit(1)
+		}
+		defer v.Close()
+
+		parser, err := multiparser.New(parser.JSON, parser.YAML)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error file parsers: %v", err))
+			os.Exit(1)
+		}
+
+		if err := runInitContainer(ctx, initContainerConfig, v, parser); err != nil {
+			slog.Error(fmt.Sprintf("error running init container: %s", err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+// runInitContainer initializes, unseals and configures Vault as configured, then writes the token and the completion file.
+func runInitContainer(ctx context.Context, initContainerConfig initContainerCfg, v bankvaults.Vault, parser multiparser.Parser) error {
+	if _, err := os.Stat(initContainerConfig.completionFile); err == nil {
+		slog.Info("completion file exists, nothing to do", "file", initContainerConfig.completionFile)
+		return nil
+	}
+
+	slog.Info("initializing vault...")
+	if err := initVault(ctx, v); err != nil {
+		return errors.Wrap(err, "error initializing vault")
+	}
+
+	if initContainerConfig.unseal {
+		sealed, err := v.Sealed()
+		if err != nil {
+			return errors.Wrap(err, "error checking if vault is sealed")
+		}
+
+		if sealed && !skipDryRun("unseal vault") {
+			slog.Info("unsealing vault...")
+			if err := v.Unseal(ctx); err != nil {
+				return errors.Wrap(err, "error unsealing vault")
+			}
+			notifyLifecycle(ctx, notify.EventUnsealed, "unsealed vault")
+		}
+	}
+
+	for _, configFile := range initContainerConfig.configFiles {
+		config, err := readConfiguration(parser, configFile)
+		if err != nil {
+			return err
+		}
+
+		slog.Info("applying config file", "file", config.Path)
+		if err := v.Configure(ctx, config.Data); err != nil {
+			return errors.Wrapf(err, "error configuring vault with %s", config.Path)
+		}
+	}
+
+	if initContainerConfig.tokenFile != "" && !skipDryRun("write token", "file", initContainerConfig.tokenFile) {
+		token, err := v.CreateToken(ctx, initContainerConfig.tokenPolicies, initContainerConfig.tokenTTL)
+		if err != nil {
+			return err
+		}
+
+		if err := writeFileAtomic(initContainerConfig.tokenFile, []byte(token), 0o600); err != nil {
+			return errors.Wrap(err, "error writing token file")
+		}
+		slog.Info("wrote token", "file", initContainerConfig.tokenFile, "policies", initContainerConfig.tokenPolicies)
+	}
+
+	// The first configure used the root token of init, it is revoked here if there was none
+	v.RevokeInitRootToken(ctx)
+
+	if skipDryRun("write completion file", "file", initContainerConfig.completionFile) {
+		return nil
+	}
+
+	if err := writeFileAtomic(initContainerConfig.completionFile, []byte(time.Now().UTC().Format(time.RFC3339)+"\n"), 0o644); err != nil {
+		return errors.Wrap(err, "error writing completion file")
+	}
+	slog.Info("wrote completion file", "file", initContainerConfig.completionFile)
+
+	return nil
+}
+
+// writeFileAtomic writes a file through a temporary file renamed in place,
+// so the containers waiting for it never read it half-written.
+func writeFileAtomic(name string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+func init() {
+	configStringVar(initContainerCmd, cfgInitContainerCompletionFile, "", "File written once Vault is initialized, nothing is done if it already exists")
+	configBoolVar(initContainerCmd, cfgInitContainerUnseal, false, "Unseal Vault after initializing it")
+	configStringSliceVar(initContainerCmd, cfgInitContainerConfigFile, nil, "Config files to apply after initializing (and unsealing) Vault")
+	configStringVar(initContainerCmd, cfgInitContainerTokenFile, "", "File to write a token with the --"+cfgInitContainerTokenPolicies+" policies to, instead of handing out the root token")
+	configStringSliceVar(initContainerCmd, cfgInitContainerTokenPolicies, nil, "Policies of the written token")
+	configDurationVar(initContainerCmd, cfgInitContainerTokenTTL, 0, "TTL of the written token, it doesn't expire if 0")
+
+	rootCmd.AddCommand(initContainerCmd)
+}
@@ -0,0 +1,103 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+// initContainerVault records the calls of the init container, the other methods aren't used.
+type initContainerVault struct {
+	bankvaults.Vault
+
+	calls    []string
+	policies []string
+}
+
+func (v *initContainerVault) Init(context.Context) error {
+	v.calls = append(v.calls, "init")
+	return nil
+}
+
+func (v *initContainerVault) Initialized() (bool, error) {
+	return false, nil
+}
+
+func (v *initContainerVault) Sealed() (bool, error) {
+	return true, nil
+}
+
+func (v *initContainerVault) Unseal(context.Context) error {
+	v.calls = append(v.calls, "unseal")
+	return nil
+}
+
+func (v *initContainerVault) Configure(context.Context, map[string]interface{}) error {
+	v.calls = append(v.calls, "configure")
+	return nil
+}
+
+func (v *initContainerVault) CreateToken(_ context.Context, policies []string, _ time.Duration) (string, error) {
+	v.calls = append(v.calls, "token")
+	v.policies = policies
+	return "app-token", nil
+}
+
+func (v *initContainerVault) RevokeInitRootToken(context.Context) {
+	v.calls = append(v.calls, "revoke-init-root-token")
+}
+
+func TestRunInitContainer(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "vault-config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte("policies: []\n"), 0o600))
+
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	config := initContainerCfg{
+		completionFile: filepath.Join(dir, "initialized"),
+		unseal:         true,
+		configFiles:    []string{configFile},
+		tokenFile:      filepath.Join(dir, "token"),
+		tokenPolicies:  []string{"app"},
+	}
+
+	v := &initContainerVault{}
+	require.NoError(t, runInitContainer(context.Background(), config, v, parser))
+	assert.Equal(t, []string{"init", "unseal", "configure", "token", "revoke-init-root-token"}, v.calls)
+	assert.Equal(t, []string{"app"}, v.policies)
+
+	token, err := os.ReadFile(config.tokenFile)
+	require.NoError(t, err)
+	assert.Equal(t, "app-token", string(token))
+	assert.FileExists(t, config.completionFile)
+
+	// Restarted init containers find the completion file
+	v = &initContainerVault{}
+	require.NoError(t, runInitContainer(context.Background(), config, v, parser))
+	assert.Empty(t, v.calls)
+}
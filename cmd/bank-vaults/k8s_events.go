@@ -0,0 +1,176 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+)
+
+const (
+	cfgKubernetesEvents       = "kubernetes-events"
+	cfgKubernetesEventsObject = "kubernetes-events-object"
+)
+
+// k8sEventReasons are the reasons of the Kubernetes Events by notification type.
+var k8sEventReasons = map[notify.EventType]string{
+	notify.EventConfigureFailed:    "ConfigureFailed",
+	notify.EventPurged:             "Purged",
+	notify.EventCredentialsRotated: "CredentialsRotated",
+	notify.EventInitialized:        "Initialized",
+	notify.EventUnsealed:           "Unsealed",
+}
+
+// k8sEventNotifier records notifications as Kubernetes Events of the pod bank-vaults runs in
+// (or another object in its namespace), so `kubectl describe` shows what bank-vaults did.
+type k8sEventNotifier struct {
+	namespace string
+	// kind and name of the object the events are recorded on
+	kind string
+	name string
+	host string
+
+	mu     sync.Mutex
+	client kubernetes.Interface
+	object corev1.ObjectReference
+}
+
+// newK8sEventNotifier returns a notifier recording Kubernetes Events on the object given as
+// "kind/name" (Pod, StatefulSet or Deployment), or on the pod bank-vaults runs in if empty.
+func newK8sEventNotifier(object string) (*k8sEventNotifier, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting hostname")
+	}
+
+	n := &k8sEventNotifier{namespace: podNamespace(), kind: "Pod", name: os.Getenv("POD_NAME"), host: host}
+	if n.name == "" {
+		n.name = host
+	}
+
+	if object != "" {
+		kind, name, ok := strings.Cut(object, "/")
+		if !ok || name == "" {
+			return nil, errors.Errorf("invalid --%s %q, expected kind/name", cfgKubernetesEventsObject, object)
+		}
+		n.kind, n.name = kind, name
+	}
+
+	if _, ok := k8sEventObjectAPIVersions[n.kind]; !ok {
+		return nil, errors.Errorf("unsupported --%s kind %q", cfgKubernetesEventsObject, n.kind)
+	}
+
+	return n, nil
+}
+
+// k8sEventObjectAPIVersions are the kinds events can be recorded on.
+var k8sEventObjectAPIVersions = map[string]string{
+	"Pod":         "v1",
+	"StatefulSet": "apps/v1",
+	"Deployment":  "apps/v1",
+}
+
+// involvedObject looks up the object the events are recorded on, its UID is needed for
+// `kubectl describe` to list the events. The client is only created on the first event.
+func (n *k8sEventNotifier) involvedObject(ctx context.Context) (corev1.ObjectReference, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.object.UID != "" {
+		return n.object, nil
+	}
+
+	if n.client == nil {
+		client, err := newK8sClient()
+		if err != nil {
+			return corev1.ObjectReference{}, err
+		}
+		n.client = client
+	}
+
+	var meta metav1.Object
+	var err error
+	switch n.kind {
+	case "Pod":
+		meta, err = n.client.CoreV1().Pods(n.namespace).Get(ctx, n.name, metav1.GetOptions{})
+	case "StatefulSet":
+		meta, err = n.client.AppsV1().StatefulSets(n.namespace).Get(ctx, n.name, metav1.GetOptions{})
+	case "Deployment":
+		meta, err = n.client.AppsV1().Deployments(n.namespace).Get(ctx, n.name, metav1.GetOptions{})
+	}
+	if err != nil {
+		return corev1.ObjectReference{}, errors.Wrapf(err, "error getting %s %s/%s", n.kind, n.namespace, n.name)
+	}
+
+	n.object = corev1.ObjectReference{
+		APIVersion: k8sEventObjectAPIVersions[n.kind],
+		Kind:       n.kind,
+		Namespace:  n.namespace,
+		Name:       n.name,
+		UID:        meta.GetUID(),
+	}
+
+	return n.object, nil
+}
+
+func (n *k8sEventNotifier) Notify(ctx context.Context, event notify.Event) error {
+	object, err := n.involvedObject(ctx)
+	if err != nil {
+		return err
+	}
+
+	eventType := corev1.EventTypeNormal
+	if event.Type == notify.EventConfigureFailed {
+		eventType = corev1.EventTypeWarning
+	}
+
+	message := event.Message
+	if event.Path != "" {
+		message = fmt.Sprintf("%s: %s", message, event.Path)
+	}
+
+	timestamp := metav1.NewTime(event.Time)
+	_, err = n.client.CoreV1().Events(n.namespace).Create(ctx, &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			// The same naming as the event recorder of client-go
+			Name:      fmt.Sprintf("%s.%x", object.Name, time.Now().UnixNano()),
+			Namespace: n.namespace,
+		},
+		InvolvedObject: object,
+		Reason:         k8sEventReasons[event.Type],
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: "bank-vaults", Host: n.host},
+		FirstTimestamp: timestamp,
+		LastTimestamp:  timestamp,
+		Count:          1,
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "error creating kubernetes event")
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+)
+
+func TestK8sEventNotifier(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "vault")
+
+	n, err := newK8sEventNotifier("StatefulSet/vault")
+	require.NoError(t, err)
+	n.client = fake.NewSimpleClientset(&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "vault", UID: "uid-1"}})
+
+	ctx := context.Background()
+	require.NoError(t, n.Notify(ctx, notify.Event{Type: notify.EventPurged, Message: "removed unmanaged auth resource", Path: "sys/auth/github", Time: time.Now()}))
+	require.NoError(t, n.Notify(ctx, notify.Event{Type: notify.EventConfigureFailed, Message: "permission denied", Time: time.Now()}))
+
+	events, err := n.client.CoreV1().Events("vault").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, events.Items, 2)
+
+	reasons := map[string]corev1.Event{}
+	for _, event := range events.Items {
+		reasons[event.Reason] = event
+	}
+
+	purged := reasons["Purged"]
+	assert.Equal(t, "removed unmanaged auth resource: sys/auth/github", purged.Message)
+	assert.Equal(t, corev1.EventTypeNormal, purged.Type)
+	assert.Equal(t, "StatefulSet", purged.InvolvedObject.Kind)
+	assert.Equal(t, "uid-1", string(purged.InvolvedObject.UID))
+	assert.Equal(t, corev1.EventTypeWarning, reasons["ConfigureFailed"].Type)
+}
+
+func TestNewK8sEventNotifierObject(t *testing.T) {
+	t.Setenv("POD_NAME", "vault-0")
+
+	n, err := newK8sEventNotifier("")
+	require.NoError(t, err)
+	assert.Equal(t, "Pod", n.kind)
+	assert.Equal(t, "vault-0", n.name)
+
+	_, err = newK8sEventNotifier("vault")
+	assert.Error(t, err)
+	_, err = newK8sEventNotifier("ConfigMap/vault")
+	assert.Error(t, err)
+}
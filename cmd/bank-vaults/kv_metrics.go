@@ -0,0 +1,79 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+var (
+	kvOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNS,
+		Subsystem: "kv",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of the key store operations",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"backend", "operation"})
+	kvOperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNS,
+		Subsystem: "kv",
+		Name:      "operation_errors_total",
+		Help:      "Number of failed key store operations, missing keys are not counted",
+	}, []string{"backend", "operation"})
+)
+
+// instrumentedKVStore records the latency and errors of the operations of a key store.
+type instrumentedKVStore struct {
+	kv.Service
+
+	backend string
+}
+
+func newInstrumentedKVStore(backend string, store kv.Service) kv.Service {
+	return &instrumentedKVStore{Service: store, backend: backend}
+}
+
+func (s *instrumentedKVStore) Set(ctx context.Context, key string, value []byte) error {
+	defer s.observe("set", time.Now())
+
+	err := s.Service.Set(ctx, key, value)
+	s.countError("set", err)
+
+	return err //nolint:wrapcheck
+}
+
+func (s *instrumentedKVStore) Get(ctx context.Context, key string) ([]byte, error) {
+	defer s.observe("get", time.Now())
+
+	value, err := s.Service.Get(ctx, key)
+	s.countError("get", err)
+
+	return value, err //nolint:wrapcheck
+}
+
+func (s *instrumentedKVStore) observe(operation string, start time.Time) {
+	kvOperationDuration.WithLabelValues(s.backend, operation).Observe(time.Since(start).Seconds())
+}
+
+func (s *instrumentedKVStore) countError(operation string, err error) {
+	if err != nil && !kv.IsNotFoundError(err) {
+		kvOperationErrors.WithLabelValues(s.backend, operation).Inc()
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+const (
+	cfgKVProxy   = "kv-proxy"
+	cfgKVNoProxy = "kv-no-proxy"
+)
+
+// kvProxyForConfig returns the proxy the storage and KMS clients of the cloud kv stores connect through,
+// nil if they use the one of the environment.
+func kvProxyForConfig(cfg *viper.Viper) (*kv.Proxy, error) {
+	proxy := kv.Proxy{
+		URL:     cfg.GetString(cfgKVProxy),
+		NoProxy: cfg.GetStringSlice(cfgKVNoProxy),
+	}
+	if proxy.URL == "" && len(proxy.NoProxy) == 0 {
+		return nil, nil
+	}
+
+	if err := proxy.Validate(); err != nil {
+		return nil, errors.Wrap(err, "error configuring kv store proxy")
+	}
+
+	return &proxy, nil
+}
+
+func init() {
+	configStringVar(rootCmd, cfgKVProxy, "", "HTTP(S) or SOCKS5 proxy URL the storage and KMS clients of the cloud kv stores connect through, defaults to HTTPS_PROXY")
+	configStringSliceVar(rootCmd, cfgKVNoProxy, nil, "Hosts, domains and CIDRs the storage and KMS clients of the cloud kv stores connect to without the proxy, defaults to NO_PROXY")
+}
@@ -0,0 +1,42 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func TestKVProxyForConfig(t *testing.T) {
+	cfg := viper.New()
+	proxy, err := kvProxyForConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, proxy, "the proxy of the environment is used")
+
+	cfg.Set(cfgKVProxy, "http://egress-kms:3128")
+	cfg.Set(cfgKVNoProxy, []string{"storage.googleapis.com"})
+	proxy, err = kvProxyForConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, &kv.Proxy{URL: "http://egress-kms:3128", NoProxy: []string{"storage.googleapis.com"}}, proxy)
+
+	cfg.Set(cfgKVProxy, "egress-kms:3128")
+	_, err = kvProxyForConfig(cfg)
+	assert.ErrorContains(t, err, "error configuring kv store proxy")
+}
@@ -0,0 +1,161 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/viper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	cfgLeaderElection          = "leader-election"
+	cfgLeaderElectionNamespace = "leader-election-namespace"
+	cfgLeaderElectionLeaseName = "leader-election-lease-name"
+	cfgLeaderElectionIdentity  = "leader-election-identity"
+
+	cfgLeaderElectionLeaseDuration = "leader-election-lease-duration"
+	cfgLeaderElectionRenewDeadline = "leader-election-renew-deadline"
+	cfgLeaderElectionRetryPeriod   = "leader-election-retry-period"
+)
+
+// Default names of the leader election Lease, the unsealers and the configurers elect their leaders apart.
+const (
+	defaultUnsealLeaseName    = "bank-vaults-unsealer"
+	defaultConfigureLeaseName = "bank-vaults-configurer"
+)
+
+var leaderElectionLeader = prometheus.NewGauge(prometheus.GaugeOpts{
+	Namespace: prometheusNS,
+	Subsystem: "leader_election",
+	Name:      "leader",
+	Help:      "Whether this replica holds the leader election Lease and is the active one (1) or is standing by (0)",
+})
+
+// runWithLeaderElection runs fn only while holding a Kubernetes Lease, so that only one of multiple
+// unsealer or configurer replicas is active at a time. The Lease is named defaultLeaseName unless set.
+// When the lease is lost, the context of fn is canceled and an error is returned once fn returned,
+// so the caller can shut down cleanly. The error of fn is returned after the Lease was released.
+func runWithLeaderElection(ctx context.Context, cfg *viper.Viper, defaultLeaseName string, fn func(ctx context.Context) error) error {
+	client, err := newK8sClient()
+	if err != nil {
+		return err
+	}
+
+	identity := cfg.GetString(cfgLeaderElectionIdentity)
+	if identity == "" {
+		if identity, err = os.Hostname(); err != nil {
+			return errors.Wrap(err, "error getting hostname for leader election identity")
+		}
+	}
+
+	namespace := cfg.GetString(cfgLeaderElectionNamespace)
+	if namespace == "" {
+		namespace = podNamespace()
+	}
+
+	leaseName := cfg.GetString(cfgLeaderElectionLeaseName)
+	if leaseName == "" {
+		leaseName = defaultLeaseName
+	}
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      leaseName,
+			Namespace: namespace,
+		},
+		Client:     client.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var started, finished, lost atomic.Bool
+	var fnErr error
+	done := make(chan struct{})
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		ReleaseOnCancel: true,
+		// The Lease is released on shutdown, a standby takes over within a retry period then
+		LeaseDuration: cfg.GetDuration(cfgLeaderElectionLeaseDuration),
+		RenewDeadline: cfg.GetDuration(cfgLeaderElectionRenewDeadline),
+		RetryPeriod:   cfg.GetDuration(cfgLeaderElectionRetryPeriod),
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				defer recoverScrubbed()
+				defer close(done)
+				started.Store(true)
+				leaderElectionLeader.Set(1)
+				slog.Info(fmt.Sprintf("acquired leader lease %s as %s, starting...", leaseName, identity))
+				fnErr = fn(ctx)
+				finished.Store(true)
+				cancel()
+			},
+			OnStoppedLeading: func() {
+				leaderElectionLeader.Set(0)
+				if !started.Load() || finished.Load() {
+					return
+				}
+				// Stop instead of continuing without the lease, so another replica takes over cleanly
+				slog.Error(fmt.Sprintf("lost leader lease as %s, stopping...", identity))
+				lost.Store(true)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					slog.Info(fmt.Sprintf("%s is the leader, waiting for the lease...", leader))
+				}
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "error creating leader elector")
+	}
+
+	elector.Run(ctx)
+
+	if lost.Load() {
+		// The context of fn is canceled, wait for it to write the report of the interrupted run
+		<-done
+
+		return errors.Errorf("lost leader lease as %s", identity)
+	}
+
+	if started.Load() {
+		<-done
+	}
+
+	return fnErr
+}
+
+func init() {
+	configBoolVar(rootCmd, cfgLeaderElection, false, "Use a Kubernetes Lease so only one of multiple unsealer or configurer replicas is active at a time")
+	configStringVar(rootCmd, cfgLeaderElectionNamespace, "", "Namespace of the leader election Lease, defaults to POD_NAMESPACE or the namespace of the pod")
+	configStringVar(rootCmd, cfgLeaderElectionLeaseName, "", fmt.Sprintf("Name of the leader election Lease, defaults to '%s' for unseal and '%s' for configure", defaultUnsealLeaseName, defaultConfigureLeaseName))
+	configStringVar(rootCmd, cfgLeaderElectionIdentity, "", "Identity of this replica in the leader election, defaults to the hostname")
+	configDurationVar(rootCmd, cfgLeaderElectionLeaseDuration, 15*time.Second, "How long a standby replica waits before taking over a Lease which wasn't renewed")
+	configDurationVar(rootCmd, cfgLeaderElectionRenewDeadline, 10*time.Second, "How long the active replica retries renewing the Lease before it stops")
+	configDurationVar(rootCmd, cfgLeaderElectionRetryPeriod, 2*time.Second, "How often the replicas try to acquire or renew the Lease")
+}
@@ -0,0 +1,49 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	cfgLicense      = "license"
+	cfgLicenseFile  = "license-file"
+	cfgLicenseKVKey = "license-kv-key"
+)
+
+// licenseForConfig returns the Vault Enterprise license text configured inline or in a file.
+func licenseForConfig(cfg *viper.Viper) (string, error) {
+	licenseFile := cfg.GetString(cfgLicenseFile)
+	if licenseFile == "" {
+		return cfg.GetString(cfgLicense), nil
+	}
+
+	license, err := os.ReadFile(licenseFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading license file %s", licenseFile)
+	}
+
+	return string(license), nil
+}
+
+func init() {
+	configStringVar(configureCmd, cfgLicense, "", "Vault Enterprise license to apply during configure")
+	configStringVar(configureCmd, cfgLicenseFile, "", "File containing the Vault Enterprise license to apply during configure")
+	configStringVar(configureCmd, cfgLicenseKVKey, "", "Key of the Vault Enterprise license to apply during configure in the unseal key store")
+}
@@ -0,0 +1,259 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgLogLevel        = "log-level"
+	cfgLogFormat       = "log-format"
+	cfgLogModuleLevels = "log-module-levels"
+	cfgLogRedactFields = "log-redact-fields"
+	cfgLogStrict       = "log-strict"
+)
+
+const (
+	cfgLogFormatValueText = "text"
+	cfgLogFormatValueJSON = "json"
+)
+
+const (
+	cfgLogStrictValueRedact = "redact"
+	cfgLogStrictValueAbort  = "abort"
+)
+
+// sensitiveFlags are the flags holding credentials, checked for by the strict log mode.
+var sensitiveFlags = []string{
+	cfgVaultToken,
+	cfgInitRootToken,
+	cfgAppRoleSecretID,
+	cfgHSMPin,
+	cfgKVEnvelopeMACKey,
+	cfgAlibabaAccessKeySecret,
+	cfgMetricsBearerToken,
+	cfgMetricsBasicAuthPassword,
+}
+
+// modulePrefix is trimmed from the package paths the per-module log levels are matched against.
+const modulePrefix = "github.com/bank-vaults/bank-vaults/"
+
+// setupLogging sets the default logger from the log level, format and per-module level flags.
+func setupLogging(cfg *viper.Viper, w io.Writer) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.GetString(cfgLogLevel))); err != nil {
+		return errors.Wrapf(err, "invalid --%s", cfgLogLevel)
+	}
+
+	moduleLevels := map[string]slog.Level{}
+	for module, moduleLevel := range cfg.GetStringMapString(cfgLogModuleLevels) {
+		var l slog.Level
+		if err := l.UnmarshalText([]byte(moduleLevel)); err != nil {
+			return errors.Wrapf(err, "invalid --%s of module %s", cfgLogModuleLevels, module)
+		}
+		moduleLevels[strings.Trim(module, "/")] = l
+	}
+
+	// The handler has to let the records of the most verbose module through, the rest is filtered per module
+	minLevel := level
+	for _, l := range moduleLevels {
+		minLevel = min(minLevel, l)
+	}
+	options := &slog.HandlerOptions{Level: minLevel}
+
+	var handler slog.Handler
+	switch format := cfg.GetString(cfgLogFormat); format {
+	case cfgLogFormatValueText:
+		handler = slog.NewTextHandler(w, options)
+	case cfgLogFormatValueJSON:
+		handler = slog.NewJSONHandler(w, options)
+	default:
+		return errors.Errorf("unsupported --%s: '%s'", cfgLogFormat, format)
+	}
+
+	if len(moduleLevels) > 0 {
+		handler = &moduleLevelHandler{Handler: handler, level: level, moduleLevels: moduleLevels}
+	}
+
+	strict := cfg.GetString(cfgLogStrict)
+	switch strict {
+	case "", cfgLogStrictValueRedact, cfgLogStrictValueAbort:
+	default:
+		return errors.Errorf("unsupported --%s: '%s'", cfgLogStrict, strict)
+	}
+
+	// Scrubbing the secrets redacts them like the strict redact mode
+	if strict != "" || cfg.GetBool(cfgScrubSecrets) {
+		secmem.EnableTracking()
+		for _, flag := range sensitiveFlags {
+			secmem.Track(cfg.GetString(flag))
+		}
+		handler = &strictHandler{Handler: handler, abort: strict == cfgLogStrictValueAbort, exit: os.Exit}
+	}
+
+	slog.SetDefault(slog.New(handler))
+
+	return nil
+}
+
+// moduleLevelHandler filters the records by the level of the module (package path relative to the
+// repository root, e.g. internal/vault or pkg/kv) they were logged from.
+type moduleLevelHandler struct {
+	slog.Handler
+
+	level        slog.Level
+	moduleLevels map[string]slog.Level
+}
+
+func (h *moduleLevelHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < h.moduleLevel(record.PC) {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *moduleLevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &moduleLevelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level, moduleLevels: h.moduleLevels}
+}
+
+func (h *moduleLevelHandler) WithGroup(name string) slog.Handler {
+	return &moduleLevelHandler{Handler: h.Handler.WithGroup(name), level: h.level, moduleLevels: h.moduleLevels}
+}
+
+// moduleLevel returns the level of the most specific module matching the package of the caller.
+func (h *moduleLevelHandler) moduleLevel(pc uintptr) slog.Level {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	pkg := strings.TrimPrefix(packagePath(frame.Function), modulePrefix)
+
+	level, matched := h.level, ""
+	for module, moduleLevel := range h.moduleLevels {
+		if (pkg == module || strings.HasPrefix(pkg, module+"/")) && len(module) > len(matched) {
+			level, matched = moduleLevel, module
+		}
+	}
+
+	return level
+}
+
+// strictHandler checks the records for the secrets currently in memory (unseal keys, tokens, credentials)
+// and redacts them, or exits without logging the record in abort mode.
+type strictHandler struct {
+	slog.Handler
+
+	abort bool
+	exit  func(code int)
+}
+
+func (h *strictHandler) Handle(ctx context.Context, record slog.Record) error {
+	message, leaked := secmem.Redact(record.Message)
+
+	var attrs []slog.Attr
+	record.Attrs(func(attr slog.Attr) bool {
+		attr, attrLeaked := redactAttr(attr)
+		leaked = leaked || attrLeaked
+		attrs = append(attrs, attr)
+
+		return true
+	})
+
+	if leaked && h.abort {
+		aborted := slog.NewRecord(record.Time, slog.LevelError, "log record containing a secret dropped, exiting", record.PC)
+		_ = h.Handler.Handle(ctx, aborted)
+		h.exit(1)
+
+		return nil
+	}
+
+	redacted := slog.NewRecord(record.Time, record.Level, message, record.PC)
+	redacted.AddAttrs(attrs...)
+
+	return h.Handler.Handle(ctx, redacted)
+}
+
+// WithAttrs checks the attributes when they are added, secrets tracked later aren't found in them.
+func (h *strictHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		attr, leaked := redactAttr(attr)
+		if leaked && h.abort {
+			_ = h.Handler.Handle(context.Background(), slog.NewRecord(time.Now(), slog.LevelError, "log attribute containing a secret dropped, exiting", 0))
+			h.exit(1)
+		}
+		redacted = append(redacted, attr)
+	}
+
+	return &strictHandler{Handler: h.Handler.WithAttrs(redacted), abort: h.abort, exit: h.exit}
+}
+
+func (h *strictHandler) WithGroup(name string) slog.Handler {
+	return &strictHandler{Handler: h.Handler.WithGroup(name), abort: h.abort, exit: h.exit}
+}
+
+// redactAttr redacts the secrets in the value of an attribute, turning it into a string if it had any.
+func redactAttr(attr slog.Attr) (slog.Attr, bool) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		leaked := false
+		group := make([]any, 0, len(value.Group()))
+		for _, member := range value.Group() {
+			member, memberLeaked := redactAttr(member)
+			leaked = leaked || memberLeaked
+			group = append(group, member)
+		}
+
+		return slog.Group(attr.Key, group...), leaked
+	}
+
+	text, leaked := secmem.Redact(value.String())
+	if !leaked {
+		return slog.Attr{Key: attr.Key, Value: value}, false
+	}
+
+	return slog.String(attr.Key, text), true
+}
+
+// packagePath returns the package path of a fully qualified function name,
+// e.g. github.com/bank-vaults/bank-vaults/pkg/vault for github.com/bank-vaults/bank-vaults/pkg/vault.(*vault).configure.
+func packagePath(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	if dot := strings.Index(function[lastSlash+1:], "."); dot >= 0 {
+		return function[:lastSlash+1+dot]
+	}
+
+	return function
+}
+
+func init() {
+	configStringVar(rootCmd, cfgLogLevel, "info", "Log level: debug, info, warn or error")
+	configStringVar(rootCmd, cfgLogFormat, cfgLogFormatValueText, fmt.Sprintf("Log format: '%s' or '%s'", cfgLogFormatValueText, cfgLogFormatValueJSON))
+	configStringMapVar(rootCmd, cfgLogModuleLevels, map[string]string{}, "Per-module log levels overriding --log-level, e.g. 'internal/vault=debug,pkg/kv=warn'")
+	configStringSliceVar(rootCmd, cfgLogRedactFields, bankvaults.DefaultRedactedFields, "Names of the fields whose values are masked in the logged config payloads")
+	configStringVar(rootCmd, cfgLogStrict, "", fmt.Sprintf("Check the log records for the unseal keys, tokens and credentials in memory and '%s' them or '%s' the process", cfgLogStrictValueRedact, cfgLogStrictValueAbort))
+}
@@ -0,0 +1,65 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+)
+
+func TestStrictHandlerRedacts(t *testing.T) {
+	secmem.EnableTracking()
+	untrack := secmem.Track("hvs.root-token")
+	defer untrack()
+
+	var out bytes.Buffer
+	logger := slog.New(&strictHandler{Handler: slog.NewTextHandler(&out, nil)})
+
+	logger.With("vault", "https://vault:8200").Info("logged in with hvs.root-token",
+		"error", errors.New("permission denied for hvs.root-token"),
+		slog.Group("request", "path", "sys/mounts"))
+
+	assert.NotContains(t, out.String(), "hvs.root-token")
+	assert.Contains(t, out.String(), `msg="logged in with <redacted>"`)
+	assert.Contains(t, out.String(), `error="permission denied for <redacted>"`)
+	assert.Contains(t, out.String(), "request.path=sys/mounts")
+	assert.Contains(t, out.String(), "vault=https://vault:8200")
+
+	untrack()
+	out.Reset()
+	logger.Info("hvs.root-token")
+	assert.Contains(t, out.String(), "hvs.root-token", "untracked values are logged")
+}
+
+func TestStrictHandlerAborts(t *testing.T) {
+	secmem.EnableTracking()
+	defer secmem.Track("unseal-key-1")()
+
+	var out bytes.Buffer
+	exitCode := -1
+	logger := slog.New(&strictHandler{Handler: slog.NewTextHandler(&out, nil), abort: true, exit: func(code int) { exitCode = code }})
+
+	logger.Info("unsealing", "key", "unseal-key-1")
+
+	assert.Equal(t, 1, exitCode)
+	assert.NotContains(t, out.String(), "unseal-key-1")
+	assert.Contains(t, out.String(), "log record containing a secret dropped")
+}
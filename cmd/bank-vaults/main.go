@@ -27,6 +27,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/csi"
 )
 
 var Version = "dev"
@@ -49,6 +52,7 @@ const (
 	cfgModeValueHSM               = "hsm"
 	cfgModeValueDev               = "dev"
 	cfgModeValueFile              = "file"
+	cfgModeValueCSI               = "csi-secrets-store"
 )
 
 const (
@@ -63,6 +67,11 @@ const (
 	cfgGoogleCloudStoragePrefix = "google-cloud-storage-prefix"
 )
 
+const (
+	cfgGoogleCredentialsFile           = "google-credentials-file"
+	cfgGoogleImpersonateServiceAccount = "google-impersonate-service-account"
+)
+
 const (
 	cfgAWSKMSRegion            = "aws-kms-region"
 	cfgAWSKMSKeyID             = "aws-kms-key-id"
@@ -74,6 +83,10 @@ const (
 	cfgAWSS3Prefix = "aws-s3-prefix"
 	cfgAWSS3Region = "aws-s3-region"
 	cfgAWS3SSEAlgo = "aws-s3-sse-algo"
+
+	cfgAWSRoleARN              = "aws-role-arn"
+	cfgAWSWebIdentityTokenFile = "aws-web-identity-token-file"
+	cfgAWSRoleSessionName      = "aws-role-session-name"
 )
 
 const (
@@ -109,9 +122,17 @@ const (
 )
 
 const (
-	cfgK8SNamespace = "k8s-secret-namespace"
-	cfgK8SSecret    = "k8s-secret-name"
-	cfgK8SLabels    = "k8s-secret-labels"
+	cfgK8SNamespace   = "k8s-secret-namespace"
+	cfgK8SSecret      = "k8s-secret-name"
+	cfgK8SLabels      = "k8s-secret-labels"
+	cfgK8SAnnotations = "k8s-secret-annotations"
+	cfgK8SType        = "k8s-secret-type"
+	cfgK8SKeyTemplate = "k8s-secret-key-template"
+	cfgK8SInclude     = "k8s-secret-include"
+	cfgK8SExclude     = "k8s-secret-exclude"
+
+	cfgK8SOwnerReference = "k8s-secret-owner-reference"
+	cfgK8SFieldManager   = "k8s-secret-field-manager"
 )
 
 const (
@@ -125,8 +146,20 @@ const (
 const cfgFilePath = "file-path"
 
 const (
-	cfgUnsealPeriod = "unseal-period"
-	cfgOnce         = "once"
+	cfgCSIPath    = "csi-path"
+	cfgCSIObjects = "csi-objects"
+)
+
+const (
+	cfgKVEnvelope             = "kv-envelope"
+	cfgKVEnvelopeMACKey       = "kv-envelope-mac-key"
+	cfgKVEnvelopeRejectLegacy = "kv-envelope-reject-legacy"
+)
+
+const (
+	cfgUnsealPeriod   = "unseal-period"
+	cfgOnce           = "once"
+	cfgAllowCoreDumps = "allow-core-dumps"
 )
 
 var c = viper.New()
@@ -135,24 +168,79 @@ var rootCmd = &cobra.Command{
 	Use:   "bank-vaults",
 	Short: "Automates initialization, unsealing and configuration of Hashicorp Vault.",
 	Long:  `This is a CLI tool to help automate the setup and management of Hashicorp Vault.`,
+	// The errors are logged by execute, where the secrets are scrubbed from them
+	SilenceErrors: true,
+	PersistentPreRunE: func(*cobra.Command, []string) error {
+		if err := setupLogging(c, os.Stderr); err != nil {
+			return err
+		}
+		setupRequestLog(c)
+		if err := setupRateLimit(c); err != nil {
+			return err
+		}
+		dryRun = c.GetBool(cfgDryRun)
+
+		// A core dump would contain the unseal keys and the root token
+		if !c.GetBool(cfgAllowCoreDumps) {
+			if err := secmem.DisableCoreDumps(); err != nil {
+				slog.Warn("error disabling core dumps", "error", err)
+			}
+		}
+
+		if c.GetBool(cfgFIPS) {
+			if err := checkFIPSCrypto(fipsCryptoEnabled()); err != nil {
+				return err
+			}
+		}
+
+		if err := setupConfigSignature(); err != nil {
+			return err
+		}
+
+		if c.GetBool(cfgEnablePprof) {
+			servePprof(c.GetInt(cfgPprofPort))
+		}
+
+		return nil
+	},
 }
 
 func execute() {
-	// Handle signals to prevent bad exit codes on `docker stop`.
-	// TODO: probably a more sophisticated exit procedure should be implemented in the future.
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, syscall.SIGTERM, syscall.SIGINT, syscall.SIGABRT)
+	defer recoverScrubbed()
+
+	// A signal cancels the context of the command, which finishes the step in progress, flushes the
+	// metrics and exits cleanly on `docker stop` or `systemctl stop`. A second signal exits right away.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGABRT)
+	defer stop()
 	go func() {
-		<-sigs
-		os.Exit(0)
+		<-ctx.Done()
+		slog.Info("shutting down...")
+		notifySystemd("STOPPING=1")
+		stop()
 	}()
 
-	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
+	// The flags are only all defined once every init ran
+	registerFlagCompletions(rootCmd)
+
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		slog.Error(fmt.Sprintf("error executing command: %s", err.Error()))
 		os.Exit(1)
 	}
 }
 
+// sleepContext waits for the duration, or returns the error of the context if it is cancelled first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 func configBoolVar(cmd *cobra.Command, key string, defaultValue bool, description string) {
 	cmd.PersistentFlags().Bool(key, defaultValue, description)
 	_ = c.BindPFlag(key, cmd.PersistentFlags().Lookup(key))
@@ -163,6 +251,11 @@ func configDurationVar(cmd *cobra.Command, key string, defaultValue time.Duratio
 	_ = c.BindPFlag(key, cmd.PersistentFlags().Lookup(key))
 }
 
+func configFloat64Var(cmd *cobra.Command, key string, defaultValue float64, description string) {
+	cmd.PersistentFlags().Float64(key, defaultValue, description)
+	_ = c.BindPFlag(key, cmd.PersistentFlags().Lookup(key))
+}
+
 func configIntVar(cmd *cobra.Command, key string, defaultValue int, description string) {
 	cmd.PersistentFlags().Int(key, defaultValue, description)
 	_ = c.BindPFlag(key, cmd.PersistentFlags().Lookup(key))
@@ -205,7 +298,8 @@ func init() {
 						'%s' => Kubernetes Secrets encrypted with HSM;
 						'%s' => HSM object on device, using HSM encryption;
 						'%s' => Dev (vault server -dev) mode
-						'%s' => File mode`,
+						'%s' => File mode
+						'%s' => Secrets Store CSI volume (read-only)`,
 			cfgModeValueGoogleCloudKMSGCS,
 			cfgModeValueAWSKMS3,
 			cfgModeValueAzureKeyVault,
@@ -217,6 +311,7 @@ func init() {
 			cfgModeValueHSM,
 			cfgModeValueDev,
 			cfgModeValueFile,
+			cfgModeValueCSI,
 		),
 	)
 
@@ -234,16 +329,23 @@ func init() {
 	configStringVar(rootCmd, cfgGoogleCloudStorageBucket, "", "The name of the Google Cloud Storage bucket to store values in")
 	configStringVar(rootCmd, cfgGoogleCloudStoragePrefix, "", "The prefix to use for values store in Google Cloud Storage")
 
+	// Google Cloud credentials flags
+	configStringVar(rootCmd, cfgGoogleCredentialsFile, "", "The workload identity federation credential configuration (external_account) of the Google Cloud backends, the Application Default Credentials are used if empty")
+	configStringVar(rootCmd, cfgGoogleImpersonateServiceAccount, "", "The Google service account the Google Cloud backends impersonate with their credentials")
+
 	// AWS KMS flags
-	configStringSliceVar(rootCmd, cfgAWSKMSRegion, nil, "The region of the AWS KMS key to encrypt values")
+	configStringSliceVar(rootCmd, cfgAWSKMSRegion, nil, "The region of the AWS KMS key to encrypt values, resolved like the S3 region if empty")
 	configStringSliceVar(rootCmd, cfgAWSKMSKeyID, nil, "The ID or ARN of the AWS KMS key to encrypt values")
 	configStringMapVar(rootCmd, cfgAWSKMSEncryptionContext, map[string]string{"Tool": "bank-vaults"}, "The encryption context that AWS KMS will use to encrypt values")
 
 	// AWS S3 Object Storage flags
-	configStringSliceVar(rootCmd, cfgAWSS3Region, []string{"us-east-1"}, "The region to use for storing values in AWS S3")
+	configStringSliceVar(rootCmd, cfgAWSS3Region, []string{"us-east-1"}, "The region to use for storing values in AWS S3, resolved from AWS_REGION, the shared config or the instance metadata if empty")
 	configStringSliceVar(rootCmd, cfgAWSS3Bucket, nil, "The name of the AWS S3 bucket to store values in")
 	configStringVar(rootCmd, cfgAWSS3Prefix, "", "The prefix to use for storing values in AWS S3")
 	configStringSliceVar(rootCmd, cfgAWS3SSEAlgo, []string{""}, "The algorithm to use for the S3 SSE")
+	configStringVar(rootCmd, cfgAWSRoleARN, "", "The AWS role to assume with the web identity token of --"+cfgAWSWebIdentityTokenFile+", if the IRSA or Pod Identity environment isn't injected")
+	configStringVar(rootCmd, cfgAWSWebIdentityTokenFile, "", "The web identity token file to assume --"+cfgAWSRoleARN+" with, e.g. a projected service account token")
+	configStringVar(rootCmd, cfgAWSRoleSessionName, "bank-vaults", "The session name of the assumed AWS role")
 
 	// Azure Key Vault flags
 	configStringVar(rootCmd, cfgAzureKeyVaultName, "", "The name of the Azure Key Vault to encrypt and store values in")
@@ -279,8 +381,15 @@ func init() {
 
 	// K8S Secret Storage flags
 	configStringVar(rootCmd, cfgK8SNamespace, "", "The namespace of the K8S Secret to store values in")
-	configStringVar(rootCmd, cfgK8SSecret, "", "The name of the K8S Secret to store values in")
+	configStringVar(rootCmd, cfgK8SSecret, "", "The name of the K8S Secret to store values in, a Go template given the {{ .Key }} and {{ .Namespace }} to split the values into several Secrets")
 	configStringMapVar(rootCmd, cfgK8SLabels, map[string]string{}, "The labels of the K8S Secret to store values in")
+	configStringMapVar(rootCmd, cfgK8SAnnotations, map[string]string{}, "The annotations of the K8S Secret to store values in")
+	configStringVar(rootCmd, cfgK8SType, "", "The type of the K8S Secret to store values in, Opaque if empty")
+	configStringVar(rootCmd, cfgK8SKeyTemplate, "", "Go template of the K8S Secret key a value is stored under, given the {{ .Key }} and {{ .Namespace }}, the key itself if empty")
+	configStringSliceVar(rootCmd, cfgK8SInclude, nil, "Patterns of the keys to store in the K8S Secret, e.g. 'vault-unseal-*', all if empty")
+	configStringSliceVar(rootCmd, cfgK8SExclude, nil, "Patterns of the keys never to store in the K8S Secret, e.g. 'vault-root' together with --store-root-token=false")
+	configStringVar(rootCmd, cfgK8SOwnerReference, "", "JSON owner reference of the created K8S Secrets, which must live in their namespace, defaults to K8S_OWNER_REFERENCE if the Secrets are stored in the namespace of the pod")
+	configStringVar(rootCmd, cfgK8SFieldManager, "bank-vaults", "The field manager the K8S Secrets are written as")
 
 	// HSM flags
 	configStringVar(rootCmd, cfgHSMModulePath, "", "The library path of the HSM device")
@@ -292,8 +401,19 @@ func init() {
 	// File flags
 	configStringVar(rootCmd, cfgFilePath, "", "The path prefix of the files where to store values in")
 
+	// Secrets Store CSI flags
+	configStringVar(rootCmd, cfgCSIPath, csi.DefaultPath, "The path the Secrets Store CSI volume is mounted at")
+	configStringMapVar(rootCmd, cfgCSIObjects, map[string]string{}, "The objects of the Secrets Store CSI volume the keys are read from, if not named after the keys, e.g. vault-root=root-token")
+
+	// Envelope flags
+	configBoolVar(rootCmd, cfgKVEnvelope, false, "Store values in authenticated envelopes binding them to their key and mode, values stored before are still read")
+	configStringVar(rootCmd, cfgKVEnvelopeMACKey, "", "Secret key of the MAC of the envelopes, without it the MAC only detects corruption and values moved between keys")
+	configBoolVar(rootCmd, cfgKVEnvelopeRejectLegacy, false, "Fail reading values stored without an envelope, once all the values were rewritten in envelopes")
+
 	// Misc common flags
 	configBoolVar(rootCmd, cfgOnce, false, "Run configure/unseal only once")
+	configBoolVar(rootCmd, cfgAllowCoreDumps, false, "Allow the process to write core dumps, which contain key material")
+	configBoolVar(rootCmd, cfgFIPS, false, "Restrict the crypto to FIPS-validated primitives, needs GODEBUG=fips140=on or a boringcrypto build, and rejects the modes without FIPS-validated encryption")
 	configDurationVar(configureCmd, cfgUnsealPeriod, time.Second*5, "How often to attempt to unseal the Vault instance")
 }
 
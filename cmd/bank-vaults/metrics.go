@@ -15,18 +15,36 @@
 package main
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/viper"
 
-	internalVault "github.com/bank-vaults/bank-vaults/internal/vault"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
 )
 
 const prometheusNS = "vault"
 
+const (
+	cfgMetricsAddress           = "metrics-address"
+	cfgMetricsTLSCertFile       = "metrics-tls-cert-file"
+	cfgMetricsTLSKeyFile        = "metrics-tls-key-file"
+	cfgMetricsBearerToken       = "metrics-bearer-token"
+	cfgMetricsBasicAuthUsername = "metrics-basic-auth-username"
+	cfgMetricsBasicAuthPassword = "metrics-basic-auth-password"
+	cfgMetricsClusterName       = "metrics-cluster-name"
+)
+
 var (
 	initializedDesc = prometheus.NewDesc(
 		prometheus.BuildFQName(prometheusNS, "sys", "initialized"),
@@ -55,11 +73,209 @@ var (
 		"Number of configurations files applied that failed",
 		nil, nil,
 	)
+	targetConfigurationsMu     sync.Mutex
+	targetConfigurationsCounts = map[string]map[bool]float64{}
+	targetConfigurationsDesc   = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "config", "target_applied"),
+		"Number of configuration files applied to a Vault target",
+		[]string{"target", "result"}, nil,
+	)
+	configInfo = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config",
+		Name:      "info",
+		Help:      "Hash and version of the config last applied successfully, always 1",
+	}, []string{"target", "hash", "version"})
+	configIdentitiesMu sync.Mutex
+	// the hash and version of the config last applied successfully, by target
+	configIdentities  = map[string][2]string{}
+	configLastSuccess = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config",
+		Name:      "last_successful_timestamp_seconds",
+		Help:      "Time of the last successful config apply in seconds since the epoch",
+	}, []string{"target"})
+	unsealLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: prometheusNS,
+		Subsystem: "unseal",
+		Name:      "last_successful_timestamp_seconds",
+		Help:      "Time the Vault node was last found or made unsealed in seconds since the epoch",
+	})
+	sectionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config_section",
+		Name:      "duration_seconds",
+		Help:      "Duration of configuring a config section",
+		Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"target", "section"})
+	sectionLastRun = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config_section",
+		Name:      "last_run_timestamp_seconds",
+		Help:      "Time a config section was last configured in seconds since the epoch, runs skipped as unchanged don't update it",
+	}, []string{"target", "section"})
+	sectionRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config_section",
+		Name:      "runs_total",
+		Help:      "Number of times a config section was configured, by result",
+	}, []string{"target", "section", "result"})
+	sectionUnmanaged = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config_section",
+		Name:      "unmanaged_resources",
+		Help:      "Number of resources in Vault which are not in the config found by the last run, whether they are purged or not",
+	}, []string{"target", "section"})
+	sectionItems = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config_section",
+		Name:      "items_total",
+		Help:      "Number of config items of a config section, by what happened to them",
+	}, []string{"target", "section", "result"})
+	tokenRenewals = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config_token",
+		Name:      "renewals_total",
+		Help:      "Number of renewals of the token the configurer logs in to a target with, by result",
+	}, []string{"target", "result"})
+	configErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config",
+		Name:      "errors_total",
+		Help:      "Number of Vault API errors of the configure runs, by category",
+	}, []string{"target", "category"})
+	tokenTTLDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "config_token", "ttl_seconds"),
+		"Remaining TTL of the token the configurer logged in to the target with, not exported for tokens which never expire",
+		[]string{"target"}, nil,
+	)
+	licenseExpirationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "license", "expiration_timestamp_seconds"),
+		"Expiration time of the applied Vault Enterprise license in seconds since the epoch",
+		[]string{"target"}, nil,
+	)
+	targetSealedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "sys", "sealed"),
+		"Is the Vault target sealed.",
+		[]string{"target"}, nil,
+	)
+	targetLeaderDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "sys", "leader"),
+		"Is the Vault node of the target the leader.",
+		[]string{"target"}, nil,
+	)
 )
 
+// metricsServer configures the listener of the /metrics endpoint.
+type metricsServer struct {
+	Address     string
+	TLSCertFile string
+	TLSKeyFile  string
+	// scrapes have to authenticate with this bearer token or basic auth credentials, if set
+	BearerToken       string
+	BasicAuthUsername string
+	BasicAuthPassword string
+}
+
+func metricsServerForConfig(cfg *viper.Viper) metricsServer {
+	return metricsServer{
+		Address:           cfg.GetString(cfgMetricsAddress),
+		TLSCertFile:       cfg.GetString(cfgMetricsTLSCertFile),
+		TLSKeyFile:        cfg.GetString(cfgMetricsTLSKeyFile),
+		BearerToken:       cfg.GetString(cfgMetricsBearerToken),
+		BasicAuthUsername: cfg.GetString(cfgMetricsBasicAuthUsername),
+		BasicAuthPassword: cfg.GetString(cfgMetricsBasicAuthPassword),
+	}
+}
+
+// validate rejects incomplete listener settings, so a misconfigured exporter fails at startup.
+func (s metricsServer) validate() error {
+	if (s.TLSCertFile == "") != (s.TLSKeyFile == "") {
+		return errors.Errorf("both --%s and --%s must be set to serve metrics over TLS", cfgMetricsTLSCertFile, cfgMetricsTLSKeyFile)
+	}
+
+	// An empty password would let anyone knowing the username scrape the metrics
+	if (s.BasicAuthUsername == "") != (s.BasicAuthPassword == "") {
+		return errors.Errorf("both --%s and --%s must be set to require basic auth", cfgMetricsBasicAuthUsername, cfgMetricsBasicAuthPassword)
+	}
+
+	return nil
+}
+
+// authenticate rejects the requests without the configured bearer token or basic auth credentials.
+func (s metricsServer) authenticate(next http.Handler) http.Handler {
+	if s.BearerToken == "" && s.BasicAuthUsername == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorized := false
+		if s.BearerToken != "" {
+			authorized = secureEqual(r.Header.Get("Authorization"), "Bearer "+s.BearerToken)
+		}
+		if !authorized && s.BasicAuthUsername != "" {
+			username, password, ok := r.BasicAuth()
+			// Compare both, so the response time doesn't tell whether the username was right
+			usernameMatch := subtle.ConstantTimeCompare([]byte(username), []byte(s.BasicAuthUsername))
+			passwordMatch := subtle.ConstantTimeCompare([]byte(password), []byte(s.BasicAuthPassword))
+			authorized = ok && usernameMatch&passwordMatch == 1
+		}
+
+		if !authorized {
+			if s.BasicAuthUsername != "" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s metricsServer) listenAndServe(handler http.Handler) error {
+	if s.TLSCertFile != "" {
+		return http.ListenAndServeTLS(s.Address, s.TLSCertFile, s.TLSKeyFile, handler) //nolint:gosec
+	}
+
+	return http.ListenAndServe(s.Address, handler) //nolint:gosec
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
 type prometheusExporter struct {
-	Vault internalVault.Vault
-	Mode  string
+	// the Vault node of the unsealer
+	Vault bankvaults.Vault
+	// the Vault clusters of the configurer
+	Targets []configureTarget
+	// the Vault endpoints of the metrics mode
+	Monitored []*monitoredTarget
+	Mode      string
+	Server    metricsServer
+	// constant labels of all the metrics, telling the bank-vaults instances apart
+	Labels prometheus.Labels
+	// if the replicas elect a leader, which exports if it is the active one
+	LeaderElection bool
+}
+
+// instanceLabelsForConfig returns the labels identifying a bank-vaults instance: the address of the Vault
+// it manages (if there is a single one), the cluster name and the namespace when running in Kubernetes.
+func instanceLabelsForConfig(cfg *viper.Viper, vaultAddress string) prometheus.Labels {
+	labels := prometheus.Labels{}
+	if vaultAddress != "" {
+		labels["vault_address"] = vaultAddress
+	}
+	if cluster := cfg.GetString(cfgMetricsClusterName); cluster != "" {
+		labels["cluster"] = cluster
+	}
+	if namespace, ok := inClusterNamespace(); ok {
+		labels["namespace"] = namespace
+	}
+
+	return labels
 }
 
 func (e *prometheusExporter) Describe(ch chan<- *prometheus.Desc) {
@@ -68,9 +284,24 @@ func (e *prometheusExporter) Describe(ch chan<- *prometheus.Desc) {
 		ch <- initializedDesc
 		ch <- sealedDesc
 		ch <- leaderDesc
+		ch <- unsealSuccessRatioDesc
 	case "configure":
 		ch <- successfulConfigurationsDesc
 		ch <- failedConfigurationsDesc
+		ch <- targetConfigurationsDesc
+		ch <- targetSealedDesc
+		ch <- targetLeaderDesc
+		ch <- licenseExpirationDesc
+		ch <- tokenTTLDesc
+		ch <- configSuccessRatioDesc
+	case "metrics":
+		ch <- targetUpDesc
+		ch <- targetInitializedDesc
+		ch <- targetSealedDesc
+		ch <- targetLeaderDesc
+		ch <- targetHARoleDesc
+		ch <- storageHealthyDesc
+		ch <- storageFailureToleranceDesc
 	}
 }
 
@@ -98,6 +329,11 @@ func (e *prometheusExporter) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(
 			leaderDesc, prometheus.GaugeValue, bToF(leader),
 		)
+		if ratio, ok := unsealOutcomes.ratios()[""]; ok {
+			ch <- prometheus.MustNewConstMetric(
+				unsealSuccessRatioDesc, prometheus.GaugeValue, ratio,
+			)
+		}
 	case "configure":
 		ch <- prometheus.MustNewConstMetric(
 			successfulConfigurationsDesc, prometheus.GaugeValue, successfulConfigurationsCount,
@@ -105,14 +341,223 @@ func (e *prometheusExporter) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(
 			failedConfigurationsDesc, prometheus.GaugeValue, failedConfigurationsCount,
 		)
+		targetConfigurationsMu.Lock()
+		for target, counts := range targetConfigurationsCounts {
+			ch <- prometheus.MustNewConstMetric(
+				targetConfigurationsDesc, prometheus.GaugeValue, counts[true], target, "successful",
+			)
+			ch <- prometheus.MustNewConstMetric(
+				targetConfigurationsDesc, prometheus.GaugeValue, counts[false], target, "failed",
+			)
+		}
+		targetConfigurationsMu.Unlock()
+		for target, ratio := range configOutcomes.ratios() {
+			ch <- prometheus.MustNewConstMetric(
+				configSuccessRatioDesc, prometheus.GaugeValue, ratio, target,
+			)
+		}
+		for _, target := range e.Targets {
+			e.collectTarget(ch, target)
+		}
+	case "metrics":
+		for _, target := range e.Monitored {
+			target.collect(ch)
+		}
+	}
+}
+
+// collectTarget exports the status of a Vault cluster the configurer applies the config to.
+func (e *prometheusExporter) collectTarget(ch chan<- prometheus.Metric, target configureTarget) {
+	if expiry := target.Vault.LicenseExpiry(); !expiry.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			licenseExpirationDesc, prometheus.GaugeValue, float64(expiry.Unix()), target.Name,
+		)
+	}
+
+	if expiry := target.Vault.TokenExpiry(); !expiry.IsZero() {
+		ch <- prometheus.MustNewConstMetric(
+			tokenTTLDesc, prometheus.GaugeValue, max(time.Until(expiry).Seconds(), 0), target.Name,
+		)
+	}
+
+	sealed, err := target.Vault.Sealed()
+	if err != nil {
+		slog.Error("error checking if vault is sealed", "target", target.Name, "error", err)
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(
+		targetSealedDesc, prometheus.GaugeValue, bToF(sealed), target.Name,
+	)
+
+	if sealed {
+		return
+	}
+
+	leader, err := target.Vault.Leader()
+	if err != nil {
+		slog.Error("error checking if vault is leader", "target", target.Name, "error", err)
+		return
 	}
+	ch <- prometheus.MustNewConstMetric(
+		targetLeaderDesc, prometheus.GaugeValue, bToF(leader), target.Name,
+	)
 }
 
 func (e prometheusExporter) Run() error {
-	slog.Info(fmt.Sprintf("vault metrics exporter enabled: %s%s", ":9091", "/metrics"))
-	prometheus.MustRegister(&e)
-	http.DefaultServeMux.Handle("/metrics", promhttp.Handler())
-	return http.ListenAndServe(":9091", http.DefaultServeMux) //nolint:gosec
+	if err := e.Server.validate(); err != nil {
+		return err
+	}
+
+	slog.Info(fmt.Sprintf("vault metrics exporter enabled: %s%s", e.Server.Address, "/metrics"))
+	registry, err := newExporterRegistry(&e)
+	if err != nil {
+		return err
+	}
+	// The process metrics of the default registry, with the instance labels as well
+	registerer := prometheus.WrapRegistererWith(e.Labels, registry)
+	for _, collector := range []prometheus.Collector{collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})} {
+		if err := registerer.Register(collector); err != nil {
+			return errors.Wrap(err, "error registering metrics")
+		}
+	}
+
+	// Not the DefaultServeMux: net/http/pprof registers its endpoints on it
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e.Server.authenticate(promhttp.InstrumentMetricHandler(registry, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))))
+	return e.Server.listenAndServe(mux)
+}
+
+// registerCollectors registers the exporter, the key store metrics and, in configure mode, the metrics
+// recorded by the configure runs (the drift checks in metrics mode), all with the instance labels of the exporter.
+func registerCollectors(registerer prometheus.Registerer, e *prometheusExporter) error {
+	registerer = prometheus.WrapRegistererWith(e.Labels, registerer)
+
+	collectors := []prometheus.Collector{e, kvOperationDuration, kvOperationErrors}
+	if e.Mode == "unseal" {
+		collectors = append(collectors, unsealLastSuccess)
+	}
+	if e.LeaderElection {
+		collectors = append(collectors, leaderElectionLeader)
+	}
+	if e.Mode == "configure" {
+		collectors = append(collectors, configInfo, configLastSuccess, sectionDuration, sectionLastRun, sectionRuns, sectionItems, sectionUnmanaged, tokenRenewals, configErrors)
+	}
+	if e.Mode == "metrics" {
+		collectors = append(collectors, configDrift, configDriftLastCheck, configDriftErrors)
+	}
+
+	for _, collector := range collectors {
+		if err := registerer.Register(collector); err != nil {
+			return errors.Wrap(err, "error registering metrics")
+		}
+	}
+
+	return nil
+}
+
+// newExporterRegistry returns a registry with all the metrics of the exporter, for the sinks
+// the metrics are pushed to instead of being scraped from the default registry.
+func newExporterRegistry(e *prometheusExporter) (*prometheus.Registry, error) {
+	registry := prometheus.NewRegistry()
+	if err := registerCollectors(registry, e); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// recordTargetConfiguration counts the result of applying a configuration file to a Vault target.
+func recordTargetConfiguration(target string, err error) {
+	targetConfigurationsMu.Lock()
+	defer targetConfigurationsMu.Unlock()
+
+	if targetConfigurationsCounts[target] == nil {
+		targetConfigurationsCounts[target] = map[bool]float64{}
+	}
+	targetConfigurationsCounts[target][err == nil]++
+	configOutcomes.record(target, err == nil)
+}
+
+// recordConfigIdentity exports the identity of the config successfully applied to a target.
+func recordConfigIdentity(target string, report *bankvaults.Report) {
+	if report == nil {
+		return
+	}
+
+	configIdentitiesMu.Lock()
+	defer configIdentitiesMu.Unlock()
+
+	// Reset drops the series of the hashes applied before, keep the current ones of the other targets
+	configIdentities[target] = [2]string{report.ConfigHash, report.ConfigVersion}
+	configInfo.Reset()
+	for name, identity := range configIdentities {
+		configInfo.WithLabelValues(name, identity[0], identity[1]).Set(1)
+	}
+	configLastSuccess.WithLabelValues(target).SetToCurrentTime()
+}
+
+// recordTokenRenewal counts the renewal of the token the configurer logged in to a target with, if it was due.
+func recordTokenRenewal(target string, report *bankvaults.Report) {
+	if report == nil || report.TokenRenewal == "" {
+		return
+	}
+
+	result := "success"
+	if report.TokenRenewal == bankvaults.TokenRenewFailed {
+		result = "failure"
+	}
+	tokenRenewals.WithLabelValues(target, result).Inc()
+}
+
+// recordErrorCategories counts the errors of a configure run of a target by category.
+func recordErrorCategories(target string, report *bankvaults.Report) {
+	if report == nil {
+		return
+	}
+
+	for category, count := range report.ErrorCategories {
+		configErrors.WithLabelValues(target, category).Add(float64(count))
+	}
+}
+
+// driftSections are the config sections unmanaged resources are detected in.
+var driftSections = []string{
+	bankvaults.SectionAudit,
+	bankvaults.SectionAuth,
+	bankvaults.SectionPolicies,
+	bankvaults.SectionSecrets,
+}
+
+// recordSectionMetrics exports the duration, result and item counts of each config section of a configure run.
+func recordSectionMetrics(target string, report *bankvaults.Report) {
+	if report == nil {
+		return
+	}
+
+	// Only the sections of this run were checked for unmanaged resources, a count of an earlier run
+	// (e.g. before a run skipped as unchanged) could be stale
+	sectionUnmanaged.DeletePartialMatch(prometheus.Labels{"target": target})
+
+	for name, section := range report.Sections {
+		sectionDuration.WithLabelValues(target, name).Observe(section.Duration.Seconds())
+		sectionLastRun.WithLabelValues(target, name).SetToCurrentTime()
+
+		result := "success"
+		if section.Error != "" || len(section.Failed) > 0 {
+			result = "failure"
+		}
+		sectionRuns.WithLabelValues(target, name, result).Inc()
+
+		sectionItems.WithLabelValues(target, name, "created").Add(float64(len(section.Created)))
+		sectionItems.WithLabelValues(target, name, "updated").Add(float64(len(section.Updated)))
+		sectionItems.WithLabelValues(target, name, "skipped").Add(float64(len(section.Skipped)))
+		sectionItems.WithLabelValues(target, name, "purged").Add(float64(len(section.Purged)))
+		sectionItems.WithLabelValues(target, name, "failed").Add(float64(len(section.Failed)))
+
+		if slices.Contains(driftSections, name) {
+			sectionUnmanaged.WithLabelValues(target, name).Set(float64(len(section.Unmanaged)))
+		}
+	}
 }
 
 func bToF(b bool) float64 {
@@ -121,3 +566,13 @@ func bToF(b bool) float64 {
 	}
 	return 0
 }
+
+func init() {
+	configStringVar(rootCmd, cfgMetricsAddress, ":9091", "Address the metrics exporter listens on")
+	configStringVar(rootCmd, cfgMetricsTLSCertFile, "", "TLS certificate file of the metrics exporter, serves plaintext if not set")
+	configStringVar(rootCmd, cfgMetricsTLSKeyFile, "", "TLS key file of the metrics exporter")
+	configStringVar(rootCmd, cfgMetricsBearerToken, "", "Bearer token scrapes of the metrics exporter have to authenticate with")
+	configStringVar(rootCmd, cfgMetricsBasicAuthUsername, "", "Basic auth username scrapes of the metrics exporter have to authenticate with")
+	configStringVar(rootCmd, cfgMetricsBasicAuthPassword, "", "Basic auth password scrapes of the metrics exporter have to authenticate with")
+	configStringVar(rootCmd, cfgMetricsClusterName, "", "Cluster name added as the 'cluster' label to all the metrics, to tell the bank-vaults instances apart")
+}
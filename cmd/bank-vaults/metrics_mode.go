@@ -0,0 +1,351 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgMetricsTargetsFile   = "metrics-targets-file"
+	cfgMetricsTokenFile     = "metrics-token-file"
+	cfgMetricsConfigFile    = "metrics-vault-config-file"
+	cfgMetricsDriftInterval = "metrics-drift-interval"
+)
+
+// HA roles of a Vault target.
+const (
+	haRoleActive             = "active"
+	haRoleStandby            = "standby"
+	haRolePerformanceStandby = "performance_standby"
+	haRoleSealed             = "sealed"
+	haRoleUninitialized      = "uninitialized"
+)
+
+var (
+	targetUpDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "sys", "up"),
+		"Was the health endpoint of the Vault target reachable.",
+		[]string{"target"}, nil,
+	)
+	targetInitializedDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "sys", "initialized"),
+		"Is the Vault target initialized.",
+		[]string{"target"}, nil,
+	)
+	targetHARoleDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "sys", "ha_role"),
+		"HA role of the Vault target: active, standby, performance_standby, sealed or uninitialized, always 1",
+		[]string{"target", "role"}, nil,
+	)
+	storageHealthyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "storage", "raft_healthy"),
+		"Is the raft storage of the Vault target healthy according to autopilot, only exported for raft storage with a token",
+		[]string{"target"}, nil,
+	)
+	storageFailureToleranceDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "storage", "raft_failure_tolerance"),
+		"Number of raft voters the Vault target can lose without losing quorum, only exported for raft storage with a token",
+		[]string{"target"}, nil,
+	)
+	configDrift = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config",
+		Name:      "drift_items",
+		Help:      "Number of differences between the config and the Vault target found by the last drift check",
+	}, []string{"target"})
+	configDriftLastCheck = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config",
+		Name:      "drift_last_check_timestamp_seconds",
+		Help:      "Time of the last successful drift check of the Vault target in seconds since the epoch",
+	}, []string{"target"})
+	configDriftErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNS,
+		Subsystem: "config",
+		Name:      "drift_check_errors_total",
+		Help:      "Number of drift checks of the Vault target that failed",
+	}, []string{"target"})
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Only export the Prometheus metrics of Vault endpoints, without unsealing or configuring them",
+	Long: `This command runs the metrics exporter alone, for read-only monitoring deployments.
+It exports the health, seal status and HA role of the Vault configured by the
+--target-* flags or of each one listed in --metrics-targets-file (in the format
+of the --targets-file of configure, one node per entry to tell their roles apart).
+
+With a token, of --metrics-token-file or the token or tokenFile of a target, the
+autopilot health of the raft storage is exported as well. With config files
+--metrics-vault-config-file the targets are compared with them every
+--metrics-drift-interval, like configure --verify, and the number of differences
+is exported. Nothing is written to Vault or the key store.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		ctx := cmd.Context()
+
+		parser, err := multiparser.New(parser.JSON, parser.YAML)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error file parsers: %v", err))
+			os.Exit(1)
+		}
+
+		configFiles := c.GetStringSlice(cfgMetricsConfigFile)
+		targets, err := monitoredTargetsForConfig(ctx, c, parser, len(configFiles) > 0)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error creating vault targets: %s", err.Error()))
+			os.Exit(1)
+		}
+
+		if len(configFiles) > 0 {
+			interval := c.GetDuration(cfgMetricsDriftInterval)
+			for _, target := range targets {
+				go target.watchDrift(ctx, parser, configFiles, interval)
+			}
+		}
+
+		// The targets are told apart by the target label, the address only identifies a single one
+		var metricsAddress string
+		if len(targets) == 1 {
+			metricsAddress = targets[0].address
+		}
+		metrics := prometheusExporter{
+			Monitored: targets,
+			Mode:      "metrics",
+			Server:    metricsServerForConfig(c),
+			Labels:    instanceLabelsForConfig(c, metricsAddress),
+		}
+		if err := metrics.Run(); err != nil {
+			slog.Error(fmt.Sprintf("error creating prometheus exporter: %s", err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+// monitoredTarget is a Vault endpoint the metrics mode exports the status of.
+type monitoredTarget struct {
+	name    string
+	address string
+	client  *api.Client
+	// the token to read the raft storage health with, read from tokenFile at every scrape if set
+	token     string
+	tokenFile string
+	// the drift is checked with it, nil without config files
+	vault    bankvaults.Vault
+	overlays []string
+}
+
+// monitoredTargetsForConfig returns the Vault endpoints listed in the metrics targets file, or the single one
+// configured by flags. The drift checks need a token, as unlike configure they never use the stored root token.
+func monitoredTargetsForConfig(ctx context.Context, cfg *viper.Viper, parser multiparser.Parser, checkDrift bool) ([]*monitoredTarget, error) {
+	clusterTargets := []clusterTarget{{vaultTarget: targetForConfig(cfg), Name: defaultTargetName}}
+	if targetsFile := cfg.GetString(cfgMetricsTargetsFile); targetsFile != "" {
+		var err error
+		clusterTargets, err = readTargetsFile(parser, targetsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	targets := make([]*monitoredTarget, 0, len(clusterTargets))
+	for _, clusterTarget := range clusterTargets {
+		cl, err := clusterTarget.newClient()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error connecting to vault target %s", clusterTarget.Name)
+		}
+
+		target := &monitoredTarget{
+			name:      clusterTarget.Name,
+			address:   cl.Address(),
+			client:    cl,
+			token:     clusterTarget.Token,
+			tokenFile: clusterTarget.TokenFile,
+			overlays:  clusterTarget.Overlays,
+		}
+		if target.token == "" && target.tokenFile == "" {
+			target.tokenFile = cfg.GetString(cfgMetricsTokenFile)
+		}
+
+		if checkDrift {
+			if target.token == "" && target.tokenFile == "" {
+				return nil, errors.Errorf("checking the drift of vault target %s needs a token, set --%s or the token of the target", clusterTarget.Name, cfgMetricsTokenFile)
+			}
+
+			target.vault, err = bankvaults.New(ctx, nil, cl, bankvaults.Config{Token: target.token, TokenFile: target.tokenFile})
+			if err != nil {
+				return nil, errors.Wrapf(err, "error creating vault helper of vault target %s", clusterTarget.Name)
+			}
+		}
+
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// readToken returns the token of the target, empty if it has none.
+func (t *monitoredTarget) readToken() (string, error) {
+	if t.tokenFile == "" {
+		return t.token, nil
+	}
+
+	token, err := os.ReadFile(t.tokenFile)
+	if err != nil {
+		return "", errors.Wrap(err, "error reading token file")
+	}
+
+	return string(bytes.TrimSpace(token)), nil
+}
+
+// collect exports the health, HA role and raft storage health of the target.
+func (t *monitoredTarget) collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+
+	health, err := t.client.Sys().HealthWithContext(ctx)
+	if err != nil {
+		slog.Error("error checking the health of vault", "target", t.name, "error", err)
+	}
+	ch <- prometheus.MustNewConstMetric(targetUpDesc, prometheus.GaugeValue, bToF(err == nil), t.name)
+	if err != nil {
+		return
+	}
+
+	role := haRole(health)
+	ch <- prometheus.MustNewConstMetric(targetInitializedDesc, prometheus.GaugeValue, bToF(health.Initialized), t.name)
+	ch <- prometheus.MustNewConstMetric(targetSealedDesc, prometheus.GaugeValue, bToF(health.Sealed), t.name)
+	ch <- prometheus.MustNewConstMetric(targetLeaderDesc, prometheus.GaugeValue, bToF(role == haRoleActive), t.name)
+	ch <- prometheus.MustNewConstMetric(targetHARoleDesc, prometheus.GaugeValue, 1, t.name, role)
+
+	if !health.Initialized || health.Sealed {
+		return
+	}
+
+	token, err := t.readToken()
+	if err != nil {
+		slog.Error("error reading the token of vault", "target", t.name, "error", err)
+		return
+	}
+	if token == "" {
+		return
+	}
+
+	cl, err := t.client.Clone()
+	if err != nil {
+		slog.Error("error cloning client", "target", t.name, "error", err)
+		return
+	}
+	cl.SetToken(token)
+
+	state, err := cl.Sys().RaftAutopilotStateWithContext(ctx)
+	if err != nil {
+		slog.Error("error checking the raft storage health of vault", "target", t.name, "error", err)
+		return
+	}
+	// Not raft storage
+	if state == nil {
+		return
+	}
+	ch <- prometheus.MustNewConstMetric(storageHealthyDesc, prometheus.GaugeValue, bToF(state.Healthy), t.name)
+	ch <- prometheus.MustNewConstMetric(storageFailureToleranceDesc, prometheus.GaugeValue, float64(state.FailureTolerance), t.name)
+}
+
+// haRole returns the HA role of a Vault node from its health, a node without HA is active.
+func haRole(health *api.HealthResponse) string {
+	switch {
+	case !health.Initialized:
+		return haRoleUninitialized
+	case health.Sealed:
+		return haRoleSealed
+	case health.PerformanceStandby:
+		return haRolePerformanceStandby
+	case health.Standby:
+		return haRoleStandby
+	default:
+		return haRoleActive
+	}
+}
+
+// watchDrift compares the target with the config files every interval until the context is done.
+func (t *monitoredTarget) watchDrift(ctx context.Context, parser multiparser.Parser, configFiles []string, interval time.Duration) {
+	defer recoverScrubbed()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.checkDrift(ctx, parser, configFiles); err != nil {
+			slog.Error("error checking the config drift of vault", "target", t.name, "error", err)
+			configDriftErrors.WithLabelValues(t.name).Inc()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkDrift exports the number of differences between the config files and the target.
+func (t *monitoredTarget) checkDrift(ctx context.Context, parser multiparser.Parser, configFiles []string) error {
+	var drifts int
+	for _, configFile := range configFiles {
+		config, err := readConfiguration(parser, configFile)
+		if err != nil {
+			return err
+		}
+
+		data, err := applyOverlays(parser, config.Data, t.overlays)
+		if err != nil {
+			return errors.Wrapf(err, "error applying overlays to config file %s", config.Path)
+		}
+
+		found, err := t.vault.Verify(ctx, data)
+		if err != nil {
+			return errors.Wrapf(err, "error verifying config file %s", config.Path)
+		}
+		drifts += len(found)
+	}
+
+	configDrift.WithLabelValues(t.name).Set(float64(drifts))
+	configDriftLastCheck.WithLabelValues(t.name).SetToCurrentTime()
+
+	return nil
+}
+
+func init() {
+	configStringVar(metricsCmd, cfgMetricsTargetsFile, "", "YAML/JSON file listing the Vault endpoints to export the metrics of under 'targets', instead of the single Vault configured by flags")
+	configStringVar(metricsCmd, cfgMetricsTokenFile, "", "File holding the token to read the raft storage health and check the config drift with, re-read at every use")
+	configStringSliceVar(metricsCmd, cfgMetricsConfigFile, nil, "The YAML/JSON Vault configuration files to check the drift of the targets against, no drift is checked if empty")
+	configDurationVar(metricsCmd, cfgMetricsDriftInterval, 5*time.Minute, "How often to check the config drift of the targets")
+
+	rootCmd.AddCommand(metricsCmd)
+}
@@ -0,0 +1,175 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+// driftVault reports the drifts of the configs holding a 'drift' list.
+type driftVault struct {
+	bankvaults.Vault
+}
+
+func (driftVault) Verify(_ context.Context, config map[string]interface{}) ([]bankvaults.Drift, error) {
+	var drifts []bankvaults.Drift
+	for range config["drift"].([]interface{}) {
+		drifts = append(drifts, bankvaults.Drift{Section: bankvaults.SectionPolicies, Reason: bankvaults.DriftMissing})
+	}
+
+	return drifts, nil
+}
+
+func newMonitoredTarget(t *testing.T, name string, health map[string]interface{}) *monitoredTarget {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response interface{}
+		switch r.URL.Path {
+		case "/v1/sys/health":
+			response = health
+		case "/v1/sys/storage/raft/autopilot/state":
+			if r.Header.Get("X-Vault-Token") != "monitoring-token" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			response = map[string]interface{}{"data": map[string]interface{}{"healthy": false, "failure_tolerance": 0}}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	cl, err := vaultTarget{Address: srv.URL}.newClient()
+	require.NoError(t, err)
+
+	return &monitoredTarget{name: name, address: srv.URL, client: cl}
+}
+
+func TestMetricsExporterTargets(t *testing.T) {
+	active := newMonitoredTarget(t, "active", map[string]interface{}{"initialized": true})
+	active.tokenFile = filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(active.tokenFile, []byte("monitoring-token\n"), 0o600))
+	standby := newMonitoredTarget(t, "standby", map[string]interface{}{"initialized": true, "standby": true, "performance_standby": true})
+	sealed := newMonitoredTarget(t, "sealed", map[string]interface{}{"initialized": true, "sealed": true})
+	down := newMonitoredTarget(t, "down", nil)
+	down.client.SetAddress("http://127.0.0.1:1")
+	down.client.SetMaxRetries(0)
+
+	exporter := &prometheusExporter{Mode: "metrics", Monitored: []*monitoredTarget{active, standby, sealed, down}}
+
+	// Only the target with a token exports its raft storage health
+	expected := `
+# HELP vault_storage_raft_failure_tolerance Number of raft voters the Vault target can lose without losing quorum, only exported for raft storage with a token
+# TYPE vault_storage_raft_failure_tolerance gauge
+vault_storage_raft_failure_tolerance{target="active"} 0
+# HELP vault_storage_raft_healthy Is the raft storage of the Vault target healthy according to autopilot, only exported for raft storage with a token
+# TYPE vault_storage_raft_healthy gauge
+vault_storage_raft_healthy{target="active"} 0
+# HELP vault_sys_ha_role HA role of the Vault target: active, standby, performance_standby, sealed or uninitialized, always 1
+# TYPE vault_sys_ha_role gauge
+vault_sys_ha_role{role="active",target="active"} 1
+vault_sys_ha_role{role="performance_standby",target="standby"} 1
+vault_sys_ha_role{role="sealed",target="sealed"} 1
+# HELP vault_sys_leader Is the Vault node of the target the leader.
+# TYPE vault_sys_leader gauge
+vault_sys_leader{target="active"} 1
+vault_sys_leader{target="sealed"} 0
+vault_sys_leader{target="standby"} 0
+# HELP vault_sys_up Was the health endpoint of the Vault target reachable.
+# TYPE vault_sys_up gauge
+vault_sys_up{target="active"} 1
+vault_sys_up{target="down"} 0
+vault_sys_up{target="sealed"} 1
+vault_sys_up{target="standby"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(exporter, strings.NewReader(expected),
+		"vault_sys_up", "vault_sys_leader", "vault_sys_ha_role", "vault_storage_raft_healthy", "vault_storage_raft_failure_tolerance"))
+}
+
+func TestHARole(t *testing.T) {
+	assert.Equal(t, haRoleUninitialized, haRole(&api.HealthResponse{Sealed: true}))
+	assert.Equal(t, haRoleSealed, haRole(&api.HealthResponse{Initialized: true, Sealed: true}))
+	assert.Equal(t, haRoleStandby, haRole(&api.HealthResponse{Initialized: true, Standby: true}))
+	assert.Equal(t, haRoleActive, haRole(&api.HealthResponse{Initialized: true}))
+}
+
+func TestCheckDrift(t *testing.T) {
+	configDrift.Reset()
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "vault-config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte("drift: [a]\n"), 0o600))
+	overlay := filepath.Join(dir, "overlay.yml")
+	require.NoError(t, os.WriteFile(overlay, []byte("drift: [a, b]\n"), 0o600))
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	target := &monitoredTarget{name: "eu", vault: driftVault{}, overlays: []string{overlay}}
+	require.NoError(t, target.checkDrift(context.Background(), parser, []string{configFile, configFile}))
+	assert.Equal(t, float64(4), testutil.ToFloat64(configDrift.WithLabelValues("eu")))
+
+	assert.Error(t, target.checkDrift(context.Background(), parser, []string{filepath.Join(dir, "missing.yml")}))
+	assert.Equal(t, float64(4), testutil.ToFloat64(configDrift.WithLabelValues("eu")), "a failed check keeps the last drift")
+}
+
+func TestMonitoredTargetsForConfig(t *testing.T) {
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	targetsFile := filepath.Join(t.TempDir(), "targets.yml")
+	require.NoError(t, os.WriteFile(targetsFile, []byte(`
+targets:
+- name: vault-0
+  address: https://vault-0.vault-internal:8200
+  token: node-token
+- name: vault-1
+  address: https://vault-1.vault-internal:8200
+`), 0o600))
+
+	cfg := viper.New()
+	cfg.Set(cfgMetricsTargetsFile, targetsFile)
+	_, err = monitoredTargetsForConfig(context.Background(), cfg, parser, true)
+	assert.ErrorContains(t, err, "vault-1 needs a token")
+
+	cfg.Set(cfgMetricsTokenFile, "/var/run/secrets/vault/token")
+	targets, err := monitoredTargetsForConfig(context.Background(), cfg, parser, true)
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+	assert.Equal(t, "node-token", targets[0].token)
+	assert.Equal(t, "https://vault-1.vault-internal:8200", targets[1].address)
+	assert.Equal(t, "/var/run/secrets/vault/token", targets[1].tokenFile)
+	assert.NotNil(t, targets[1].vault)
+}
@@ -0,0 +1,274 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+// fakeVault answers the status calls of the exporter, the other methods are not implemented.
+type fakeVault struct {
+	bankvaults.Vault
+	sealed        bool
+	leader        bool
+	licenseExpiry time.Time
+	tokenExpiry   time.Time
+}
+
+func (f fakeVault) Sealed() (bool, error)    { return f.sealed, nil }
+func (f fakeVault) Leader() (bool, error)    { return f.leader, nil }
+func (f fakeVault) LicenseExpiry() time.Time { return f.licenseExpiry }
+func (f fakeVault) TokenExpiry() time.Time   { return f.tokenExpiry }
+
+func TestConfigureExporterConfigurationCounts(t *testing.T) {
+	successfulConfigurationsCount = 3
+	failedConfigurationsCount = 1
+	defer func() { successfulConfigurationsCount, failedConfigurationsCount = 0, 0 }()
+
+	exporter := &prometheusExporter{Mode: "configure"}
+
+	expected := `
+# HELP vault_config_failed Number of configurations files applied that failed
+# TYPE vault_config_failed gauge
+vault_config_failed 1
+# HELP vault_config_successful Number of successful configurations files applied
+# TYPE vault_config_successful gauge
+vault_config_successful 3
+`
+	require.NoError(t, testutil.CollectAndCompare(exporter, strings.NewReader(expected), "vault_config_successful", "vault_config_failed"))
+}
+
+func TestRecordSectionMetrics(t *testing.T) {
+	sectionRuns.Reset()
+	sectionItems.Reset()
+
+	recordSectionMetrics("eu", &bankvaults.Report{Sections: map[string]*bankvaults.ReportSection{
+		bankvaults.SectionPolicies: {Created: []string{"admin"}, Failed: map[string]string{"reader": "permission denied"}},
+		bankvaults.SectionAudit:    {Updated: []string{"file"}},
+	}})
+
+	expected := `
+# HELP vault_config_section_runs_total Number of times a config section was configured, by result
+# TYPE vault_config_section_runs_total counter
+vault_config_section_runs_total{result="failure",section="policies",target="eu"} 1
+vault_config_section_runs_total{result="success",section="audit",target="eu"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(sectionRuns, strings.NewReader(expected)))
+
+	// Skipped runs have no sections, nothing is recorded for them
+	recordSectionMetrics("eu", &bankvaults.Report{Skipped: true})
+	require.NoError(t, testutil.CollectAndCompare(sectionRuns, strings.NewReader(expected)))
+}
+
+func TestRecordSectionMetricsUnmanaged(t *testing.T) {
+	sectionUnmanaged.Reset()
+
+	recordSectionMetrics("eu", &bankvaults.Report{Sections: map[string]*bankvaults.ReportSection{
+		bankvaults.SectionPolicies: {Unmanaged: []string{"legacy"}},
+	}})
+	recordSectionMetrics("us", &bankvaults.Report{Sections: map[string]*bankvaults.ReportSection{
+		bankvaults.SectionAuth: {Unmanaged: []string{"userpass", "github"}},
+	}})
+
+	expected := `
+# HELP vault_config_section_unmanaged_resources Number of resources in Vault which are not in the config found by the last run, whether they are purged or not
+# TYPE vault_config_section_unmanaged_resources gauge
+vault_config_section_unmanaged_resources{section="auth",target="us"} 2
+vault_config_section_unmanaged_resources{section="policies",target="eu"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(sectionUnmanaged, strings.NewReader(expected)))
+
+	// A skipped run didn't check for unmanaged resources, the earlier count is dropped
+	recordSectionMetrics("eu", &bankvaults.Report{Skipped: true})
+
+	expected = `
+# HELP vault_config_section_unmanaged_resources Number of resources in Vault which are not in the config found by the last run, whether they are purged or not
+# TYPE vault_config_section_unmanaged_resources gauge
+vault_config_section_unmanaged_resources{section="auth",target="us"} 2
+`
+	require.NoError(t, testutil.CollectAndCompare(sectionUnmanaged, strings.NewReader(expected)))
+}
+
+func TestRecordConfigIdentity(t *testing.T) {
+	recordConfigIdentity("eu", &bankvaults.Report{ConfigHash: "aaa", ConfigVersion: "1"})
+	recordConfigIdentity("us", &bankvaults.Report{ConfigHash: "aaa", ConfigVersion: "1"})
+	recordConfigIdentity("eu", &bankvaults.Report{ConfigHash: "bbb", ConfigVersion: "2"})
+
+	expected := `
+# HELP vault_config_info Hash and version of the config last applied successfully, always 1
+# TYPE vault_config_info gauge
+vault_config_info{hash="aaa",target="us",version="1"} 1
+vault_config_info{hash="bbb",target="eu",version="2"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(configInfo, strings.NewReader(expected)))
+}
+
+func TestConfigureExporterTargets(t *testing.T) {
+	exporter := &prometheusExporter{Mode: "configure", Targets: []configureTarget{
+		{Name: "eu", Vault: fakeVault{leader: true, licenseExpiry: time.Unix(1800000000, 0)}},
+		{Name: "us", Vault: fakeVault{sealed: true}},
+	}}
+
+	expected := `
+# HELP vault_license_expiration_timestamp_seconds Expiration time of the applied Vault Enterprise license in seconds since the epoch
+# TYPE vault_license_expiration_timestamp_seconds gauge
+vault_license_expiration_timestamp_seconds{target="eu"} 1.8e+09
+# HELP vault_sys_leader Is the Vault node of the target the leader.
+# TYPE vault_sys_leader gauge
+vault_sys_leader{target="eu"} 1
+# HELP vault_sys_sealed Is the Vault target sealed.
+# TYPE vault_sys_sealed gauge
+vault_sys_sealed{target="eu"} 0
+vault_sys_sealed{target="us"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(exporter, strings.NewReader(expected), "vault_sys_sealed", "vault_sys_leader", "vault_license_expiration_timestamp_seconds"))
+}
+
+func TestConfigureExporterTokenTTL(t *testing.T) {
+	exporter := &prometheusExporter{Mode: "configure", Targets: []configureTarget{
+		{Name: "eu", Vault: fakeVault{tokenExpiry: time.Now().Add(-time.Minute)}},
+		{Name: "us", Vault: fakeVault{}},
+	}}
+
+	// An expired token reports no TTL left, a token which never expires no TTL at all
+	expected := `
+# HELP vault_config_token_ttl_seconds Remaining TTL of the token the configurer logged in to the target with, not exported for tokens which never expire
+# TYPE vault_config_token_ttl_seconds gauge
+vault_config_token_ttl_seconds{target="eu"} 0
+`
+	require.NoError(t, testutil.CollectAndCompare(exporter, strings.NewReader(expected), "vault_config_token_ttl_seconds"))
+}
+
+func TestRecordTokenRenewal(t *testing.T) {
+	tokenRenewals.Reset()
+
+	recordTokenRenewal("eu", &bankvaults.Report{TokenRenewal: bankvaults.TokenRenewed})
+	recordTokenRenewal("eu", &bankvaults.Report{TokenRenewal: bankvaults.TokenRenewFailed})
+	recordTokenRenewal("eu", &bankvaults.Report{})
+
+	expected := `
+# HELP vault_config_token_renewals_total Number of renewals of the token the configurer logs in to a target with, by result
+# TYPE vault_config_token_renewals_total counter
+vault_config_token_renewals_total{result="failure",target="eu"} 1
+vault_config_token_renewals_total{result="success",target="eu"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(tokenRenewals, strings.NewReader(expected)))
+}
+
+func TestRecordErrorCategories(t *testing.T) {
+	configErrors.Reset()
+
+	recordErrorCategories("eu", &bankvaults.Report{ErrorCategories: map[string]int{bankvaults.ErrorPermissionDenied: 2}})
+	recordErrorCategories("eu", &bankvaults.Report{ErrorCategories: map[string]int{bankvaults.ErrorSealed: 1, bankvaults.ErrorPermissionDenied: 1}})
+	recordErrorCategories("eu", nil)
+
+	expected := `
+# HELP vault_config_errors_total Number of Vault API errors of the configure runs, by category
+# TYPE vault_config_errors_total counter
+vault_config_errors_total{category="permission_denied",target="eu"} 3
+vault_config_errors_total{category="sealed",target="eu"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(configErrors, strings.NewReader(expected)))
+}
+
+func TestUnsealLastSuccess(t *testing.T) {
+	unsealLastSuccess.Set(0)
+
+	require.NoError(t, unseal(context.Background(), fakeVault{sealed: false}, nil))
+
+	assert.InDelta(t, float64(time.Now().Unix()), testutil.ToFloat64(unsealLastSuccess), 5)
+}
+
+func TestExporterInstanceLabels(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "vault")
+
+	cfg := viper.New()
+	cfg.Set(cfgMetricsClusterName, "eu-west")
+	labels := instanceLabelsForConfig(cfg, "https://vault:8200")
+	assert.Equal(t, prometheus.Labels{"vault_address": "https://vault:8200", "cluster": "eu-west", "namespace": "vault"}, labels)
+
+	registry, err := newExporterRegistry(&prometheusExporter{Mode: "unseal", Vault: fakeVault{leader: true}, Labels: labels})
+	require.NoError(t, err)
+
+	expected := `
+# HELP vault_sys_leader Is the Vault node the leader.
+# TYPE vault_sys_leader gauge
+vault_sys_leader{cluster="eu-west",namespace="vault",vault_address="https://vault:8200"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "vault_sys_leader"))
+}
+
+func TestExporterLeaderElection(t *testing.T) {
+	leaderElectionLeader.Set(1)
+	defer leaderElectionLeader.Set(0)
+
+	registry, err := newExporterRegistry(&prometheusExporter{Mode: "configure"})
+	require.NoError(t, err)
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		assert.NotEqual(t, "vault_leader_election_leader", family.GetName())
+	}
+
+	registry, err = newExporterRegistry(&prometheusExporter{Mode: "unseal", Vault: fakeVault{}, LeaderElection: true})
+	require.NoError(t, err)
+
+	expected := `
+# HELP vault_leader_election_leader Whether this replica holds the leader election Lease and is the active one (1) or is standing by (0)
+# TYPE vault_leader_election_leader gauge
+vault_leader_election_leader 1
+`
+	require.NoError(t, testutil.GatherAndCompare(registry, strings.NewReader(expected), "vault_leader_election_leader"))
+}
+
+func TestMetricsServerBasicAuth(t *testing.T) {
+	require.Error(t, metricsServer{BasicAuthUsername: "prometheus"}.validate())
+	require.Error(t, metricsServer{BasicAuthPassword: "secret"}.validate())
+	require.NoError(t, metricsServer{BasicAuthUsername: "prometheus", BasicAuthPassword: "secret"}.validate())
+
+	server := metricsServer{BasicAuthUsername: "prometheus", BasicAuthPassword: "secret"}
+	handler := server.authenticate(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tt := range []struct {
+		username, password string
+		status             int
+	}{
+		{"prometheus", "secret", http.StatusOK},
+		{"prometheus", "", http.StatusUnauthorized},
+		{"prometheus", "wrong", http.StatusUnauthorized},
+		{"other", "secret", http.StatusUnauthorized},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.SetBasicAuth(tt.username, tt.password)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, tt.status, rec.Code, "%s:%s", tt.username, tt.password)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+
+	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+)
+
+const (
+	cfgNotifyWebhookURL      = "notify-webhook-url"
+	cfgNotifyWebhookHeaders  = "notify-webhook-headers"
+	cfgNotifySlackWebhookURL = "notify-slack-webhook-url"
+	cfgNotifySlackChannel    = "notify-slack-channel"
+	cfgNotifyEvents          = "notify-events"
+)
+
+// notifierForConfig returns the notifier configured by the notify flags, or nil if none is configured.
+func notifierForConfig(cfg *viper.Viper) notify.Notifier {
+	var notifiers []notify.Notifier
+
+	if url := cfg.GetString(cfgNotifyWebhookURL); url != "" {
+		notifiers = append(notifiers, notify.NewWebhook(url, cfg.GetStringMapString(cfgNotifyWebhookHeaders)))
+	}
+
+	if url := cfg.GetString(cfgNotifySlackWebhookURL); url != "" {
+		notifiers = append(notifiers, notify.NewSlack(url, cfg.GetString(cfgNotifySlackChannel)))
+	}
+
+	if cfg.GetBool(cfgKubernetesEvents) {
+		notifiers = append(notifiers, k8sEventNotifierForConfig(cfg))
+	}
+
+	if len(notifiers) == 0 {
+		return nil
+	}
+
+	return notify.Filter(notify.New(notifiers...), notifyEventsForConfig(cfg))
+}
+
+// lifecycleNotifier records the init and unseal of Vault as Kubernetes Events, the other
+// notification channels are only about configure.
+var lifecycleNotifier = sync.OnceValue(func() notify.Notifier {
+	if !c.GetBool(cfgKubernetesEvents) {
+		return nil
+	}
+
+	return notify.Filter(k8sEventNotifierForConfig(c), notifyEventsForConfig(c))
+})
+
+// notifyLifecycle records an init or unseal of Vault, if Kubernetes Events are enabled.
+func notifyLifecycle(ctx context.Context, eventType notify.EventType, message string) {
+	notify.Send(ctx, lifecycleNotifier(), notify.Event{Type: eventType, Message: message})
+}
+
+// k8sEventNotifierForConfig returns the notifier recording Kubernetes Events configured by the flags.
+func k8sEventNotifierForConfig(cfg *viper.Viper) notify.Notifier {
+	notifier, err := newK8sEventNotifier(cfg.GetString(cfgKubernetesEventsObject))
+	if err != nil {
+		slog.Error(fmt.Sprintf("error creating kubernetes event notifier: %s", err.Error()))
+		os.Exit(1)
+	}
+
+	return notifier
+}
+
+func notifyEventsForConfig(cfg *viper.Viper) []notify.EventType {
+	var events []notify.EventType
+	for _, event := range cfg.GetStringSlice(cfgNotifyEvents) {
+		events = append(events, notify.EventType(event))
+	}
+
+	return events
+}
+
+func init() {
+	defaultEvents := make([]string, 0, len(notify.EventTypes))
+	for _, event := range notify.EventTypes {
+		defaultEvents = append(defaultEvents, string(event))
+	}
+
+	// Shared by unseal (initialized and unsealed, only recorded as Kubernetes Events) and configure (the rest of the events)
+	configStringVar(rootCmd, cfgNotifyWebhookURL, "", "URL of a webhook to post JSON notifications to")
+	configStringMapVar(rootCmd, cfgNotifyWebhookHeaders, map[string]string{}, "Additional HTTP headers to send with webhook notifications")
+	configStringVar(rootCmd, cfgNotifySlackWebhookURL, "", "URL of a Slack incoming webhook to post notifications to")
+	configStringVar(rootCmd, cfgNotifySlackChannel, "", "Slack channel to post notifications to, defaults to the webhook's channel")
+	configBoolVar(rootCmd, cfgKubernetesEvents, false, "Record notifications as Kubernetes Events when running in a pod")
+	configStringVar(rootCmd, cfgKubernetesEventsObject, "", "Object to record the Kubernetes Events on as kind/name (Pod, StatefulSet or Deployment), defaults to the pod bank-vaults runs in")
+	configStringSliceVar(rootCmd, cfgNotifyEvents, defaultEvents, fmt.Sprintf("Events to send notifications about, any of: %v", defaultEvents))
+}
@@ -0,0 +1,189 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"emperror.dev/errors"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/ramizpolic/multiparser"
+	"github.com/spf13/cast"
+)
+
+const cfgOverlays = "overlays"
+
+// overlayPatchKey marks an overlay holding a list of JSON patch (RFC 6902) operations
+// instead of a document to merge into the base config.
+const overlayPatchKey = "patch"
+
+// overlayListKeys are the fields identifying the items of config lists, in order of precedence.
+var overlayListKeys = []string{"name", "path", "type"}
+
+// applyOverlays returns the base config with the overlay files applied in order.
+func applyOverlays(parser multiparser.Parser, base map[string]interface{}, overlayFiles []string) (map[string]interface{}, error) {
+	config := base
+	for _, overlayFile := range overlayFiles {
+		overlayConfig, err := readConfiguration(parser, overlayFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading overlay %s", overlayFile)
+		}
+		overlay := overlayConfig.Data
+
+		if patch, ok := overlay[overlayPatchKey]; ok && len(overlay) == 1 {
+			config, err = applyJSONPatch(config, patch)
+		} else {
+			config = cast.ToStringMap(mergeOverlay(config, overlay))
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error applying overlay %s", overlayFile)
+		}
+	}
+
+	return config, nil
+}
+
+func applyJSONPatch(config map[string]interface{}, operations interface{}) (map[string]interface{}, error) {
+	patchJSON, err := json.Marshal(normalizeOverlay(operations))
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling patch")
+	}
+
+	patch, err := jsonpatch.DecodePatch(patchJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding patch")
+	}
+
+	configJSON, err := json.Marshal(normalizeOverlay(config))
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling config")
+	}
+
+	patchedJSON, err := patch.Apply(configJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "error applying patch")
+	}
+
+	var patched map[string]interface{}
+	if err := json.Unmarshal(patchedJSON, &patched); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling patched config")
+	}
+
+	return patched, nil
+}
+
+// mergeOverlay merges the overlay into the base with strategic merge semantics: maps are merged
+// recursively, null values delete keys, items of lists of maps are merged by their name, path or type,
+// and every other value is replaced.
+func mergeOverlay(base, overlay interface{}) interface{} {
+	baseMap, baseIsMap := asOverlayMap(base)
+	overlayMap, overlayIsMap := asOverlayMap(overlay)
+	if baseIsMap && overlayIsMap {
+		merged := make(map[string]interface{}, len(baseMap))
+		for k, v := range baseMap {
+			merged[k] = v
+		}
+		for k, v := range overlayMap {
+			if v == nil {
+				delete(merged, k)
+				continue
+			}
+			merged[k] = mergeOverlay(merged[k], v)
+		}
+
+		return merged
+	}
+
+	baseList, baseIsList := base.([]interface{})
+	overlayList, overlayIsList := overlay.([]interface{})
+	if baseIsList && overlayIsList {
+		return mergeOverlayList(baseList, overlayList)
+	}
+
+	return overlay
+}
+
+func mergeOverlayList(base, overlay []interface{}) []interface{} {
+	merged := append([]interface{}{}, base...)
+
+overlayItems:
+	for _, overlayItem := range overlay {
+		overlayItemMap, ok := asOverlayMap(overlayItem)
+		if !ok {
+			// Lists of scalars are replaced as a whole
+			return overlay
+		}
+
+		for _, key := range overlayListKeys {
+			id, ok := overlayItemMap[key]
+			if !ok {
+				continue
+			}
+
+			for i, baseItem := range merged {
+				// The ids can be maps or lists, which can't be compared with ==
+				if baseItemMap, ok := asOverlayMap(baseItem); ok && reflect.DeepEqual(baseItemMap[key], id) {
+					merged[i] = mergeOverlay(baseItem, overlayItem)
+					continue overlayItems
+				}
+			}
+
+			break
+		}
+
+		merged = append(merged, overlayItem)
+	}
+
+	return merged
+}
+
+func asOverlayMap(v interface{}) (map[string]interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v, true
+	case map[interface{}]interface{}:
+		return cast.ToStringMap(v), true
+	default:
+		return nil, false
+	}
+}
+
+// normalizeOverlay converts the maps decoded from YAML to ones which can be marshaled to JSON.
+func normalizeOverlay(v interface{}) interface{} {
+	if m, ok := asOverlayMap(v); ok {
+		normalized := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			normalized[k] = normalizeOverlay(v)
+		}
+
+		return normalized
+	}
+
+	if l, ok := v.([]interface{}); ok {
+		normalized := make([]interface{}, 0, len(l))
+		for _, v := range l {
+			normalized = append(normalized, normalizeOverlay(v))
+		}
+
+		return normalized
+	}
+
+	return v
+}
+
+func init() {
+	configStringSliceVar(configureCmd, cfgOverlays, []string{}, "Overlay files applied in order to the config of the Vault configured by flags: strategic merge documents, or JSON patch operations under 'patch'")
+}
@@ -0,0 +1,123 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeOverlay(t *testing.T) {
+	base := map[string]interface{}{
+		"auth": []interface{}{
+			map[string]interface{}{
+				"type": "kubernetes",
+				"config": map[string]interface{}{
+					"kubernetes_host": "https://base:443",
+					"issuer":          "base",
+				},
+			},
+		},
+		"policies": []interface{}{
+			map[string]interface{}{"name": "admin", "rules": "base"},
+		},
+		"purgeUnmanagedConfig": map[string]interface{}{"enabled": true},
+	}
+
+	overlay := map[string]interface{}{
+		"auth": []interface{}{
+			map[string]interface{}{
+				"type": "kubernetes",
+				"config": map[string]interface{}{
+					"kubernetes_host": "https://prod:443",
+					"issuer":          nil,
+				},
+			},
+		},
+		"policies": []interface{}{
+			map[string]interface{}{"name": "prod", "rules": "prod"},
+		},
+		"purgeUnmanagedConfig": nil,
+	}
+
+	merged := mergeOverlay(base, overlay)
+
+	assert.Equal(t, map[string]interface{}{
+		"auth": []interface{}{
+			map[string]interface{}{
+				"type":   "kubernetes",
+				"config": map[string]interface{}{"kubernetes_host": "https://prod:443"},
+			},
+		},
+		"policies": []interface{}{
+			map[string]interface{}{"name": "admin", "rules": "base"},
+			map[string]interface{}{"name": "prod", "rules": "prod"},
+		},
+	}, merged)
+}
+
+func TestApplyJSONPatch(t *testing.T) {
+	config := map[string]interface{}{
+		"auth": []interface{}{
+			map[string]interface{}{"type": "kubernetes", "roles": []interface{}{"a"}},
+		},
+	}
+
+	patched, err := applyJSONPatch(config, []interface{}{
+		map[string]interface{}{"op": "replace", "path": "/auth/0/roles/0", "value": "b"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"auth": []interface{}{
+			map[string]interface{}{"type": "kubernetes", "roles": []interface{}{"b"}},
+		},
+	}, patched)
+}
+
+func TestMergeOverlayListUncomparableIDs(t *testing.T) {
+	base := []interface{}{
+		map[string]interface{}{"path": map[string]interface{}{"a": "b"}, "value": "base"},
+	}
+	overlay := []interface{}{
+		map[string]interface{}{"path": map[string]interface{}{"a": "b"}, "value": "overlay"},
+		map[string]interface{}{"path": []interface{}{"c"}, "value": "new"},
+	}
+
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"path": map[string]interface{}{"a": "b"}, "value": "overlay"},
+		map[string]interface{}{"path": []interface{}{"c"}, "value": "new"},
+	}, mergeOverlayList(base, overlay))
+}
+
+func TestApplyOverlaysInvalidFile(t *testing.T) {
+	overlayFile := filepath.Join(t.TempDir(), "overlay.yml")
+	require.NoError(t, os.WriteFile(overlayFile, []byte("policies: [\n"), 0o600))
+
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	_, err = applyOverlays(parser, map[string]interface{}{}, []string{overlayFile})
+	assert.ErrorContains(t, err, "error reading overlay "+overlayFile)
+
+	_, err = applyOverlays(parser, map[string]interface{}{}, []string{filepath.Join(t.TempDir(), "missing.yml")})
+	assert.ErrorContains(t, err, "error reading overlay")
+}
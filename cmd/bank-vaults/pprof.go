@@ -0,0 +1,53 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"strconv"
+)
+
+const (
+	cfgEnablePprof = "enable-pprof"
+	cfgPprofPort   = "pprof-port"
+)
+
+// servePprof serves the net/http/pprof endpoints on localhost only, they are reachable
+// with kubectl port-forward but not exposed by the pod.
+func servePprof(port int) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	address := net.JoinHostPort("localhost", strconv.Itoa(port))
+	slog.Info(fmt.Sprintf("pprof endpoints enabled: %s/debug/pprof/", address))
+	go func() {
+		if err := http.ListenAndServe(address, mux); err != nil { //nolint:gosec
+			slog.Error(fmt.Sprintf("error serving pprof endpoints: %s", err.Error()))
+		}
+	}()
+}
+
+func init() {
+	configBoolVar(rootCmd, cfgEnablePprof, false, "Serve the pprof profiling endpoints on localhost")
+	configIntVar(rootCmd, cfgPprofPort, 6060, "Localhost port of the pprof profiling endpoints")
+}
@@ -0,0 +1,69 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/spf13/viper"
+)
+
+const (
+	cfgPushgatewayURL      = "metrics-pushgateway-url"
+	cfgPushgatewayJob      = "metrics-pushgateway-job"
+	cfgPushgatewayInstance = "metrics-pushgateway-instance"
+)
+
+// pushMetrics pushes the metrics of a one-shot configure run to a Prometheus Pushgateway,
+// as the pod may be gone before it could be scraped.
+func pushMetrics(ctx context.Context, cfg *viper.Viper, exporter *prometheusExporter) error {
+	url := cfg.GetString(cfgPushgatewayURL)
+	if url == "" {
+		return nil
+	}
+
+	instance := cfg.GetString(cfgPushgatewayInstance)
+	if instance == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrap(err, "error getting hostname for the pushgateway instance label")
+		}
+		instance = hostname
+	}
+
+	registry, err := newExporterRegistry(exporter)
+	if err != nil {
+		return err
+	}
+
+	err = push.New(url, cfg.GetString(cfgPushgatewayJob)).
+		Grouping("instance", instance).
+		Gatherer(registry).
+		PushContext(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "error pushing metrics to %s", url)
+	}
+
+	return nil
+}
+
+func init() {
+	configStringVar(configureCmd, cfgPushgatewayURL, "", "Push the metrics of a --once run to this Prometheus Pushgateway")
+	configStringVar(configureCmd, cfgPushgatewayJob, "bank-vaults-configure", "Job label of the metrics pushed to the Pushgateway")
+	configStringVar(configureCmd, cfgPushgatewayInstance, "", "Instance label of the metrics pushed to the Pushgateway, defaults to the hostname")
+}
@@ -0,0 +1,53 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushMetrics(t *testing.T) {
+	var path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	cfg := viper.New()
+	cfg.Set(cfgPushgatewayURL, srv.URL)
+	cfg.Set(cfgPushgatewayJob, "vault-configure")
+	cfg.Set(cfgPushgatewayInstance, "ci")
+
+	exporter := &prometheusExporter{Mode: "configure", Targets: []configureTarget{{Name: "eu", Vault: fakeVault{leader: true}}}}
+	require.NoError(t, pushMetrics(context.Background(), cfg, exporter))
+
+	assert.Equal(t, "/metrics/job/vault-configure/instance/ci", path)
+	assert.NotEmpty(t, body)
+}
+
+func TestPushMetricsDisabled(t *testing.T) {
+	require.NoError(t, pushMetrics(context.Background(), viper.New(), &prometheusExporter{Mode: "configure"}))
+}
@@ -0,0 +1,145 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgRaftHeadlessService = "raft-headless-service"
+	cfgRaftReplicas        = "raft-replicas"
+	cfgRaftScheme          = "raft-scheme"
+	cfgRaftPort            = "raft-port"
+	cfgRaftClusterDomain   = "raft-cluster-domain"
+)
+
+// raftPeers addresses the raft peers of a Vault StatefulSet by the DNS names its headless service
+// gives the pods: <statefulset>-<ordinal>.<service>.<namespace>.svc.<cluster domain>.
+type raftPeers struct {
+	statefulSet   string
+	ordinal       int
+	service       string
+	namespace     string
+	replicas      int
+	scheme        string
+	port          int
+	clusterDomain string
+}
+
+// raftPeersForConfig returns the raft peers of the pod bank-vaults runs in,
+// nil if no headless service is configured.
+func raftPeersForConfig(cfg *viper.Viper) (*raftPeers, error) {
+	service := cfg.GetString(cfgRaftHeadlessService)
+	if service == "" {
+		return nil, nil
+	}
+
+	statefulSet, ordinal, err := parseStatefulSetPodName(os.Getenv("POD_NAME"))
+	if err != nil {
+		return nil, err
+	}
+
+	peers := &raftPeers{
+		statefulSet:   statefulSet,
+		ordinal:       ordinal,
+		service:       service,
+		namespace:     podNamespace(),
+		replicas:      cfg.GetInt(cfgRaftReplicas),
+		scheme:        cfg.GetString(cfgRaftScheme),
+		port:          cfg.GetInt(cfgRaftPort),
+		clusterDomain: cfg.GetString(cfgRaftClusterDomain),
+	}
+	if peers.replicas < 1 {
+		return nil, errors.Errorf("invalid number of raft replicas: %d", peers.replicas)
+	}
+
+	return peers, nil
+}
+
+// parseStatefulSetPodName splits the name of a StatefulSet pod into the name of the StatefulSet and the ordinal of the pod.
+func parseStatefulSetPodName(podName string) (string, int, error) {
+	i := strings.LastIndex(podName, "-")
+	if i <= 0 {
+		return "", 0, errors.Errorf("pod name '%s' is not the name of a statefulset pod, POD_NAME must be set", podName)
+	}
+
+	ordinal, err := strconv.Atoi(podName[i+1:])
+	if err != nil || ordinal < 0 {
+		return "", 0, errors.Errorf("pod name '%s' is not the name of a statefulset pod, POD_NAME must be set", podName)
+	}
+
+	return podName[:i], ordinal, nil
+}
+
+// address returns the API address of the peer with the ordinal.
+func (p *raftPeers) address(ordinal int) string {
+	host := fmt.Sprintf("%s-%d.%s.%s.svc", p.statefulSet, ordinal, p.service, p.namespace)
+	if p.clusterDomain != "" {
+		host += "." + p.clusterDomain
+	}
+
+	return fmt.Sprintf("%s://%s:%d", p.scheme, host, p.port)
+}
+
+// leaderAddresses returns the API addresses of the other peers, the first pod first,
+// since it initializes the cluster.
+func (p *raftPeers) leaderAddresses() []string {
+	addresses := make([]string, 0, p.replicas)
+	for ordinal := 0; ordinal < p.replicas; ordinal++ {
+		if ordinal != p.ordinal {
+			addresses = append(addresses, p.address(ordinal))
+		}
+	}
+
+	return addresses
+}
+
+// joinRaftPeers joins Vault to the raft cluster through the first peer that lets it join.
+func joinRaftPeers(v bankvaults.Vault, addresses []string) error {
+	var errs error
+	for _, address := range addresses {
+		err := joinRaft(v, address)
+		if err == nil {
+			return nil
+		}
+
+		slog.Warn(fmt.Sprintf("error joining raft cluster through %s: %s", address, err.Error()))
+		errs = errors.Append(errs, errors.Wrapf(err, "leader %s", address))
+	}
+
+	if errs == nil {
+		return errors.New("no raft peer to join")
+	}
+
+	return errs
+}
+
+func init() {
+	configStringVar(unsealCmd, cfgRaftHeadlessService, "", "Headless service of the Vault StatefulSet, the raft leader addresses are generated from the DNS names of its pods unless "+cfgRaftLeaderAddress+" is set")
+	configIntVar(unsealCmd, cfgRaftReplicas, 3, "Number of replicas of the Vault StatefulSet, used with "+cfgRaftHeadlessService)
+	configStringVar(unsealCmd, cfgRaftScheme, "https", "Scheme of the generated raft leader addresses")
+	configIntVar(unsealCmd, cfgRaftPort, 8200, "API port of the generated raft leader addresses")
+	configStringVar(unsealCmd, cfgRaftClusterDomain, "cluster.local", "Cluster domain of the generated raft leader addresses, empty to leave it off")
+}
@@ -0,0 +1,68 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatefulSetPodName(t *testing.T) {
+	statefulSet, ordinal, err := parseStatefulSetPodName("vault-ha-12")
+	require.NoError(t, err)
+	assert.Equal(t, "vault-ha", statefulSet)
+	assert.Equal(t, 12, ordinal)
+
+	for _, podName := range []string{"", "vault", "vault-", "-1", "vault-abc"} {
+		_, _, err := parseStatefulSetPodName(podName)
+		assert.Error(t, err, podName)
+	}
+}
+
+func TestRaftPeers(t *testing.T) {
+	t.Setenv("POD_NAME", "vault-1")
+	t.Setenv("POD_NAMESPACE", "vault")
+
+	cfg := viper.New()
+	peers, err := raftPeersForConfig(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, peers)
+
+	cfg.Set(cfgRaftHeadlessService, "vault-internal")
+	cfg.Set(cfgRaftReplicas, 3)
+	cfg.Set(cfgRaftScheme, "https")
+	cfg.Set(cfgRaftPort, 8200)
+	cfg.Set(cfgRaftClusterDomain, "cluster.local")
+	peers, err = raftPeersForConfig(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"https://vault-0.vault-internal.vault.svc.cluster.local:8200",
+		"https://vault-2.vault-internal.vault.svc.cluster.local:8200",
+	}, peers.leaderAddresses())
+
+	assert.True(t, unsealCfg{raftPeers: peers}.secondary())
+	peers.ordinal = 0
+	assert.False(t, unsealCfg{raftPeers: peers}.secondary())
+
+	peers.clusterDomain = ""
+	assert.Equal(t, "https://vault-1.vault-internal.vault.svc:8200", peers.address(1))
+
+	cfg.Set(cfgRaftReplicas, 0)
+	_, err = raftPeersForConfig(cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/spf13/viper"
+	"golang.org/x/time/rate"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgTargetRateLimit          = "target-rate-limit"
+	cfgTargetRateBurst          = "target-rate-burst"
+	cfgTargetRetryBudget        = "target-retry-budget"
+	cfgTargetRetryBudgetReserve = "target-retry-budget-reserve"
+)
+
+var (
+	// vaultRateLimiter is shared by the clients of the Vault to operate on, nil if the requests are not limited.
+	vaultRateLimiter *rate.Limiter
+	// vaultRetryBudget is shared by the clients of the Vault to operate on and configure, nil if the retries are not capped.
+	vaultRetryBudget *bankvaults.RetryBudget
+)
+
+// setupRateLimit sets up the rate limiter and the retry budget of the Vault clients created afterwards.
+func setupRateLimit(cfg *viper.Viper) error {
+	vaultRateLimiter, vaultRetryBudget = nil, nil
+
+	if limit := cfg.GetFloat64(cfgTargetRateLimit); limit > 0 {
+		burst := cfg.GetInt(cfgTargetRateBurst)
+		if burst <= 0 {
+			burst = int(math.Ceil(limit))
+		}
+		vaultRateLimiter = rate.NewLimiter(rate.Limit(limit), burst)
+	} else if limit < 0 {
+		return errors.Errorf("invalid --%s: %v, it must not be negative", cfgTargetRateLimit, limit)
+	}
+
+	if ratio := cfg.GetFloat64(cfgTargetRetryBudget); ratio > 0 {
+		reserve := cfg.GetInt(cfgTargetRetryBudgetReserve)
+		if reserve < 0 {
+			return errors.Errorf("invalid --%s: %d, it must not be negative", cfgTargetRetryBudgetReserve, reserve)
+		}
+		vaultRetryBudget = bankvaults.NewRetryBudget(ratio, reserve)
+	} else if ratio < 0 {
+		return errors.Errorf("invalid --%s: %v, it must not be negative", cfgTargetRetryBudget, ratio)
+	}
+
+	return nil
+}
+
+// rateLimitedTransport waits for the limiter before sending each request, retries and
+// requests failed over to another address included, and deposits them into the retry budget.
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *rate.Limiter
+	budget  *bankvaults.RetryBudget
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter != nil {
+		if err := t.limiter.Wait(req.Context()); err != nil {
+			return nil, errors.Wrap(err, "error waiting for the vault request rate limit")
+		}
+	}
+	t.budget.Deposit()
+
+	return t.base.RoundTrip(req)
+}
+
+// withRateLimit limits the requests of the client config and caps the retries of the Vault API client,
+// the default retry policy is used unless the budget allows the retry.
+func withRateLimit(config *api.Config, limiter *rate.Limiter, budget *bankvaults.RetryBudget) {
+	if limiter == nil && budget == nil {
+		return
+	}
+
+	config.HttpClient.Transport = &rateLimitedTransport{base: config.HttpClient.Transport, limiter: limiter, budget: budget}
+
+	if budget != nil {
+		checkRetry := config.CheckRetry
+		if checkRetry == nil {
+			checkRetry = api.DefaultRetryPolicy
+		}
+		config.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+			retry, checkErr := checkRetry(ctx, resp, err)
+			if retry && !budget.Withdraw() {
+				slog.Warn("vault request failed, not retrying it since the retry budget is exhausted")
+				return false, checkErr
+			}
+
+			return retry, checkErr
+		}
+	}
+}
+
+func init() {
+	configFloat64Var(rootCmd, cfgTargetRateLimit, 0, "Maximum number of requests per second sent to the Vault to operate on, shared by all its clients, 0 for no limit")
+	configIntVar(rootCmd, cfgTargetRateBurst, 0, "Number of requests sent to the Vault to operate on at once above the "+cfgTargetRateLimit+", defaults to the rate limit")
+	configFloat64Var(rootCmd, cfgTargetRetryBudget, 0, "Number of retries per request sent to the Vault to operate on, shared by all its clients and the config retries, 0 for no budget")
+	configIntVar(rootCmd, cfgTargetRetryBudgetReserve, 10, "Number of retries of the "+cfgTargetRetryBudget+" available at once")
+}
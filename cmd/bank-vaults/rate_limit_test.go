@@ -0,0 +1,57 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	config := api.DefaultConfig()
+	config.Address = server.URL
+	config.MaxRetries = 5
+	config.MinRetryWait = time.Millisecond
+	config.MaxRetryWait = time.Millisecond
+	withRateLimit(config, rate.NewLimiter(rate.Every(50*time.Millisecond), 1), bankvaults.NewRetryBudget(0, 1))
+
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Logical().Read("secret/data/app")
+	assert.Error(t, err)
+
+	// The budget allows a single retry, which waits for the limiter
+	assert.Equal(t, int32(2), requests.Load())
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+}
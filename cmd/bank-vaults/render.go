@@ -0,0 +1,136 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"emperror.dev/errors"
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgRenderOverlays    = "render-overlays"
+	cfgRenderFormat      = "render-format"
+	cfgRenderShowSecrets = "render-show-secrets"
+)
+
+const (
+	cfgRenderFormatValueYAML = "yaml"
+	cfgRenderFormatValueJSON = "json"
+)
+
+type renderCfg struct {
+	overlays    []string
+	format      string
+	showSecrets bool
+	// fields masked unless showSecrets is set
+	redactFields []string
+}
+
+var renderCmd = &cobra.Command{
+	Use:   "render [config files]",
+	Short: "Print the external configuration as configure would apply it",
+	Long: `This command reads the external configuration files like configure does, substitutes
+the environment variables, renders the templates and applies the overlays, then prints
+the result without contacting Vault.
+
+The values of the fields listed by --log-redact-fields are masked unless --render-show-secrets
+is set. Renders the default config file if no files are given.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{bankvaults.DefaultConfigFile}
+		}
+
+		renderConfig := renderCfg{
+			overlays:     c.GetStringSlice(cfgRenderOverlays),
+			format:       c.GetString(cfgRenderFormat),
+			showSecrets:  c.GetBool(cfgRenderShowSecrets),
+			redactFields: c.GetStringSlice(cfgLogRedactFields),
+		}
+
+		parser, err := multiparser.New(parser.JSON, parser.YAML)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error file parsers: %v", err))
+			os.Exit(1)
+		}
+
+		if err := renderConfigurations(os.Stdout, parser, renderConfig, args); err != nil {
+			slog.Error(fmt.Sprintf("error rendering configuration: %s", err.Error()))
+			os.Exit(1)
+		}
+	},
+}
+
+// renderConfigurations prints the config files after templating and overlays, one document each.
+func renderConfigurations(w io.Writer, parser multiparser.Parser, renderConfig renderCfg, vaultConfigFiles []string) error {
+	for i, vaultConfigFile := range vaultConfigFiles {
+		config, err := readConfiguration(parser, vaultConfigFile)
+		if err != nil {
+			return errors.Wrapf(err, "error reading %s", vaultConfigFile)
+		}
+
+		data, err := applyOverlays(parser, config.Data, renderConfig.overlays)
+		if err != nil {
+			return errors.Wrapf(err, "error applying overlays to %s", vaultConfigFile)
+		}
+
+		rendered := normalizeOverlay(data)
+		if !renderConfig.showSecrets {
+			rendered = bankvaults.RedactedPayload(rendered, renderConfig.redactFields).LogValue().Any()
+		}
+
+		switch renderConfig.format {
+		case cfgRenderFormatValueYAML:
+			out, err := yaml.Marshal(rendered)
+			if err != nil {
+				return errors.Wrapf(err, "error marshaling %s", vaultConfigFile)
+			}
+			if i > 0 {
+				fmt.Fprintln(w, "---")
+			}
+			fmt.Fprintf(w, "# %s\n%s", config.Path, out)
+
+		case cfgRenderFormatValueJSON:
+			out, err := json.MarshalIndent(rendered, "", "  ")
+			if err != nil {
+				return errors.Wrapf(err, "error marshaling %s", vaultConfigFile)
+			}
+			fmt.Fprintf(w, "%s\n", out)
+
+		default:
+			return errors.Errorf("unsupported format: '%s'", renderConfig.format)
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	configStringSliceVar(renderCmd, cfgRenderOverlays, []string{}, "Overlay files applied in order to the rendered config, like --"+cfgOverlays+" of configure")
+	configStringVar(renderCmd, cfgRenderFormat, cfgRenderFormatValueYAML, fmt.Sprintf("Output format: '%s' or '%s'", cfgRenderFormatValueYAML, cfgRenderFormatValueJSON))
+	configBoolVar(renderCmd, cfgRenderShowSecrets, false, "Print the values of the fields masked by default")
+
+	rootCmd.AddCommand(renderCmd)
+}
@@ -0,0 +1,76 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+func TestRenderConfigurations(t *testing.T) {
+	t.Setenv("LDAP_URL", "ldap://ldap.example.com")
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "vault-config.yml")
+	require.NoError(t, os.WriteFile(configFile, []byte(`
+auth:
+  - type: ldap
+    config:
+      url: ${ .Env.LDAP_URL }
+      bindpass: hunter2
+`), 0o600))
+	overlayFile := filepath.Join(dir, "prod.yml")
+	require.NoError(t, os.WriteFile(overlayFile, []byte(`
+auth:
+  - type: ldap
+    config:
+      userdn: ou=users
+`), 0o600))
+
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	renderConfig := renderCfg{
+		overlays:     []string{overlayFile},
+		format:       cfgRenderFormatValueYAML,
+		redactFields: bankvaults.DefaultRedactedFields,
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, renderConfigurations(&out, parser, renderConfig, []string{configFile}))
+	assert.Equal(t, "# "+configFile+`
+auth:
+- config:
+    bindpass: <redacted>
+    url: ldap://ldap.example.com
+    userdn: ou=users
+  type: ldap
+`, out.String())
+
+	renderConfig.showSecrets = true
+	renderConfig.format = cfgRenderFormatValueJSON
+	out.Reset()
+	require.NoError(t, renderConfigurations(&out, parser, renderConfig, []string{configFile}))
+	assert.Contains(t, out.String(), `"bindpass": "hunter2"`)
+}
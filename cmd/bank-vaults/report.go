@@ -0,0 +1,69 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"emperror.dev/errors"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	reportOutputStdout = "stdout"
+	reportOutputKV     = "kv"
+
+	// keyApplyReport is the key store entry holding the report of the last configure run
+	keyApplyReport = "vault-config-report"
+)
+
+type applyReport struct {
+	Target     string `json:"target"`
+	ConfigFile string `json:"configFile"`
+	*bankvaults.Report
+}
+
+// writeReport emits the report of the last configure run of a target to the given output,
+// which is either 'stdout', 'kv' (the configured key store) or a file path.
+func writeReport(ctx context.Context, output string, store kv.Service, target, configFile string, report *bankvaults.Report) error {
+	if output == "" || report == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(applyReport{Target: target, ConfigFile: configFile, Report: report})
+	if err != nil {
+		return errors.Wrap(err, "error marshaling apply report")
+	}
+
+	switch output {
+	case reportOutputStdout:
+		_, err = fmt.Fprintln(os.Stdout, string(data))
+	case reportOutputKV:
+		key := keyApplyReport
+		if target != defaultTargetName {
+			key = fmt.Sprintf("%s-%s", keyApplyReport, target)
+		}
+		err = store.Set(ctx, key, data)
+	default:
+		err = os.WriteFile(output, data, 0o600)
+	}
+
+	return errors.Wrapf(err, "error writing apply report to %s", output)
+}
@@ -0,0 +1,113 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/spf13/viper"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgLogVaultRequests      = "log-vault-requests"
+	cfgLogVaultRequestBodies = "log-vault-request-bodies"
+)
+
+// vaultRequestLog is the request log of the Vault clients, nil if disabled.
+var vaultRequestLog *requestLog
+
+// requestLog logs every Vault API call, to debug why Vault rejects a request without enabling an audit device.
+type requestLog struct {
+	// log the request bodies as well, with the values of these fields masked
+	bodies       bool
+	redactFields []string
+}
+
+// setupRequestLog enables the request log of the Vault clients created afterwards.
+func setupRequestLog(cfg *viper.Viper) {
+	vaultRequestLog = nil
+	if cfg.GetBool(cfgLogVaultRequests) {
+		vaultRequestLog = &requestLog{
+			bodies:       cfg.GetBool(cfgLogVaultRequestBodies),
+			redactFields: cfg.GetStringSlice(cfgLogRedactFields),
+		}
+	}
+}
+
+// requestLoggingTransport logs the method, path, status and duration of each request,
+// and the errors Vault responded with.
+type requestLoggingTransport struct {
+	base http.RoundTripper
+	log  *requestLog
+}
+
+func (t *requestLoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []interface{}{"method", req.Method, "path", req.URL.Path}
+	if t.log.bodies && req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		attrs = append(attrs, "body", t.log.payload(body))
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	attrs = append(attrs, "duration", time.Since(start))
+	if err != nil {
+		slog.Info("vault request failed", append(attrs, "error", err)...)
+		return resp, err
+	}
+
+	attrs = append(attrs, "status", resp.StatusCode)
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		attrs = append(attrs, "response", t.log.payload(body))
+	}
+
+	slog.Info("vault request", attrs...)
+
+	return resp, nil
+}
+
+// payload returns a JSON body to log with the sensitive fields masked, or just its size if it isn't JSON.
+func (l *requestLog) payload(body []byte) interface{} {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return fmt.Sprintf("<%d bytes>", len(body))
+	}
+
+	return bankvaults.RedactedPayload(value, l.redactFields)
+}
+
+func init() {
+	configBoolVar(rootCmd, cfgLogVaultRequests, false, "Log the method, path, status and duration of every Vault API call, and the errors Vault responded with")
+	configBoolVar(rootCmd, cfgLogVaultRequestBodies, false, "Log the request bodies of the Vault API calls as well, with the values of the --log-redact-fields masked")
+}
@@ -0,0 +1,63 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLoggingTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"url":"ldap://ldap","bindpass":"s3cr3t"}`, string(body))
+
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"errors":["invalid url"]}`)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	client := &http.Client{Transport: &requestLoggingTransport{
+		base: http.DefaultTransport,
+		log:  &requestLog{bodies: true, redactFields: []string{"bindpass"}},
+	}}
+	resp, err := client.Post(server.URL+"/v1/auth/ldap/config", "application/json", strings.NewReader(`{"url":"ldap://ldap","bindpass":"s3cr3t"}`))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The response is still readable by the client
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"errors":["invalid url"]}`, string(body))
+
+	assert.Contains(t, logs.String(), "method=POST path=/v1/auth/ldap/config")
+	assert.Contains(t, logs.String(), "status=400")
+	assert.Contains(t, logs.String(), "bindpass:<redacted>")
+	assert.Contains(t, logs.String(), "errors:[invalid url]")
+	assert.NotContains(t, logs.String(), "s3cr3t")
+}
@@ -0,0 +1,157 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	cfgRolloutPause       = "rollout-pause"
+	cfgRolloutStatefulSet = "rollout-statefulset"
+	cfgRolloutNamespace   = "rollout-namespace"
+)
+
+// rolloutPauseAnnotation pauses configuring while it is "true" on the Vault StatefulSet,
+// e.g. during a maintenance its status doesn't show.
+const rolloutPauseAnnotation = "bank-vaults.io/pause-configure"
+
+// rolloutGate pauses configuring and purging while the Vault StatefulSet of a target is rolled out,
+// so config writes don't land on a half-upgraded cluster.
+type rolloutGate struct {
+	client kubernetes.Interface
+	// StatefulSet of the single target, the targets of a targets file set theirs
+	namespace string
+	name      string
+}
+
+// rolloutGateForConfig returns the rollout gate of the configurer, nil if configuring isn't paused during rollouts.
+func rolloutGateForConfig(cfg *viper.Viper) (*rolloutGate, error) {
+	if !cfg.GetBool(cfgRolloutPause) {
+		return nil, nil
+	}
+
+	client, err := newK8sClient()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := cfg.GetString(cfgRolloutNamespace)
+	if namespace == "" {
+		namespace = podNamespace()
+	}
+
+	return &rolloutGate{client: client, namespace: namespace, name: cfg.GetString(cfgRolloutStatefulSet)}, nil
+}
+
+// statefulSet returns the namespace and name of the Vault StatefulSet of the target, the one set by flags for
+// the single target. The name is empty for the targets of a targets file without one, they aren't paused.
+func (g *rolloutGate) statefulSet(target configureTarget) (string, string) {
+	switch {
+	case target.RolloutStatefulSet != "":
+		if target.RolloutNamespace != "" {
+			return target.RolloutNamespace, target.RolloutStatefulSet
+		}
+		return g.namespace, target.RolloutStatefulSet
+	case target.Name == defaultTargetName:
+		return g.namespace, g.name
+	default:
+		return "", ""
+	}
+}
+
+// pauseReason returns why configuring the target is paused, empty if it isn't.
+func (g *rolloutGate) pauseReason(ctx context.Context, target configureTarget) (string, error) {
+	namespace, name := g.statefulSet(target)
+	if name == "" {
+		return "", nil
+	}
+
+	statefulSet, err := g.client.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting statefulset %s/%s", namespace, name)
+	}
+
+	// The status doesn't tell how far an OnDelete rollout got, the revisions of the pods do
+	var pods []corev1.Pod
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		selector, err := metav1.LabelSelectorAsSelector(statefulSet.Spec.Selector)
+		if err != nil {
+			return "", errors.Wrapf(err, "error parsing selector of statefulset %s/%s", namespace, name)
+		}
+
+		podList, err := g.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return "", errors.Wrapf(err, "error listing pods of statefulset %s/%s", namespace, name)
+		}
+		pods = podList.Items
+	}
+
+	return statefulSetPauseReason(statefulSet, pods), nil
+}
+
+// statefulSetPauseReason returns why a StatefulSet isn't settled: it is paused by the annotation,
+// its spec changed, not all its replicas run the update revision yet or not all of them are ready.
+// With the OnDelete update strategy the current revision is never updated, the pods are recreated
+// at the update revision one by one as they are deleted, the rollout is in progress while only some
+// of the given pods run it.
+func statefulSetPauseReason(statefulSet *appsv1.StatefulSet, pods []corev1.Pod) string {
+	if statefulSet.Annotations[rolloutPauseAnnotation] == "true" {
+		return fmt.Sprintf("paused by the %s annotation", rolloutPauseAnnotation)
+	}
+
+	replicas := int32(1)
+	if statefulSet.Spec.Replicas != nil {
+		replicas = *statefulSet.Spec.Replicas
+	}
+
+	status := statefulSet.Status
+	updated := status.UpdatedReplicas
+	rollingOut := status.UpdateRevision != "" && status.CurrentRevision != status.UpdateRevision
+	if statefulSet.Spec.UpdateStrategy.Type == appsv1.OnDeleteStatefulSetStrategyType {
+		updated = 0
+		for _, pod := range pods {
+			if pod.Labels[appsv1.ControllerRevisionHashLabelKey] == status.UpdateRevision {
+				updated++
+			}
+		}
+		rollingOut = status.UpdateRevision != "" && updated > 0 && int(updated) < len(pods)
+	}
+
+	switch {
+	case status.ObservedGeneration < statefulSet.Generation:
+		return "a new spec is not rolled out yet"
+	case rollingOut:
+		return fmt.Sprintf("%d of %d replicas are updated", updated, replicas)
+	case status.ReadyReplicas < replicas:
+		return fmt.Sprintf("%d of %d replicas are ready", status.ReadyReplicas, replicas)
+	}
+
+	return ""
+}
+
+func init() {
+	configBoolVar(configureCmd, cfgRolloutPause, false, "Pause configuring and purging while the Vault StatefulSet is rolled out or has the "+rolloutPauseAnnotation+" annotation")
+	configStringVar(configureCmd, cfgRolloutStatefulSet, "vault", "Name of the Vault StatefulSet whose rollouts pause configuring, the targets of a targets file set theirs with rolloutStatefulSet")
+	configStringVar(configureCmd, cfgRolloutNamespace, "", "Namespace of the Vault StatefulSets, defaults to POD_NAMESPACE or the namespace of the pod")
+}
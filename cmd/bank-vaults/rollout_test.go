@@ -0,0 +1,142 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRolloutGate(t *testing.T) {
+	replicas := int32(3)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "vault", Generation: 2},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status: appsv1.StatefulSetStatus{
+			ObservedGeneration: 2,
+			CurrentRevision:    "vault-1",
+			UpdateRevision:     "vault-2",
+			UpdatedReplicas:    1,
+			ReadyReplicas:      3,
+		},
+	}
+	client := fake.NewSimpleClientset(statefulSet)
+	gate := &rolloutGate{client: client, namespace: "vault", name: "vault"}
+	ctx := context.Background()
+
+	reason, err := gate.pauseReason(ctx, configureTarget{Name: defaultTargetName})
+	require.NoError(t, err)
+	assert.Equal(t, "1 of 3 replicas are updated", reason)
+
+	statefulSet.Status.CurrentRevision = "vault-2"
+	statefulSet.Status.UpdatedReplicas = 3
+	statefulSet.Status.ReadyReplicas = 2
+	assert.Equal(t, "2 of 3 replicas are ready", statefulSetPauseReason(statefulSet, nil))
+
+	statefulSet.Status.ReadyReplicas = 3
+	assert.Empty(t, statefulSetPauseReason(statefulSet, nil))
+
+	statefulSet.Generation = 3
+	assert.Equal(t, "a new spec is not rolled out yet", statefulSetPauseReason(statefulSet, nil))
+
+	statefulSet.Status.ObservedGeneration = 3
+	statefulSet.Annotations = map[string]string{rolloutPauseAnnotation: "true"}
+	assert.Contains(t, statefulSetPauseReason(statefulSet, nil), "annotation")
+
+	_, err = (&rolloutGate{client: client, namespace: "vault", name: "missing"}).pauseReason(ctx, configureTarget{Name: defaultTargetName})
+	assert.Error(t, err)
+}
+
+func TestRolloutGateTargets(t *testing.T) {
+	replicas := int32(1)
+	statefulSet := func(namespace, name, currentRevision string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+			Status:     appsv1.StatefulSetStatus{CurrentRevision: currentRevision, UpdateRevision: name + "-2", ReadyReplicas: 1},
+		}
+	}
+	client := fake.NewSimpleClientset(statefulSet("vault", "vault", "vault-1"), statefulSet("vault", "vault-eu", "vault-eu-2"), statefulSet("vault-us", "vault-us", "vault-us-1"))
+	gate := &rolloutGate{client: client, namespace: "vault", name: "vault"}
+	ctx := context.Background()
+
+	reasons := map[string]string{}
+	for _, target := range []configureTarget{
+		{Name: defaultTargetName},
+		{Name: "eu", RolloutStatefulSet: "vault-eu"},
+		{Name: "us", RolloutStatefulSet: "vault-us", RolloutNamespace: "vault-us"},
+		{Name: "ap"},
+	} {
+		reason, err := gate.pauseReason(ctx, target)
+		require.NoError(t, err)
+		reasons[target.Name] = reason
+	}
+
+	// The StatefulSet set by flags only gates the single target, the targets of a targets file set theirs
+	assert.Equal(t, map[string]string{
+		defaultTargetName: "0 of 1 replicas are updated",
+		"eu":              "",
+		"us":              "0 of 1 replicas are updated",
+		"ap":              "",
+	}, reasons)
+}
+
+func TestRolloutGateOnDelete(t *testing.T) {
+	replicas := int32(3)
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "vault", Namespace: "vault"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:       &replicas,
+			Selector:       &metav1.LabelSelector{MatchLabels: map[string]string{"app": "vault"}},
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{Type: appsv1.OnDeleteStatefulSetStrategyType},
+		},
+		// The current revision stays behind with OnDelete
+		Status: appsv1.StatefulSetStatus{CurrentRevision: "vault-1", UpdateRevision: "vault-2", ReadyReplicas: 3},
+	}
+	pod := func(name, revision string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "vault",
+			Labels:    map[string]string{"app": "vault", appsv1.ControllerRevisionHashLabelKey: revision},
+		}}
+	}
+	ctx := context.Background()
+
+	pauseReason := func(revisions ...string) string {
+		objects := []runtime.Object{statefulSet}
+		for i, revision := range revisions {
+			objects = append(objects, pod(fmt.Sprintf("vault-%d", i), revision))
+		}
+		objects = append(objects, &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "vault", Labels: map[string]string{"app": "other"}}})
+
+		reason, err := (&rolloutGate{client: fake.NewSimpleClientset(objects...), namespace: "vault", name: "vault"}).pauseReason(ctx, configureTarget{Name: defaultTargetName})
+		require.NoError(t, err)
+
+		return reason
+	}
+
+	assert.Empty(t, pauseReason("vault-1", "vault-1", "vault-1"), "no pod was deleted yet")
+	assert.Equal(t, "1 of 3 replicas are updated", pauseReason("vault-2", "vault-1", "vault-1"))
+	assert.Empty(t, pauseReason("vault-2", "vault-2", "vault-2"), "every pod runs the update revision")
+}
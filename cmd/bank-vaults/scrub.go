@@ -0,0 +1,47 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+)
+
+const cfgScrubSecrets = "scrub-secrets"
+
+// recoverScrubbed is deferred first by the goroutines handling secrets: a panic is printed with the
+// secrets in memory redacted from its value and stack trace, instead of the runtime printing it as is.
+func recoverScrubbed() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	writeScrubbedPanic(os.Stderr, r, debug.Stack())
+	os.Exit(2)
+}
+
+func writeScrubbedPanic(w io.Writer, r interface{}, stack []byte) {
+	text, _ := secmem.Redact(fmt.Sprintf("panic: %v\n\n%s", r, stack))
+	fmt.Fprint(w, text)
+}
+
+func init() {
+	configBoolVar(rootCmd, cfgScrubSecrets, true, "Redact the unseal keys, tokens and credentials in memory from the logs, errors and panics, which keeps copies of them to look for")
+}
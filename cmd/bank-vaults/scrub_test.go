@@ -0,0 +1,38 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+)
+
+func TestWriteScrubbedPanic(t *testing.T) {
+	secmem.EnableTracking()
+	untrack := secmem.Track("hvs.panicking-root-token")
+	defer untrack()
+
+	var out bytes.Buffer
+	writeScrubbedPanic(&out, errors.New("login failed with token hvs.panicking-root-token"), []byte("goroutine 1 [running]:\nmain.login(hvs.panicking-root-token)"))
+
+	assert.NotContains(t, out.String(), "hvs.panicking-root-token")
+	assert.Contains(t, out.String(), "panic: login failed with token "+secmem.RedactedValue)
+	assert.Contains(t, out.String(), "goroutine 1 [running]")
+}
@@ -0,0 +1,167 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const cfgSecretRefNamespace = "secret-ref-namespace"
+
+// inClusterNamespaceFile holds the namespace of the pod the configurer runs in.
+const inClusterNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+func newK8sClient() (*kubernetes.Clientset, error) {
+	config, err := newK8sConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating k8s client")
+	}
+
+	return client, nil
+}
+
+// newK8sConfig returns the config of the k8s clients, from KUBECONFIG or the in-cluster service account.
+func newK8sConfig() (*rest.Config, error) {
+	var config *rest.Config
+	var err error
+	if kubeconfig := os.Getenv(clientcmd.RecommendedConfigPathEnvVar); kubeconfig != "" {
+		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
+	} else {
+		config, err = rest.InClusterConfig()
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating k8s config")
+	}
+
+	return config, nil
+}
+
+// k8sSecretResolver reads the Kubernetes Secrets referenced by the config, the client is
+// only created on the first reference so configs without references don't need Kubernetes.
+type k8sSecretResolver struct {
+	namespace string
+
+	once   sync.Once
+	client *kubernetes.Clientset
+	err    error
+}
+
+// podNamespace returns the namespace of the pod the configurer runs in, from the POD_NAMESPACE
+// environment variable (set via the downward API) or the service account, "default" outside of a pod.
+func podNamespace() string {
+	if namespace, ok := inClusterNamespace(); ok {
+		return namespace
+	}
+
+	return "default"
+}
+
+// inClusterNamespace returns the namespace of the pod bank-vaults runs in, if it runs in Kubernetes.
+func inClusterNamespace() (string, bool) {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace, true
+	}
+
+	if namespace, err := os.ReadFile(inClusterNamespaceFile); err == nil {
+		return strings.TrimSpace(string(namespace)), true
+	}
+
+	return "", false
+}
+
+func newK8sSecretResolver(namespace string) *k8sSecretResolver {
+	if namespace == "" {
+		namespace = podNamespace()
+	}
+
+	return &k8sSecretResolver{namespace: namespace}
+}
+
+func (r *k8sSecretResolver) k8sClient() (*kubernetes.Clientset, error) {
+	r.once.Do(func() {
+		r.client, r.err = newK8sClient()
+	})
+
+	return r.client, r.err
+}
+
+func (r *k8sSecretResolver) SecretValue(ctx context.Context, namespace, name, key string) (string, error) {
+	client, err := r.k8sClient()
+	if err != nil {
+		return "", err
+	}
+
+	if namespace == "" {
+		namespace = r.namespace
+	}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting secret %s/%s", namespace, name)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", errors.Errorf("key %s not found in secret %s/%s", key, namespace, name)
+	}
+
+	return string(value), nil
+}
+
+// updateSecretValue sets the value of a key of an existing Secret.
+func (r *k8sSecretResolver) updateSecretValue(ctx context.Context, namespace, name, key, value string) error {
+	client, err := r.k8sClient()
+	if err != nil {
+		return err
+	}
+
+	if namespace == "" {
+		namespace = r.namespace
+	}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "error getting secret %s/%s", namespace, name)
+	}
+
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[key] = []byte(value)
+
+	if _, err := client.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return errors.Wrapf(err, "error updating secret %s/%s", namespace, name)
+	}
+
+	return nil
+}
+
+func init() {
+	configStringVar(configureCmd, cfgSecretRefNamespace, "", "Namespace of the Kubernetes Secrets referenced by secretKeyRef config values without a namespace, defaults to the namespace of the pod")
+}
@@ -0,0 +1,115 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const cfgMetricsSLOWindow = "metrics-slo-window"
+
+var (
+	// the outcomes of the configure runs by target, and of the unseal checks
+	configOutcomes = newOutcomeWindow(time.Hour)
+	unsealOutcomes = newOutcomeWindow(time.Hour)
+
+	configSuccessRatioDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "config", "success_ratio"),
+		"Ratio of the successful configure runs of a target within the --metrics-slo-window",
+		[]string{"target"}, nil,
+	)
+	unsealSuccessRatioDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNS, "unseal", "success_ratio"),
+		"Ratio of the successful unseal checks within the --metrics-slo-window",
+		nil, nil,
+	)
+)
+
+// outcome is the result of a configure run or unseal check.
+type outcome struct {
+	time    time.Time
+	success bool
+}
+
+// outcomeWindow keeps the outcomes within a rolling time window by key, to export success ratios
+// SLOs can be defined on, without having to compute them from counter deltas.
+type outcomeWindow struct {
+	mu       sync.Mutex
+	window   time.Duration
+	outcomes map[string][]outcome
+}
+
+func newOutcomeWindow(window time.Duration) *outcomeWindow {
+	return &outcomeWindow{window: window, outcomes: map[string][]outcome{}}
+}
+
+func (w *outcomeWindow) setWindow(window time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.window = window
+}
+
+func (w *outcomeWindow) record(key string, success bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	w.outcomes[key] = append(w.prune(key, now), outcome{time: now, success: success})
+}
+
+// ratios returns the success ratios by key, the keys without outcomes within the window are left out.
+func (w *outcomeWindow) ratios() map[string]float64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	ratios := map[string]float64{}
+	for key := range w.outcomes {
+		outcomes := w.prune(key, now)
+		if len(outcomes) == 0 {
+			delete(w.outcomes, key)
+			continue
+		}
+		w.outcomes[key] = outcomes
+
+		var successes float64
+		for _, o := range outcomes {
+			if o.success {
+				successes++
+			}
+		}
+		ratios[key] = successes / float64(len(outcomes))
+	}
+
+	return ratios
+}
+
+// prune drops the outcomes of a key older than the window, the caller must hold the lock.
+func (w *outcomeWindow) prune(key string, now time.Time) []outcome {
+	outcomes := w.outcomes[key]
+	i := 0
+	for i < len(outcomes) && now.Sub(outcomes[i].time) > w.window {
+		i++
+	}
+
+	return outcomes[i:]
+}
+
+func init() {
+	configDurationVar(rootCmd, cfgMetricsSLOWindow, time.Hour, "Rolling time window of the success ratio metrics of the configure runs and unseal checks")
+}
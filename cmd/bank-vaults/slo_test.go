@@ -0,0 +1,58 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutcomeWindow(t *testing.T) {
+	w := newOutcomeWindow(time.Hour)
+	w.record("eu", true)
+	w.record("eu", true)
+	w.record("eu", false)
+	w.record("us", false)
+
+	assert.Equal(t, map[string]float64{"eu": 2.0 / 3, "us": 0}, w.ratios())
+
+	// The outcomes age out of the window
+	w.mu.Lock()
+	w.outcomes["eu"][0].time = time.Now().Add(-2 * time.Hour)
+	w.outcomes["us"][0].time = time.Now().Add(-2 * time.Hour)
+	w.mu.Unlock()
+
+	assert.Equal(t, map[string]float64{"eu": 0.5}, w.ratios())
+}
+
+func TestConfigSuccessRatio(t *testing.T) {
+	configOutcomes = newOutcomeWindow(time.Hour)
+	defer func() { configOutcomes = newOutcomeWindow(time.Hour) }()
+
+	recordTargetConfiguration("eu", nil)
+	recordTargetConfiguration("eu", assert.AnError)
+
+	expected := `
+# HELP vault_config_success_ratio Ratio of the successful configure runs of a target within the --metrics-slo-window
+# TYPE vault_config_success_ratio gauge
+vault_config_success_ratio{target="eu"} 0.5
+`
+	require.NoError(t, testutil.CollectAndCompare(&prometheusExporter{Mode: "configure"}, strings.NewReader(expected), "vault_config_success_ratio"))
+}
@@ -0,0 +1,122 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgSnapshotChunkSize = "snapshot-chunk-size"
+	cfgSnapshotForce     = "snapshot-force"
+	cfgSnapshotTokenFile = "snapshot-token-file"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore raft snapshots of the target Vault instance",
+	Long: `The raft snapshots are streamed to and from the key store the unseal keys are
+stored in, encrypted the same way, so Vault can be backed up and restored with
+the storage credentials already configured.
+
+The commands use the stored root token, or the token of --snapshot-token-file,
+which needs sudo on sys/storage/raft/snapshot*.`,
+}
+
+var snapshotSaveCmd = &cobra.Command{
+	Use:   "save [key]",
+	Short: "Stream a raft snapshot of the target Vault instance into the key store",
+	Long: `This command takes a raft snapshot of the target Vault instance and stores it
+in the key store under the key, vault-raft-snapshot by default, in chunks and a
+manifest with its checksum. A snapshot stored under the same key is overwritten,
+use a key per snapshot to keep several ones.
+
+The snapshot is taken in a single request, raise --target-client-timeout for large ones.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runSnapshot(cmd.Context(), args, func(ctx context.Context, v bankvaults.Vault, key string) (*bankvaults.SnapshotManifest, error) {
+			return v.SaveSnapshot(ctx, key, c.GetInt(cfgSnapshotChunkSize))
+		})
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore [key]",
+	Short: "Stream a raft snapshot from the key store into the target Vault instance",
+	Long: `This command restores the raft snapshot stored in the key store under the key,
+vault-raft-snapshot by default, after checking it against its manifest.
+
+Restoring the snapshot of another cluster needs --snapshot-force, that cluster
+is unsealed with its own unseal keys afterwards, not the stored ones.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runSnapshot(cmd.Context(), args, func(ctx context.Context, v bankvaults.Vault, key string) (*bankvaults.SnapshotManifest, error) {
+			return v.RestoreSnapshot(ctx, key, c.GetBool(cfgSnapshotForce))
+		})
+	},
+}
+
+// runSnapshot runs a snapshot command against the target Vault, exiting with 1 if it fails.
+func runSnapshot(ctx context.Context, args []string, fn func(ctx context.Context, v bankvaults.Vault, key string) (*bankvaults.SnapshotManifest, error)) {
+	key := bankvaults.DefaultSnapshotKey
+	if len(args) > 0 {
+		key = args[0]
+	}
+
+	store, _, err := kvStoreForConfig(ctx, c)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error creating kv store: %s", err.Error()))
+		os.Exit(1)
+	}
+
+	cl, err := targetForConfig(c).newClient()
+	if err != nil {
+		slog.Error(fmt.Sprintf("error connecting to vault: %s", err.Error()))
+		os.Exit(1)
+	}
+
+	vaultConfig := vaultConfigForConfig(c)
+	vaultConfig.TokenFile = c.GetString(cfgSnapshotTokenFile)
+	v, err := bankvaults.New(ctx, store, cl, vaultConfig)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error creating vault helper: %s", err.Error()))
+		os.Exit(1)
+	}
+	defer v.Close()
+
+	manifest, err := fn(ctx, v, key)
+	if err != nil {
+		slog.Error(fmt.Sprintf("error running raft snapshot command: %s", err.Error()))
+		os.Exit(1)
+	}
+
+	slog.Info("raft snapshot", "key", key, "created", manifest.Created, "size", manifest.Size, "sha256", manifest.SHA256)
+}
+
+func init() {
+	configIntVar(snapshotSaveCmd, cfgSnapshotChunkSize, bankvaults.DefaultSnapshotChunkSize, "Size of the values the snapshot is stored in, each one is encrypted on its own by the KMS backends, which limit their size")
+	configBoolVar(snapshotRestoreCmd, cfgSnapshotForce, false, "Restore the snapshot even if it was taken of another cluster")
+	configStringVar(snapshotCmd, cfgSnapshotTokenFile, "", "File holding the token to take and restore the snapshots with instead of the root token")
+
+	snapshotCmd.AddCommand(snapshotSaveCmd, snapshotRestoreCmd)
+	rootCmd.AddCommand(snapshotCmd)
+}
@@ -0,0 +1,231 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/spf13/viper"
+)
+
+const (
+	cfgStatsdAddress  = "metrics-statsd-address"
+	cfgStatsdInterval = "metrics-statsd-interval"
+	cfgStatsdPrefix   = "metrics-statsd-prefix"
+	cfgStatsdTags     = "metrics-statsd-tags"
+)
+
+// statsdMaxPacketSize keeps the UDP packets below the usual MTU.
+const statsdMaxPacketSize = 1432
+
+// statsdSink sends the metrics of the exporter to a StatsD or DogStatsD agent, for monitoring
+// stacks which can't scrape the exporter. Gauges are sent as gauges, counters as the increments since
+// the last flush, histograms and summaries as the increments of their count and sum.
+type statsdSink struct {
+	conn   net.Conn
+	prefix string
+	// send the labels as DogStatsD tags, otherwise they are appended to the metric name
+	tags bool
+	// the counter values of the last flush, by metric
+	counters map[string]float64
+}
+
+func newStatsdSink(address, prefix string, tags bool) (*statsdSink, error) {
+	conn, err := net.Dial("udp", address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error connecting to statsd at %s", address)
+	}
+
+	return &statsdSink{conn: conn, prefix: prefix, tags: tags, counters: map[string]float64{}}, nil
+}
+
+// startStatsdSink flushes the metrics of the exporter to StatsD periodically, if an address is configured.
+// The returned function stops the sink after a last flush.
+func startStatsdSink(ctx context.Context, cfg *viper.Viper, exporter *prometheusExporter) (func(), error) {
+	address := cfg.GetString(cfgStatsdAddress)
+	if address == "" {
+		return func() {}, nil
+	}
+
+	registry, err := newExporterRegistry(exporter)
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := newStatsdSink(address, cfg.GetString(cfgStatsdPrefix), cfg.GetBool(cfgStatsdTags))
+	if err != nil {
+		return nil, err
+	}
+
+	slog.Info(fmt.Sprintf("vault metrics statsd sink enabled: %s", address))
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sink.run(ctx, registry, cfg.GetDuration(cfgStatsdInterval))
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}, nil
+}
+
+func (s *statsdSink) run(ctx context.Context, gatherer prometheus.Gatherer, interval time.Duration) {
+	defer s.conn.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.flush(gatherer); err != nil {
+				slog.Error(fmt.Sprintf("error flushing metrics to statsd: %s", err.Error()))
+			}
+
+			return
+		case <-ticker.C:
+			if err := s.flush(gatherer); err != nil {
+				slog.Error(fmt.Sprintf("error flushing metrics to statsd: %s", err.Error()))
+			}
+		}
+	}
+}
+
+// flush sends the current metrics of the gatherer.
+func (s *statsdSink) flush(gatherer prometheus.Gatherer) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return errors.Wrap(err, "error gathering metrics")
+	}
+
+	var lines []string
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			lines = append(lines, s.lines(family.GetName(), family.GetType(), metric)...)
+		}
+	}
+
+	return s.send(lines)
+}
+
+func (s *statsdSink) lines(name string, metricType dto.MetricType, metric *dto.Metric) []string {
+	labels := metric.GetLabel()
+
+	switch metricType {
+	case dto.MetricType_GAUGE:
+		return []string{s.line(name, labels, metric.GetGauge().GetValue(), "g")}
+	case dto.MetricType_UNTYPED:
+		return []string{s.line(name, labels, metric.GetUntyped().GetValue(), "g")}
+	case dto.MetricType_COUNTER:
+		return []string{s.counterLine(name, labels, metric.GetCounter().GetValue())}
+	case dto.MetricType_HISTOGRAM:
+		return []string{
+			s.counterLine(name+"_count", labels, float64(metric.GetHistogram().GetSampleCount())),
+			s.counterLine(name+"_sum", labels, metric.GetHistogram().GetSampleSum()),
+		}
+	case dto.MetricType_SUMMARY:
+		return []string{
+			s.counterLine(name+"_count", labels, float64(metric.GetSummary().GetSampleCount())),
+			s.counterLine(name+"_sum", labels, metric.GetSummary().GetSampleSum()),
+		}
+	default:
+		return nil
+	}
+}
+
+// counterLine returns the increment of a counter since the last flush.
+func (s *statsdSink) counterLine(name string, labels []*dto.LabelPair, value float64) string {
+	key := name
+	for _, label := range labels {
+		key += "," + label.GetName() + "=" + label.GetValue()
+	}
+	delta := value - s.counters[key]
+	// The counter was reset, e.g. by a metric vector Reset
+	if delta < 0 {
+		delta = value
+	}
+	s.counters[key] = value
+
+	return s.line(name, labels, delta, "c")
+}
+
+func (s *statsdSink) line(name string, labels []*dto.LabelPair, value float64, metricType string) string {
+	var tags []string
+	for _, label := range labels {
+		if s.tags {
+			tags = append(tags, label.GetName()+":"+label.GetValue())
+		} else {
+			name += "." + label.GetValue()
+		}
+	}
+
+	line := fmt.Sprintf("%s%s:%s|%s", s.prefix, name, strconv.FormatFloat(value, 'f', -1, 64), metricType)
+	if len(tags) > 0 {
+		sort.Strings(tags)
+		line += "|#" + strings.Join(tags, ",")
+	}
+
+	return line
+}
+
+// send writes the lines in as few packets as possible.
+func (s *statsdSink) send(lines []string) error {
+	var packet bytes.Buffer
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+len(line)+1 > statsdMaxPacketSize {
+			if _, err := s.conn.Write(packet.Bytes()); err != nil {
+				return errors.Wrap(err, "error sending metrics to statsd")
+			}
+			packet.Reset()
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+
+	if packet.Len() > 0 {
+		if _, err := s.conn.Write(packet.Bytes()); err != nil {
+			return errors.Wrap(err, "error sending metrics to statsd")
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	configStringVar(rootCmd, cfgStatsdAddress, "", "Send the metrics to this StatsD/DogStatsD agent (host:port) as well, configure --disable-metrics only sends them there")
+	configDurationVar(rootCmd, cfgStatsdInterval, 10*time.Second, "Interval of sending the metrics to StatsD")
+	configStringVar(rootCmd, cfgStatsdPrefix, "", "Prefix of the metric names sent to StatsD, e.g. 'bank_vaults.'")
+	configBoolVar(rootCmd, cfgStatsdTags, true, "Send the metric labels as DogStatsD tags, otherwise they are appended to the metric names")
+}
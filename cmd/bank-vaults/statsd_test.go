@@ -0,0 +1,63 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsdSinkFlush(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	read := func() []string {
+		buf := make([]byte, statsdMaxPacketSize)
+		require.NoError(t, listener.SetReadDeadline(time.Now().Add(5*time.Second)))
+		n, _, err := listener.ReadFrom(buf)
+		require.NoError(t, err)
+
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	registry := prometheus.NewRegistry()
+	runs := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "runs_total", Help: "runs"}, []string{"target"})
+	sealed := prometheus.NewGauge(prometheus.GaugeOpts{Name: "sealed", Help: "sealed"})
+	registry.MustRegister(runs, sealed)
+
+	sink, err := newStatsdSink(listener.LocalAddr().String(), "bank_vaults.", true)
+	require.NoError(t, err)
+
+	runs.WithLabelValues("eu").Add(3)
+	sealed.Set(1)
+	require.NoError(t, sink.flush(registry))
+	assert.Equal(t, []string{"bank_vaults.runs_total:3|c|#target:eu", "bank_vaults.sealed:1|g"}, read())
+
+	// Counters are sent as their increments since the last flush
+	runs.WithLabelValues("eu").Inc()
+	require.NoError(t, sink.flush(registry))
+	assert.Equal(t, []string{"bank_vaults.runs_total:1|c|#target:eu", "bank_vaults.sealed:1|g"}, read())
+
+	sink.tags = false
+	require.NoError(t, sink.flush(registry))
+	assert.Equal(t, []string{"bank_vaults.runs_total.eu:0|c", "bank_vaults.sealed:1|g"}, read())
+}
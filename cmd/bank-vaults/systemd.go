@@ -0,0 +1,104 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Environment variables set by systemd for services with Type=notify and WatchdogSec.
+const (
+	envNotifySocket = "NOTIFY_SOCKET"
+	envWatchdogUSec = "WATCHDOG_USEC"
+	envWatchdogPID  = "WATCHDOG_PID"
+)
+
+// sdNotify sends a state like READY=1 to systemd, it does nothing when not running under systemd.
+func sdNotify(state string) error {
+	socket := os.Getenv(envNotifySocket)
+	if socket == "" {
+		return nil
+	}
+
+	// Abstract sockets are given with a leading @
+	if socket[0] == '@' {
+		socket = "\x00" + socket[1:]
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return errors.Wrap(err, "error connecting to systemd notify socket")
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte(state)); err != nil {
+		return errors.Wrap(err, "error notifying systemd")
+	}
+
+	return nil
+}
+
+// notifySystemd sends a state to systemd, errors are only logged.
+func notifySystemd(state string) {
+	if err := sdNotify(state); err != nil {
+		slog.Warn("error notifying systemd", "state", state, "error", err)
+	}
+}
+
+// systemdWatchdogInterval returns the watchdog timeout systemd expects pings within, if enabled for this process.
+func systemdWatchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv(envWatchdogUSec), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+
+	if pid := os.Getenv(envWatchdogPID); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+// watchSystemd pings the systemd watchdog at half its timeout while the loop is live,
+// so systemd restarts a wedged process like the Kubernetes liveness probe would.
+func (h *healthChecker) watchSystemd(ctx context.Context) {
+	interval, ok := systemdWatchdogInterval()
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.live(); err != nil {
+				slog.Warn("not pinging the systemd watchdog", "error", err)
+				continue
+			}
+			notifySystemd("WATCHDOG=1")
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSdNotify(t *testing.T) {
+	t.Setenv(envNotifySocket, "")
+	require.NoError(t, sdNotify("READY=1"))
+
+	// Socket paths are limited to about 100 bytes, which t.TempDir() may exceed
+	dir, err := os.MkdirTemp("", "sd")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	socket := filepath.Join(dir, "notify")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	t.Setenv(envNotifySocket, socket)
+	require.NoError(t, sdNotify("READY=1"))
+
+	buf := make([]byte, 64)
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, "READY=1", string(buf[:n]))
+}
+
+func TestSystemdWatchdogInterval(t *testing.T) {
+	t.Setenv(envWatchdogUSec, "")
+	_, ok := systemdWatchdogInterval()
+	assert.False(t, ok)
+
+	t.Setenv(envWatchdogUSec, "30000000")
+	t.Setenv(envWatchdogPID, strconv.Itoa(os.Getpid()))
+	interval, ok := systemdWatchdogInterval()
+	assert.True(t, ok)
+	assert.Equal(t, 30*time.Second, interval)
+
+	// The watchdog is meant for another process
+	t.Setenv(envWatchdogPID, strconv.Itoa(os.Getpid()+1))
+	_, ok = systemdWatchdogInterval()
+	assert.False(t, ok)
+}
@@ -0,0 +1,398 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+const (
+	cfgTargetAddress       = "target-addr"
+	cfgTargetCACert        = "target-ca-cert"
+	cfgTargetClientCert    = "target-client-cert"
+	cfgTargetClientKey     = "target-client-key"
+	cfgTargetTLSServerName = "target-tls-server-name"
+	cfgTargetProxy         = "target-proxy"
+	cfgTargetNoProxy       = "target-no-proxy"
+	cfgTargetNamespace     = "target-namespace"
+	cfgTargetClientTimeout = "target-client-timeout"
+	cfgTargetMaxRetries    = "target-max-retries"
+	cfgTargetPinnedCerts   = "target-pinned-certs"
+	cfgTargetPinnedSPKIs   = "target-pinned-spkis"
+
+	cfgTargetMaxIdleConns        = "target-max-idle-conns"
+	cfgTargetMaxIdleConnsPerHost = "target-max-idle-conns-per-host"
+	cfgTargetIdleConnTimeout     = "target-idle-conn-timeout"
+	cfgTargetDialTimeout         = "target-dial-timeout"
+	cfgTargetKeepAlive           = "target-keep-alive"
+	cfgTargetDisableKeepAlives   = "target-disable-keep-alives"
+	cfgTargetDisableHTTP2        = "target-disable-http2"
+)
+
+const (
+	cfgVaultCACert        = "vault-ca-cert"
+	cfgVaultClientCert    = "vault-client-cert"
+	cfgVaultClientKey     = "vault-client-key"
+	cfgVaultTLSServerName = "vault-tls-server-name"
+	cfgVaultProxy         = "vault-proxy"
+	cfgVaultNoProxy       = "vault-no-proxy"
+	cfgVaultNamespace     = "vault-namespace"
+	cfgVaultClientTimeout = "vault-client-timeout"
+	cfgVaultMaxRetries    = "vault-max-retries"
+	cfgVaultPinnedCerts   = "vault-pinned-certs"
+	cfgVaultPinnedSPKIs   = "vault-pinned-spkis"
+)
+
+// vaultTarget holds the connection settings of a Vault endpoint,
+// empty fields fall back to the VAULT_* environment variables.
+type vaultTarget struct {
+	Address       string `mapstructure:"address"`
+	CACert        string `mapstructure:"caCert"`
+	ClientCert    string `mapstructure:"clientCert"`
+	ClientKey     string `mapstructure:"clientKey"`
+	TLSServerName string `mapstructure:"tlsServerName"`
+	Proxy         string `mapstructure:"proxy"`
+	// hosts connected to directly like in NO_PROXY, which is used if empty
+	NoProxy []string `mapstructure:"noProxy"`
+	// Vault Enterprise namespace
+	Namespace     string        `mapstructure:"namespace"`
+	ClientTimeout time.Duration `mapstructure:"clientTimeout"`
+	MaxRetries    *int          `mapstructure:"maxRetries"`
+	// SHA-256 fingerprints of the server certificates or hashes of their public keys, one has to match
+	PinnedCerts []string `mapstructure:"pinnedCerts"`
+	PinnedSPKIs []string `mapstructure:"pinnedSPKIs"`
+	// client certificates of single addresses, for clusters enforcing a certificate per instance
+	Instances []vaultInstance `mapstructure:"instances"`
+	// tuning of the connections of the client, the zero values keep the defaults of the Vault API client
+	Transport transportTuning `mapstructure:"transport"`
+}
+
+// transportTuning tunes the HTTP transport a target client reuses for all its requests.
+type transportTuning struct {
+	MaxIdleConns        int           `mapstructure:"maxIdleConns"`
+	MaxIdleConnsPerHost int           `mapstructure:"maxIdleConnsPerHost"`
+	IdleConnTimeout     time.Duration `mapstructure:"idleConnTimeout"`
+	DialTimeout         time.Duration `mapstructure:"dialTimeout"`
+	// period of the TCP keep-alive probes
+	KeepAlive         time.Duration `mapstructure:"keepAlive"`
+	DisableKeepAlives bool          `mapstructure:"disableKeepAlives"`
+	DisableHTTP2      bool          `mapstructure:"disableHTTP2"`
+}
+
+// vaultInstance is one of the addresses of a target, authenticated with its own client certificate.
+type vaultInstance struct {
+	Address    string `mapstructure:"address"`
+	ClientCert string `mapstructure:"clientCert"`
+	ClientKey  string `mapstructure:"clientKey"`
+}
+
+// targetForConfig returns the Vault being initialized, unsealed or configured.
+func targetForConfig(cfg *viper.Viper) vaultTarget {
+	return vaultTarget{
+		Address:       cfg.GetString(cfgTargetAddress),
+		CACert:        cfg.GetString(cfgTargetCACert),
+		ClientCert:    cfg.GetString(cfgTargetClientCert),
+		ClientKey:     cfg.GetString(cfgTargetClientKey),
+		TLSServerName: cfg.GetString(cfgTargetTLSServerName),
+		Proxy:         cfg.GetString(cfgTargetProxy),
+		NoProxy:       cfg.GetStringSlice(cfgTargetNoProxy),
+		Namespace:     cfg.GetString(cfgTargetNamespace),
+		ClientTimeout: cfg.GetDuration(cfgTargetClientTimeout),
+		MaxRetries:    maxRetriesForConfig(cfg, cfgTargetMaxRetries),
+		PinnedCerts:   cfg.GetStringSlice(cfgTargetPinnedCerts),
+		PinnedSPKIs:   cfg.GetStringSlice(cfgTargetPinnedSPKIs),
+		Transport: transportTuning{
+			MaxIdleConns:        cfg.GetInt(cfgTargetMaxIdleConns),
+			MaxIdleConnsPerHost: cfg.GetInt(cfgTargetMaxIdleConnsPerHost),
+			IdleConnTimeout:     cfg.GetDuration(cfgTargetIdleConnTimeout),
+			DialTimeout:         cfg.GetDuration(cfgTargetDialTimeout),
+			KeepAlive:           cfg.GetDuration(cfgTargetKeepAlive),
+			DisableKeepAlives:   cfg.GetBool(cfgTargetDisableKeepAlives),
+			DisableHTTP2:        cfg.GetBool(cfgTargetDisableHTTP2),
+		},
+	}
+}
+
+// kvVaultTargetForConfig returns the Vault used by the 'vault' kv store.
+func kvVaultTargetForConfig(cfg *viper.Viper) vaultTarget {
+	return vaultTarget{
+		Address:       cfg.GetString(cfgVaultAddress),
+		CACert:        cfg.GetString(cfgVaultCACert),
+		ClientCert:    cfg.GetString(cfgVaultClientCert),
+		ClientKey:     cfg.GetString(cfgVaultClientKey),
+		TLSServerName: cfg.GetString(cfgVaultTLSServerName),
+		Proxy:         cfg.GetString(cfgVaultProxy),
+		NoProxy:       cfg.GetStringSlice(cfgVaultNoProxy),
+		Namespace:     cfg.GetString(cfgVaultNamespace),
+		ClientTimeout: cfg.GetDuration(cfgVaultClientTimeout),
+		MaxRetries:    maxRetriesForConfig(cfg, cfgVaultMaxRetries),
+		PinnedCerts:   cfg.GetStringSlice(cfgVaultPinnedCerts),
+		PinnedSPKIs:   cfg.GetStringSlice(cfgVaultPinnedSPKIs),
+	}
+}
+
+// maxRetriesForConfig returns the max retries flag, nil if it's negative to fall back to VAULT_MAX_RETRIES.
+func maxRetriesForConfig(cfg *viper.Viper, key string) *int {
+	maxRetries := cfg.GetInt(key)
+	if maxRetries < 0 {
+		return nil
+	}
+
+	return &maxRetries
+}
+
+// apiConfig returns the Vault API client config of the target.
+func (t vaultTarget) apiConfig() (*api.Config, error) {
+	config := api.DefaultConfig()
+	if config.Error != nil {
+		return nil, config.Error
+	}
+
+	transport := config.HttpClient.Transport.(*http.Transport)
+	transport.TLSHandshakeTimeout = 5 * time.Second
+	t.Transport.apply(transport)
+
+	if addresses := t.addresses(); len(addresses) > 0 {
+		config.Address = addresses[0]
+	}
+
+	if t.CACert != "" || t.ClientCert != "" || t.ClientKey != "" || t.TLSServerName != "" {
+		if err := config.ConfigureTLS(t.tlsConfig()); err != nil {
+			return nil, errors.Wrap(err, "error configuring vault client TLS")
+		}
+	}
+
+	if len(t.PinnedCerts) > 0 || len(t.PinnedSPKIs) > 0 {
+		pinner, err := newCertPinner(t.PinnedCerts, t.PinnedSPKIs)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig.VerifyConnection = pinner.verifyConnection
+	}
+
+	if t.ClientTimeout > 0 {
+		config.Timeout = t.ClientTimeout
+	}
+	if t.MaxRetries != nil {
+		config.MaxRetries = *t.MaxRetries
+	}
+
+	if t.Proxy != "" || len(t.NoProxy) > 0 {
+		proxy := kv.Proxy{URL: t.Proxy, NoProxy: t.NoProxy}
+		if err := proxy.Validate(); err != nil {
+			return nil, errors.Wrap(err, "error configuring vault proxy")
+		}
+		transport.Proxy = proxy.ProxyFunc()
+	}
+
+	return config, nil
+}
+
+// apply tunes the transport, which is configured for HTTP/2 by the Vault API client.
+func (tuning transportTuning) apply(transport *http.Transport) {
+	if tuning.MaxIdleConns > 0 {
+		transport.MaxIdleConns = tuning.MaxIdleConns
+	}
+	if tuning.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = tuning.MaxIdleConnsPerHost
+	}
+	if tuning.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = tuning.IdleConnTimeout
+	}
+	if tuning.DialTimeout > 0 || tuning.KeepAlive != 0 {
+		// The defaults of the pooled client of the Vault API client
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if tuning.DialTimeout > 0 {
+			dialer.Timeout = tuning.DialTimeout
+		}
+		if tuning.KeepAlive != 0 {
+			dialer.KeepAlive = tuning.KeepAlive
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	transport.DisableKeepAlives = tuning.DisableKeepAlives
+
+	if tuning.DisableHTTP2 {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		transport.Protocols = protocols
+		// A non-nil empty map turns off the HTTP/2 upgrade of TLS connections
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+		if transport.TLSClientConfig != nil {
+			transport.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(transport.TLSClientConfig.NextProtos), func(proto string) bool {
+				return proto == "h2"
+			})
+		}
+	}
+}
+
+// tlsConfig returns the TLS settings of the target, the ones it doesn't set are read from the
+// VAULT_* environment variables, which configuring the TLS of the client would reset otherwise.
+func (t vaultTarget) tlsConfig() *api.TLSConfig {
+	config := &api.TLSConfig{
+		CACert:        os.Getenv(api.EnvVaultCACert),
+		CACertBytes:   []byte(os.Getenv(api.EnvVaultCACertBytes)),
+		CAPath:        os.Getenv(api.EnvVaultCAPath),
+		ClientCert:    os.Getenv(api.EnvVaultClientCert),
+		ClientKey:     os.Getenv(api.EnvVaultClientKey),
+		TLSServerName: os.Getenv(api.EnvVaultTLSServerName),
+	}
+	// Already validated by api.DefaultConfig
+	config.Insecure, _ = strconv.ParseBool(os.Getenv(api.EnvVaultSkipVerify))
+
+	if t.CACert != "" {
+		config.CACert, config.CACertBytes, config.CAPath = t.CACert, nil, ""
+	}
+	if t.ClientCert != "" || t.ClientKey != "" {
+		config.ClientCert, config.ClientKey = t.ClientCert, t.ClientKey
+	}
+	if t.TLSServerName != "" {
+		config.TLSServerName = t.TLSServerName
+	}
+
+	return config
+}
+
+// addresses returns the addresses of the target, several ones can be listed separated by commas.
+func (t vaultTarget) addresses() []string {
+	var addresses []string
+	for _, address := range strings.Split(t.Address, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	return addresses
+}
+
+// tlsTransport returns the API client config of the target with its TLS settings applied, and the
+// HTTP transport underneath.
+func (t vaultTarget) tlsTransport() (*api.Config, *http.Transport, error) {
+	config, err := t.apiConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	transport := config.HttpClient.Transport.(*http.Transport)
+
+	// Wrapped after the TLS and proxy settings, which need the underlying transport
+	if err := t.withReloadingClientCert(config); err != nil {
+		return nil, nil, err
+	}
+
+	return config, transport, nil
+}
+
+// newClient creates a raw Vault client for the target, which must be a single Vault node.
+func (t vaultTarget) newClient() (*api.Client, error) {
+	if len(t.addresses()) > 1 {
+		return nil, errors.Errorf("multiple vault addresses are only supported by configure: %s", t.Address)
+	}
+
+	return t.newFailoverClient()
+}
+
+// newFailoverClient creates a raw Vault client for the target, failing over between its addresses.
+func (t vaultTarget) newFailoverClient() (*api.Client, error) {
+	config, transport, err := t.tlsTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	if files := t.caFiles(); len(files) > 0 {
+		config.HttpClient.Transport = newReloadingCATransport(files, config.HttpClient.Transport, transport, func() (http.RoundTripper, *http.Transport, error) {
+			config, transport, err := t.tlsTransport()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			return config.HttpClient.Transport, transport, nil
+		})
+	}
+	// Beneath the failover, each address tried is a request to wait for
+	withRateLimit(config, vaultRateLimiter, vaultRetryBudget)
+	if addresses := t.addresses(); len(addresses) > 1 {
+		config.HttpClient.Transport, err = newFailoverTransport(config.HttpClient.Transport, addresses)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if dryRun {
+		config.HttpClient.Transport = &dryRunTransport{base: config.HttpClient.Transport}
+	}
+	if vaultRequestLog != nil {
+		config.HttpClient.Transport = &requestLoggingTransport{base: config.HttpClient.Transport, log: vaultRequestLog}
+	}
+	config.HttpClient.Transport = &tracingTransport{base: config.HttpClient.Transport}
+
+	cl, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+
+	// VAULT_NAMESPACE is read by api.NewClient
+	if t.Namespace != "" {
+		cl.SetNamespace(t.Namespace)
+	}
+
+	return cl, nil
+}
+
+func init() {
+	configStringVar(rootCmd, cfgTargetAddress, "", "The address of the Vault to operate on, defaults to VAULT_ADDR. Configure accepts several comma separated addresses of a cluster, failing over between them")
+	configStringVar(rootCmd, cfgTargetCACert, "", "CA certificate file to verify the Vault to operate on, defaults to VAULT_CACERT")
+	configStringVar(rootCmd, cfgTargetClientCert, "", "Client certificate file to authenticate to the Vault to operate on, defaults to VAULT_CLIENT_CERT")
+	configStringVar(rootCmd, cfgTargetClientKey, "", "Client key file to authenticate to the Vault to operate on, defaults to VAULT_CLIENT_KEY")
+	configStringVar(rootCmd, cfgTargetTLSServerName, "", "SNI server name to use connecting to the Vault to operate on, defaults to VAULT_TLS_SERVER_NAME")
+	configStringVar(rootCmd, cfgTargetProxy, "", "HTTP(S) or SOCKS5 proxy URL to use connecting to the Vault to operate on, defaults to HTTPS_PROXY")
+	configStringSliceVar(rootCmd, cfgTargetNoProxy, nil, "Hosts, domains and CIDRs of the Vault to operate on connected to without the proxy, defaults to NO_PROXY")
+	configStringVar(rootCmd, cfgTargetNamespace, "", "Vault Enterprise namespace to operate in, defaults to VAULT_NAMESPACE")
+	configDurationVar(rootCmd, cfgTargetClientTimeout, 0, "Timeout of the requests to the Vault to operate on, defaults to VAULT_CLIENT_TIMEOUT or 60s")
+	configIntVar(rootCmd, cfgTargetMaxRetries, -1, "How many times failing requests to the Vault to operate on are retried, defaults to VAULT_MAX_RETRIES or 2")
+	configStringSliceVar(rootCmd, cfgTargetPinnedCerts, nil, "SHA-256 fingerprints of the certificates the Vault to operate on may present, checked on top of the CA")
+	configStringSliceVar(rootCmd, cfgTargetPinnedSPKIs, nil, "Base64 SHA-256 hashes of the public keys the certificate of the Vault to operate on may have, checked on top of the CA")
+	configIntVar(rootCmd, cfgTargetMaxIdleConns, 0, "Maximum number of idle connections kept to the Vault to operate on, defaults to 100")
+	configIntVar(rootCmd, cfgTargetMaxIdleConnsPerHost, 0, "Maximum number of idle connections kept to each address of the Vault to operate on, defaults to the number of CPUs + 1")
+	configDurationVar(rootCmd, cfgTargetIdleConnTimeout, 0, "How long idle connections to the Vault to operate on are kept, defaults to 90s")
+	configDurationVar(rootCmd, cfgTargetDialTimeout, 0, "Timeout of connecting to the Vault to operate on, defaults to 30s")
+	configDurationVar(rootCmd, cfgTargetKeepAlive, 0, "Period of the TCP keep-alive probes of the connections to the Vault to operate on, defaults to 30s, negative to turn them off")
+	configBoolVar(rootCmd, cfgTargetDisableKeepAlives, false, "Use a new connection for each request to the Vault to operate on")
+	configBoolVar(rootCmd, cfgTargetDisableHTTP2, false, "Talk HTTP/1.1 to the Vault to operate on, even if it supports HTTP/2")
+
+	configStringVar(rootCmd, cfgVaultCACert, "", "CA certificate file to verify the Vault to store values in")
+	configStringVar(rootCmd, cfgVaultClientCert, "", "Client certificate file to authenticate to the Vault to store values in")
+	configStringVar(rootCmd, cfgVaultClientKey, "", "Client key file to authenticate to the Vault to store values in")
+	configStringVar(rootCmd, cfgVaultTLSServerName, "", "SNI server name to use connecting to the Vault to store values in")
+	configStringVar(rootCmd, cfgVaultProxy, "", "HTTP(S) or SOCKS5 proxy URL to use connecting to the Vault to store values in, defaults to HTTPS_PROXY")
+	configStringSliceVar(rootCmd, cfgVaultNoProxy, nil, "Hosts, domains and CIDRs of the Vault to store values in connected to without the proxy, defaults to NO_PROXY")
+	configStringVar(rootCmd, cfgVaultNamespace, "", "Vault Enterprise namespace of the Vault to store values in, defaults to VAULT_NAMESPACE")
+	configDurationVar(rootCmd, cfgVaultClientTimeout, 0, "Timeout of the requests to the Vault to store values in, defaults to VAULT_CLIENT_TIMEOUT or 60s")
+	configIntVar(rootCmd, cfgVaultMaxRetries, -1, "How many times failing requests to the Vault to store values in are retried, defaults to VAULT_MAX_RETRIES or 2")
+	configStringSliceVar(rootCmd, cfgVaultPinnedCerts, nil, "SHA-256 fingerprints of the certificates the Vault to store values in may present, checked on top of the CA")
+	configStringSliceVar(rootCmd, cfgVaultPinnedSPKIs, nil, "Base64 SHA-256 hashes of the public keys the certificate of the Vault to store values in may have, checked on top of the CA")
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultTargetTLSConfigFallsBackToEnvironment(t *testing.T) {
+	t.Setenv(api.EnvVaultCACert, "/env/ca.crt")
+	t.Setenv(api.EnvVaultTLSServerName, "vault.env")
+	t.Setenv(api.EnvVaultSkipVerify, "true")
+
+	config := vaultTarget{ClientCert: "/flag/tls.crt", ClientKey: "/flag/tls.key"}.tlsConfig()
+
+	assert.Equal(t, "/env/ca.crt", config.CACert)
+	assert.Equal(t, "/flag/tls.crt", config.ClientCert)
+	assert.Equal(t, "/flag/tls.key", config.ClientKey)
+	assert.Equal(t, "vault.env", config.TLSServerName)
+	assert.True(t, config.Insecure)
+
+	config = vaultTarget{CACert: "/flag/ca.crt", TLSServerName: "vault.flag"}.tlsConfig()
+
+	assert.Equal(t, "/flag/ca.crt", config.CACert)
+	assert.Equal(t, "vault.flag", config.TLSServerName)
+}
+
+func TestVaultTargetClientSettings(t *testing.T) {
+	t.Setenv(api.EnvVaultNamespace, "env-namespace")
+	t.Setenv(api.EnvVaultClientTimeout, "10s")
+	t.Setenv(api.EnvVaultMaxRetries, "5")
+
+	config, err := vaultTarget{Address: "http://127.0.0.1:8200"}.apiConfig()
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Second, config.Timeout)
+	assert.Equal(t, 5, config.MaxRetries)
+
+	cl, err := vaultTarget{Address: "http://127.0.0.1:8200"}.newClient()
+	require.NoError(t, err)
+	assert.Equal(t, "env-namespace", cl.Namespace())
+
+	maxRetries := 0
+	target := vaultTarget{Address: "http://127.0.0.1:8200", Namespace: "team-a", ClientTimeout: time.Minute, MaxRetries: &maxRetries}
+
+	config, err = target.apiConfig()
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, config.Timeout)
+	assert.Equal(t, 0, config.MaxRetries)
+
+	cl, err = target.newClient()
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", cl.Namespace())
+}
+
+func TestVaultTargetTransportTuning(t *testing.T) {
+	config, err := vaultTarget{Address: "https://127.0.0.1:8200"}.apiConfig()
+	require.NoError(t, err)
+	transport := config.HttpClient.Transport.(*http.Transport)
+	assert.False(t, transport.DisableKeepAlives)
+
+	config, err = vaultTarget{Address: "https://127.0.0.1:8200", Transport: transportTuning{
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 5,
+		IdleConnTimeout:     time.Minute,
+		DialTimeout:         time.Second,
+		DisableKeepAlives:   true,
+		DisableHTTP2:        true,
+	}}.apiConfig()
+	require.NoError(t, err)
+	transport = config.HttpClient.Transport.(*http.Transport)
+	assert.Equal(t, 10, transport.MaxIdleConns)
+	assert.Equal(t, 5, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.True(t, transport.DisableKeepAlives)
+	assert.NotContains(t, transport.TLSClientConfig.NextProtos, "h2")
+	assert.NotNil(t, transport.TLSNextProto)
+	assert.Empty(t, transport.TLSNextProto)
+	assert.False(t, transport.ForceAttemptHTTP2)
+	assert.False(t, transport.Protocols.HTTP2())
+}
+
+func TestVaultTargetProxy(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "")
+	t.Setenv("NO_PROXY", "")
+
+	config, err := vaultTarget{
+		Address: "https://vault.vault-internal:8200",
+		Proxy:   "socks5://egress:1080",
+		NoProxy: []string{".vault-internal", "10.0.0.0/8"},
+	}.apiConfig()
+	require.NoError(t, err)
+	transport := config.HttpClient.Transport.(*http.Transport)
+
+	proxy, err := transport.Proxy(httptest.NewRequest(http.MethodGet, "https://vault.example.com:8200/v1/sys/health", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "socks5://egress:1080", proxy.String())
+
+	for _, address := range []string{"https://vault-0.vault-internal:8200", "https://10.1.2.3:8200"} {
+		proxy, err = transport.Proxy(httptest.NewRequest(http.MethodGet, address+"/v1/sys/health", nil))
+		require.NoError(t, err)
+		assert.Nil(t, proxy, address)
+	}
+
+	_, err = vaultTarget{Address: "https://vault:8200", Proxy: "ftp://egress:21"}.apiConfig()
+	assert.ErrorContains(t, err, "unsupported proxy url")
+}
@@ -0,0 +1,226 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"os"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/mitchellh/mapstructure"
+	"github.com/ramizpolic/multiparser"
+	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgTargetsFile     = "targets-file"
+	cfgTargetsParallel = "targets-parallel"
+)
+
+const defaultTargetName = "default"
+
+// clusterTarget is a Vault cluster listed in the targets file.
+type clusterTarget struct {
+	vaultTarget `mapstructure:",squash"`
+
+	Name string `mapstructure:"name"`
+	// token to configure the cluster with instead of the root token
+	Token     string `mapstructure:"token"`
+	TokenFile string `mapstructure:"tokenFile"`
+	// Kubernetes auth role and path to configure the cluster with, instead of the ones set by flags
+	KubernetesAuthRole string `mapstructure:"kubernetesAuthRole"`
+	KubernetesAuthPath string `mapstructure:"kubernetesAuthPath"`
+	// overlay files applied to the config for this cluster
+	Overlays []string `mapstructure:"overlays"`
+	// Vault StatefulSet whose rollouts pause configuring the cluster, in the namespace set by flags if empty
+	RolloutStatefulSet string `mapstructure:"rolloutStatefulSet"`
+	RolloutNamespace   string `mapstructure:"rolloutNamespace"`
+}
+
+// configureTarget is a Vault cluster the configurer applies the config to.
+type configureTarget struct {
+	Name      string
+	Address   string
+	Namespace string
+	Vault     bankvaults.Vault
+	Overlays  []string
+
+	// Vault StatefulSet whose rollouts pause configuring the target
+	RolloutStatefulSet string
+	RolloutNamespace   string
+
+	// newVault creates another helper for the target, with its own view of the applied config
+	newVault func(ctx context.Context) (bankvaults.Vault, error)
+}
+
+// clusterTargetsForConfig returns the clusters listed in the targets file, or the single cluster configured by flags.
+func clusterTargetsForConfig(cfg *viper.Viper, parser multiparser.Parser) ([]clusterTarget, error) {
+	targetsFile := cfg.GetString(cfgTargetsFile)
+	if targetsFile == "" {
+		return []clusterTarget{{vaultTarget: targetForConfig(cfg), Name: defaultTargetName, Overlays: cfg.GetStringSlice(cfgOverlays)}}, nil
+	}
+
+	return readTargetsFile(parser, targetsFile)
+}
+
+// readTargetsFile returns the clusters listed in a targets file.
+func readTargetsFile(parser multiparser.Parser, targetsFile string) ([]clusterTarget, error) {
+	content, err := os.ReadFile(targetsFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading targets file %s", targetsFile)
+	}
+
+	var data map[string]interface{}
+	if err := parser.Parse(content, &data); err != nil {
+		return nil, errors.Wrapf(err, "error parsing targets file %s", targetsFile)
+	}
+
+	var targets []clusterTarget
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:  mapstructure.StringToTimeDurationHookFunc(),
+		ErrorUnused: true,
+		Result:      &targets,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating targets decoder")
+	}
+	if err := decoder.Decode(data["targets"]); err != nil {
+		return nil, errors.Wrapf(err, "error decoding targets file %s", targetsFile)
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.Errorf("no targets listed in targets file %s", targetsFile)
+	}
+
+	names := map[string]bool{}
+	for i, target := range targets {
+		if target.Name == "" {
+			targets[i].Name = target.Address
+		}
+		if names[targets[i].Name] {
+			return nil, errors.Errorf("duplicate target %s in targets file %s", targets[i].Name, targetsFile)
+		}
+		names[targets[i].Name] = true
+	}
+
+	return targets, nil
+}
+
+// configureTargetsForConfig creates a Vault helper for each cluster the config is applied to.
+func configureTargetsForConfig(ctx context.Context, cfg *viper.Viper, parser multiparser.Parser, store kv.Service) ([]configureTarget, error) {
+	clusterTargets, err := clusterTargetsForConfig(cfg, parser)
+	if err != nil {
+		return nil, err
+	}
+
+	license, err := licenseForConfig(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading license")
+	}
+
+	secretResolver := newK8sSecretResolver(cfg.GetString(cfgSecretRefNamespace))
+
+	targets := make([]configureTarget, 0, len(clusterTargets))
+	for _, clusterTarget := range clusterTargets {
+		cl, err := clusterTarget.newFailoverClient()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error connecting to vault target %s", clusterTarget.Name)
+		}
+
+		vaultConfig := vaultConfigForConfig(cfg)
+		vaultConfig.License = license
+		vaultConfig.LicenseKVKey = cfg.GetString(cfgLicenseKVKey)
+		vaultConfig.SecretResolver = secretResolver
+		vaultConfig.KubernetesAuth = kubernetesAuthForConfig(cfg, clusterTarget)
+		vaultConfig.AppRoleAuth = appRoleAuthForConfig(cfg, secretResolver)
+		vaultConfig.CertAuth = certAuthForConfig(cfg)
+		if clusterTarget.Name != defaultTargetName {
+			vaultConfig.StateKeySuffix = clusterTarget.Name
+		}
+
+		vaultConfig.Token = clusterTarget.Token
+		vaultConfig.TokenFile = clusterTarget.TokenFile
+		if vaultConfig.Token == "" && vaultConfig.TokenFile == "" {
+			vaultConfig.TokenFile = cfg.GetString(cfgTokenFile)
+		}
+
+		vaultConfig.AuditTrail, err = auditTrailForConfig(cfg, store, cl)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating audit trail of vault target %s", clusterTarget.Name)
+		}
+
+		newVault := func(ctx context.Context) (bankvaults.Vault, error) {
+			return bankvaults.New(ctx, store, cl, vaultConfig)
+		}
+		v, err := newVault(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error creating vault helper of vault target %s", clusterTarget.Name)
+		}
+
+		targets = append(targets, configureTarget{
+			Name:               clusterTarget.Name,
+			Address:            clusterTarget.Address,
+			Namespace:          clusterTarget.Namespace,
+			Vault:              v,
+			Overlays:           clusterTarget.Overlays,
+			RolloutStatefulSet: clusterTarget.RolloutStatefulSet,
+			RolloutNamespace:   clusterTarget.RolloutNamespace,
+			newVault:           newVault,
+		})
+	}
+
+	return targets, nil
+}
+
+// applyToTargets calls fn for each target, sequentially or in parallel, returning all the errors.
+func applyToTargets(targets []configureTarget, parallel bool, fn func(target configureTarget) error) error {
+	if !parallel {
+		var errs error
+		for _, target := range targets {
+			errs = errors.Append(errs, errors.Wrapf(fn(target), "vault target %s", target.Name))
+		}
+
+		return errs
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs error
+	for _, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer recoverScrubbed()
+			defer wg.Done()
+
+			err := errors.Wrapf(fn(target), "vault target %s", target.Name)
+
+			mu.Lock()
+			defer mu.Unlock()
+			errs = errors.Append(errs, err)
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func init() {
+	configStringVar(configureCmd, cfgTargetsFile, "", "YAML/JSON file listing the Vault clusters to apply the config to under 'targets', instead of the single Vault configured by flags")
+	configBoolVar(configureCmd, cfgTargetsParallel, false, "Apply the config to the Vault clusters of the targets file in parallel")
+}
@@ -0,0 +1,71 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyToTargetsParallel(t *testing.T) {
+	targets := []configureTarget{{Name: "eu"}, {Name: "us"}, {Name: "ap"}}
+
+	// Every target waits for all of them to start, which only finishes when they run in parallel
+	var started sync.WaitGroup
+	started.Add(len(targets))
+	allStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+
+	err := applyToTargets(targets, true, func(target configureTarget) error {
+		started.Done()
+		select {
+		case <-allStarted:
+		case <-time.After(5 * time.Second):
+			return errors.New("targets were not applied in parallel")
+		}
+
+		if target.Name == "us" {
+			return errors.New("permission denied")
+		}
+
+		return nil
+	})
+
+	require.Error(t, err)
+	errs := errors.GetErrors(err)
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "vault target us: permission denied")
+}
+
+func TestApplyToTargetsSequential(t *testing.T) {
+	targets := []configureTarget{{Name: "eu"}, {Name: "us"}, {Name: "ap"}}
+
+	var applied []string
+	err := applyToTargets(targets, false, func(target configureTarget) error {
+		applied = append(applied, target.Name)
+		return errors.Errorf("error applying to %s", target.Name)
+	})
+
+	assert.Equal(t, []string{"eu", "us", "ap"}, applied)
+	assert.Len(t, errors.GetErrors(err), 3)
+}
@@ -0,0 +1,173 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+const (
+	cfgTokenSyncSecret    = "token-sync-secret"
+	cfgTokenSyncNamespace = "token-sync-namespace"
+	cfgTokenSyncKeys      = "token-sync-keys"
+)
+
+// tokenSyncRotatedAnnotation tells when a token of the synced Secret last changed.
+const tokenSyncRotatedAnnotation = "bank-vaults.io/token-rotated-at"
+
+// tokenSyncKVStore copies the tokens written to the key store into a Kubernetes Secret, so the automation
+// reading it always finds the current root or configurer token. The Secret is written with a single
+// update guarded by its resource version, a failed sync is retried the next time the token is read.
+type tokenSyncKVStore struct {
+	kv.Service
+
+	client       kubernetes.Interface
+	namespace    string
+	secret       string
+	keys         []string
+	fieldManager string
+
+	mu sync.Mutex
+	// digests of the tokens in the Secret, by key
+	synced map[string][sha256.Size]byte
+}
+
+func newTokenSyncKVStore(store kv.Service, client kubernetes.Interface, namespace, secret string, keys []string, fieldManager string) *tokenSyncKVStore {
+	return &tokenSyncKVStore{
+		Service:      store,
+		client:       client,
+		namespace:    namespace,
+		secret:       secret,
+		keys:         keys,
+		fieldManager: fieldManager,
+		synced:       map[string][sha256.Size]byte{},
+	}
+}
+
+func tokenSyncStoreForConfig(cfg *viper.Viper, store kv.Service) (kv.Service, error) {
+	client, err := newK8sClient()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := cfg.GetString(cfgTokenSyncNamespace)
+	if namespace == "" {
+		namespace = podNamespace()
+	}
+
+	return newTokenSyncKVStore(store, client, namespace, cfg.GetString(cfgTokenSyncSecret), cfg.GetStringSlice(cfgTokenSyncKeys), cfg.GetString(cfgK8SFieldManager)), nil
+}
+
+func (s *tokenSyncKVStore) Set(ctx context.Context, key string, value []byte) error {
+	if err := s.Service.Set(ctx, key, value); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	// The token is stored, failing to sync it mustn't fail the init or the rotation
+	if slices.Contains(s.keys, key) {
+		if err := s.sync(ctx, key, value); err != nil {
+			slog.Error("error syncing token to secret, retrying at its next read", "key", key, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Get syncs the tokens stored before the sync was enabled, or rotated by another replica.
+func (s *tokenSyncKVStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.Service.Get(ctx, key)
+	if err != nil || !slices.Contains(s.keys, key) {
+		return value, err //nolint:wrapcheck
+	}
+
+	if err := s.sync(ctx, key, value); err != nil {
+		slog.Warn("error syncing token to secret", "key", key, "error", err)
+	}
+
+	return value, nil
+}
+
+func (s *tokenSyncKVStore) sync(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	digest := sha256.Sum256(value)
+	if synced, ok := s.synced[key]; ok && synced == digest {
+		return nil
+	}
+
+	secrets := s.client.CoreV1().Secrets(s.namespace)
+	rotatedAt := time.Now().UTC().Format(time.RFC3339)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		secret, err := secrets.Get(ctx, s.secret, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			_, err = secrets.Create(ctx, &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   s.namespace,
+					Name:        s.secret,
+					Annotations: map[string]string{tokenSyncRotatedAnnotation: rotatedAt},
+				},
+				Data: map[string][]byte{key: value},
+			}, metav1.CreateOptions{FieldManager: s.fieldManager})
+
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[key] = value
+		if secret.Annotations == nil {
+			secret.Annotations = map[string]string{}
+		}
+		secret.Annotations[tokenSyncRotatedAnnotation] = rotatedAt
+		_, err = secrets.Update(ctx, secret, metav1.UpdateOptions{FieldManager: s.fieldManager})
+
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error writing secret %s/%s", s.namespace, s.secret)
+	}
+
+	s.synced[key] = digest
+	slog.Info(fmt.Sprintf("synced token %s to secret %s/%s", key, s.namespace, s.secret))
+
+	return nil
+}
+
+func init() {
+	configStringVar(rootCmd, cfgTokenSyncSecret, "", "Name of a K8S Secret the tokens are copied to whenever they are stored or rotated, disabled if empty")
+	configStringVar(rootCmd, cfgTokenSyncNamespace, "", "Namespace of the K8S Secret the tokens are copied to, defaults to POD_NAMESPACE or the namespace of the pod")
+	configStringSliceVar(rootCmd, cfgTokenSyncKeys, []string{"vault-root", "vault-configurer-token"}, "Key store keys of the tokens copied to the K8S Secret, only the stored tokens are copied")
+}
@@ -0,0 +1,76 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+// mapKVStore keeps the values in memory.
+type mapKVStore map[string][]byte
+
+func (s mapKVStore) Set(_ context.Context, key string, value []byte) error {
+	s[key] = value
+	return nil
+}
+
+func (s mapKVStore) Get(_ context.Context, key string) ([]byte, error) {
+	value, ok := s[key]
+	if !ok {
+		return nil, kv.NewNotFoundError("key '%s' is not found", key)
+	}
+
+	return value, nil
+}
+
+func TestTokenSyncKVStore(t *testing.T) {
+	ctx := context.Background()
+	backend := mapKVStore{"vault-configurer-token": []byte("configurer-1")}
+	client := fake.NewSimpleClientset()
+	store := newTokenSyncKVStore(backend, client, "ops", "vault-tokens", []string{"vault-root", "vault-configurer-token"}, "bank-vaults")
+
+	tokens := func() map[string][]byte {
+		secret, err := client.CoreV1().Secrets("ops").Get(ctx, "vault-tokens", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.NotEmpty(t, secret.Annotations[tokenSyncRotatedAnnotation])
+
+		return secret.Data
+	}
+
+	// A token stored before the sync was enabled is synced at its first read
+	_, err := store.Get(ctx, "vault-configurer-token")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"vault-configurer-token": []byte("configurer-1")}, tokens())
+
+	require.NoError(t, store.Set(ctx, "vault-root", []byte("root")))
+	require.NoError(t, store.Set(ctx, "vault-unseal-0", []byte("unseal-key")))
+	require.NoError(t, store.Set(ctx, "vault-configurer-token", []byte("configurer-2")))
+	assert.Equal(t, map[string][]byte{"vault-root": []byte("root"), "vault-configurer-token": []byte("configurer-2")}, tokens())
+	assert.Equal(t, []byte("unseal-key"), backend["vault-unseal-0"])
+
+	// Unchanged tokens aren't written again
+	writes := len(client.Actions())
+	_, err = store.Get(ctx, "vault-root")
+	require.NoError(t, err)
+	assert.Len(t, client.Actions(), writes)
+}
@@ -21,10 +21,12 @@ import (
 	"os"
 	"time"
 
-	"github.com/bank-vaults/vault-sdk/vault"
+	"emperror.dev/errors"
 	"github.com/spf13/cobra"
 
-	internalVault "github.com/bank-vaults/bank-vaults/internal/vault"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/wrapped"
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
 )
 
 const (
@@ -36,6 +38,14 @@ const (
 	cfgRaftHAStorage     = "raft-ha-storage"
 )
 
+// Exit codes of unseal --once.
+const (
+	unsealExitUnsealed = 0
+	unsealExitError    = 1
+	// Vault is left sealed, e.g. in auto-unseal or dry-run mode
+	unsealExitSealed = 2
+)
+
 type unsealCfg struct {
 	unsealPeriod      time.Duration
 	proceedInit       bool
@@ -45,6 +55,7 @@ type unsealCfg struct {
 	raftLeaderAddress string
 	raftSecondary     bool
 	raftHAStorage     bool
+	raftPeers         *raftPeers
 }
 
 var unsealCmd = &cobra.Command{
@@ -56,8 +67,18 @@ from one of the following:
 - AWS KMS keyring (backed by S3)
 - Azure Key Vault
 - Alibaba KMS (backed by OSS)
-- Kubernetes Secrets (should be used only for development purposes)`,
+- Kubernetes Secrets (should be used only for development purposes)
+
+With --once the seal status is checked and the keys are submitted a single time, the exit code
+is 0 if Vault is unsealed, 1 on errors and 2 if Vault is left sealed.`,
 	Run: func(cmd *cobra.Command, _ []string) {
+		exitCode := 0
+		defer func() {
+			if exitCode != 0 {
+				os.Exit(exitCode)
+			}
+		}()
+
 		ctx, cancel := context.WithCancel(cmd.Context())
 		defer cancel()
 		var unsealConfig unsealCfg
@@ -70,26 +91,41 @@ from one of the following:
 		unsealConfig.raftLeaderAddress = c.GetString(cfgRaftLeaderAddress)
 		unsealConfig.raftSecondary = c.GetBool(cfgRaftSecondary)
 		unsealConfig.raftHAStorage = c.GetBool(cfgRaftHAStorage)
+		raftPeers, err := raftPeersForConfig(c)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error generating raft peer addresses: %s", err.Error()))
+			os.Exit(1)
+		}
+		unsealConfig.raftPeers = raftPeers
 
-		store, err := kvStoreForConfig(ctx, c)
+		store, wrappedInitOutput, err := kvStoreForConfig(ctx, c)
 		if err != nil {
 			slog.Error(fmt.Sprintf("error creating kv store: %s", err.Error()))
 			os.Exit(1)
 		}
 
-		cl, err := vault.NewRawClient()
+		cl, err := targetForConfig(c).newClient()
 		if err != nil {
 			slog.Error(fmt.Sprintf("error connecting to vault: %s", err.Error()))
 			os.Exit(1)
 		}
 
-		v, err := internalVault.New(ctx, store, cl, vaultConfigForConfig(c))
+		v, err := bankvaults.New(ctx, store, cl, vaultConfigForConfig(c))
 		if err != nil {
 			slog.Error(fmt.Sprintf("error creating vault helper: %s", err.Error()))
 			os.Exit(1)
 		}
+		defer v.Close()
+
+		unsealOutcomes.setWindow(c.GetDuration(cfgMetricsSLOWindow))
+		metrics := prometheusExporter{
+			Vault:  v,
+			Mode:   "unseal",
+			Server: metricsServerForConfig(c),
+			Labels: instanceLabelsForConfig(c, cl.Address()),
 
-		metrics := prometheusExporter{Vault: v, Mode: "unseal"}
+			LeaderElection: c.GetBool(cfgLeaderElection),
+		}
 		go func() {
 			err := metrics.Run()
 			if err != nil {
@@ -98,87 +134,168 @@ from one of the following:
 			}
 		}()
 
-		if unsealConfig.proceedInit && unsealConfig.raft {
-			slog.Info("joining leader vault...")
+		stopStatsd, err := startStatsdSink(ctx, c, &metrics)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error creating statsd sink: %s", err.Error()))
+			os.Exit(1)
+		}
+		defer stopStatsd()
+
+		// A standby replica idles until it acquires the leader election Lease
+		health := newHealthChecker(store, []bankvaults.Vault{v}, c.GetDuration(cfgHealthLoopTimeout), c.GetBool(cfgLeaderElection))
+		health.maxConsecutiveFailures = c.GetInt(cfgMaxConsecutiveFailures)
+		health.serve(ctx, c)
 
-			initialized, err := v.RaftInitialized(ctx)
-			if err != nil {
-				sealed, sErr := v.Sealed()
-				if sErr != nil || sealed {
-					slog.Error(fmt.Sprintf("error checking if vault is initialized: %s", err.Error()))
-					os.Exit(1)
+		// Only the active replica initializes and unseals Vault
+		run := func(ctx context.Context) error {
+			if unsealConfig.proceedInit && unsealConfig.raft {
+				slog.Info("joining leader vault...")
+
+				initialized, err := v.RaftInitialized(ctx)
+				if err != nil {
+					sealed, sErr := v.Sealed()
+					if sErr != nil || sealed {
+						return errors.Wrap(err, "error checking if vault is initialized")
+					}
+					slog.Warn(fmt.Sprintf("error checking if vault is initialized, but vault is unsealed so continuing: %s", err.Error()))
 				}
-				slog.Warn(fmt.Sprintf("error checking if vault is initialized, but vault is unsealed so continuing: %s", err.Error()))
-			}
 
-			// If this is the first instance we have to init it, this happens once in the clusters lifetime
-			if !initialized && !unsealConfig.raftSecondary {
-				slog.Info("initializing vault...")
-				if err := v.Init(ctx); err != nil {
-					slog.Error(fmt.Sprintf("error initializing vault: %s", err.Error()))
-					os.Exit(1)
+				// If this is the first instance we have to init it, this happens once in the clusters lifetime
+				if !initialized && !unsealConfig.secondary() {
+					slog.Info("initializing vault...")
+					if err := initVault(ctx, v); err != nil {
+						return errors.Wrap(err, "error initializing vault")
+					}
+				} else {
+					slog.Info("joining raft cluster...")
+					if err := unsealConfig.joinRaft(v); err != nil {
+						return errors.Wrap(err, "error joining leader vault")
+					}
 				}
-			} else {
-				slog.Info("joining raft cluster...")
-				if err := v.RaftJoin(unsealConfig.raftLeaderAddress); err != nil {
-					slog.Error(fmt.Sprintf("error joining leader vault: %s", err.Error()))
-					os.Exit(1)
+			} else if unsealConfig.proceedInit {
+				slog.Info("initializing vault...")
+				if err := initVault(ctx, v); err != nil {
+					return errors.Wrap(err, "error initializing vault")
 				}
 			}
-		} else if unsealConfig.proceedInit {
-			slog.Info("initializing vault...")
-			if err := v.Init(ctx); err != nil {
-				slog.Error(fmt.Sprintf("error initializing vault: %s", err.Error()))
-				os.Exit(1)
-			}
-		}
 
-		raftEstablished := false
-		for {
-			if !unsealConfig.auto {
-				unseal(ctx, unsealConfig, v)
-			}
+			raftEstablished := false
+			for {
+				health.heartbeat()
+				var err error
+				if !unsealConfig.auto {
+					err = unseal(ctx, v, wrappedInitOutput)
+					unsealOutcomes.record("", err == nil)
+				}
+				// The unsealer never configures, the root token of init is revoked once Vault is unsealed
+				v.RevokeInitRootToken(ctx)
+
+				if unsealConfig.raftHAStorage && !raftEstablished {
+					raftEstablished = raftJoin(v)
+				}
+
+				health.iterationDone(err)
+				if unsealConfig.runOnce {
+					exitCode = unsealExitCode(v, err)
+					if err := pushMetrics(ctx, c, &metrics); err != nil {
+						slog.Error(fmt.Sprintf("error pushing metrics: %s", err.Error()))
+					}
 
-			if unsealConfig.raftHAStorage && !raftEstablished {
-				raftEstablished = raftJoin(v)
+					return nil
+				}
+				if health.failedTooOften() {
+					return errors.Errorf("unsealing failed %d times in a row", health.maxConsecutiveFailures)
+				}
+
+				// wait unsealPeriod before trying again
+				if err := sleepContext(ctx, unsealConfig.unsealPeriod); err != nil {
+					return nil
+				}
 			}
+		}
 
-			// wait unsealPeriod before trying again
-			time.Sleep(unsealConfig.unsealPeriod)
+		if c.GetBool(cfgLeaderElection) {
+			err = runWithLeaderElection(ctx, c, defaultUnsealLeaseName, run)
+		} else {
+			err = run(ctx)
+		}
+		if err != nil {
+			slog.Error(err.Error())
+			// The Lease is released by now, exit after the deferred shutdown of the statsd sink
+			exitCode = 1
 		}
 	},
 }
 
-func unseal(ctx context.Context, unsealConfig unsealCfg, v internalVault.Vault) {
+func unseal(ctx context.Context, v bankvaults.Vault, wrappedInitOutput *wrapped.Store) error {
 	slog.Debug("checking if vault is sealed...")
 	sealed, err := v.Sealed()
 	if err != nil {
 		slog.Error(fmt.Sprintf("error checking if vault is sealed: %s", err.Error()))
-		exitIfNecessary(unsealConfig, 1)
-		return
+		return err
 	}
 
 	// If vault is not sealed, we stop here and wait for another unsealPeriod
 	if !sealed {
 		slog.Debug("vault is not sealed")
-		exitIfNecessary(unsealConfig, 0)
-		return
+		unsealLastSuccess.SetToCurrentTime()
+		refreshWrappedInitOutput(ctx, wrappedInitOutput)
+		return nil
 	}
 
 	slog.Info("vault is sealed, unsealing")
+	if skipDryRun("unseal vault") {
+		return nil
+	}
 
 	if err = v.Unseal(ctx); err != nil {
 		slog.Error(fmt.Sprintf("error unsealing vault: %s", err.Error()))
-		exitIfNecessary(unsealConfig, 1)
-		return
+		return err
 	}
 
 	slog.Info("successfully unsealed vault")
+	unsealLastSuccess.SetToCurrentTime()
+	notifyLifecycle(ctx, notify.EventUnsealed, "unsealed vault")
+
+	return nil
+}
+
+// unsealExitCode returns the exit code of a one-shot run from its error and the final seal status.
+func unsealExitCode(v bankvaults.Vault, err error) int {
+	if err != nil {
+		return unsealExitError
+	}
+
+	sealed, err := v.Sealed()
+	if err != nil {
+		slog.Error(fmt.Sprintf("error checking if vault is sealed: %s", err.Error()))
+		return unsealExitError
+	}
+	if sealed {
+		slog.Warn("vault is left sealed")
+		return unsealExitSealed
+	}
+
+	return unsealExitUnsealed
+}
 
-	exitIfNecessary(unsealConfig, 0)
+// secondary tells if this instance should always join a raft leader: it is configured so,
+// or it isn't the first pod of the StatefulSet.
+func (cfg unsealCfg) secondary() bool {
+	return cfg.raftSecondary || (cfg.raftPeers != nil && cfg.raftPeers.ordinal > 0)
 }
 
-func raftJoin(v internalVault.Vault) bool {
+// joinRaft joins Vault to the raft cluster of the configured leader,
+// or of the first generated peer that lets it join.
+func (cfg unsealCfg) joinRaft(v bankvaults.Vault) error {
+	if cfg.raftLeaderAddress != "" || cfg.raftPeers == nil {
+		return joinRaft(v, cfg.raftLeaderAddress)
+	}
+
+	return joinRaftPeers(v, cfg.raftPeers.leaderAddresses())
+}
+
+func raftJoin(v bankvaults.Vault) bool {
 	leaderAddress, err := v.LeaderAddress()
 	if err != nil {
 		slog.Error(fmt.Sprintf("error checking leader vault: %s", err.Error()))
@@ -188,7 +305,7 @@ func raftJoin(v internalVault.Vault) bool {
 	// If this instance can't tell the leaderAddress, it is not part of the cluster,
 	// so we should ask it join.
 	if leaderAddress == "" {
-		if err = v.RaftJoin(""); err != nil {
+		if err = joinRaft(v, ""); err != nil {
 			slog.Error(fmt.Sprintf("error joining leader vault: %s", err.Error()))
 			return false
 		}
@@ -197,10 +314,35 @@ func raftJoin(v internalVault.Vault) bool {
 	return true
 }
 
-func exitIfNecessary(unsealConfig unsealCfg, code int) {
-	if unsealConfig.runOnce {
-		os.Exit(code)
+// initVault initializes Vault, unless in dry-run mode.
+func initVault(ctx context.Context, v bankvaults.Vault) error {
+	if skipDryRun("initialize vault") {
+		return nil
+	}
+
+	initialized, err := v.Initialized()
+	if err != nil {
+		return err
+	}
+
+	if err := v.Init(ctx); err != nil {
+		return err
+	}
+
+	if !initialized {
+		notifyLifecycle(ctx, notify.EventInitialized, "initialized vault")
+	}
+
+	return nil
+}
+
+// joinRaft joins Vault to the raft cluster of the leader, unless in dry-run mode.
+func joinRaft(v bankvaults.Vault, leaderAddress string) error {
+	if skipDryRun("join raft cluster", "leader", leaderAddress) {
+		return nil
 	}
+
+	return v.RaftJoin(leaderAddress)
 }
 
 func init() {
@@ -210,7 +352,7 @@ func init() {
 	configBoolVar(unsealCmd, cfgRaftSecondary, false, "This instance should always join a raft leader")
 	configBoolVar(unsealCmd, cfgRaftHAStorage, false, "Join leader vault instance in raft HA storage mode")
 	configStringVar(unsealCmd, cfgInitRootToken, "", "Root token for the new vault cluster (only if -init=true)")
-	configBoolVar(unsealCmd, cfgPreFlightChecks, true, "should the key store be tested first to validate access rights")
+	configBoolVar(unsealCmd, cfgPreFlightChecks, true, "should the key store be tested first to validate access rights and that it decrypts what it encrypts")
 	configBoolVar(unsealCmd, cfgAuto, false, "Run in auto-unseal mode")
 
 	rootCmd.AddCommand(unsealCmd)
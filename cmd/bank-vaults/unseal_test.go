@@ -0,0 +1,28 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnsealExitCode(t *testing.T) {
+	assert.Equal(t, unsealExitUnsealed, unsealExitCode(fakeVault{sealed: false}, nil))
+	assert.Equal(t, unsealExitSealed, unsealExitCode(fakeVault{sealed: true}, nil))
+	assert.Equal(t, unsealExitError, unsealExitCode(fakeVault{sealed: false}, errors.New("error unsealing vault")))
+}
@@ -0,0 +1,81 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/spf13/cobra"
+
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [config files]",
+	Short: "Validate the external configuration offline",
+	Long: `This command parses and templates the external configuration files like
+configure does, then decodes them, lints the policies and looks for duplicate
+paths and names, without contacting Vault. It exits with 1 if any problem is found,
+so it can be used in pre-merge CI checks.
+
+Validates the default config file if no files are given.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{bankvaults.DefaultConfigFile}
+		}
+
+		parser, err := multiparser.New(parser.JSON, parser.YAML)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error file parsers: %v", err))
+			os.Exit(1)
+		}
+
+		if problems := validateConfigurations(os.Stdout, parser, args); problems > 0 {
+			slog.Error(fmt.Sprintf("found %d problems in the configuration", problems))
+			os.Exit(1)
+		}
+
+		slog.Info("configuration is valid")
+	},
+}
+
+// validateConfigurations prints the problems of the config files and returns their number.
+func validateConfigurations(w io.Writer, parser multiparser.Parser, vaultConfigFiles []string) int {
+	problems := 0
+	for _, vaultConfigFile := range vaultConfigFiles {
+		config, err := readConfiguration(parser, vaultConfigFile)
+		if err != nil {
+			fmt.Fprintf(w, "%s: %s\n", vaultConfigFile, err)
+			problems++
+			continue
+		}
+
+		for _, err := range bankvaults.ValidateConfig(config.Data) {
+			fmt.Fprintf(w, "%s: %s\n", vaultConfigFile, err)
+			problems++
+		}
+	}
+
+	return problems
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
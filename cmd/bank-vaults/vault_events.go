@@ -0,0 +1,316 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/coder/websocket"
+	"github.com/hashicorp/vault/api"
+	"github.com/jpillora/backoff"
+	"github.com/ramizpolic/multiparser"
+	"github.com/spf13/viper"
+)
+
+const (
+	cfgVaultEvents            = "vault-events"
+	cfgVaultEventTypes        = "vault-event-types"
+	cfgVaultEventsTokenFile   = "vault-events-token-file"
+	cfgVaultEventsQuietPeriod = "vault-events-quiet-period"
+)
+
+// Vault events are small JSON documents, anything larger is not one
+const maxVaultEventSize = 1 << 20
+
+// errVaultEventsUnsupported is returned by Vaults without the event notification API, e.g. before 1.16.
+var errVaultEventsUnsupported = errors.New("vault doesn't support event notifications")
+
+// vaultEvent is the part of a Vault event notification used to log it.
+type vaultEvent struct {
+	Data struct {
+		EventType string `json:"event_type"`
+		Event     struct {
+			Metadata map[string]interface{} `json:"metadata"`
+		} `json:"event"`
+	} `json:"data"`
+}
+
+// vaultEventSource is a target subscribed to, with the token of the subscription.
+type vaultEventSource struct {
+	name      string
+	client    *api.Client
+	token     string
+	tokenFile string
+}
+
+// currentToken returns the token of the subscription, the file is read at every connection so renewed tokens are picked up.
+func (s vaultEventSource) currentToken() (string, error) {
+	if s.tokenFile == "" {
+		return s.token, nil
+	}
+
+	token, err := os.ReadFile(s.tokenFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading token file %s", s.tokenFile)
+	}
+
+	return strings.TrimSpace(string(token)), nil
+}
+
+// defaultVaultEventTypes are the events of the secrets configure writes being deleted out-of-band, e.g. the startup
+// secrets. Vault has no events for mounts, auth methods or policies, changes of those are only reverted by the next
+// configure run, and the writes to the KV engines are left out as they are mostly the ones of the applications.
+var defaultVaultEventTypes = []string{"kv-v1/delete", "kv-v2/data-delete", "kv-v2/destroy", "kv-v2/metadata-delete"}
+
+// vaultEventTrigger applies the configs again when Vault notifies about a change, so changes made out-of-band are
+// reverted right away instead of at the next change of the config. The events arriving during a configure run or
+// its quiet period are ignored, they are most likely caused by the run itself. With --skip-unchanged the runs are
+// skipped unless the mount table changed too, the rest of Vault isn't part of the fingerprint of the last apply.
+type vaultEventTrigger struct {
+	sources     []vaultEventSource
+	eventTypes  []string
+	quietPeriod time.Duration
+	parser      multiparser.Parser
+
+	mu sync.Mutex
+	// the configs applied last, by path
+	configs    map[string]*configFile
+	running    int
+	pending    bool
+	quietUntil time.Time
+}
+
+// vaultEventTriggerForConfig returns the trigger subscribed to the events of the targets, nil if it is disabled.
+func vaultEventTriggerForConfig(cfg *viper.Viper, parser multiparser.Parser) (*vaultEventTrigger, error) {
+	if !cfg.GetBool(cfgVaultEvents) {
+		return nil, nil
+	}
+
+	clusterTargets, err := clusterTargetsForConfig(cfg, parser)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger := &vaultEventTrigger{
+		eventTypes:  cfg.GetStringSlice(cfgVaultEventTypes),
+		quietPeriod: cfg.GetDuration(cfgVaultEventsQuietPeriod),
+		parser:      parser,
+		configs:     map[string]*configFile{},
+	}
+	for _, target := range clusterTargets {
+		cl, err := target.newFailoverClient()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error connecting to vault target %s", target.Name)
+		}
+
+		source := vaultEventSource{name: target.Name, client: cl, token: target.Token, tokenFile: target.TokenFile}
+		switch {
+		case cfg.GetString(cfgVaultEventsTokenFile) != "":
+			source.token, source.tokenFile = "", cfg.GetString(cfgVaultEventsTokenFile)
+		case source.token == "" && source.tokenFile == "":
+			source.tokenFile = cfg.GetString(cfgTokenFile)
+		}
+		if source.token == "" && source.tokenFile == "" {
+			return nil, errors.Errorf("subscribing to the events of vault target %s needs a token, set --%s", target.Name, cfgVaultEventsTokenFile)
+		}
+
+		trigger.sources = append(trigger.sources, source)
+	}
+
+	return trigger, nil
+}
+
+// watch subscribes to the events of the targets until the context is done, sending the configs to apply again.
+func (t *vaultEventTrigger) watch(ctx context.Context, configurations chan<- *configFile) {
+	if t == nil {
+		return
+	}
+
+	for _, source := range t.sources {
+		for _, eventType := range t.eventTypes {
+			go t.subscribe(ctx, source, eventType, configurations)
+		}
+	}
+}
+
+// subscribe keeps a subscription to an event type of a target, reconnecting with a backoff.
+func (t *vaultEventTrigger) subscribe(ctx context.Context, source vaultEventSource, eventType string, configurations chan<- *configFile) {
+	b := &backoff.Backoff{Min: time.Second, Max: time.Minute, Factor: 2}
+	for {
+		connected, err := subscribeVaultEvents(ctx, source, eventType, func(event vaultEvent) {
+			t.eventReceived(ctx, source.name, event, configurations)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if errors.Is(err, errVaultEventsUnsupported) {
+			slog.Warn("vault event notifications are not available, changes made out-of-band are only reverted at the next config change", "target", source.name, "eventType", eventType)
+			return
+		}
+		if connected {
+			b.Reset()
+		}
+
+		wait := b.Duration()
+		slog.Warn("vault event subscription lost, subscribing again...", "target", source.name, "eventType", eventType, "error", err, "period", wait)
+		if err := sleepContext(ctx, wait); err != nil {
+			return
+		}
+	}
+}
+
+// eventReceived sends the configs applied last to apply again, unless a run is pending, in progress or just finished.
+func (t *vaultEventTrigger) eventReceived(ctx context.Context, target string, event vaultEvent, configurations chan<- *configFile) {
+	attrs := []interface{}{"target", target, "eventType", event.Data.EventType, "path", event.Data.Event.Metadata["path"]}
+
+	t.mu.Lock()
+	if t.running > 0 || t.pending || time.Now().Before(t.quietUntil) {
+		t.mu.Unlock()
+		slog.Debug("ignoring vault event during a configure run", attrs...)
+		return
+	}
+	configs := make([]*configFile, 0, len(t.configs))
+	for _, path := range slices.Sorted(maps.Keys(t.configs)) {
+		configs = append(configs, t.configs[path])
+	}
+	t.pending = len(configs) > 0
+	t.mu.Unlock()
+
+	if len(configs) == 0 {
+		return
+	}
+
+	slog.Info("vault changed, applying the configs again", attrs...)
+	go func() {
+		for _, config := range configs {
+			reloaded, err := t.reload(ctx, config)
+			if err != nil {
+				slog.Error("error reloading config, not applying it again", "file", config.Path, "error", err)
+				t.forget(config)
+				continue
+			}
+
+			select {
+			case configurations <- reloaded:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// reload reads a config again, the file or the resource may have changed since it was applied.
+func (t *vaultEventTrigger) reload(ctx context.Context, config *configFile) (*configFile, error) {
+	if config.resource == nil {
+		return readConfiguration(t.parser, config.Path)
+	}
+
+	return config.resource.reload(ctx)
+}
+
+// runStarted is called before a config is applied.
+func (t *vaultEventTrigger) runStarted() {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running++
+	t.pending = false
+}
+
+// runFinished is called after a config is applied, it is applied again on the next event.
+func (t *vaultEventTrigger) runFinished(config *configFile) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.running--
+	t.quietUntil = time.Now().Add(t.quietPeriod)
+	t.configs[config.Path] = config
+}
+
+// forget stops applying a config again which can't be reloaded, e.g. its custom resource was deleted.
+func (t *vaultEventTrigger) forget(config *configFile) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.configs, config.Path)
+	t.pending = false
+}
+
+// subscribeVaultEvents subscribes to an event type of the target through its client, so the TLS and proxy settings
+// of the target apply, calling onEvent for each event until the subscription fails or the context is done.
+// It tells if the subscription was established.
+func subscribeVaultEvents(ctx context.Context, source vaultEventSource, eventType string, onEvent func(vaultEvent)) (bool, error) {
+	token, err := source.currentToken()
+	if err != nil {
+		return false, err
+	}
+
+	header := http.Header{}
+	header.Set("X-Vault-Token", token)
+	if namespace := source.client.Namespace(); namespace != "" {
+		header.Set("X-Vault-Namespace", namespace)
+	}
+
+	address := strings.TrimSuffix(source.client.Address(), "/") + "/v1/sys/events/subscribe/" + eventType + "?json=true"
+	conn, resp, err := websocket.Dial(ctx, address, &websocket.DialOptions{
+		// The subscription lasts longer than the timeout of the client
+		HTTPClient: &http.Client{Transport: source.client.CloneConfig().HttpClient.Transport},
+		HTTPHeader: header,
+	})
+	if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed) {
+		return false, errVaultEventsUnsupported
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "error subscribing to vault events")
+	}
+	defer conn.CloseNow()
+
+	conn.SetReadLimit(maxVaultEventSize)
+	for {
+		_, message, err := conn.Read(ctx)
+		if err != nil {
+			return true, err
+		}
+
+		var event vaultEvent
+		if err := json.Unmarshal(message, &event); err != nil {
+			slog.Warn("error parsing vault event", "target", source.name, "error", err)
+			continue
+		}
+		onEvent(event)
+	}
+}
+
+func init() {
+	configBoolVar(configureCmd, cfgVaultEvents, false, "Apply the configs again when Vault notifies about a change through its event notifications (Vault 1.16+), reverting changes made out-of-band right away")
+	configStringSliceVar(configureCmd, cfgVaultEventTypes, defaultVaultEventTypes, "Vault event types to apply the configs again on, may contain * wildcards, Vault has no events for mounts, auth methods and policies")
+	configStringVar(configureCmd, cfgVaultEventsTokenFile, "", "File holding the token to subscribe to the Vault events with, it needs the subscribe capability, defaults to the token configure uses if set")
+	configDurationVar(configureCmd, cfgVaultEventsQuietPeriod, 10*time.Second, "How long the Vault events are ignored after a configure run, the run itself causes them")
+}
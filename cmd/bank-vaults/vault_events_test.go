@@ -0,0 +1,114 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/ramizpolic/multiparser"
+	"github.com/ramizpolic/multiparser/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveVaultEvents answers a subscription with the events, closing it afterwards.
+func serveVaultEvents(t *testing.T, events ...string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/sys/events/subscribe/kv-v2/*", r.URL.Path)
+		assert.Equal(t, "events-token", r.Header.Get("X-Vault-Token"))
+
+		conn, err := websocket.Accept(w, r, nil)
+		require.NoError(t, err)
+		defer conn.CloseNow()
+
+		for _, event := range events {
+			require.NoError(t, conn.Write(r.Context(), websocket.MessageText, []byte(event)))
+		}
+		conn.Close(websocket.StatusNormalClosure, "") //nolint:errcheck
+	}))
+}
+
+func TestSubscribeVaultEvents(t *testing.T) {
+	srv := serveVaultEvents(t,
+		`{"data":{"event_type":"kv-v2/data-write","event":{"metadata":{"path":"secret/data/app"}}}}`,
+		`{"data":{"event_type":"kv-v2/data-delete","event":{"metadata":{"path":"secret/data/app"}}}}`,
+	)
+	defer srv.Close()
+
+	cl, err := vaultTarget{Address: srv.URL}.newClient()
+	require.NoError(t, err)
+
+	var events []string
+	connected, err := subscribeVaultEvents(context.Background(), vaultEventSource{name: "default", client: cl, token: "events-token"}, "kv-v2/*", func(event vaultEvent) {
+		events = append(events, event.Data.EventType+" "+event.Data.Event.Metadata["path"].(string))
+	})
+	assert.True(t, connected)
+	assert.Equal(t, websocket.StatusNormalClosure, websocket.CloseStatus(err))
+	assert.Equal(t, []string{"kv-v2/data-write secret/data/app", "kv-v2/data-delete secret/data/app"}, events)
+}
+
+func TestSubscribeVaultEventsUnsupported(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	cl, err := vaultTarget{Address: srv.URL}.newClient()
+	require.NoError(t, err)
+
+	connected, err := subscribeVaultEvents(context.Background(), vaultEventSource{client: cl, token: "events-token"}, "*", func(vaultEvent) {})
+	assert.False(t, connected)
+	assert.ErrorIs(t, err, errVaultEventsUnsupported)
+}
+
+func TestVaultEventTrigger(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "vault-config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte("policies: []\n"), 0o600))
+	parser, err := multiparser.New(parser.JSON, parser.YAML)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	configurations := make(chan *configFile, 1)
+	trigger := &vaultEventTrigger{quietPeriod: time.Hour, parser: parser, configs: map[string]*configFile{}}
+
+	trigger.eventReceived(ctx, "default", vaultEvent{}, configurations)
+	assert.Empty(t, configurations, "nothing was applied yet")
+
+	config := &configFile{Path: configPath}
+	trigger.runStarted()
+	trigger.eventReceived(ctx, "default", vaultEvent{}, configurations)
+	trigger.runFinished(config)
+	trigger.eventReceived(ctx, "default", vaultEvent{}, configurations)
+	assert.Empty(t, configurations, "the events of the run and its quiet period are ignored")
+
+	trigger.quietUntil = time.Time{}
+	trigger.eventReceived(ctx, "default", vaultEvent{}, configurations)
+	trigger.eventReceived(ctx, "default", vaultEvent{}, configurations)
+	select {
+	case reloaded := <-configurations:
+		assert.Equal(t, config.Path, reloaded.Path)
+		assert.Equal(t, map[string]interface{}{"policies": []interface{}{}}, reloaded.Data)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the config wasn't applied again")
+	}
+	assert.True(t, trigger.pending, "the second event is covered by the pending run")
+}
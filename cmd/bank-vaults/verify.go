@@ -0,0 +1,79 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/ramizpolic/multiparser"
+)
+
+const cfgVerify = "verify"
+
+// Exit codes of configure --verify.
+const (
+	verifyExitConverged = 0
+	verifyExitError     = 1
+	verifyExitDrift     = 2
+)
+
+// verifyConfigurations compares the config files with the state of Vault, prints the drift
+// and returns the exit code: 0 when Vault matches the config, 2 when drift exists and 1 on errors.
+func verifyConfigurations(ctx context.Context, target configureTarget, parser multiparser.Parser, vaultConfigFiles []string) int {
+	v := target.Vault
+	sealed, err := v.Sealed()
+	if err != nil {
+		slog.Error(fmt.Sprintf("error checking if vault is sealed: %s", err.Error()))
+		return verifyExitError
+	}
+	if sealed {
+		slog.Error("vault is sealed, can't verify configuration")
+		return verifyExitError
+	}
+
+	exitCode := verifyExitConverged
+	for _, vaultConfigFile := range vaultConfigFiles {
+		config := parseConfiguration(parser, vaultConfigFile)
+
+		data, err := applyOverlays(parser, config.Data, target.Overlays)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error applying overlays to config file %s: %s", config.Path, err.Error()))
+			return verifyExitError
+		}
+
+		drifts, err := v.Verify(ctx, data)
+		if err != nil {
+			slog.Error(fmt.Sprintf("error verifying config file %s: %s", config.Path, err.Error()))
+			return verifyExitError
+		}
+
+		for _, drift := range drifts {
+			fmt.Fprintf(os.Stdout, "%s: %s\n", config.Path, drift)
+		}
+
+		if len(drifts) > 0 {
+			exitCode = verifyExitDrift
+		}
+	}
+
+	if exitCode == verifyExitConverged {
+		slog.Info("vault matches the configuration")
+	}
+
+	return exitCode
+}
@@ -0,0 +1,85 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"emperror.dev/errors"
+	vaultpkg "github.com/bank-vaults/vault-sdk/vault"
+	"github.com/spf13/viper"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/wrapped"
+	bankvaults "github.com/bank-vaults/bank-vaults/pkg/vault"
+)
+
+const (
+	cfgWrapInitOutput    = "wrap-init-output"
+	cfgWrapInitOutputTTL = "wrap-init-output-ttl"
+)
+
+// wrapInitOutput returns the key store keeping only response-wrapping tokens of the init output,
+// wrapped by the Vault of the vault mode flags.
+func wrapInitOutput(cfg *viper.Viper, store kv.Service) (*wrapped.Store, error) {
+	vaultTarget := kvVaultTargetForConfig(cfg)
+	if vaultTarget.Address == "" {
+		return nil, errors.Errorf("wrapping the init output needs the Vault set by --%s", cfgVaultAddress)
+	}
+
+	vaultConfig, err := vaultTarget.apiConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating wrapping Vault client config")
+	}
+
+	vaultOptions := []vaultpkg.ClientOption{
+		vaultpkg.ClientRole(cfg.GetString(cfgVaultRole)),
+		vaultpkg.ClientAuthPath(cfg.GetString(cfgVaultAuthPath)),
+		vaultpkg.ClientTokenPath(cfg.GetString(cfgVaultTokenPath)),
+		vaultpkg.ClientToken(cfg.GetString(cfgVaultToken)),
+	}
+	if vaultTarget.Namespace != "" {
+		vaultOptions = append(vaultOptions, vaultpkg.VaultNamespace(vaultTarget.Namespace))
+	}
+
+	client, err := vaultpkg.NewClientFromConfig(vaultConfig, vaultOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating wrapping Vault client")
+	}
+
+	keys := bankvaults.InitOutputKeys(vaultConfigForConfig(cfg))
+
+	return wrapped.New(client.RawClient(), store, cfg.GetDuration(cfgWrapInitOutputTTL), keys), nil
+}
+
+// refreshWrappedInitOutput wraps again the init output before its wrapping tokens expire,
+// it is a no-op if the init output isn't wrapped.
+func refreshWrappedInitOutput(ctx context.Context, wrappedInitOutput *wrapped.Store) {
+	if wrappedInitOutput == nil || dryRun {
+		return
+	}
+
+	if err := wrappedInitOutput.Refresh(ctx); err != nil {
+		slog.Error(fmt.Sprintf("error refreshing wrapped init output: %s", err.Error()))
+	}
+}
+
+func init() {
+	configBoolVar(rootCmd, cfgWrapInitOutput, false, "Store only response-wrapping tokens of the unseal keys and root token, wrapped by the Vault of --"+cfgVaultAddress+" and unwrapped when needed")
+	configDurationVar(rootCmd, cfgWrapInitOutputTTL, 768*time.Hour, "TTL of the wrapping tokens of the init output, they are wrapped again when half of it passed")
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secmem
+
+import (
+	"strings"
+	"sync"
+)
+
+// minTrackedLength is the length under which values aren't tracked, they would match too much of the logs.
+const minTrackedLength = 8
+
+// RedactedValue replaces the tracked values in the redacted texts.
+const RedactedValue = "<redacted>"
+
+// registry holds the secrets currently in memory, so the logs can be checked for them.
+// It keeps copies of the secrets, so it is only used once tracking is enabled.
+var registry = struct {
+	sync.RWMutex
+	enabled    bool
+	values     map[string]int
+	sources    map[int]func() []string
+	nextSource int
+}{values: map[string]int{}, sources: map[int]func() []string{}}
+
+// EnableTracking makes Track and TrackSource record the secrets, they are no-ops otherwise.
+func EnableTracking() {
+	registry.Lock()
+	defer registry.Unlock()
+
+	registry.enabled = true
+}
+
+// Track records a secret until the returned function is called.
+func Track(value string) (untrack func()) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if !registry.enabled || len(value) < minTrackedLength {
+		return func() {}
+	}
+	registry.values[value]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			registry.Lock()
+			defer registry.Unlock()
+
+			if registry.values[value]--; registry.values[value] <= 0 {
+				delete(registry.values, value)
+			}
+		})
+	}
+}
+
+// TrackSource records a function returning the secrets it holds at the time it is called,
+// e.g. the token a client is logged in with, until the returned function is called.
+func TrackSource(source func() []string) (untrack func()) {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if !registry.enabled {
+		return func() {}
+	}
+	id := registry.nextSource
+	registry.nextSource++
+	registry.sources[id] = source
+
+	return func() {
+		registry.Lock()
+		defer registry.Unlock()
+
+		delete(registry.sources, id)
+	}
+}
+
+// Redact replaces the tracked secrets found in text, and reports whether there were any.
+func Redact(text string) (string, bool) {
+	registry.RLock()
+	enabled := registry.enabled
+	values := make([]string, 0, len(registry.values))
+	for value := range registry.values {
+		values = append(values, value)
+	}
+	sources := make([]func() []string, 0, len(registry.sources))
+	for _, source := range registry.sources {
+		sources = append(sources, source)
+	}
+	registry.RUnlock()
+
+	if !enabled {
+		return text, false
+	}
+
+	for _, source := range sources {
+		for _, value := range source() {
+			if len(value) >= minTrackedLength {
+				values = append(values, value)
+			}
+		}
+	}
+
+	found := false
+	for _, value := range values {
+		if strings.Contains(text, value) {
+			text = strings.ReplaceAll(text, value, RedactedValue)
+			found = true
+		}
+	}
+
+	return text, found
+}
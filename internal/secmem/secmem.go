@@ -0,0 +1,80 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package secmem holds key material in memory that is locked against swapping and wiped after use.
+//
+// The Vault API client only takes strings, which can't be wiped: the key material is kept in a
+// Buffer for as long as possible and only converted right before it is sent.
+//
+// Once tracking is enabled, the secrets in memory are also recorded, so the logs can be checked for them.
+package secmem
+
+import "sync"
+
+// Buffer is a fixed size piece of memory locked in RAM where the platform allows it.
+// The zero value is an empty, destroyed buffer.
+type Buffer struct {
+	mu      sync.Mutex
+	data    []byte
+	untrack func()
+}
+
+// NewBuffer moves data into a new locked buffer, wiping data.
+func NewBuffer(data []byte) *Buffer {
+	b := &Buffer{data: alloc(len(data))}
+	copy(b.data, data)
+	Wipe(data)
+	b.untrack = Track(string(b.data))
+
+	return b
+}
+
+// Bytes returns the content of the buffer, only valid until Destroy is called.
+func (b *Buffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.data
+}
+
+// String returns a copy of the content, which can't be wiped: only call it right before handing
+// the content to an API taking a string.
+func (b *Buffer) String() string {
+	return string(b.Bytes())
+}
+
+// Len returns the size of the content.
+func (b *Buffer) Len() int {
+	return len(b.Bytes())
+}
+
+// Destroy wipes and releases the buffer. It is safe to call it more than once.
+func (b *Buffer) Destroy() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.data == nil {
+		return
+	}
+
+	b.untrack()
+	Wipe(b.data)
+	free(b.data)
+	b.data = nil
+}
+
+// Wipe overwrites data with zeroes.
+func Wipe(data []byte) {
+	clear(data)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux || darwin
+
+package secmem
+
+import (
+	"log/slog"
+	"syscall"
+)
+
+// alloc maps the memory of a buffer outside of the Go heap, so the garbage collector never copies it,
+// and locks it so it is never swapped out.
+func alloc(size int) []byte {
+	if size == 0 {
+		return []byte{}
+	}
+
+	data, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_ANON|syscall.MAP_PRIVATE)
+	if err != nil {
+		slog.Warn("error mapping locked memory, using unlocked memory", "error", err)
+		return make([]byte, size)
+	}
+
+	if err := syscall.Mlock(data); err != nil {
+		// Typically RLIMIT_MEMLOCK is too low, the memory is still wiped
+		slog.Debug("error locking memory, key material may be swapped out", "error", err)
+	}
+
+	return data
+}
+
+func free(data []byte) {
+	if len(data) == 0 {
+		return
+	}
+
+	_ = syscall.Munlock(data)
+	// Fails for the unlocked fallback memory, which is left to the garbage collector
+	_ = syscall.Munmap(data)
+}
+
+// DisableCoreDumps prevents the process from writing core dumps, which would contain key material.
+func DisableCoreDumps() error {
+	return syscall.Setrlimit(syscall.RLIMIT_CORE, &syscall.Rlimit{Cur: 0, Max: 0})
+}
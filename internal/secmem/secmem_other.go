@@ -0,0 +1,29 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !darwin
+
+package secmem
+
+// alloc allocates the memory of a buffer on the heap, memory can't be locked on this platform.
+func alloc(size int) []byte {
+	return make([]byte, size)
+}
+
+func free([]byte) {}
+
+// DisableCoreDumps is a no-op on this platform.
+func DisableCoreDumps() error {
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secmem
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuffer(t *testing.T) {
+	key := []byte("unseal-key")
+
+	b := NewBuffer(key)
+	assert.Equal(t, make([]byte, len("unseal-key")), key, "the source is wiped")
+	assert.Equal(t, "unseal-key", b.String())
+	assert.Equal(t, len("unseal-key"), b.Len())
+
+	b.Destroy()
+	b.Destroy()
+	assert.Empty(t, b.Bytes())
+
+	empty := NewBuffer(nil)
+	assert.Equal(t, "", empty.String())
+	empty.Destroy()
+}
+
+func TestWipe(t *testing.T) {
+	data := []byte("root-token")
+	Wipe(data)
+	assert.Equal(t, make([]byte, len("root-token")), data)
+}
+
+func TestRedact(t *testing.T) {
+	text, found := Redact("token hvs.tracked")
+	assert.False(t, found)
+	assert.Equal(t, "token hvs.tracked", text)
+
+	EnableTracking()
+	untrack := Track("hvs.tracked")
+	Track("short")
+	token := "hvs.client-token"
+	untrackSource := TrackSource(func() []string { return []string{token, ""} })
+	defer untrackSource()
+
+	text, found = Redact("token hvs.tracked, client hvs.client-token, short")
+	assert.True(t, found)
+	assert.Equal(t, "token <redacted>, client <redacted>, short", text)
+
+	untrack()
+	text, found = Redact("token hvs.tracked")
+	assert.False(t, found)
+	assert.Equal(t, "token hvs.tracked", text)
+
+	untrackSource()
+	text, found = Redact("client hvs.client-token")
+	assert.False(t, found)
+	assert.Equal(t, "client hvs.client-token", text)
+}
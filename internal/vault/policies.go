@@ -1,156 +0,0 @@
-// Copyright © 2021 Banzai Cloud
-//
-// Licensed under the Apache License, Version 2.0 (the "License");
-// you may not use this file except in compliance with the License.
-// You may obtain a copy of the License at
-//
-//     http://www.apache.org/licenses/LICENSE-2.0
-//
-// Unless required by applicable law or agreed to in writing, software
-// distributed under the License is distributed on an "AS IS" BASIS,
-// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-// See the License for the specific language governing permissions and
-// limitations under the License.
-
-package vault
-
-import (
-	"fmt"
-	"log/slog"
-	"maps"
-	"slices"
-	"strings"
-
-	"emperror.dev/errors"
-	"github.com/hashicorp/hcl"
-	hclPrinter "github.com/hashicorp/hcl/hcl/printer"
-	"github.com/hashicorp/vault/api"
-)
-
-type policy struct {
-	Name           string `mapstructure:"name"`
-	Rules          string `mapstructure:"rules"`
-	RulesFormatted string
-}
-
-func initPoliciesConfig(policiesConfig []policy, mounts map[string]*api.MountOutput) ([]policy, error) {
-	// Sort mount paths by length (longest first) to avoid substring collisions
-	// e.g., "kubernetes_cluster" should be processed before "kubernetes"
-	mountPaths := slices.Collect(maps.Keys(mounts))
-	slices.SortFunc(mountPaths, func(a, b string) int {
-		return len(b) - len(a)
-	})
-
-	for i := range policiesConfig {
-		policy := &policiesConfig[i]
-
-		// Replace accessor placeholders
-		for _, mountPath := range mountPaths {
-			placeholder := fmt.Sprintf("__accessor__%s", strings.TrimSuffix(mountPath, "/"))
-			policy.Rules = strings.ReplaceAll(policy.Rules, placeholder, mounts[mountPath].Accessor)
-		}
-
-		// Format as HCL, falling back to original if it's valid JSON
-		formatted, err := hclPrinter.Format([]byte(policy.Rules))
-		if err != nil {
-			if _, parseErr := hcl.Parse(policy.Rules); parseErr != nil {
-				return nil, fmt.Errorf("parsing %s policy rules: %w", policy.Name, parseErr)
-			}
-			slog.Debug("could not HCL-format policy rules (may be JSON)",
-				"policy", policy.Name,
-				"error", err)
-			formatted = []byte(policy.Rules)
-		}
-		policy.RulesFormatted = string(formatted)
-	}
-
-	return policiesConfig, nil
-}
-
-func (v *vault) addManagedPolicies(managedPolicies []policy) error {
-	for _, policy := range managedPolicies {
-		slog.Info(fmt.Sprintf("adding policy %s", policy.Name))
-		if err := v.cl.Sys().PutPolicy(policy.Name, policy.RulesFormatted); err != nil {
-			return errors.Wrapf(err, "error putting %s policy into vault", policy.Name)
-		}
-	}
-
-	return nil
-}
-
-// getExistingPolicies gets all policies that are already in Vault.
-// The existing policies are in a map to make it easy to delete easily from it with "O(n)" complexity.
-func (v *vault) getExistingPolicies() (map[string]bool, error) {
-	existingPolicies := make(map[string]bool)
-
-	existingPoliciesList, err := v.cl.Sys().ListPolicies()
-	if err != nil {
-		return nil, errors.Wrapf(err, "unable to list existing policies")
-	}
-
-	for _, existingPolicy := range existingPoliciesList {
-		existingPolicies[existingPolicy] = true
-	}
-
-	return existingPolicies, nil
-}
-
-// builtInPolicies are policies Vault provides out of the box and refuses to
-// delete via the API. Attempting to delete them returns HTTP 400.
-// "default-ceiling" was introduced in Vault 2.0.
-var builtInPolicies = []string{"root", "default", "default-ceiling"}
-
-// getUnmanagedPolicies gets unmanaged policies by comparing what's already in Vault and what's in the externalConfig.
-func (v *vault) getUnmanagedPolicies(managedPolicies []policy) map[string]bool {
-	unmanagedPolicies, _ := v.getExistingPolicies()
-
-	// Vault doesn't allow removing built-in policies.
-	for _, builtIn := range builtInPolicies {
-		delete(unmanagedPolicies, builtIn)
-	}
-
-	// Remove managed polices form the items since the reset will be removed.
-	for _, managedPolicy := range managedPolicies {
-		delete(unmanagedPolicies, managedPolicy.Name)
-	}
-
-	return unmanagedPolicies
-}
-
-func (v *vault) removeUnmanagedPolicies(managedPolicies []policy) error {
-	if !v.externalConfig.PurgeUnmanagedConfig.Enabled || v.externalConfig.PurgeUnmanagedConfig.Exclude.Policies {
-		slog.Debug("purge config is disabled, no unmanaged policies will be removed")
-		return nil
-	}
-
-	unmanagedPolicies := v.getUnmanagedPolicies(managedPolicies)
-	for policyName := range unmanagedPolicies {
-		slog.Info(fmt.Sprintf("removing policy %s", policyName))
-		if err := v.cl.Sys().DeletePolicy(policyName); err != nil {
-			return errors.Wrapf(err, "error deleting %s policy from vault", policyName)
-		}
-	}
-	return nil
-}
-
-func (v *vault) configurePolicies() error {
-	auths, err := v.cl.Sys().ListAuth()
-	if err != nil {
-		return errors.Wrap(err, "error while getting list of auth engines")
-	}
-
-	managedPolicies, err := initPoliciesConfig(v.externalConfig.Policies, auths)
-	if err != nil {
-		return errors.Wrap(err, "error while initializing policies config")
-	}
-
-	if err := v.addManagedPolicies(managedPolicies); err != nil {
-		return errors.Wrap(err, "error while adding policies")
-	}
-
-	if err := v.removeUnmanagedPolicies(managedPolicies); err != nil {
-		return errors.Wrap(err, "error while removing policies")
-	}
-
-	return nil
-}
@@ -16,6 +16,7 @@ package alibabakms
 
 import (
 	"context"
+	"net/http"
 
 	"emperror.dev/errors"
 	"github.com/aliyun/alibaba-cloud-sdk-go/sdk/requests"
@@ -35,12 +36,22 @@ var _ kv.Service = &alibabaKMS{}
 
 // New creates a new kv.Service encrypted by Alibaba KMS
 func New(regionID, accessKeyID, accessKeySecret, kmsID string, store kv.Service) (kv.Service, error) {
+	return NewWithHTTPClient(regionID, accessKeyID, accessKeySecret, kmsID, store, nil)
+}
+
+// NewWithHTTPClient creates a new kv.Service encrypted by Alibaba KMS like New,
+// whose requests are sent through the transport of the HTTP client, the one of the SDK if nil.
+func NewWithHTTPClient(regionID, accessKeyID, accessKeySecret, kmsID string, store kv.Service, httpClient *http.Client) (kv.Service, error) {
 	client, err := kms.NewClientWithAccessKey(regionID, accessKeyID, accessKeySecret)
 	if err != nil {
 		return nil, errors.WrapIf(err, "failed to create KMS client")
 	}
 
 	client.GetConfig().Scheme = requests.HTTPS
+	if httpClient != nil {
+		// Hidden from the SDK, which sets the proxy of the environment on an *http.Transport
+		client.SetTransport(struct{ http.RoundTripper }{httpClient.Transport})
+	}
 
 	return &alibabaKMS{store: store, kmsClient: client, kmsID: kmsID}, nil
 }
@@ -0,0 +1,44 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alibabakms
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("alibabakms", newFromOptions)
+}
+
+// newFromOptions creates the alibabakms backend encrypting the values with the key of the "keyID" setting
+// in the region of the "region" setting, authenticated by the "accessKeyID" and "accessKeySecret" settings.
+func newFromOptions(_ context.Context, options kv.Options) (kv.Service, error) {
+	if options.Store == nil {
+		return nil, errors.New("the backend the encrypted values are stored in must be specified")
+	}
+
+	return NewWithHTTPClient(
+		options.Settings["region"],
+		options.Settings["accessKeyID"],
+		options.Settings["accessKeySecret"],
+		options.Settings["keyID"],
+		options.Store,
+		options.HTTPClient(),
+	)
+}
@@ -35,7 +35,23 @@ type ossStorage struct {
 
 // New creates a new kv.Service backed by AWS S3
 func New(endpoint, accessKeyID, accessKeySecret, bucket, prefix string) (kv.Service, error) {
-	client, err := oss.New(endpoint, accessKeyID, accessKeySecret)
+	return NewWithHTTPClient(endpoint, accessKeyID, accessKeySecret, bucket, prefix, nil)
+}
+
+// NewWithHTTPClient creates a new kv.Service backed by Alibaba OSS like New,
+// whose requests are sent by the HTTP client, the one of the SDK if nil.
+func NewWithHTTPClient(endpoint, accessKeyID, accessKeySecret, bucket, prefix string, httpClient *http.Client) (kv.Service, error) {
+	var options []oss.ClientOption
+	if httpClient != nil {
+		// Redirects are returned to the SDK, like by its own client
+		client := *httpClient
+		client.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		options = append(options, oss.HTTPClient(&client))
+	}
+
+	client, err := oss.New(endpoint, accessKeyID, accessKeySecret, options...)
 	if err != nil {
 		return nil, err
 	}
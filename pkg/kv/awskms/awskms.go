@@ -21,7 +21,6 @@ import (
 	"emperror.dev/errors"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/bank-vaults/bank-vaults/pkg/kv"
 )
@@ -59,11 +58,11 @@ func NewWithConfig(ctx context.Context, config aws.Config, store kv.Service, kms
 	}, nil
 }
 
-// New creates a new kv.Service encrypted by AWS KMS
+// New creates a new kv.Service encrypted by AWS KMS, authenticated by the default credential chain
 func New(ctx context.Context, store kv.Service, region string, kmsID string, encryptionContext map[string]string) (kv.Service, error) {
-	config, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	config, err := LoadConfig(ctx, region, Credentials{})
 	if err != nil {
-		return nil, errors.WrapIf(err, "failed to load AWS config")
+		return nil, err
 	}
 
 	return NewWithConfig(ctx, config, store, kmsID, encryptionContext)
@@ -0,0 +1,85 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskms
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+// DefaultRoleSessionName is the session name of the roles assumed with a web identity token.
+const DefaultRoleSessionName = "bank-vaults"
+
+// Credentials configures how the AWS kv backends authenticate. If empty, the default credential chain is used:
+// the static keys of the environment, the shared config, IRSA (AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE
+// injected by EKS), EKS Pod Identity (AWS_CONTAINER_CREDENTIALS_FULL_URI) and the role of the instance.
+type Credentials struct {
+	// role assumed with the web identity token of the file, e.g. a projected service account token,
+	// when EKS doesn't inject the IRSA environment
+	RoleARN              string
+	WebIdentityTokenFile string
+	// DefaultRoleSessionName if empty
+	RoleSessionName string
+}
+
+// LoadConfig returns the AWS config of the AWS kv backends, loaded with the extra options, e.g. WithProxy.
+// The region is resolved from AWS_REGION, the shared config or the instance metadata if empty.
+func LoadConfig(ctx context.Context, region string, credentials Credentials, optFns ...func(*config.LoadOptions) error) (aws.Config, error) {
+	if (credentials.RoleARN == "") != (credentials.WebIdentityTokenFile == "") {
+		return aws.Config{}, errors.New("both the role ARN and the web identity token file must be specified")
+	}
+
+	optFns = append([]func(*config.LoadOptions) error{config.WithRegion(region), config.WithEC2IMDSRegion()}, optFns...)
+	awsConfig, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return aws.Config{}, errors.WrapIf(err, "failed to load AWS config")
+	}
+	if awsConfig.Region == "" {
+		return aws.Config{}, errors.New("region must be specified, or set in AWS_REGION or the shared config")
+	}
+
+	if credentials.RoleARN != "" {
+		sessionName := credentials.RoleSessionName
+		if sessionName == "" {
+			sessionName = DefaultRoleSessionName
+		}
+
+		provider := stscreds.NewWebIdentityRoleProvider(
+			sts.NewFromConfig(awsConfig),
+			credentials.RoleARN,
+			stscreds.IdentityTokenFile(credentials.WebIdentityTokenFile),
+			func(o *stscreds.WebIdentityRoleOptions) { o.RoleSessionName = sessionName },
+		)
+		awsConfig.Credentials = aws.NewCredentialsCache(provider)
+	}
+
+	return awsConfig, nil
+}
+
+// WithProxy makes the AWS clients connect through the proxy, the assumption of the role included.
+func WithProxy(proxy kv.Proxy) config.LoadOptionsFunc {
+	return config.WithHTTPClient(awshttp.NewBuildableClient().WithTransportOptions(func(transport *http.Transport) {
+		transport.Proxy = proxy.ProxyFunc()
+	}))
+}
@@ -0,0 +1,92 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskms
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func isolateAWSEnv(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+	t.Setenv("AWS_REGION", "")
+	t.Setenv("AWS_DEFAULT_REGION", "")
+}
+
+func TestLoadConfigRegion(t *testing.T) {
+	isolateAWSEnv(t)
+
+	_, err := LoadConfig(context.Background(), "", Credentials{})
+	assert.Error(t, err)
+
+	t.Setenv("AWS_REGION", "eu-west-1")
+	config, err := LoadConfig(context.Background(), "", Credentials{})
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", config.Region)
+
+	config, err = LoadConfig(context.Background(), "us-east-2", Credentials{})
+	require.NoError(t, err)
+	assert.Equal(t, "us-east-2", config.Region)
+}
+
+func TestLoadConfigWebIdentity(t *testing.T) {
+	isolateAWSEnv(t)
+
+	_, err := LoadConfig(context.Background(), "eu-west-1", Credentials{RoleARN: "arn:aws:iam::123456789012:role/bank-vaults"})
+	assert.Error(t, err)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token"), 0o600))
+
+	config, err := LoadConfig(context.Background(), "eu-west-1", Credentials{
+		RoleARN:              "arn:aws:iam::123456789012:role/bank-vaults",
+		WebIdentityTokenFile: tokenFile,
+	})
+	require.NoError(t, err)
+	assert.IsType(t, &aws.CredentialsCache{}, config.Credentials)
+}
+
+func TestLoadConfigProxy(t *testing.T) {
+	isolateAWSEnv(t)
+
+	config, err := LoadConfig(context.Background(), "eu-west-1", Credentials{},
+		WithProxy(kv.Proxy{URL: "http://egress:3128", NoProxy: []string{"s3.eu-west-1.amazonaws.com"}}))
+	require.NoError(t, err)
+	transport := config.HTTPClient.(*awshttp.BuildableClient).GetTransport()
+
+	proxy, err := transport.Proxy(httptest.NewRequest(http.MethodPost, "https://kms.eu-west-1.amazonaws.com/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "http://egress:3128", proxy.String())
+
+	proxy, err = transport.Proxy(httptest.NewRequest(http.MethodGet, "https://s3.eu-west-1.amazonaws.com/bucket", nil))
+	require.NoError(t, err)
+	assert.Nil(t, proxy)
+}
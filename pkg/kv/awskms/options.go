@@ -0,0 +1,85 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package awskms
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("awskms", newFromOptions)
+}
+
+// newFromOptions creates the awskms backend encrypting the values with the key of the "keyID" setting,
+// in the region of the "region" setting, with the encryption context of the "encryptionContext" setting.
+func newFromOptions(ctx context.Context, options kv.Options) (kv.Service, error) {
+	if options.Store == nil {
+		return nil, errors.New("the backend the encrypted values are stored in must be specified")
+	}
+	encryptionContext, err := options.MapSetting("encryptionContext")
+	if err != nil {
+		return nil, err
+	}
+	config, err := ConfigForOptions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithConfig(ctx, config, options.Store, options.Settings["keyID"], encryptionContext)
+}
+
+// ConfigForOptions returns the AWS config of the AWS kv backends created with the options, in the region
+// of the "region" setting. The credentials may be Credentials or an aws.CredentialsProvider.
+func ConfigForOptions(ctx context.Context, options kv.Options) (aws.Config, error) {
+	var credentials Credentials
+	var provider aws.CredentialsProvider
+	switch c := options.Credentials.(type) {
+	case nil:
+	case Credentials:
+		credentials = c
+	case aws.CredentialsProvider:
+		provider = c
+	default:
+		return aws.Config{}, errors.Errorf("unsupported AWS credentials: %T", options.Credentials)
+	}
+
+	var optFns []func(*awsconfig.LoadOptions) error
+	if options.Proxy != nil {
+		optFns = append(optFns, WithProxy(*options.Proxy))
+	}
+
+	config, err := LoadConfig(ctx, options.Settings["region"], credentials, optFns...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	if provider != nil {
+		config.Credentials = aws.NewCredentialsCache(provider)
+	}
+	if options.Endpoint != "" {
+		config.BaseEndpoint = aws.String(options.Endpoint)
+	}
+	if options.MaxRetries != nil {
+		config.RetryMaxAttempts = *options.MaxRetries + 1
+	}
+
+	return config, nil
+}
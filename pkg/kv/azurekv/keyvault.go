@@ -58,19 +58,51 @@ var _ kv.Service = &azureKeyVault{}
 // The prefix is prepended to all secret names, allowing multiple
 // clients to share the same Key Vault without collisions.
 func New(name, prefix string) (kv.Service, error) {
+	return NewWithClientOptions(name, prefix, nil, azcore.ClientOptions{})
+}
+
+// NewWithCredentials creates a new kv.Service backed by Azure Key Vault like New,
+// authenticating with the federated credential of Azure Workload Identity.
+func NewWithCredentials(name, prefix string, credentials Credentials) (kv.Service, error) {
+	return NewWithClientOptions(name, prefix, &credentials, azcore.ClientOptions{})
+}
+
+// NewWithClientOptions creates a new kv.Service backed by Azure Key Vault like NewWithCredentials,
+// or like New if the credentials are nil. The clients of the Key Vault and the credentials are
+// created with the options, e.g. the ones of ClientOptions.
+func NewWithClientOptions(name, prefix string, credentials *Credentials, options azcore.ClientOptions) (kv.Service, error) {
 	if name == "" {
 		return nil, errors.Errorf("invalid Key Vault specified: '%s'", name)
 	}
 
-	cred, err := NewAzureAuthCredentials()
-	if err != nil {
-		log.Fatalf("failed to obtain a credential: %v", err)
+	var cred azcore.TokenCredential
+	if credentials != nil {
+		var err error
+		cred, err = newWorkloadIdentityCredential(*credentials, options)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		authCred, err := newAzureAuthCredentials(options)
+		if err != nil {
+			log.Fatalf("failed to obtain a credential: %v", err)
+		}
+		cred = authCred
 	}
 
+	return newWithCredential(name, prefix, cred, options)
+}
+
+// ClientOptions returns the options of the Azure clients connecting through the proxy.
+func ClientOptions(proxy kv.Proxy) azcore.ClientOptions {
+	return azcore.ClientOptions{Transport: proxy.HTTPClient()}
+}
+
+func newWithCredential(name, prefix string, cred azcore.TokenCredential, options azcore.ClientOptions) (kv.Service, error) {
 	// Establish a connection to the Key Vault client
-	client, err := azsecrets.NewClient(fmt.Sprintf("https://%s.%s", name, "vault.azure.net"), cred, nil)
+	client, err := azsecrets.NewClient(fmt.Sprintf("https://%s.%s", name, "vault.azure.net"), cred, &azsecrets.ClientOptions{ClientOptions: options})
 	if err != nil {
-		log.Fatalf("failed to create Key Vault client: %v", err)
+		return nil, errors.Wrap(err, "failed to create Key Vault client")
 	}
 
 	return &azureKeyVault{
@@ -122,15 +154,19 @@ func (d *AzureAuthCredentials) GetToken(ctx context.Context, options policy.Toke
 }
 
 func NewAzureAuthCredentials() (*AzureAuthCredentials, error) {
+	return newAzureAuthCredentials(azcore.ClientOptions{})
+}
+
+func newAzureAuthCredentials(options azcore.ClientOptions) (*AzureAuthCredentials, error) {
 	var errorMessages []string
 	creds := make(map[string]azcore.TokenCredential)
-	defaultCred, err := azidentity.NewDefaultAzureCredential(nil)
+	defaultCred, err := azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{ClientOptions: options})
 	if err != nil {
 		errorMessages = append(errorMessages, "DefaultCredential: "+err.Error())
 	}
 	creds["DefaultCredential"] = defaultCred
 
-	fileBasedCred, err := NewFileBasedCredential()
+	fileBasedCred, err := newFileBasedCredential(options)
 	if err != nil {
 		errorMessages = append(errorMessages, "FileBasedCredential: "+err.Error())
 	}
@@ -144,6 +180,10 @@ func NewAzureAuthCredentials() (*AzureAuthCredentials, error) {
 }
 
 func NewFileBasedCredential() (azcore.TokenCredential, error) {
+	return newFileBasedCredential(azcore.ClientOptions{})
+}
+
+func newFileBasedCredential(options azcore.ClientOptions) (azcore.TokenCredential, error) {
 	// Implementation based on github.com/Azure/go-autorest/autorest/azure/auth.GetSettingsFromFile()
 	fileLocation := os.Getenv(AzureAuthLocation)
 	if fileLocation == "" {
@@ -167,7 +207,8 @@ func NewFileBasedCredential() (azcore.TokenCredential, error) {
 		return nil, err
 	}
 
-	cred, err := azidentity.NewClientSecretCredential(authFile.TenantID, authFile.ClientID, authFile.ClientSecret, nil)
+	cred, err := azidentity.NewClientSecretCredential(authFile.TenantID, authFile.ClientID, authFile.ClientSecret,
+		&azidentity.ClientSecretCredentialOptions{ClientOptions: options})
 	if err != nil {
 		return nil, err
 	}
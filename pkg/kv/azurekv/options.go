@@ -0,0 +1,54 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurekv
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("azurekv", newFromOptions)
+}
+
+// newFromOptions creates the azurekv backend storing the values in the Key Vault of the "name" setting
+// under the "prefix" setting. The credentials may be Credentials of Workload Identity or an azcore.TokenCredential.
+func newFromOptions(_ context.Context, options kv.Options) (kv.Service, error) {
+	name, prefix := options.Settings["name"], options.Settings["prefix"]
+
+	var clientOptions azcore.ClientOptions
+	if options.Proxy != nil {
+		clientOptions = ClientOptions(*options.Proxy)
+	}
+
+	switch c := options.Credentials.(type) {
+	case nil:
+		return NewWithClientOptions(name, prefix, nil, clientOptions)
+	case Credentials:
+		return NewWithClientOptions(name, prefix, &c, clientOptions)
+	case azcore.TokenCredential:
+		if name == "" {
+			return nil, errors.Errorf("invalid Key Vault specified: '%s'", name)
+		}
+
+		return newWithCredential(name, prefix, c, clientOptions)
+	default:
+		return nil, errors.Errorf("unsupported azure credentials: %T", options.Credentials)
+	}
+}
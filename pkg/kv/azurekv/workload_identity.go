@@ -0,0 +1,83 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurekv
+
+import (
+	"cmp"
+	"context"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// DefaultFederatedTokenAudience is the audience Entra ID expects the service account tokens of federated credentials for.
+const DefaultFederatedTokenAudience = "api://AzureADTokenExchange"
+
+// Credentials configures Azure Workload Identity: a Kubernetes service account token is exchanged for
+// an Entra ID token of the application which trusts it with a federated credential, so neither
+// client secrets nor storage account keys have to be stored.
+type Credentials struct {
+	// the application and its tenant, AZURE_TENANT_ID and AZURE_CLIENT_ID injected by the
+	// Workload Identity webhook if empty
+	TenantID string
+	ClientID string
+	// service account token projected with the audience of the federated credential,
+	// AZURE_FEDERATED_TOKEN_FILE injected by the webhook if empty
+	TokenFile string
+	// returns the service account token instead of the file, e.g. minted with the TokenRequest API
+	Assertion func(ctx context.Context) (string, error)
+}
+
+// NewWorkloadIdentityCredential returns the federated credential of Azure Workload Identity.
+func NewWorkloadIdentityCredential(credentials Credentials) (azcore.TokenCredential, error) {
+	return newWorkloadIdentityCredential(credentials, azcore.ClientOptions{})
+}
+
+func newWorkloadIdentityCredential(credentials Credentials, options azcore.ClientOptions) (azcore.TokenCredential, error) {
+	tenantID := cmp.Or(credentials.TenantID, os.Getenv("AZURE_TENANT_ID"))
+	clientID := cmp.Or(credentials.ClientID, os.Getenv("AZURE_CLIENT_ID"))
+	if tenantID == "" || clientID == "" {
+		return nil, errors.New("both the tenant and the client ID of the workload identity must be specified")
+	}
+
+	getAssertion := credentials.Assertion
+	if getAssertion == nil {
+		tokenFile := cmp.Or(credentials.TokenFile, os.Getenv("AZURE_FEDERATED_TOKEN_FILE"))
+		if tokenFile == "" {
+			return nil, errors.New("the service account token file of the workload identity must be specified")
+		}
+
+		// The kubelet rotates the projected token, it is read for every exchange
+		getAssertion = func(context.Context) (string, error) {
+			token, err := os.ReadFile(tokenFile)
+			if err != nil {
+				return "", errors.Wrap(err, "error reading the service account token of the workload identity")
+			}
+
+			return strings.TrimSpace(string(token)), nil
+		}
+	}
+
+	cred, err := azidentity.NewClientAssertionCredential(tenantID, clientID, getAssertion,
+		&azidentity.ClientAssertionCredentialOptions{ClientOptions: options})
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating workload identity credential")
+	}
+
+	return cred, nil
+}
@@ -0,0 +1,45 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azurekv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkloadIdentityCredential(t *testing.T) {
+	t.Setenv("AZURE_TENANT_ID", "")
+	t.Setenv("AZURE_CLIENT_ID", "")
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+
+	_, err := NewWorkloadIdentityCredential(Credentials{ClientID: "00000000-0000-0000-0000-000000000001"})
+	assert.Error(t, err, "the tenant is required")
+
+	_, err = NewWorkloadIdentityCredential(Credentials{TenantID: "00000000-0000-0000-0000-000000000002", ClientID: "00000000-0000-0000-0000-000000000001"})
+	assert.Error(t, err, "the token is required")
+
+	t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "/var/run/secrets/azure/tokens/azure-identity-token")
+	_, err = NewWorkloadIdentityCredential(Credentials{TenantID: "00000000-0000-0000-0000-000000000002", ClientID: "00000000-0000-0000-0000-000000000001"})
+	assert.NoError(t, err, "the token file of the webhook is used")
+
+	_, err = NewWorkloadIdentityCredential(Credentials{
+		TenantID:  "00000000-0000-0000-0000-000000000002",
+		ClientID:  "00000000-0000-0000-0000-000000000001",
+		Assertion: func(context.Context) (string, error) { return "token", nil },
+	})
+	assert.NoError(t, err)
+}
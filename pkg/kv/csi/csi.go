@@ -0,0 +1,77 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package csi reads unseal material from the files the Secrets Store CSI driver mounts.
+package csi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"emperror.dev/errors"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+// DefaultPath is where the Secrets Store CSI volume is usually mounted.
+const DefaultPath = "/mnt/secrets-store"
+
+type csi struct {
+	path    string
+	objects map[string]string
+}
+
+// New creates a new read-only kv.Service backed by the files of a Secrets Store CSI volume.
+// A key is read from the file named after it, or after its object in objects, e.g. the
+// objectAlias of a SecretProviderClass. The files are read on every Get, so the values
+// the CSI driver rotates are picked up without a restart.
+func New(path string, objects map[string]string) (kv.Service, error) {
+	if path == "" {
+		path = DefaultPath
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "error checking the secrets store csi volume")
+	}
+	if !info.IsDir() {
+		return nil, errors.Errorf("secrets store csi volume path '%s' is not a directory", path)
+	}
+
+	return &csi{path: path, objects: objects}, nil
+}
+
+func (c *csi) Set(_ context.Context, key string, _ []byte) error {
+	return errors.Errorf("key '%s' can't be set, the secrets store csi kv store is read-only", key)
+}
+
+func (c *csi) Get(_ context.Context, key string) ([]byte, error) {
+	name := key
+	if object, ok := c.objects[key]; ok {
+		name = object
+	}
+
+	// The object names come from the configuration, they mustn't point out of the volume
+	if !filepath.IsLocal(name) {
+		return nil, errors.Errorf("object '%s' of key '%s' is not in the secrets store csi volume", name, key)
+	}
+
+	val, err := os.ReadFile(filepath.Join(c.path, name))
+	if os.IsNotExist(err) {
+		return nil, kv.NewNotFoundError("key '%s' is not present in the secrets store csi volume", key)
+	}
+
+	return val, errors.WrapIff(err, "failed to read secrets store csi object for key: %s", key)
+}
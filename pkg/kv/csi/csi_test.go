@@ -0,0 +1,74 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csi
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func TestCSI(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vault-root"), []byte("root"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unseal-key-0"), []byte("key0"), 0o600))
+
+	service, err := New(dir, map[string]string{"vault-unseal-0": "unseal-key-0", "vault-unseal-1": "../escape"})
+	require.NoError(t, err)
+
+	val, err := service.Get(ctx, "vault-root")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("root"), val)
+
+	val, err = service.Get(ctx, "vault-unseal-0")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key0"), val)
+
+	// The CSI driver rotates the files in place
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "vault-root"), []byte("rotated"), 0o600))
+	val, err = service.Get(ctx, "vault-root")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("rotated"), val)
+
+	_, err = service.Get(ctx, "vault-unseal-2")
+	assert.True(t, kv.IsNotFoundError(err))
+
+	_, err = service.Get(ctx, "vault-unseal-1")
+	assert.ErrorContains(t, err, "not in the secrets store csi volume")
+
+	assert.Error(t, service.Set(ctx, "vault-root", []byte("root")))
+
+	_, err = New(filepath.Join(dir, "vault-root"), nil)
+	assert.Error(t, err)
+}
+
+func TestCSIFromOptions(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "unseal-key-0"), []byte("key0"), 0o600))
+
+	service, err := kv.New(context.Background(), "csi", kv.WithSetting("path", dir), kv.WithSetting("objects", "vault-unseal-0=unseal-key-0"))
+	require.NoError(t, err)
+
+	val, err := service.Get(context.Background(), "vault-unseal-0")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key0"), val)
+}
@@ -15,6 +15,7 @@
 package dev
 
 import (
+	"bytes"
 	"context"
 	"os"
 
@@ -48,7 +49,7 @@ func (d *dev) Set(_ context.Context, _ string, _ []byte) error {
 
 func (d *dev) Get(_ context.Context, key string) ([]byte, error) {
 	if key == "vault-root" {
-		return d.rootToken, nil
+		return bytes.Clone(d.rootToken), nil
 	}
 
 	return nil, kv.NewNotFoundError("key '%s' is not present in dev mode, only visible in server logs", key)
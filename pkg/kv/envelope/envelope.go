@@ -0,0 +1,208 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"log/slog"
+
+	"emperror.dev/errors"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+// Version is the version of the envelope format written.
+const Version = 1
+
+// magic starts every envelope, values without it are legacy raw blobs.
+var magic = []byte("BVENV")
+
+// Envelope is a value as stored in the key store: the payload of a backend with what binds it to its key.
+//
+// Its encoding is the magic, the version byte, the backend ID and the AAD prefixed with their
+// 2 byte length, the payload prefixed with its 4 byte length and the HMAC-SHA256 of all that.
+type Envelope struct {
+	Version   byte
+	BackendID string
+	// additional authenticated data, the key the value is stored under
+	AAD     string
+	Payload []byte
+}
+
+// Options customize how the envelopes are read.
+type Options struct {
+	// RejectLegacy fails reading values stored without an envelope, once all of them were rewritten,
+	// so a value replaced by a raw blob can't bypass the MAC
+	RejectLegacy bool
+}
+
+type envelopeKV struct {
+	service   kv.Service
+	backendID string
+	macKey    []byte
+	options   Options
+}
+
+// New returns a kv.Service storing the values of the service in envelopes authenticated with the MAC key.
+// The MAC only detects tampering if the key is secret, otherwise it only detects corruption and values
+// moved between keys or backends. Legacy values stored without an envelope are read as they are.
+func New(service kv.Service, backendID string, macKey []byte) kv.Service {
+	return NewWithOptions(service, backendID, macKey, Options{})
+}
+
+// NewWithOptions returns a kv.Service storing the values of the service in envelopes like New,
+// reading them as the options tell.
+func NewWithOptions(service kv.Service, backendID string, macKey []byte, options Options) kv.Service {
+	return &envelopeKV{
+		service:   service,
+		backendID: backendID,
+		macKey:    macKey,
+		options:   options,
+	}
+}
+
+func (e *envelopeKV) Set(ctx context.Context, key string, value []byte) error {
+	data := Seal(Envelope{
+		Version:   Version,
+		BackendID: e.backendID,
+		AAD:       key,
+		Payload:   value,
+	}, e.macKey)
+	defer clear(data)
+
+	return e.service.Set(ctx, key, data) //nolint:wrapcheck
+}
+
+func (e *envelopeKV) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := e.service.Get(ctx, key)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if !bytes.HasPrefix(data, magic) {
+		if e.options.RejectLegacy {
+			clear(data)
+			return nil, errors.Errorf("value of key '%s' is stored without an envelope", key)
+		}
+
+		slog.Debug("reading legacy value stored without an envelope", "key", key)
+		return data, nil
+	}
+
+	envelope, err := Open(data, e.macKey)
+	clear(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening envelope of key '%s'", key)
+	}
+
+	if envelope.AAD != key {
+		return nil, errors.Errorf("envelope of key '%s' was stored under key '%s'", key, envelope.AAD)
+	}
+	if envelope.BackendID != e.backendID {
+		return nil, errors.Errorf("envelope of key '%s' was written by backend '%s', not '%s'", key, envelope.BackendID, e.backendID)
+	}
+
+	return envelope.Payload, nil
+}
+
+// Seal encodes the envelope, authenticated with the MAC key.
+func Seal(envelope Envelope, macKey []byte) []byte {
+	data := make([]byte, 0, len(magic)+1+2+len(envelope.BackendID)+2+len(envelope.AAD)+4+len(envelope.Payload)+sha256.Size)
+	data = append(data, magic...)
+	data = append(data, envelope.Version)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(envelope.BackendID))) //nolint:gosec
+	data = append(data, envelope.BackendID...)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(envelope.AAD))) //nolint:gosec
+	data = append(data, envelope.AAD...)
+	data = binary.BigEndian.AppendUint32(data, uint32(len(envelope.Payload))) //nolint:gosec
+	data = append(data, envelope.Payload...)
+
+	return append(data, mac(data, macKey)...)
+}
+
+// Open decodes an envelope, after checking its MAC with the MAC key.
+func Open(data, macKey []byte) (*Envelope, error) {
+	if !bytes.HasPrefix(data, magic) {
+		return nil, errors.New("not an envelope")
+	}
+	if len(data) < len(magic)+1+sha256.Size {
+		return nil, errors.New("envelope is truncated")
+	}
+
+	content, sum := data[:len(data)-sha256.Size], data[len(data)-sha256.Size:]
+	if !hmac.Equal(sum, mac(content, macKey)) {
+		return nil, errors.New("envelope MAC doesn't match, the value was tampered with or the MAC key is wrong")
+	}
+
+	envelope := &Envelope{Version: content[len(magic)]}
+	if envelope.Version != Version {
+		return nil, errors.Errorf("unsupported envelope version: %d", envelope.Version)
+	}
+
+	rest := content[len(magic)+1:]
+	backendID, rest, err := readField(rest, 2)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading backend ID")
+	}
+	aad, rest, err := readField(rest, 2)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading AAD")
+	}
+	payload, rest, err := readField(rest, 4)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading payload")
+	}
+	if len(rest) > 0 {
+		return nil, errors.New("envelope has trailing data")
+	}
+
+	envelope.BackendID = string(backendID)
+	envelope.AAD = string(aad)
+	envelope.Payload = bytes.Clone(payload)
+
+	return envelope, nil
+}
+
+// readField reads a field prefixed with its length of lengthSize bytes.
+func readField(data []byte, lengthSize int) ([]byte, []byte, error) {
+	if len(data) < lengthSize {
+		return nil, nil, errors.New("envelope is truncated")
+	}
+
+	var length uint64
+	if lengthSize == 2 {
+		length = uint64(binary.BigEndian.Uint16(data))
+	} else {
+		length = uint64(binary.BigEndian.Uint32(data))
+	}
+	data = data[lengthSize:]
+
+	if uint64(len(data)) < length {
+		return nil, nil, errors.New("envelope is truncated")
+	}
+
+	return data[:length], data[length:], nil
+}
+
+func mac(data, macKey []byte) []byte {
+	h := hmac.New(sha256.New, macKey)
+	h.Write(data)
+
+	return h.Sum(nil)
+}
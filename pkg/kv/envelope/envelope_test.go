@@ -0,0 +1,109 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+type memKV map[string][]byte
+
+func (m memKV) Set(_ context.Context, key string, value []byte) error {
+	m[key] = bytes.Clone(value)
+	return nil
+}
+
+func (m memKV) Get(_ context.Context, key string) ([]byte, error) {
+	value, ok := m[key]
+	if !ok {
+		return nil, kv.NewNotFoundError("key not found: %s", key)
+	}
+
+	return bytes.Clone(value), nil
+}
+
+func TestEnvelope(t *testing.T) {
+	ctx := context.Background()
+	storage := memKV{}
+	store := New(storage, "k8s", []byte("mac key"))
+
+	require.NoError(t, store.Set(ctx, "vault-unseal-0", []byte("key share")))
+	assert.True(t, bytes.HasPrefix(storage["vault-unseal-0"], magic))
+
+	value, err := store.Get(ctx, "vault-unseal-0")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key share"), value)
+
+	storage["vault-unseal-1"] = []byte("legacy key share")
+	value, err = store.Get(ctx, "vault-unseal-1")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("legacy key share"), value, "legacy values are read as they are")
+
+	storage["vault-unseal-1"] = storage["vault-unseal-0"]
+	_, err = store.Get(ctx, "vault-unseal-1")
+	assert.ErrorContains(t, err, "was stored under key 'vault-unseal-0'")
+
+	_, err = New(storage, "file", []byte("mac key")).Get(ctx, "vault-unseal-0")
+	assert.ErrorContains(t, err, "written by backend 'k8s'")
+
+	_, err = New(storage, "k8s", []byte("other key")).Get(ctx, "vault-unseal-0")
+	assert.ErrorContains(t, err, "MAC doesn't match")
+
+	tampered := bytes.Clone(storage["vault-unseal-0"])
+	tampered[len(tampered)-40] ^= 0xff
+	storage["vault-unseal-0"] = tampered
+	_, err = store.Get(ctx, "vault-unseal-0")
+	assert.ErrorContains(t, err, "MAC doesn't match")
+}
+
+func TestEnvelopeRejectLegacy(t *testing.T) {
+	ctx := context.Background()
+	storage := memKV{}
+	store := NewWithOptions(storage, "k8s", []byte("mac key"), Options{RejectLegacy: true})
+
+	require.NoError(t, store.Set(ctx, "vault-unseal-0", []byte("key share")))
+	value, err := store.Get(ctx, "vault-unseal-0")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("key share"), value)
+
+	storage["vault-unseal-1"] = []byte("legacy key share")
+	_, err = store.Get(ctx, "vault-unseal-1")
+	assert.ErrorContains(t, err, "value of key 'vault-unseal-1' is stored without an envelope")
+
+	_, err = store.Get(ctx, "vault-unseal-2")
+	assert.True(t, kv.IsNotFoundError(err), "missing keys are still reported as not found")
+}
+
+func TestOpenRejectsMalformed(t *testing.T) {
+	data := Seal(Envelope{Version: Version, BackendID: "dev", AAD: "vault-root", Payload: []byte("root")}, nil)
+
+	envelope, err := Open(data, nil)
+	require.NoError(t, err)
+	assert.Equal(t, &Envelope{Version: Version, BackendID: "dev", AAD: "vault-root", Payload: []byte("root")}, envelope)
+
+	_, err = Open(data[:len(magic)+3], nil)
+	assert.Error(t, err)
+
+	future := Seal(Envelope{Version: Version + 1, BackendID: "dev", AAD: "vault-root"}, nil)
+	_, err = Open(future, nil)
+	assert.ErrorContains(t, err, "unsupported envelope version")
+}
@@ -0,0 +1,35 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package file
+
+import (
+	"context"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("file", newFromOptions)
+}
+
+// newFromOptions creates the file backend storing the values in the directory of the "path" setting.
+func newFromOptions(_ context.Context, options kv.Options) (kv.Service, error) {
+	path, err := options.Setting("path")
+	if err != nil {
+		return nil, err
+	}
+
+	return New(path)
+}
@@ -40,12 +40,21 @@ var _ kv.Service = &googleKms{}
 
 // New creates a new kv.Service encrypted by Google KMS
 func New(ctx context.Context, store kv.Service, project, location, keyring, cryptoKey string) (kv.Service, error) {
-	client, err := google.DefaultClient(ctx, cloudkms.CloudPlatformScope)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating google client")
+	return NewWithClientOptions(ctx, store, project, location, keyring, cryptoKey)
+}
+
+// NewWithClientOptions creates a new kv.Service encrypted by Google KMS, whose client is created with the options,
+// e.g. the ones of ClientOptions, or with the Application Default Credentials if there are none
+func NewWithClientOptions(ctx context.Context, store kv.Service, project, location, keyring, cryptoKey string, options ...option.ClientOption) (kv.Service, error) {
+	if len(options) == 0 {
+		client, err := google.DefaultClient(ctx, cloudkms.CloudPlatformScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating google client")
+		}
+		options = []option.ClientOption{option.WithHTTPClient(client)}
 	}
 
-	kmsService, err := cloudkms.NewService(ctx, option.WithHTTPClient(client))
+	kmsService, err := cloudkms.NewService(ctx, options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating google kms service client")
 	}
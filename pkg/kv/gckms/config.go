@@ -0,0 +1,121 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gckms
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"slices"
+
+	"emperror.dev/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+	htransport "google.golang.org/api/transport/http"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+// Credentials configures how the Google Cloud kv backends authenticate. If empty, the Application Default
+// Credentials are used: GOOGLE_APPLICATION_CREDENTIALS, the gcloud config and the metadata server of GKE.
+type Credentials struct {
+	// credential configuration of workload identity federation (an external_account JSON file),
+	// exchanging a token of the environment, e.g. a projected service account token, for a Google token
+	CredentialsFile string
+	// service account impersonated with the credentials, so they only need the Service Account
+	// Token Creator role on it instead of access to the bucket and key
+	ImpersonateServiceAccount string
+}
+
+// ClientOptions returns the options of the Google API clients of the Google Cloud kv backends,
+// none if the Application Default Credentials are used as they are.
+func ClientOptions(ctx context.Context, credentials Credentials) ([]option.ClientOption, error) {
+	return ClientOptionsWithProxy(ctx, credentials, nil)
+}
+
+// ClientOptionsWithProxy returns the options of ClientOptions, of clients connecting through the proxy
+// if not nil, the token exchanges of the credentials included.
+func ClientOptionsWithProxy(ctx context.Context, credentials Credentials, proxy *kv.Proxy) ([]option.ClientOption, error) {
+	options, err := credentialsOptions(ctx, credentials, proxy)
+	if err != nil || proxy == nil {
+		return options, err
+	}
+
+	return proxiedClientOptions(ctx, proxy, options)
+}
+
+// credentialsOptions returns the options authenticating with the credentials, their token exchanges
+// connecting through the proxy if not nil.
+func credentialsOptions(ctx context.Context, credentials Credentials, proxy *kv.Proxy) ([]option.ClientOption, error) {
+	var options []option.ClientOption
+
+	if proxy != nil {
+		// The federated credentials exchange their tokens with the client of the context
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, proxy.HTTPClient())
+	}
+
+	if credentials.CredentialsFile != "" {
+		data, err := os.ReadFile(credentials.CredentialsFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading google credentials file")
+		}
+
+		// Only federated credentials, exported service account keys are what this replaces
+		creds, err := google.CredentialsFromJSONWithType(ctx, data, google.ExternalAccount, cloudkms.CloudPlatformScope)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing google workload identity federation credentials")
+		}
+		options = append(options, option.WithCredentials(creds))
+	}
+
+	if credentials.ImpersonateServiceAccount != "" {
+		impersonateOptions := options
+		if proxy != nil {
+			var err error
+			impersonateOptions, err = proxiedClientOptions(ctx, proxy, options)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		tokenSource, err := impersonate.CredentialsTokenSource(ctx, impersonate.CredentialsConfig{
+			TargetPrincipal: credentials.ImpersonateServiceAccount,
+			Scopes:          []string{cloudkms.CloudPlatformScope},
+		}, impersonateOptions...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error impersonating google service account %s", credentials.ImpersonateServiceAccount)
+		}
+		options = []option.ClientOption{option.WithTokenSource(tokenSource)}
+	}
+
+	return options, nil
+}
+
+// proxiedClientOptions returns the option of a client connecting through the proxy,
+// authenticated with the credentials of the options, the Application Default Credentials if there are none.
+func proxiedClientOptions(ctx context.Context, proxy *kv.Proxy, options []option.ClientOption) ([]option.ClientOption, error) {
+	// The Application Default Credentials exchange their tokens with the client of the context too
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, proxy.HTTPClient())
+	options = append(slices.Clone(options), option.WithScopes(cloudkms.CloudPlatformScope))
+	transport, err := htransport.NewTransport(ctx, proxy.HTTPClient().Transport, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating google client transport")
+	}
+
+	return []option.ClientOption{option.WithHTTPClient(&http.Client{Transport: transport})}, nil
+}
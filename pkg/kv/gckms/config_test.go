@@ -0,0 +1,56 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gckms
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func TestClientOptions(t *testing.T) {
+	options, err := ClientOptions(context.Background(), Credentials{})
+	require.NoError(t, err)
+	assert.Empty(t, options, "the Application Default Credentials are used")
+
+	dir := t.TempDir()
+	credentialsFile := filepath.Join(dir, "credentials.json")
+	require.NoError(t, os.WriteFile(credentialsFile, []byte(`{
+		"type": "external_account",
+		"audience": "//iam.googleapis.com/projects/123456789012/locations/global/workloadIdentityPools/bank-vaults/providers/k8s",
+		"subject_token_type": "urn:ietf:params:oauth:token-type:jwt",
+		"token_url": "https://sts.googleapis.com/v1/token",
+		"credential_source": {"file": "/var/run/secrets/tokens/gcp/token"}
+	}`), 0o600))
+
+	options, err = ClientOptions(context.Background(), Credentials{CredentialsFile: credentialsFile})
+	require.NoError(t, err)
+	assert.Len(t, options, 1)
+
+	options, err = ClientOptionsWithProxy(context.Background(), Credentials{CredentialsFile: credentialsFile}, &kv.Proxy{URL: "http://egress:3128"})
+	require.NoError(t, err)
+	assert.Len(t, options, 1, "the credentials authenticate the proxied client")
+
+	keyFile := filepath.Join(dir, "key.json")
+	require.NoError(t, os.WriteFile(keyFile, []byte(`{"type": "service_account", "client_email": "vault@example.iam.gserviceaccount.com"}`), 0o600))
+	_, err = ClientOptions(context.Background(), Credentials{CredentialsFile: keyFile})
+	assert.Error(t, err, "exported service account keys are rejected")
+}
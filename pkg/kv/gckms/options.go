@@ -0,0 +1,82 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gckms
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"google.golang.org/api/option"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("gckms", newFromOptions)
+}
+
+// newFromOptions creates the gckms backend encrypting the values with the key of the "project", "location",
+// "keyRing" and "cryptoKey" settings.
+func newFromOptions(ctx context.Context, options kv.Options) (kv.Service, error) {
+	if options.Store == nil {
+		return nil, errors.New("the backend the encrypted values are stored in must be specified")
+	}
+	clientOptions, err := ClientOptionsForOptions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithClientOptions(ctx, options.Store,
+		options.Settings["project"],
+		options.Settings["location"],
+		options.Settings["keyRing"],
+		options.Settings["cryptoKey"],
+		clientOptions...,
+	)
+}
+
+// ClientOptionsForOptions returns the options of the Google API clients of the Google Cloud kv backends
+// created with the options. The credentials may be Credentials, an option.ClientOption or a list of them.
+func ClientOptionsForOptions(ctx context.Context, options kv.Options) ([]option.ClientOption, error) {
+	var clientOptions []option.ClientOption
+	var err error
+	switch c := options.Credentials.(type) {
+	case nil:
+	case Credentials:
+		clientOptions, err = credentialsOptions(ctx, c, options.Proxy)
+	case option.ClientOption:
+		clientOptions = []option.ClientOption{c}
+	case []option.ClientOption:
+		clientOptions = c
+	default:
+		return nil, errors.Errorf("unsupported google credentials: %T", options.Credentials)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if options.Proxy != nil {
+		clientOptions, err = proxiedClientOptions(ctx, options.Proxy, clientOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if options.Endpoint != "" {
+		clientOptions = append(clientOptions, option.WithEndpoint(options.Endpoint))
+	}
+
+	return clientOptions, nil
+}
@@ -21,6 +21,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"emperror.dev/errors"
+	"google.golang.org/api/option"
 
 	"github.com/bank-vaults/bank-vaults/pkg/kv"
 )
@@ -33,7 +34,13 @@ type gcsStorage struct {
 
 // New creates a new kv.Service backed by Google GCS
 func New(ctx context.Context, bucket, prefix string) (kv.Service, error) {
-	cl, err := storage.NewClient(ctx)
+	return NewWithClientOptions(ctx, bucket, prefix)
+}
+
+// NewWithClientOptions creates a new kv.Service backed by Google GCS, whose client is created with the options,
+// e.g. the ones of gckms.ClientOptions
+func NewWithClientOptions(ctx context.Context, bucket, prefix string, options ...option.ClientOption) (kv.Service, error) {
+	cl, err := storage.NewClient(ctx, options...)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating gcs client")
 	}
@@ -0,0 +1,37 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcs
+
+import (
+	"context"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/gckms"
+)
+
+func init() {
+	kv.Register("gcs", newFromOptions)
+}
+
+// newFromOptions creates the gcs backend storing the values in the bucket of the "bucket" setting under
+// the "prefix" setting. The client options are the ones of gckms.ClientOptionsForOptions.
+func newFromOptions(ctx context.Context, options kv.Options) (kv.Service, error) {
+	clientOptions, err := gckms.ClientOptionsForOptions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithClientOptions(ctx, options.Settings["bucket"], options.Settings["prefix"], clientOptions...)
+}
@@ -53,6 +53,8 @@ type Config struct {
 	TokenLabel string
 	Pin        string
 	KeyLabel   string
+	// FIPS rejects devices that can't encrypt on the device, the software fallback uses RSA-OAEP with SHA-1
+	FIPS bool
 }
 
 // New returns a HSM backed KV encryptor. Currently RSA keys are supported only.
@@ -190,6 +192,10 @@ func New(config Config, storage kv.Service) (kv.Service, error) {
 	var encrypt cryptoFunc
 
 	if info.ManufacturerID == "OpenSC Project" {
+		if config.FIPS {
+			return nil, errors.New("this HSM doesn't support on-device encryption, which is required in FIPS mode")
+		}
+
 		log.Info("this HSM doesn't support on-device encryption, extracting public key and doing encryption on the computer")
 
 		publicKeyValue, err := p11.Object(publicKey).Value()
@@ -0,0 +1,56 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hsm
+
+import (
+	"context"
+	"strconv"
+
+	"emperror.dev/errors"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("hsm", newFromOptions)
+}
+
+// newFromOptions creates the hsm backend from the "modulePath", "slotID", "tokenLabel", "pin", "keyLabel"
+// and "fips" settings, it stores the encrypted values in the store, if any, or on the device.
+func newFromOptions(_ context.Context, options kv.Options) (kv.Service, error) {
+	config := Config{
+		ModulePath: options.Settings["modulePath"],
+		TokenLabel: options.Settings["tokenLabel"],
+		Pin:        options.Settings["pin"],
+		KeyLabel:   options.Settings["keyLabel"],
+	}
+
+	if slotID := options.Settings["slotID"]; slotID != "" {
+		id, err := strconv.ParseUint(slotID, 10, 0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid slot ID: %s", slotID)
+		}
+		config.SlotID = uint(id)
+	}
+	if fips := options.Settings["fips"]; fips != "" {
+		var err error
+		config.FIPS, err = strconv.ParseBool(fips)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid fips setting: %s", fips)
+		}
+	}
+
+	return New(config, options.Store)
+}
@@ -15,9 +15,13 @@
 package k8s
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"maps"
 	"os"
+	"path"
+	"text/template"
 
 	"emperror.dev/errors"
 	v1 "k8s.io/api/core/v1"
@@ -34,19 +38,54 @@ import (
 // TODO: remove this in the next release.
 const EnvK8SOwnerReference = "K8S_OWNER_REFERENCE"
 
+// Options customize the layout of the K8S Secrets the values are stored in.
+type Options struct {
+	// Labels and Annotations of the created Secrets
+	Labels      map[string]string
+	Annotations map[string]string
+	// Type of the created Secrets, Opaque if empty
+	Type v1.SecretType
+	// Go template of the Secret data key a value is stored under, given the .Key and .Namespace,
+	// the key itself if empty
+	KeyTemplate string
+	// Patterns of the keys to store, all if empty, and of the keys never to store (e.g. the root token)
+	Include []string
+	Exclude []string
+	// Owners of the created Secrets, which must live in their namespace, read from K8S_OWNER_REFERENCE if nil
+	OwnerReferences []metav1.OwnerReference
+	// Field manager the Secrets are written as, telling bank-vaults apart in their managed fields
+	FieldManager string
+}
+
+// templateData is what the Secret name and data key templates are rendered with.
+type templateData struct {
+	Key       string
+	Namespace string
+}
+
 type k8sStorage struct {
-	client         *kubernetes.Clientset
-	namespace      string
-	secret         string
-	labels         map[string]string
-	ownerReference *metav1.OwnerReference
+	client      kubernetes.Interface
+	namespace   string
+	secret      *template.Template
+	keyTemplate *template.Template
+	options     Options
 }
 
 // New creates a new kv.Service backed by K8S Secrets
 func New(namespace, secret string, labels map[string]string) (kv.Service, error) {
+	return NewWithOptions(namespace, secret, Options{Labels: labels})
+}
+
+// NewWithOptions creates a new kv.Service backed by K8S Secrets with a custom layout,
+// the secret name is a Go template given the .Key and .Namespace, so values can be split into several Secrets
+func NewWithOptions(namespace, secret string, options Options) (kv.Service, error) {
+	k, err := newStorage(namespace, secret, options)
+	if err != nil {
+		return nil, err
+	}
+
 	kubeconfig := os.Getenv(clientcmd.RecommendedConfigPathEnvVar)
 	var config *rest.Config
-	var err error
 	if kubeconfig != "" {
 		config, err = clientcmd.BuildConfigFromFlags("", kubeconfig)
 	} else {
@@ -62,60 +101,157 @@ func New(namespace, secret string, labels map[string]string) (kv.Service, error)
 		return nil, errors.Wrap(err, "error creating k8s client")
 	}
 
-	var ownerReference *metav1.OwnerReference
 	ownerReferenceJSON := os.Getenv(EnvK8SOwnerReference)
-	if ownerReferenceJSON != "" {
-		ownerReference = &metav1.OwnerReference{}
-		err := json.Unmarshal([]byte(ownerReferenceJSON), ownerReference)
+	if k.options.OwnerReferences == nil && ownerReferenceJSON != "" {
+		var ownerReference metav1.OwnerReference
+		err := json.Unmarshal([]byte(ownerReferenceJSON), &ownerReference)
 		if err != nil {
 			return nil, errors.Wrap(err, "error unmarshaling OwnerReference")
 		}
+		k.options.OwnerReferences = []metav1.OwnerReference{ownerReference}
+	}
+
+	k.client = client
+
+	return k, nil
+}
+
+// newStorage parses the layout of the Secrets.
+func newStorage(namespace, secret string, options Options) (*k8sStorage, error) {
+	secretTemplate, err := template.New("secret").Parse(secret)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing secret name template")
+	}
+
+	keyTemplate := options.KeyTemplate
+	if keyTemplate == "" {
+		keyTemplate = "{{ .Key }}"
+	}
+	parsedKeyTemplate, err := template.New("key").Parse(keyTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing secret key template")
+	}
+
+	for _, pattern := range append(append([]string{}, options.Include...), options.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid key pattern '%s'", pattern)
+		}
 	}
 
 	return &k8sStorage{
-		client:         client,
-		namespace:      namespace,
-		secret:         secret,
-		labels:         labels,
-		ownerReference: ownerReference,
+		namespace:   namespace,
+		secret:      secretTemplate,
+		keyTemplate: parsedKeyTemplate,
+		options:     options,
 	}, nil
 }
 
+// stored tells whether a key is written into the Secrets.
+func (o Options) stored(key string) bool {
+	for _, pattern := range o.Exclude {
+		if matched, _ := path.Match(pattern, key); matched {
+			return false
+		}
+	}
+
+	if len(o.Include) == 0 {
+		return true
+	}
+
+	for _, pattern := range o.Include {
+		if matched, _ := path.Match(pattern, key); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// location returns the name of the Secret and its data key a key is stored under.
+func (k *k8sStorage) location(key string) (string, string, error) {
+	data := templateData{Key: key, Namespace: k.namespace}
+
+	var secretName, secretKey bytes.Buffer
+	if err := k.secret.Execute(&secretName, data); err != nil {
+		return "", "", errors.Wrapf(err, "error rendering secret name of key '%s'", key)
+	}
+	if err := k.keyTemplate.Execute(&secretKey, data); err != nil {
+		return "", "", errors.Wrapf(err, "error rendering secret key of key '%s'", key)
+	}
+
+	return secretName.String(), secretKey.String(), nil
+}
+
 func (k *k8sStorage) Set(ctx context.Context, key string, val []byte) error {
-	secret, err := k.client.CoreV1().Secrets(k.namespace).Get(ctx, k.secret, metav1.GetOptions{})
+	if !k.options.stored(key) {
+		return nil
+	}
+
+	secretName, secretKey, err := k.location(key)
+	if err != nil {
+		return err
+	}
+
+	secret, err := k.client.CoreV1().Secrets(k.namespace).Get(ctx, secretName, metav1.GetOptions{})
 
 	switch {
 	case k8serrors.IsNotFound(err):
 		secret = &v1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: k.namespace,
-				Name:      k.secret,
-				Labels:    k.labels,
+				Namespace:   k.namespace,
+				Name:        secretName,
+				Labels:      k.options.Labels,
+				Annotations: k.options.Annotations,
 			},
-			Data: map[string][]byte{key: val},
+			Type: k.options.Type,
+			Data: map[string][]byte{secretKey: val},
 		}
-		if k.ownerReference != nil {
-			secret.SetOwnerReferences([]metav1.OwnerReference{*k.ownerReference})
+		if len(k.options.OwnerReferences) > 0 {
+			secret.SetOwnerReferences(k.options.OwnerReferences)
 		}
-		_, err = k.client.CoreV1().Secrets(k.namespace).Create(ctx, secret, metav1.CreateOptions{})
+		_, err = k.client.CoreV1().Secrets(k.namespace).Create(ctx, secret, metav1.CreateOptions{FieldManager: k.options.FieldManager})
 	case err == nil:
 		if secret.Data == nil {
 			secret.Data = map[string][]byte{}
 		}
-		secret.Data[key] = val
-		_, err = k.client.CoreV1().Secrets(k.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+		secret.Data[secretKey] = val
+		// Secrets written by earlier versions get the labels and annotations configured since
+		secret.Labels = mergeStringMaps(secret.Labels, k.options.Labels)
+		secret.Annotations = mergeStringMaps(secret.Annotations, k.options.Annotations)
+		_, err = k.client.CoreV1().Secrets(k.namespace).Update(ctx, secret, metav1.UpdateOptions{FieldManager: k.options.FieldManager})
 	default:
-		return errors.Wrapf(err, "error checking if '%s' secret exists", k.secret)
+		return errors.Wrapf(err, "error checking if '%s' secret exists", secretName)
 	}
 	if err != nil {
-		return errors.Wrapf(err, "error writing secret key '%s' into secret '%s'", key, k.secret)
+		return errors.Wrapf(err, "error writing secret key '%s' into secret '%s'", secretKey, secretName)
 	}
 
 	return nil
 }
 
+func mergeStringMaps(m, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return m
+	}
+	if m == nil {
+		m = make(map[string]string, len(overrides))
+	}
+	maps.Copy(m, overrides)
+
+	return m
+}
+
 func (k *k8sStorage) Get(ctx context.Context, key string) ([]byte, error) {
-	secret, err := k.client.CoreV1().Secrets(k.namespace).Get(ctx, k.secret, metav1.GetOptions{})
+	if !k.options.stored(key) {
+		return nil, kv.NewNotFoundError("key '%s' is not stored in secrets", key)
+	}
+
+	secretName, secretKey, err := k.location(key)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := k.client.CoreV1().Secrets(k.namespace).Get(ctx, secretName, metav1.GetOptions{})
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
 			return nil, kv.NewNotFoundError("error getting secret for key '%s': %s", key, err.Error())
@@ -124,9 +260,9 @@ func (k *k8sStorage) Get(ctx context.Context, key string) ([]byte, error) {
 		return nil, errors.Wrapf(err, "error getting secret for key '%s'", key)
 	}
 
-	if secret.Data[key] == nil {
-		return nil, kv.NewNotFoundError("key '%s' is not present in secret: %s", key, secret.GetName())
+	if secret.Data[secretKey] == nil {
+		return nil, kv.NewNotFoundError("key '%s' is not present in secret: %s", secretKey, secret.GetName())
 	}
 
-	return secret.Data[key], nil
+	return secret.Data[secretKey], nil
 }
@@ -0,0 +1,109 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestStorageLocation(t *testing.T) {
+	k, err := newStorage("vault", "vault-unseal-keys", Options{})
+	require.NoError(t, err)
+
+	secretName, secretKey, err := k.location("vault-root")
+	require.NoError(t, err)
+	assert.Equal(t, "vault-unseal-keys", secretName)
+	assert.Equal(t, "vault-root", secretKey)
+
+	k, err = newStorage("vault", "{{ .Namespace }}-{{ .Key }}", Options{
+		KeyTemplate: `{{ if eq .Key "vault-root" }}root-token{{ else }}value{{ end }}`,
+	})
+	require.NoError(t, err)
+
+	secretName, secretKey, err = k.location("vault-root")
+	require.NoError(t, err)
+	assert.Equal(t, "vault-vault-root", secretName)
+	assert.Equal(t, "root-token", secretKey)
+
+	secretName, secretKey, err = k.location("vault-unseal-0")
+	require.NoError(t, err)
+	assert.Equal(t, "vault-vault-unseal-0", secretName)
+	assert.Equal(t, "value", secretKey)
+}
+
+func TestOptionsStored(t *testing.T) {
+	options := Options{Exclude: []string{"vault-root"}}
+	assert.False(t, options.stored("vault-root"))
+	assert.True(t, options.stored("vault-unseal-0"))
+
+	options = Options{Include: []string{"vault-unseal-*", "vault-root"}, Exclude: []string{"vault-unseal-4"}}
+	assert.True(t, options.stored("vault-unseal-0"))
+	assert.True(t, options.stored("vault-root"))
+	assert.False(t, options.stored("vault-unseal-4"))
+	assert.False(t, options.stored("vault-test"))
+}
+
+func TestInvalidOptions(t *testing.T) {
+	_, err := newStorage("vault", "{{ .Key", Options{})
+	assert.Error(t, err)
+
+	_, err = newStorage("vault", "vault-unseal-keys", Options{Exclude: []string{"["}})
+	assert.Error(t, err)
+}
+
+func TestSetOwnerReferencesAndFieldManager(t *testing.T) {
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: "vault-unseal-owner", UID: "1234"}
+	k, err := newStorage("vault-ops", "vault-unseal-keys", Options{
+		Labels:          map[string]string{"app": "vault"},
+		OwnerReferences: []metav1.OwnerReference{owner},
+		FieldManager:    "bank-vaults",
+	})
+	require.NoError(t, err)
+	client := fake.NewSimpleClientset()
+	k.client = client
+
+	require.NoError(t, k.Set(context.Background(), "vault-unseal-0", []byte("key")))
+
+	secret, err := client.CoreV1().Secrets("vault-ops").Get(context.Background(), "vault-unseal-keys", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []metav1.OwnerReference{owner}, secret.OwnerReferences)
+	assert.Equal(t, map[string]string{"app": "vault"}, secret.Labels)
+	assert.Equal(t, []byte("key"), secret.Data["vault-unseal-0"])
+
+	k.options.Labels = map[string]string{"tier": "ops"}
+	require.NoError(t, k.Set(context.Background(), "vault-root", []byte("token")))
+
+	secret, err = client.CoreV1().Secrets("vault-ops").Get(context.Background(), "vault-unseal-keys", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "vault", "tier": "ops"}, secret.Labels)
+
+	var fieldManagers []string
+	for _, action := range client.Actions() {
+		switch action := action.(type) {
+		case k8stesting.CreateActionImpl:
+			fieldManagers = append(fieldManagers, action.CreateOptions.FieldManager)
+		case k8stesting.UpdateActionImpl:
+			fieldManagers = append(fieldManagers, action.UpdateOptions.FieldManager)
+		}
+	}
+	assert.Equal(t, []string{"bank-vaults", "bank-vaults"}, fieldManagers)
+}
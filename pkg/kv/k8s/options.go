@@ -0,0 +1,40 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8s
+
+import (
+	"context"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("k8s", newFromOptions)
+}
+
+// newFromOptions creates the k8s backend storing the values in the Secret of the "secret" setting,
+// in the namespace of the "namespace" setting, labeled with the "labels" setting.
+func newFromOptions(_ context.Context, options kv.Options) (kv.Service, error) {
+	secret, err := options.Setting("secret")
+	if err != nil {
+		return nil, err
+	}
+	labels, err := options.MapSetting("labels")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithOptions(options.Settings["namespace"], secret, Options{Labels: labels})
+}
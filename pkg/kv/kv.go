@@ -50,6 +50,9 @@ func IsNotFoundError(err error) bool {
 
 // Service defines a basic key-value store. Implementations of this interface
 // may or may not guarantee consistency or security properties.
+//
+// The values belong to the caller: Get returns a value the caller may overwrite and Set
+// doesn't keep the value it is given, so key material can be wiped after use.
 type Service interface {
 	Set(ctx context.Context, key string, value []byte) error
 	Get(ctx context.Context, key string) ([]byte, error)
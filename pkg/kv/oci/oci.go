@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 
 	"emperror.dev/errors"
 	"github.com/oracle/oci-go-sdk/v65/common"
@@ -39,10 +40,19 @@ type ociStorage struct {
 
 // New creates a new kv.Service backed by Oracle OCI Object Storage
 func New(namespace, bucket, prefix string) (kv.Service, error) {
+	return NewWithHTTPClient(namespace, bucket, prefix, nil)
+}
+
+// NewWithHTTPClient creates a new kv.Service backed by Oracle OCI Object Storage like New,
+// whose requests are sent by the HTTP client, the one of the SDK if nil.
+func NewWithHTTPClient(namespace, bucket, prefix string, httpClient *http.Client) (kv.Service, error) {
 	client, err := objectstorage.NewObjectStorageClientWithConfigurationProvider(common.DefaultConfigProvider())
 	if err != nil {
 		slog.Error(fmt.Sprintf("error creating oracle object storage client: %s", err.Error()))
 	}
+	if httpClient != nil {
+		client.HTTPClient = httpClient
+	}
 
 	return &ociStorage{client: &client, namespace: namespace, bucket: bucket, prefix: prefix}, nil
 }
@@ -17,6 +17,7 @@ package ocikms
 import (
 	"context"
 	"encoding/base64"
+	"net/http"
 
 	"emperror.dev/errors"
 	"github.com/oracle/oci-go-sdk/v65/common"
@@ -37,6 +38,12 @@ var _ kv.Service = &ociKms{}
 
 // New creates a new kv.Service encrypted by Oracle KMS
 func New(store kv.Service, keyOCID, endpoint string) (kv.Service, error) {
+	return NewWithHTTPClient(store, keyOCID, endpoint, nil)
+}
+
+// NewWithHTTPClient creates a new kv.Service encrypted by Oracle KMS like New,
+// whose requests are sent by the HTTP client, the one of the SDK if nil.
+func NewWithHTTPClient(store kv.Service, keyOCID, endpoint string, httpClient *http.Client) (kv.Service, error) {
 	client, err := keymanagement.NewKmsCryptoClientWithConfigurationProvider(
 		common.DefaultConfigProvider(),
 		endpoint,
@@ -44,6 +51,9 @@ func New(store kv.Service, keyOCID, endpoint string) (kv.Service, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating oracle secret client")
 	}
+	if httpClient != nil {
+		client.HTTPClient = httpClient
+	}
 
 	return &ociKms{
 		store:   store,
@@ -0,0 +1,37 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ocikms
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("ocikms", newFromOptions)
+}
+
+// newFromOptions creates the ocikms backend encrypting the values with the key of the "keyOCID" setting,
+// through the cryptographic endpoint of the vault of the key.
+func newFromOptions(_ context.Context, options kv.Options) (kv.Service, error) {
+	if options.Store == nil {
+		return nil, errors.New("the backend the encrypted values are stored in must be specified")
+	}
+
+	return NewWithHTTPClient(options.Store, options.Settings["keyOCID"], options.Endpoint, options.HTTPClient())
+}
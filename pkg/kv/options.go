@@ -0,0 +1,216 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+)
+
+// Options configure a kv backend created by New. The backends ignore the options they don't support,
+// the settings each backend reads are listed where it registers its Factory.
+type Options struct {
+	// backend specific settings, like the bucket or the key ID
+	Settings map[string]string
+	// endpoint of the storage or KMS service, the one of the SDK if empty
+	Endpoint string
+	// credentials of the SDK of the backend, e.g. an aws.CredentialsProvider or a Google option.ClientOption,
+	// the default credential chain of the SDK if nil
+	Credentials interface{}
+	// how many times failing requests are retried, the default of the SDK if nil
+	MaxRetries *int
+	// backend the values are stored in by the KMS backends, which encrypt them
+	Store Service
+	// proxy the SDK clients connect through, the one of the environment if nil
+	Proxy *Proxy
+
+	encryption []encryption
+}
+
+// encryption is a KMS backend wrapping the backend being created.
+type encryption struct {
+	name    string
+	options []Option
+}
+
+// Option sets an option of a kv backend.
+type Option func(*Options)
+
+// WithSetting sets a backend specific setting.
+func WithSetting(key, value string) Option {
+	return func(o *Options) {
+		o.Settings[key] = value
+	}
+}
+
+// WithSettings sets backend specific settings.
+func WithSettings(settings map[string]string) Option {
+	return func(o *Options) {
+		maps.Copy(o.Settings, settings)
+	}
+}
+
+// WithEndpoint sets the endpoint of the storage or KMS service.
+func WithEndpoint(endpoint string) Option {
+	return func(o *Options) {
+		o.Endpoint = endpoint
+	}
+}
+
+// WithCredentials sets the credentials of the SDK of the backend.
+func WithCredentials(credentials interface{}) Option {
+	return func(o *Options) {
+		o.Credentials = credentials
+	}
+}
+
+// WithMaxRetries sets how many times failing requests are retried.
+func WithMaxRetries(maxRetries int) Option {
+	return func(o *Options) {
+		o.MaxRetries = &maxRetries
+	}
+}
+
+// WithStore sets the backend a KMS backend stores the encrypted values in.
+func WithStore(store Service) Option {
+	return func(o *Options) {
+		o.Store = store
+	}
+}
+
+// WithProxy sets the proxy the SDK clients of the backend connect through. The KMS backends of WithEncryption
+// have their own one, so the storage and the KMS services may be reached through different proxies.
+func WithProxy(proxy Proxy) Option {
+	return func(o *Options) {
+		o.Proxy = &proxy
+	}
+}
+
+// WithEncryption encrypts the values of the backend with the KMS backend of the name, created with the options.
+// Several ones are applied in order, the last one encrypting first.
+func WithEncryption(name string, options ...Option) Option {
+	return func(o *Options) {
+		o.encryption = append(o.encryption, encryption{name: name, options: options})
+	}
+}
+
+// Setting returns a backend specific setting, an error if it is empty.
+func (o Options) Setting(key string) (string, error) {
+	value := o.Settings[key]
+	if value == "" {
+		return "", errors.Errorf("setting '%s' must be specified", key)
+	}
+
+	return value, nil
+}
+
+// MapSetting returns a backend specific setting of comma separated key=value pairs as a map.
+func (o Options) MapSetting(key string) (map[string]string, error) {
+	if o.Settings[key] == "" {
+		return nil, nil
+	}
+
+	values := map[string]string{}
+	for _, pair := range strings.Split(o.Settings[key], ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || k == "" {
+			return nil, errors.Errorf("setting '%s' must be a list of key=value pairs: %s", key, o.Settings[key])
+		}
+		values[k] = v
+	}
+
+	return values, nil
+}
+
+// HTTPClient returns the HTTP client of the SDK clients connecting through the proxy, nil if there is none,
+// so the SDK creates its own one.
+func (o Options) HTTPClient() *http.Client {
+	if o.Proxy == nil {
+		return nil
+	}
+
+	return o.Proxy.HTTPClient()
+}
+
+// Factory creates a kv backend with the options.
+type Factory func(ctx context.Context, options Options) (Service, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register makes a kv backend available to New by the name, the backend packages register themselves
+// when they are imported. It panics if a backend is registered twice under the same name.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("kv: register factory of backend " + name + " is nil")
+	}
+	if _, ok := factories[name]; ok {
+		panic("kv: register called twice for backend " + name)
+	}
+	factories[name] = factory
+}
+
+// Backends returns the sorted names of the registered kv backends.
+func Backends() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	return slices.Sorted(maps.Keys(factories))
+}
+
+// New creates the kv backend registered under the name with the options, wrapped into its encryption.
+func New(ctx context.Context, name string, options ...Option) (Service, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("unknown kv backend: '%s', the registered ones are %v", name, Backends())
+	}
+
+	opts := Options{Settings: map[string]string{}}
+	for _, option := range options {
+		option(&opts)
+	}
+	if opts.Proxy != nil {
+		if err := opts.Proxy.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	store, err := factory(ctx, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error creating %s kv store", name)
+	}
+
+	for _, encryption := range opts.encryption {
+		store, err = New(ctx, encryption.name, append(slices.Clone(encryption.options), WithStore(store))...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return store, nil
+}
@@ -0,0 +1,129 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryStore struct {
+	options Options
+	values  map[string][]byte
+}
+
+func (m *memoryStore) Set(_ context.Context, key string, value []byte) error {
+	m.values[key] = value
+	return nil
+}
+
+func (m *memoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return nil, NewNotFoundError("key '%s' not found", key)
+	}
+
+	return value, nil
+}
+
+// reversingStore stands in for a KMS backend, reversing the values stored in the underlying store.
+type reversingStore struct {
+	Service
+}
+
+func (r reversingStore) Set(ctx context.Context, key string, value []byte) error {
+	return r.Service.Set(ctx, key, reverse(value))
+}
+
+func (r reversingStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.Service.Get(ctx, key)
+	return reverse(value), err
+}
+
+func reverse(value []byte) []byte {
+	reversed := make([]byte, len(value))
+	for i, b := range value {
+		reversed[len(value)-1-i] = b
+	}
+
+	return reversed
+}
+
+func TestNew(t *testing.T) {
+	var memory *memoryStore
+	Register("test-memory", func(_ context.Context, options Options) (Service, error) {
+		if _, err := options.Setting("bucket"); err != nil {
+			return nil, err
+		}
+		memory = &memoryStore{options: options, values: map[string][]byte{}}
+
+		return memory, nil
+	})
+	Register("test-kms", func(_ context.Context, options Options) (Service, error) {
+		return reversingStore{options.Store}, nil
+	})
+
+	assert.Panics(t, func() { Register("test-kms", nil) })
+	assert.Subset(t, Backends(), []string{"test-kms", "test-memory"})
+
+	_, err := New(context.Background(), "unknown")
+	assert.ErrorContains(t, err, "unknown kv backend: 'unknown'")
+
+	_, err = New(context.Background(), "test-memory")
+	assert.EqualError(t, err, "error creating test-memory kv store: setting 'bucket' must be specified")
+
+	_, err = New(context.Background(), "test-memory", WithSetting("bucket", "unseal-keys"), WithProxy(Proxy{URL: "ftp://egress:21"}))
+	assert.ErrorContains(t, err, "unsupported proxy url")
+
+	store, err := New(context.Background(), "test-memory",
+		WithSettings(map[string]string{"bucket": "unseal-keys", "prefix": "vault"}),
+		WithEndpoint("https://storage.example.com"),
+		WithMaxRetries(5),
+		WithProxy(Proxy{URL: "http://egress:3128"}),
+		WithEncryption("test-kms"),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"bucket": "unseal-keys", "prefix": "vault"}, memory.options.Settings)
+	assert.Equal(t, "https://storage.example.com", memory.options.Endpoint)
+	assert.Equal(t, 5, *memory.options.MaxRetries)
+	assert.Equal(t, &Proxy{URL: "http://egress:3128"}, memory.options.Proxy)
+	assert.NotNil(t, memory.options.HTTPClient())
+
+	require.NoError(t, store.Set(context.Background(), "vault-root", []byte("root")))
+	assert.Equal(t, []byte("toor"), memory.values["vault-root"])
+
+	value, err := store.Get(context.Background(), "vault-root")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("root"), value)
+}
+
+func TestOptionsMapSetting(t *testing.T) {
+	options := Options{Settings: map[string]string{"labels": "app=vault,team=platform", "invalid": "app"}}
+
+	labels, err := options.MapSetting("labels")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "vault", "team": "platform"}, labels)
+
+	empty, err := options.MapSetting("annotations")
+	require.NoError(t, err)
+	assert.Nil(t, empty)
+
+	_, err = options.MapSetting("invalid")
+	assert.Error(t, err)
+}
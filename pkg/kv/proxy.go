@@ -0,0 +1,80 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"emperror.dev/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Proxy is the HTTP, HTTPS or SOCKS5 proxy the SDK clients of a backend connect through, so egress-restricted
+// clusters may route the storage and KMS services through other proxies than the rest of the traffic.
+type Proxy struct {
+	// URL of the proxy, e.g. http://proxy:3128 or socks5://proxy:1080, HTTPS_PROXY and HTTP_PROXY if empty
+	URL string
+	// hosts connected to directly: host names, domains with a leading dot, IP addresses and CIDRs,
+	// optionally with a port, like in NO_PROXY, which is used if empty
+	NoProxy []string
+}
+
+// Validate checks the URL of the proxy.
+func (p Proxy) Validate() error {
+	if p.URL == "" {
+		return nil
+	}
+
+	proxyURL, err := url.Parse(p.URL)
+	if err != nil {
+		return errors.Wrapf(err, "error parsing proxy url %s", p.URL)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return errors.Errorf("unsupported proxy url %s, the scheme must be http, https, socks5 or socks5h", p.URL)
+	}
+
+	return nil
+}
+
+// ProxyFunc returns the proxy of the requests for http.Transport, falling back to the environment
+// for the unset fields.
+func (p Proxy) ProxyFunc() func(*http.Request) (*url.URL, error) {
+	config := httpproxy.FromEnvironment()
+	if p.URL != "" {
+		config.HTTPProxy = p.URL
+		config.HTTPSProxy = p.URL
+	}
+	if len(p.NoProxy) > 0 {
+		config.NoProxy = strings.Join(p.NoProxy, ",")
+	}
+
+	proxyFunc := config.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+}
+
+// HTTPClient returns a client connecting through the proxy, with the defaults of http.DefaultTransport otherwise.
+func (p Proxy) HTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = p.ProxyFunc()
+
+	return &http.Client{Transport: transport}
+}
@@ -0,0 +1,81 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func proxyFor(t *testing.T, proxy Proxy, address string) string {
+	t.Helper()
+
+	proxyURL, err := proxy.ProxyFunc()(httptest.NewRequest(http.MethodGet, address, nil))
+	require.NoError(t, err)
+	if proxyURL == nil {
+		return ""
+	}
+
+	return proxyURL.String()
+}
+
+func TestProxyValidate(t *testing.T) {
+	assert.NoError(t, Proxy{}.Validate())
+	assert.NoError(t, Proxy{URL: "https://egress:3128"}.Validate())
+	assert.NoError(t, Proxy{URL: "socks5h://egress:1080"}.Validate())
+	assert.ErrorContains(t, Proxy{URL: "ftp://egress:21"}.Validate(), "unsupported proxy url")
+	assert.ErrorContains(t, Proxy{URL: "http://egress:port"}.Validate(), "error parsing proxy url")
+}
+
+func TestProxyFunc(t *testing.T) {
+	t.Setenv("HTTPS_PROXY", "http://env-egress:3128")
+	t.Setenv("HTTP_PROXY", "")
+	t.Setenv("NO_PROXY", "kms.eu-west-1.amazonaws.com")
+
+	assert.Equal(t, "http://env-egress:3128", proxyFor(t, Proxy{}, "https://s3.eu-west-1.amazonaws.com/bucket"))
+	assert.Empty(t, proxyFor(t, Proxy{}, "https://kms.eu-west-1.amazonaws.com/"))
+
+	proxy := Proxy{URL: "socks5://kms-egress:1080", NoProxy: []string{".s3.eu-west-1.amazonaws.com", "10.0.0.0/8"}}
+	assert.Equal(t, "socks5://kms-egress:1080", proxyFor(t, proxy, "https://kms.eu-west-1.amazonaws.com/"))
+	assert.Equal(t, "socks5://kms-egress:1080", proxyFor(t, proxy, "http://kms.eu-west-1.amazonaws.com/"))
+	assert.Empty(t, proxyFor(t, proxy, "https://bucket.s3.eu-west-1.amazonaws.com/key"))
+	assert.Empty(t, proxyFor(t, proxy, "https://10.1.2.3/key"))
+
+	proxy = Proxy{NoProxy: []string{"s3.eu-west-1.amazonaws.com"}}
+	assert.Equal(t, "http://env-egress:3128", proxyFor(t, proxy, "https://kms.eu-west-1.amazonaws.com/"), "the no proxy list replaces NO_PROXY")
+}
+
+func TestProxyHTTPClient(t *testing.T) {
+	var proxied string
+	egress := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer egress.Close()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "http://storage.example.com/bucket/key", nil)
+	require.NoError(t, err)
+	resp, err := Proxy{URL: egress.URL}.HTTPClient().Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "http://storage.example.com/bucket/key", proxied)
+}
@@ -0,0 +1,38 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package s3
+
+import (
+	"context"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+	"github.com/bank-vaults/bank-vaults/pkg/kv/awskms"
+)
+
+func init() {
+	kv.Register("s3", newFromOptions)
+}
+
+// newFromOptions creates the s3 backend storing the values in the bucket of the "bucket" setting under the
+// "prefix" setting, encrypted on the server side as the "sseAlgo" and "sseKeyID" settings say.
+// The AWS config is the one of awskms.ConfigForOptions.
+func newFromOptions(ctx context.Context, options kv.Options) (kv.Service, error) {
+	config, err := awskms.ConfigForOptions(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewWithConfig(ctx, config, options.Settings["bucket"], options.Settings["prefix"], options.Settings["sseAlgo"], options.Settings["sseKeyID"])
+}
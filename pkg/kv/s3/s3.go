@@ -22,7 +22,6 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
 
@@ -39,12 +38,19 @@ type s3Storage struct {
 	sseKeyID string
 }
 
-// New creates a new kv.Service backed by AWS S3
+// New creates a new kv.Service backed by AWS S3, authenticated by the default credential chain.
+// The region is resolved like awskms.LoadConfig does if empty.
 func New(ctx context.Context, region, bucket, prefix, sseAlgo, sseKeyID string) (kv.Service, error) {
-	if region == "" {
-		return nil, errors.New("region must be specified")
+	config, err := awskms.LoadConfig(ctx, region, awskms.Credentials{})
+	if err != nil {
+		return nil, err
 	}
 
+	return NewWithConfig(ctx, config, bucket, prefix, sseAlgo, sseKeyID)
+}
+
+// NewWithConfig creates a new kv.Service backed by AWS S3 with an existing AWS config
+func NewWithConfig(ctx context.Context, config aws.Config, bucket, prefix, sseAlgo, sseKeyID string) (kv.Service, error) {
 	if bucket == "" {
 		return nil, errors.New("bucket must be specified")
 	}
@@ -57,11 +63,6 @@ func New(ctx context.Context, region, bucket, prefix, sseAlgo, sseKeyID string)
 		return nil, errors.New("you need to provide a CMK KeyID when using aws:kms for SSE")
 	}
 
-	config, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
-	if err != nil {
-		return nil, errors.WrapIf(err, "failed to load AWS config")
-	}
-
 	return &s3Storage{ctx, s3.NewFromConfig(config), bucket, prefix, sseAlgo, sseKeyID}, nil
 }
 
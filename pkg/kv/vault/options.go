@@ -0,0 +1,55 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+func init() {
+	kv.Register("vault", newFromOptions)
+}
+
+// newFromOptions creates the vault backend storing the values in the KV Version 2 path of the "path" setting
+// of the Vault at the endpoint, VAULT_ADDR if empty. It logs in as the "role", "authPath", "tokenPath" and
+// "token" settings say, connecting through the proxy of the options.
+func newFromOptions(_ context.Context, options kv.Options) (kv.Service, error) {
+	config := vaultapi.DefaultConfig()
+	if config.Error != nil {
+		return nil, config.Error
+	}
+	if options.Endpoint != "" {
+		config.Address = options.Endpoint
+	}
+	if options.MaxRetries != nil {
+		config.MaxRetries = *options.MaxRetries
+	}
+	if options.Proxy != nil {
+		config.HttpClient.Transport.(*http.Transport).Proxy = options.Proxy.ProxyFunc()
+	}
+
+	return NewWithConfig(config,
+		options.Settings["path"],
+		options.Settings["role"],
+		options.Settings["authPath"],
+		options.Settings["tokenPath"],
+		options.Settings["token"],
+	)
+}
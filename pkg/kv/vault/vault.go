@@ -21,6 +21,7 @@ import (
 
 	"emperror.dev/errors"
 	"github.com/bank-vaults/vault-sdk/vault"
+	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/spf13/cast"
 
 	"github.com/bank-vaults/bank-vaults/pkg/kv"
@@ -49,6 +50,25 @@ func New(addr, unsealKeysPath, role, authPath, tokenPath, token string) (kv.Serv
 	}, nil
 }
 
+// NewWithConfig creates a new kv.Service backed by Vault KV Version 2, using the given Vault API client config
+// and additional client options, e.g. the Vault Enterprise namespace
+func NewWithConfig(config *vaultapi.Config, unsealKeysPath, role, authPath, tokenPath, token string, opts ...vault.ClientOption) (kv.Service, error) {
+	client, err := vault.NewClientFromConfig(config, append([]vault.ClientOption{
+		vault.ClientRole(role),
+		vault.ClientAuthPath(authPath),
+		vault.ClientTokenPath(tokenPath),
+		vault.ClientToken(token),
+	}, opts...)...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create vault client")
+	}
+
+	return &vaultStorage{
+		client: client,
+		path:   unsealKeysPath,
+	}, nil
+}
+
 func (v *vaultStorage) Set(ctx context.Context, key string, val []byte) error {
 	// Done to prevent overwrite in Vault
 	if _, err := v.client.RawClient().Logical().WriteWithContext(ctx,
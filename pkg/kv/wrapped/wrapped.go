@@ -0,0 +1,224 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrapped
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	vaultapi "github.com/hashicorp/vault/api"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+const wrapPath = "sys/wrapping/wrap"
+
+// wrapping is what the storage holds instead of a wrapped value.
+type wrapping struct {
+	Token        string    `json:"token"`
+	Accessor     string    `json:"accessor"`
+	CreationTime time.Time `json:"creation_time"`
+	TTL          int       `json:"ttl"`
+}
+
+// Store is a kv.Service keeping only response-wrapping tokens of some values in the storage:
+// the values are wrapped by a Vault on Set, and unwrapped just-in-time on Get.
+//
+// A wrapping token can only be unwrapped once, so Get wraps the value again with a new token.
+// The tokens expire after the wrapping TTL, Refresh wraps again the values about to expire.
+type Store struct {
+	client  *vaultapi.Client
+	storage kv.Service
+	ttl     time.Duration
+	keys    []string
+
+	mu          sync.Mutex
+	lastRefresh time.Time
+}
+
+// New returns a Store wrapping the values of the keys with the Vault of the client,
+// which has to be another Vault than the one whose init output is stored. The client is dedicated
+// to the Store, its wrapping lookup function is replaced. The values of other keys are stored as they are.
+func New(client *vaultapi.Client, storage kv.Service, ttl time.Duration, keys []string) *Store {
+	wrapTTL := fmt.Sprintf("%ds", int(ttl.Seconds()))
+	client.SetWrappingLookupFunc(func(_, path string) string {
+		if path == wrapPath {
+			return wrapTTL
+		}
+
+		return ""
+	})
+
+	return &Store{
+		client:  client,
+		storage: storage,
+		ttl:     ttl,
+		keys:    keys,
+	}
+}
+
+func (s *Store) Set(ctx context.Context, key string, value []byte) error {
+	if !slices.Contains(s.keys, key) {
+		return s.storage.Set(ctx, key, value) //nolint:wrapcheck
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.wrap(ctx, key, value)
+}
+
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if !slices.Contains(s.keys, key) {
+		return s.storage.Get(ctx, key) //nolint:wrapcheck
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wrapped, err := s.lookup(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := s.unwrap(ctx, key, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token is used up, the value is lost unless it is wrapped again
+	if err := s.wrap(context.WithoutCancel(ctx), key, value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// Refresh wraps again the values whose tokens passed half of their TTL, at most every quarter of the TTL.
+func (s *Store) Refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.lastRefresh) < s.ttl/4 {
+		return nil
+	}
+
+	for _, key := range s.keys {
+		wrapped, err := s.lookup(ctx, key)
+		if kv.IsNotFoundError(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if time.Since(wrapped.CreationTime) < time.Duration(wrapped.TTL)*time.Second/2 {
+			continue
+		}
+
+		slog.Info("wrapping token about to expire, wrapping again", "key", key)
+		value, err := s.unwrap(ctx, key, wrapped)
+		if err != nil {
+			return err
+		}
+		err = s.wrap(context.WithoutCancel(ctx), key, value)
+		clear(value)
+		if err != nil {
+			return err
+		}
+	}
+	s.lastRefresh = time.Now()
+
+	return nil
+}
+
+func (s *Store) wrap(ctx context.Context, key string, value []byte) error {
+	secret, err := s.client.Logical().WriteWithContext(ctx, wrapPath, map[string]interface{}{
+		"value": base64.StdEncoding.EncodeToString(value),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error wrapping key '%s'", key)
+	}
+	if secret == nil || secret.WrapInfo == nil {
+		return errors.Errorf("no wrapping token in the response of wrapping key '%s'", key)
+	}
+
+	data, err := json.Marshal(wrapping{
+		Token:        secret.WrapInfo.Token,
+		Accessor:     secret.WrapInfo.Accessor,
+		CreationTime: secret.WrapInfo.CreationTime,
+		TTL:          secret.WrapInfo.TTL,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling wrapping token of key '%s'", key)
+	}
+
+	return s.storage.Set(ctx, key, data) //nolint:wrapcheck
+}
+
+func (s *Store) lookup(ctx context.Context, key string) (*wrapping, error) {
+	data, err := s.storage.Get(ctx, key)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	var wrapped wrapping
+	if err := json.Unmarshal(data, &wrapped); err != nil || wrapped.Token == "" {
+		return nil, errors.Errorf("key '%s' doesn't hold a wrapping token", key)
+	}
+
+	return &wrapped, nil
+}
+
+// unwrap returns the value of the token, after checking the token was created by wrapping a value:
+// a token created otherwise or already unwrapped means the storage was tampered with.
+func (s *Store) unwrap(ctx context.Context, key string, wrapped *wrapping) ([]byte, error) {
+	info, err := s.client.Logical().WriteWithContext(ctx, "sys/wrapping/lookup", map[string]interface{}{
+		"token": wrapped.Token,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "wrapping token of key '%s' is invalid, expired or already unwrapped", key)
+	}
+	if info == nil || info.Data["creation_path"] != wrapPath {
+		return nil, errors.Errorf("wrapping token of key '%s' wasn't created by wrapping a value", key)
+	}
+
+	secret, err := s.client.Logical().UnwrapWithContext(ctx, wrapped.Token)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error unwrapping key '%s'", key)
+	}
+	if secret == nil {
+		return nil, errors.Errorf("no value in the wrapping token of key '%s'", key)
+	}
+
+	encoded, ok := secret.Data["value"].(string)
+	if !ok {
+		return nil, errors.Errorf("no value in the wrapping token of key '%s'", key)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decoding value of key '%s'", key)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,141 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wrapped
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	vaultapi "github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+type memKV struct {
+	values map[string][]byte
+}
+
+func (m *memKV) Set(_ context.Context, key string, value []byte) error {
+	m.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+func (m *memKV) Get(_ context.Context, key string) ([]byte, error) {
+	value, ok := m.values[key]
+	if !ok {
+		return nil, kv.NewNotFoundError("key not found: %s", key)
+	}
+
+	return append([]byte(nil), value...), nil
+}
+
+// wrappingVault serves the response-wrapping endpoints, its tokens can be unwrapped once.
+func wrappingVault(t *testing.T) *vaultapi.Client {
+	var mu sync.Mutex
+	wrapped := map[string]interface{}{}
+	count := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/v1/sys/wrapping/wrap":
+			assert.Equal(t, "3600s", r.Header.Get("X-Vault-Wrap-TTL"))
+			count++
+			token := fmt.Sprint("wrapping-token-", count)
+			wrapped[token] = body["value"]
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"wrap_info": map[string]interface{}{
+					"token":         token,
+					"ttl":           3600,
+					"creation_time": time.Now(),
+					"creation_path": "sys/wrapping/wrap",
+				},
+			})
+
+		case "/v1/sys/wrapping/lookup":
+			if _, ok := wrapped[body["token"].(string)]; !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"creation_path": "sys/wrapping/wrap"},
+			})
+
+		case "/v1/sys/wrapping/unwrap":
+			value, ok := wrapped[body["token"].(string)]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			delete(wrapped, body["token"].(string))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"value": value},
+			})
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	config := vaultapi.DefaultConfig()
+	config.Address = server.URL
+	client, err := vaultapi.NewClient(config)
+	require.NoError(t, err)
+	client.SetToken("wrapping-vault-token")
+
+	return client
+}
+
+func TestStore(t *testing.T) {
+	storage := &memKV{values: map[string][]byte{}}
+	store := New(wrappingVault(t), storage, time.Hour, []string{"vault-unseal-0"})
+	ctx := context.Background()
+
+	require.NoError(t, store.Set(ctx, "vault-unseal-0", []byte("key share")))
+	require.NoError(t, store.Set(ctx, "vault-test", []byte("test")))
+
+	assert.Equal(t, []byte("test"), storage.values["vault-test"], "other keys are stored as they are")
+	assert.NotContains(t, string(storage.values["vault-unseal-0"]), "key share")
+	assert.Contains(t, string(storage.values["vault-unseal-0"]), "wrapping-token-1")
+
+	for range 2 {
+		value, err := store.Get(ctx, "vault-unseal-0")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("key share"), value)
+	}
+	assert.Contains(t, string(storage.values["vault-unseal-0"]), "wrapping-token-3", "the value is wrapped again after unwrapping")
+
+	// A token unwrapped by someone else can't be unwrapped again
+	stolen := storage.values["vault-unseal-0"]
+	_, err := store.Get(ctx, "vault-unseal-0")
+	require.NoError(t, err)
+	storage.values["vault-unseal-0"] = stolen
+	_, err = store.Get(ctx, "vault-unseal-0")
+	assert.ErrorContains(t, err, "already unwrapped")
+}
@@ -0,0 +1,189 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package notify delivers notifications about the operations of bank-vaults to external systems.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// EventType identifies the kind of operation a notification is about.
+type EventType string
+
+const (
+	// EventConfigureFailed is sent when applying a config to Vault fails
+	EventConfigureFailed EventType = "configure-failed"
+	// EventPurged is sent when an unmanaged resource is removed from Vault
+	EventPurged EventType = "purged"
+	// EventCredentialsRotated is sent when the root credentials of a secret engine are rotated
+	EventCredentialsRotated EventType = "credentials-rotated"
+	// EventInitialized is sent when Vault is initialized
+	EventInitialized EventType = "initialized"
+	// EventUnsealed is sent when a Vault node is unsealed
+	EventUnsealed EventType = "unsealed"
+)
+
+// EventTypes lists all the supported event types.
+var EventTypes = []EventType{EventConfigureFailed, EventPurged, EventCredentialsRotated, EventInitialized, EventUnsealed}
+
+const sendTimeout = 10 * time.Second
+
+// Event is a single notification.
+type Event struct {
+	Type    EventType `json:"type"`
+	Message string    `json:"message"`
+	Section string    `json:"section,omitempty"`
+	Path    string    `json:"path,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Notifier delivers events to an external system.
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Send delivers an event through the notifier, errors are only logged so that
+// a broken notification channel never fails the operation being reported.
+func Send(ctx context.Context, n Notifier, event Event) {
+	if n == nil {
+		return
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	if err := n.Notify(ctx, event); err != nil {
+		slog.Error(fmt.Sprintf("error sending %s notification: %s", event.Type, err.Error()))
+	}
+}
+
+type multi []Notifier
+
+// New returns a Notifier fanning out events to all the given notifiers.
+func New(notifiers ...Notifier) Notifier {
+	return multi(notifiers)
+}
+
+func (m multi) Notify(ctx context.Context, event Event) error {
+	var errs error
+	for _, n := range m {
+		errs = errors.Append(errs, n.Notify(ctx, event))
+	}
+
+	return errs
+}
+
+type filtered struct {
+	notifier Notifier
+	events   []EventType
+}
+
+// Filter returns a Notifier which only forwards the given event types.
+func Filter(n Notifier, events []EventType) Notifier {
+	return &filtered{notifier: n, events: events}
+}
+
+func (f *filtered) Notify(ctx context.Context, event Event) error {
+	if !slices.Contains(f.events, event.Type) {
+		return nil
+	}
+
+	return f.notifier.Notify(ctx, event)
+}
+
+type webhook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewWebhook creates a Notifier posting events as JSON to the given URL.
+func NewWebhook(url string, headers map[string]string) Notifier {
+	return &webhook{url: url, headers: headers, client: http.DefaultClient}
+}
+
+func (w *webhook) Notify(ctx context.Context, event Event) error {
+	return post(ctx, w.client, w.url, w.headers, event)
+}
+
+type slack struct {
+	url     string
+	channel string
+	client  *http.Client
+}
+
+// NewSlack creates a Notifier posting events to a Slack incoming webhook.
+func NewSlack(url, channel string) Notifier {
+	return &slack{url: url, channel: channel, client: http.DefaultClient}
+}
+
+func (s *slack) Notify(ctx context.Context, event Event) error {
+	text := fmt.Sprintf("*bank-vaults %s*: %s", event.Type, event.Message)
+	if event.Path != "" {
+		text = fmt.Sprintf("%s (`%s`)", text, event.Path)
+	}
+
+	payload := map[string]string{"text": text}
+	if s.channel != "" {
+		payload["channel"] = s.channel
+	}
+
+	return post(ctx, s.client, s.url, nil, payload)
+}
+
+func post(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling notification")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating notification request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "error sending notification")
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Error(fmt.Sprintf("error closing response body: %s", err.Error()))
+		}
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("notification endpoint returned unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
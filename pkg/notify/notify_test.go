@@ -0,0 +1,123 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	header http.Header
+	body   map[string]interface{}
+}
+
+func newEndpoint(t *testing.T, status int) (string, *[]request) {
+	t.Helper()
+
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, request{header: r.Header, body: body})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, &requests
+}
+
+type recorder []Event
+
+func (r *recorder) Notify(_ context.Context, event Event) error {
+	*r = append(*r, event)
+
+	return nil
+}
+
+func TestWebhook(t *testing.T) {
+	url, requests := newEndpoint(t, http.StatusNoContent)
+	event := Event{
+		Type:    EventPurged,
+		Message: "removed unmanaged policies resource",
+		Section: "policies",
+		Path:    "old-policy",
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	require.NoError(t, NewWebhook(url, map[string]string{"Authorization": "Bearer token"}).Notify(context.Background(), event))
+	require.Len(t, *requests, 1)
+	assert.Equal(t, "application/json", (*requests)[0].header.Get("Content-Type"))
+	assert.Equal(t, "Bearer token", (*requests)[0].header.Get("Authorization"))
+	assert.Equal(t, map[string]interface{}{
+		"type":    "purged",
+		"message": "removed unmanaged policies resource",
+		"section": "policies",
+		"path":    "old-policy",
+		"time":    "2026-01-02T03:04:05Z",
+	}, (*requests)[0].body)
+}
+
+func TestSlack(t *testing.T) {
+	url, requests := newEndpoint(t, http.StatusOK)
+
+	require.NoError(t, NewSlack(url, "#vault").Notify(context.Background(), Event{Type: EventPurged, Message: "removed", Path: "old-policy"}))
+	require.NoError(t, NewSlack(url, "").Notify(context.Background(), Event{Type: EventUnsealed, Message: "unsealed vault-0"}))
+	require.Len(t, *requests, 2)
+	assert.Equal(t, map[string]interface{}{"text": "*bank-vaults purged*: removed (`old-policy`)", "channel": "#vault"}, (*requests)[0].body)
+	assert.Equal(t, map[string]interface{}{"text": "*bank-vaults unsealed*: unsealed vault-0"}, (*requests)[1].body)
+}
+
+func TestNotifyErrors(t *testing.T) {
+	url, _ := newEndpoint(t, http.StatusInternalServerError)
+	assert.EqualError(t, NewWebhook(url, nil).Notify(context.Background(), Event{Type: EventPurged}),
+		"notification endpoint returned unexpected status code: 500")
+
+	assert.ErrorContains(t, NewWebhook("http://127.0.0.1:0", nil).Notify(context.Background(), Event{Type: EventPurged}),
+		"error sending notification")
+
+	// The notifiers after a failing one are still notified
+	events := &recorder{}
+	assert.Error(t, New(NewSlack(url, ""), events).Notify(context.Background(), Event{Type: EventPurged}))
+	assert.Len(t, *events, 1)
+
+	// Send only logs the errors
+	assert.NotPanics(t, func() {
+		Send(context.Background(), NewWebhook(url, nil), Event{Type: EventPurged})
+		Send(context.Background(), nil, Event{Type: EventPurged})
+	})
+}
+
+func TestFilter(t *testing.T) {
+	events := &recorder{}
+	n := Filter(events, []EventType{EventPurged, EventUnsealed})
+
+	for _, eventType := range EventTypes {
+		Send(context.Background(), n, Event{Type: eventType})
+	}
+
+	require.Len(t, *events, 2)
+	assert.Equal(t, EventPurged, (*events)[0].Type)
+	assert.Equal(t, EventUnsealed, (*events)[1].Type)
+	assert.False(t, (*events)[0].Time.IsZero(), "Send stamps the events")
+}
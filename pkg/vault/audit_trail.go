@@ -0,0 +1,129 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+)
+
+// Operations recorded in the audit trail.
+const (
+	AuditOperationWrite  = "write"
+	AuditOperationMount  = "mount"
+	AuditOperationTune   = "tune"
+	AuditOperationDelete = "delete"
+)
+
+// AuditTrailEntry describes a single write performed by the configurer.
+// Only the names of the written fields are recorded, never their values.
+type AuditTrailEntry struct {
+	Time       time.Time `json:"time"`
+	Operation  string    `json:"operation"`
+	Path       string    `json:"path"`
+	Fields     []string  `json:"fields,omitempty"`
+	ConfigHash string    `json:"configHash,omitempty"`
+}
+
+// AuditTrail is an append-only log of the writes performed by the configurer.
+type AuditTrail interface {
+	Append(ctx context.Context, entry AuditTrailEntry) error
+}
+
+var auditTrailSeq atomic.Uint64
+
+// auditTrailKey returns a unique, chronologically sortable key for an entry, so entries are never overwritten.
+func auditTrailKey(entry AuditTrailEntry) string {
+	return fmt.Sprintf("%s-%06d", entry.Time.UTC().Format("20060102T150405.000000000Z"), auditTrailSeq.Add(1))
+}
+
+type kvAuditTrail struct {
+	keyStore KVService
+}
+
+// NewKVAuditTrail returns an AuditTrail storing entries in the key store, each under its own key.
+func NewKVAuditTrail(keyStore KVService) AuditTrail {
+	return &kvAuditTrail{keyStore: keyStore}
+}
+
+func (t *kvAuditTrail) Append(ctx context.Context, entry AuditTrailEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling audit trail entry")
+	}
+
+	return t.keyStore.Set(ctx, "vault-audit-trail-"+auditTrailKey(entry), data)
+}
+
+type vaultAuditTrail struct {
+	cl   *api.Client
+	path string
+}
+
+// NewVaultAuditTrail returns an AuditTrail storing entries in a Vault KV mount under the given path.
+// Paths containing '/data/' are treated as KV version 2.
+func NewVaultAuditTrail(cl *api.Client, path string) AuditTrail {
+	return &vaultAuditTrail{cl: cl, path: strings.Trim(path, "/")}
+}
+
+// withClient returns a copy of the audit trail writing with the given client.
+func (t *vaultAuditTrail) withClient(cl *api.Client) AuditTrail {
+	return &vaultAuditTrail{cl: cl, path: t.path}
+}
+
+func (t *vaultAuditTrail) Append(ctx context.Context, entry AuditTrailEntry) error {
+	data := map[string]interface{}{
+		"time":        entry.Time.Format(time.RFC3339Nano),
+		"operation":   entry.Operation,
+		"path":        entry.Path,
+		"fields":      entry.Fields,
+		"config_hash": entry.ConfigHash,
+	}
+	if strings.Contains(t.path+"/", "/data/") {
+		data = map[string]interface{}{"data": data}
+	}
+
+	_, err := t.cl.Logical().WriteWithContext(ctx, fmt.Sprintf("%s/%s", t.path, auditTrailKey(entry)), data)
+
+	return errors.Wrap(err, "error writing audit trail entry to vault")
+}
+
+// recordWrite appends an entry to the audit trail, if one is configured.
+func (v *vault) recordWrite(operation, path string, data map[string]interface{}) {
+	if v.config == nil || v.config.AuditTrail == nil {
+		return
+	}
+
+	entry := AuditTrailEntry{
+		Time:       time.Now(),
+		Operation:  operation,
+		Path:       path,
+		Fields:     slices.Sorted(maps.Keys(data)),
+		ConfigHash: v.configHash,
+	}
+
+	if err := v.config.AuditTrail.Append(v.ctx, entry); err != nil {
+		v.log().Error("error recording write in audit trail", "operation", operation, "path", path, "error", err)
+	}
+}
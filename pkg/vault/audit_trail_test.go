@@ -0,0 +1,57 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultAuditTrailUsesConfigureToken(t *testing.T) {
+	var mu sync.Mutex
+	tokens := map[string]string{}
+
+	cl := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tokens[strings.TrimPrefix(r.URL.Path, "/v1/")] = r.Header.Get("X-Vault-Token")
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	})).cl
+
+	ctx := context.Background()
+	v, err := New(ctx, nil, cl, Config{
+		Token:      "configurer-token",
+		AuditTrail: NewVaultAuditTrail(cl, "audit/bank-vaults"),
+	})
+	require.NoError(t, err)
+
+	impl := v.(*vault)
+	require.NoError(t, impl.login(ctx))
+	impl.recordWrite(AuditOperationWrite, "sys/policies/acl/admin", map[string]interface{}{"policy": "..."})
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, tokens, 1)
+	for path, token := range tokens {
+		assert.True(t, strings.HasPrefix(path, "audit/bank-vaults/"), path)
+		assert.Equal(t, "configurer-token", token)
+	}
+}
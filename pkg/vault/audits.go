@@ -15,8 +15,9 @@
 package vault
 
 import (
-	"fmt"
-	"log/slog"
+	"context"
+	"maps"
+	"slices"
 	"strings"
 
 	"emperror.dev/errors"
@@ -24,14 +25,15 @@ import (
 	"github.com/mitchellh/mapstructure"
 )
 
-type audit struct {
+// AuditDevice is an audit device of the audit section, enabled at its path, the type if empty.
+type AuditDevice struct {
 	Type        string                 `mapstructure:"type"`
 	Path        string                 `mapstructure:"path"`
 	Description string                 `mapstructure:"description"`
 	Options     map[string]interface{} `mapstructure:"options"`
 }
 
-func initAuditConfig(configs []audit) []audit {
+func initAuditConfig(configs []AuditDevice) []AuditDevice {
 	for index, config := range configs {
 		if config.Path == "" {
 			configs[index].Path = config.Type
@@ -52,7 +54,7 @@ func (v *vault) getExistingAudits() (map[string]bool, error) {
 		return nil, errors.Wrapf(err, "unable to list existing audits")
 	}
 
-	slog.Debug(fmt.Sprintf("already existing audit devices: %#v", existingAuditsList))
+	v.log().Debug("already existing audit devices", "section", SectionAudit, "audits", existingAuditsList)
 
 	for existingAuditPath := range existingAuditsList {
 		existingAudits[strings.Trim(existingAuditPath, "/")] = true
@@ -61,7 +63,7 @@ func (v *vault) getExistingAudits() (map[string]bool, error) {
 	return existingAudits, nil
 }
 
-func (v *vault) getUnmanagedAudits(managedAudits []audit) map[string]bool {
+func (v *vault) getUnmanagedAudits(managedAudits []AuditDevice) map[string]bool {
 	unmanagedAudits, _ := v.getExistingAudits()
 
 	// Remove managed audits form the items since the reset will be removed.
@@ -72,54 +74,75 @@ func (v *vault) getUnmanagedAudits(managedAudits []audit) map[string]bool {
 	return unmanagedAudits
 }
 
-func (v *vault) addManagedAudits(managedAudits []audit) error {
+func (v *vault) addManagedAudits(managedAudits []AuditDevice) error {
 	existingAudits, _ := v.getExistingAudits()
 
 	for _, auditDevice := range managedAudits {
-		if existingAudits[auditDevice.Path] {
-			slog.Info(fmt.Sprintf("audit device is already mounted %s/", auditDevice.Path))
-		} else {
-			var options api.EnableAuditOptions
-			err := mapstructure.Decode(auditDevice, &options)
-			if err != nil {
-				return errors.Wrap(err, "error parsing audit options")
-			}
-
-			slog.Info(fmt.Sprintf("adding audit device %s (%s)", auditDevice.Path, auditDevice.Type))
-			slog.Debug(fmt.Sprintf("audit device options %#v", options))
-			err = v.cl.Sys().EnableAuditWithOptions(auditDevice.Path+"/", &options)
-			if err != nil {
-				return errors.Wrapf(err, "error enabling audit device %s in vault", auditDevice.Path)
-			}
+		if err := v.itemFailed(SectionAudit, auditDevice.Path, v.addManagedAudit(auditDevice, existingAudits)); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+func (v *vault) addManagedAudit(auditDevice AuditDevice, existingAudits map[string]bool) error {
+	if existingAudits[auditDevice.Path] {
+		v.log().Info("audit device is already mounted", "section", SectionAudit, "path", auditDevice.Path)
+		v.report.skipped(SectionAudit, auditDevice.Path)
+
+		return nil
+	}
+
+	var options api.EnableAuditOptions
+	err := mapstructure.Decode(auditDevice, &options)
+	if err != nil {
+		return errors.Wrap(err, "error parsing audit options")
+	}
+
+	v.log().Info("adding audit device", "section", SectionAudit, "path", auditDevice.Path, "type", auditDevice.Type)
+	v.log().Debug("audit device options", "section", SectionAudit, "path", auditDevice.Path, "options", v.redact(options))
+	err = v.cl.Sys().EnableAuditWithOptions(auditDevice.Path+"/", &options)
+	if err != nil {
+		return errors.Wrapf(err, "error enabling audit device %s in vault", auditDevice.Path)
+	}
+	v.report.created(SectionAudit, auditDevice.Path)
+	v.recordWrite(AuditOperationMount, "sys/audit/"+auditDevice.Path, auditDevice.Options)
+
+	return nil
+}
+
 // Disables any audit that's not managed if purgeUnmanagedConfig option is enabled, otherwise it leaves them
-func (v *vault) removeUnmanagedAudits(unmanagedAudits map[string]bool) error {
+func (v *vault) removeUnmanagedAudits(ctx context.Context, unmanagedAudits map[string]bool) error {
 	if len(unmanagedAudits) == 0 || !v.externalConfig.PurgeUnmanagedConfig.Enabled || v.externalConfig.PurgeUnmanagedConfig.Exclude.Audit {
 		return nil
 	}
 
 	for auditPath := range unmanagedAudits {
-		slog.Info(fmt.Sprintf("removing unmanaged audit device %s", auditPath))
+		v.log().Info("removing unmanaged audit device", "section", SectionAudit, "path", auditPath)
 		err := v.cl.Sys().DisableAudit(auditPath)
 		if err != nil {
-			return errors.Wrapf(err, "error disabling %s audit in vault", auditPath)
+			if err := v.itemFailed(SectionAudit, auditPath, errors.Wrapf(err, "error disabling %s audit in vault", auditPath)); err != nil {
+				return err
+			}
+
+			continue
 		}
+		v.resourcePurged(ctx, SectionAudit, auditPath)
 	}
 	return nil
 }
 
-func (v *vault) configureAuditDevices() error {
+func (v *vault) configureAuditDevices(ctx context.Context) error {
 	managedAudits := initAuditConfig(v.externalConfig.Audit)
 	if err := v.addManagedAudits(managedAudits); err != nil {
 		return errors.Wrap(err, "error configuring managed audits")
 	}
 
-	if err := v.removeUnmanagedAudits(v.getUnmanagedAudits(managedAudits)); err != nil {
+	unmanagedAudits := v.getUnmanagedAudits(managedAudits)
+	v.report.unmanaged(SectionAudit, slices.Sorted(maps.Keys(unmanagedAudits)))
+
+	if err := v.removeUnmanagedAudits(ctx, unmanagedAudits); err != nil {
 		return errors.Wrap(err, "error while disabling unmanaged auth methods")
 	}
 
@@ -15,10 +15,11 @@
 package vault
 
 import (
+	"context"
 	"fmt"
-	"log/slog"
 	"maps"
 	"os"
+	"slices"
 	"strings"
 
 	"emperror.dev/errors"
@@ -27,7 +28,9 @@ import (
 	"github.com/spf13/cast"
 )
 
-type auth struct {
+// AuthMethod is an auth method of the auth section, enabled at its path, the type if empty, with the roles,
+// users, groups and config the type supports written below it.
+type AuthMethod struct {
 	Type             string                 `mapstructure:"type"`
 	Path             string                 `mapstructure:"path"`
 	Description      string                 `mapstructure:"description"`
@@ -41,7 +44,7 @@ type auth struct {
 	Config           map[string]interface{} `mapstructure:"config"`
 }
 
-func initAuthConfig(auths []auth) []auth {
+func initAuthConfig(auths []AuthMethod) []AuthMethod {
 	for index, auth := range auths {
 		// Use the type as a path in case the path is not set.
 		if auth.Path == "" {
@@ -62,7 +65,7 @@ func initAuthConfig(auths []auth) []auth {
 	return auths
 }
 
-func (v *vault) addAdditionalAuthConfig(authMethod auth) error {
+func (v *vault) addAdditionalAuthConfig(authMethod AuthMethod) error {
 	switch authMethod.Type {
 	case "kubernetes":
 		if authMethod.Config == nil {
@@ -319,7 +322,7 @@ func (v *vault) configureAWSCrossAccountRoles(path string, crossAccountRoles []i
 
 // TODO try to generalize this with configureGenericAuthRoles() fix the type flaw
 func (v *vault) configureJwtRoles(path string, roles []interface{}) error {
-	for _, roleInterface := range roles {
+	return firstError(forEachItem(v.concurrency(), roles, true, func(roleInterface interface{}) error {
 		role, err := cast.ToStringMapE(roleInterface)
 		if err != nil {
 			return errors.Wrap(err, "error converting roles for jwt")
@@ -336,12 +339,9 @@ func (v *vault) configureJwtRoles(path string, roles []interface{}) error {
 		}
 
 		_, err = v.writeWithWarningCheck(fmt.Sprintf("auth/%s/role/%s", path, role["name"]), role)
-		if err != nil {
-			return errors.Wrapf(err, "error putting %s jwt role into vault", role["name"])
-		}
-	}
 
-	return nil
+		return errors.Wrapf(err, "error putting %s jwt role into vault", role["name"])
+	}))
 }
 
 func (v *vault) configureGenericUserAndGroupMappings(method, path string, mappingType string, mappings map[string]interface{}) error {
@@ -384,97 +384,116 @@ func (v *vault) configureGenericAuthConfig(method, path string, config map[strin
 // https://www.vaultproject.io/api/auth/approle/index.html
 // https://www.vaultproject.io/api/auth/token/index.html
 func (v *vault) configureGenericAuthRoles(method, path, roleSubPath string, roles []interface{}) error {
-	for _, roleInterface := range roles {
+	return firstError(forEachItem(v.concurrency(), roles, true, func(roleInterface interface{}) error {
 		role, err := cast.ToStringMapE(roleInterface)
 		if err != nil {
 			return errors.Wrapf(err, "error converting roles for %s", method)
 		}
 
 		_, err = v.writeWithWarningCheck(fmt.Sprintf("auth/%s/%s/%s", path, roleSubPath, role["name"]), role)
-		if err != nil {
-			return errors.Wrapf(err, "error putting %s %s role into vault", role["name"], method)
-		}
-	}
 
-	return nil
+		return errors.Wrapf(err, "error putting %s %s role into vault", role["name"], method)
+	}))
 }
 
-func (v *vault) addManagedAuthMethods(managedAuths []auth) error {
-	slog.Info("about to add managed auth methods")
+func (v *vault) addManagedAuthMethods(ctx context.Context, managedAuths []AuthMethod) error {
+	v.log().Info("about to add managed auth methods", "section", SectionAuth)
 	existingAuths, err := v.getExistingAuthMethods()
 	if err != nil {
 		return errors.Wrapf(err, "unable to list existing auth methods")
 	}
 
+	retryPolicy := v.retryPolicy(SectionAuth)
+
 	for _, authMethod := range managedAuths {
-		slog.Info(fmt.Sprintf("checking auth method %s (%s)", authMethod.Path, authMethod.Type))
-		description := fmt.Sprintf("%s backend", authMethod.Type)
-
-		// get auth mount options
-		// https://www.vaultproject.io/api/system/auth.html#config
-		var authConfigInput api.AuthConfigInput
-		hasMountOptions := authMethod.Options != nil
-		// https://www.vaultproject.io/api/system/auth.html
-		var options api.EnableAuthOptions
-		if hasMountOptions {
-			if err := mapstructure.Decode(authMethod.Options, &authConfigInput); err != nil {
-				return errors.Wrap(err, "error parsing auth method options")
-			}
+		if err := v.itemFailed(SectionAuth, authMethod.Path, v.addManagedAuthMethod(ctx, authMethod, existingAuths, retryPolicy)); err != nil {
+			return err
+		}
+	}
 
-			options = api.EnableAuthOptions{
-				Type:        authMethod.Type,
-				Description: description,
-				Config:      authConfigInput,
-			}
-		} else {
-			options = api.EnableAuthOptions{
-				Type:        authMethod.Type,
-				Description: description,
-			}
+	return nil
+}
+
+func (v *vault) addManagedAuthMethod(ctx context.Context, authMethod AuthMethod, existingAuths map[string]*api.MountOutput, retryPolicy RetryPolicy) error {
+	v.log().Info("checking auth method", "section", SectionAuth, "path", authMethod.Path, "type", authMethod.Type)
+	description := fmt.Sprintf("%s backend", authMethod.Type)
+
+	// get auth mount options
+	// https://www.vaultproject.io/api/system/auth.html#config
+	var authConfigInput api.AuthConfigInput
+	hasMountOptions := authMethod.Options != nil
+	// https://www.vaultproject.io/api/system/auth.html
+	var options api.EnableAuthOptions
+	if hasMountOptions {
+		if err := mapstructure.Decode(authMethod.Options, &authConfigInput); err != nil {
+			return errors.Wrap(err, "error parsing auth method options")
 		}
 
-		// We have to filter all existing auths, not to re-enable them as that would raise an error
-		if existingAuths[authMethod.Path] == nil {
-			slog.Info(fmt.Sprintf("adding auth method %s (%s)", authMethod.Path, authMethod.Type))
-			if err := v.cl.Sys().EnableAuthWithOptions(authMethod.Path, &options); err != nil {
-				return errors.Wrapf(err, "error enabling %s auth method in vault", authMethod.Path)
-			}
+		options = api.EnableAuthOptions{
+			Type:        authMethod.Type,
+			Description: description,
+			Config:      authConfigInput,
+		}
+	} else {
+		options = api.EnableAuthOptions{
+			Type:        authMethod.Type,
+			Description: description,
 		}
+	}
 
-		// If auth method exists but has additional mount options
-		if hasMountOptions {
-			slog.Info(fmt.Sprintf("tuning existing auth %s (%s)", authMethod.Path, authMethod.Type))
-			// all auth methods are mounted below auth/
-			tunePath := fmt.Sprintf("auth/%s", authMethod.Path)
-			if err := v.cl.Sys().TuneMountAllowNilWithContext(v.ctx, tunePath, convertToTuneMountConfigInput(authConfigInput)); err != nil {
-				return errors.Wrapf(err, "error tuning %s (%s) auth method in vault", authMethod.Path, authMethod.Type)
-			}
+	// We have to filter all existing auths, not to re-enable them as that would raise an error
+	if existingAuths[authMethod.Path] == nil {
+		v.log().Info("adding auth method", "section", SectionAuth, "path", authMethod.Path, "type", authMethod.Type)
+		err := retryPolicy.retry(ctx, v.log(), fmt.Sprintf("enabling %s auth method", authMethod.Path), func() error {
+			return v.cl.Sys().EnableAuthWithOptions(authMethod.Path, &options)
+		})
+		v.authsChanged()
+		if err != nil {
+			return errors.Wrapf(err, "error enabling %s auth method in vault", authMethod.Path)
 		}
+		v.report.created(SectionAuth, authMethod.Path)
+		v.recordWrite(AuditOperationMount, "sys/auth/"+authMethod.Path, authMethod.Options)
+	} else {
+		v.report.updated(SectionAuth, authMethod.Path)
+	}
 
-		if err := v.addAdditionalAuthConfig(authMethod); err != nil {
-			return errors.Wrapf(err, "error while adding auth method config")
+	// If auth method exists but has additional mount options
+	if hasMountOptions {
+		v.log().Info("tuning existing auth method", "section", SectionAuth, "path", authMethod.Path, "type", authMethod.Type)
+		// all auth methods are mounted below auth/
+		tunePath := fmt.Sprintf("auth/%s", authMethod.Path)
+		err := retryPolicy.retry(ctx, v.log(), fmt.Sprintf("tuning %s auth method", authMethod.Path), func() error {
+			return v.cl.Sys().TuneMountAllowNilWithContext(ctx, tunePath, convertToTuneMountConfigInput(authConfigInput))
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error tuning %s (%s) auth method in vault", authMethod.Path, authMethod.Type)
 		}
+		v.recordWrite(AuditOperationTune, "sys/mounts/"+tunePath+"/tune", authMethod.Options)
+	}
 
-		// This configuration only makes sense if authentication is done against AWS
-		// However, AWS authentication can be configured using an "aws" or "plugin" backend.
-		// Since it's not specific for only one backend type,
-		// this code lives in this function rather than in addAdditionalAuthConfig
-		if authMethod.Config != nil {
-			for configOption, configDataRaw := range authMethod.Config {
-				slog.Debug(fmt.Sprintf("Handling auth method config option: %s", configOption))
-				switch configOption {
-				case configKeyAwsIdentityIntegration:
-					configData, err := cast.ToStringMapE(configDataRaw)
-					if err != nil {
-						return errors.Wrap(err, "error converting configDataRaw for aws-identity-integration configuration")
-					}
-					err = v.configureAwsIdentityIntegration(authMethod.Path, configData)
-					if err != nil {
-						return errors.Wrap(err, "error configuring aws identity integration")
-					}
-				default:
-					continue
+	if err := v.addAdditionalAuthConfig(authMethod); err != nil {
+		return errors.Wrapf(err, "error while adding auth method config")
+	}
+
+	// This configuration only makes sense if authentication is done against AWS
+	// However, AWS authentication can be configured using an "aws" or "plugin" backend.
+	// Since it's not specific for only one backend type,
+	// this code lives in this function rather than in addAdditionalAuthConfig
+	if authMethod.Config != nil {
+		for configOption, configDataRaw := range authMethod.Config {
+			v.log().Debug("handling auth method config option", "section", SectionAuth, "path", authMethod.Path, "option", configOption)
+			switch configOption {
+			case configKeyAwsIdentityIntegration:
+				configData, err := cast.ToStringMapE(configDataRaw)
+				if err != nil {
+					return errors.Wrap(err, "error converting configDataRaw for aws-identity-integration configuration")
+				}
+				err = v.configureAwsIdentityIntegration(authMethod.Path, configData)
+				if err != nil {
+					return errors.Wrap(err, "error configuring aws identity integration")
 				}
+			default:
+				continue
 			}
 		}
 	}
@@ -487,7 +506,7 @@ func (v *vault) addManagedAuthMethods(managedAuths []auth) error {
 func (v *vault) getExistingAuthMethods() (map[string]*api.MountOutput, error) {
 	existingAuths := make(map[string]*api.MountOutput)
 
-	existingAuthList, err := v.cl.Sys().ListAuth()
+	existingAuthList, err := v.listAuth()
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to list existing auth methods")
 	}
@@ -501,7 +520,7 @@ func (v *vault) getExistingAuthMethods() (map[string]*api.MountOutput, error) {
 }
 
 // getUnmanagedAuthMethods gets unmanaged auth methods by comparing what's already in Vault and what's in the externalConfig
-func (v *vault) getUnmanagedAuthMethods(managedAuthMethods []auth) map[string]*api.MountOutput {
+func (v *vault) getUnmanagedAuthMethods(managedAuthMethods []AuthMethod) map[string]*api.MountOutput {
 	unmanagedAuths, _ := v.getExistingAuthMethods()
 
 	// Remove managed auth methods form the items since the rest will be disabled.
@@ -515,32 +534,39 @@ func (v *vault) getUnmanagedAuthMethods(managedAuthMethods []auth) map[string]*a
 }
 
 // Disables any auth method that's not managed if purgeUnmanagedConfig option is enabled
-func (v *vault) removeUnmanagedAuthMethods(unmanagedAuths map[string]*api.MountOutput) error {
+func (v *vault) removeUnmanagedAuthMethods(ctx context.Context, unmanagedAuths map[string]*api.MountOutput) error {
 	if len(unmanagedAuths) == 0 || !v.externalConfig.PurgeUnmanagedConfig.Enabled || v.externalConfig.PurgeUnmanagedConfig.Exclude.Auth {
 		return nil
 	}
 
 	for authMethod := range unmanagedAuths {
-		slog.Info(fmt.Sprintf("removing auth method %s ", authMethod))
+		v.log().Info("removing auth method", "section", SectionAuth, "path", authMethod)
 		err := v.cl.Sys().DisableAuth(authMethod)
+		v.authsChanged()
 		if err != nil {
-			return errors.Wrapf(err, "error disabling %s auth method in vault", authMethod)
+			if err := v.itemFailed(SectionAuth, authMethod, errors.Wrapf(err, "error disabling %s auth method in vault", authMethod)); err != nil {
+				return err
+			}
+
+			continue
 		}
+		v.resourcePurged(ctx, SectionAuth, authMethod)
 	}
 
 	return nil
 }
 
-func (v *vault) configureAuthMethods() error {
-	slog.Info("configuring auth methods")
+func (v *vault) configureAuthMethods(ctx context.Context) error {
+	v.log().Info("configuring auth methods", "section", SectionAuth)
 	managedAuths := initAuthConfig(v.externalConfig.Auth)
 	unmanagedAuths := v.getUnmanagedAuthMethods(managedAuths)
+	v.report.unmanaged(SectionAuth, slices.Sorted(maps.Keys(unmanagedAuths)))
 
-	if err := v.addManagedAuthMethods(managedAuths); err != nil {
+	if err := v.addManagedAuthMethods(ctx, managedAuths); err != nil {
 		return errors.Wrap(err, "error configuring managed auth methods")
 	}
 
-	if err := v.removeUnmanagedAuthMethods(unmanagedAuths); err != nil {
+	if err := v.removeUnmanagedAuthMethods(ctx, unmanagedAuths); err != nil {
 		return errors.Wrap(err, "error while disabling unmanaged auth methods")
 	}
 
@@ -24,35 +24,35 @@ import (
 func TestInitAuthConfig(t *testing.T) {
 	tests := []struct {
 		name     string
-		input    []auth
-		expected []auth
+		input    []AuthMethod
+		expected []AuthMethod
 	}{
 		{
 			name: "sets path to type when path is empty",
-			input: []auth{
+			input: []AuthMethod{
 				{Type: "aws", Path: ""},
 			},
-			expected: []auth{
+			expected: []AuthMethod{
 				{Type: "aws", Path: "aws"},
 			},
 		},
 		{
 			name: "preserves explicit path",
-			input: []auth{
+			input: []AuthMethod{
 				{Type: "aws", Path: "custom-aws"},
 			},
-			expected: []auth{
+			expected: []AuthMethod{
 				{Type: "aws", Path: "custom-aws"},
 			},
 		},
 		{
 			name: "handles multiple auth methods",
-			input: []auth{
+			input: []AuthMethod{
 				{Type: "kubernetes", Path: ""},
 				{Type: "aws", Path: "aws-prod"},
 				{Type: "github", Path: ""},
 			},
-			expected: []auth{
+			expected: []AuthMethod{
 				{Type: "kubernetes", Path: "kubernetes"},
 				{Type: "aws", Path: "aws-prod"},
 				{Type: "github", Path: "github"},
@@ -60,12 +60,12 @@ func TestInitAuthConfig(t *testing.T) {
 		},
 		{
 			name:     "handles empty auth list",
-			input:    []auth{},
-			expected: []auth{},
+			input:    []AuthMethod{},
+			expected: []AuthMethod{},
 		},
 		{
 			name: "converts nested map types in config",
-			input: []auth{
+			input: []AuthMethod{
 				{
 					Type: "jwt",
 					Path: "jwt",
@@ -77,7 +77,7 @@ func TestInitAuthConfig(t *testing.T) {
 					},
 				},
 			},
-			expected: []auth{
+			expected: []AuthMethod{
 				{
 					Type: "jwt",
 					Path: "jwt",
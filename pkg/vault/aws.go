@@ -0,0 +1,114 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"log/slog"
+
+	"emperror.dev/errors"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/spf13/cast"
+)
+
+// awsPreflightRegion is the region of the STS calls when the root config sets none, the default of the AWS secrets engine.
+const awsPreflightRegion = "us-east-1"
+
+// awsRootConfig holds the fields of the AWS secrets engine config/root the pre-flight check uses.
+type awsRootConfig struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Region       string
+	STSEndpoint  string
+	// RoleARN, IdentityTokenAudience and IdentityTokenTTL configure plugin workload identity federation,
+	// where Vault exchanges its own identity token for credentials of the role.
+	RoleARN               string
+	IdentityTokenAudience string
+	IdentityTokenTTL      string
+}
+
+func newAWSRootConfig(data map[string]interface{}) awsRootConfig {
+	return awsRootConfig{
+		AccessKey:             cast.ToString(data["access_key"]),
+		SecretKey:             cast.ToString(data["secret_key"]),
+		SessionToken:          cast.ToString(data["session_token"]),
+		Region:                cast.ToString(data["region"]),
+		STSEndpoint:           cast.ToString(data["sts_endpoint"]),
+		RoleARN:               cast.ToString(data["role_arn"]),
+		IdentityTokenAudience: cast.ToString(data["identity_token_audience"]),
+		IdentityTokenTTL:      cast.ToString(data["identity_token_ttl"]),
+	}
+}
+
+// workloadIdentity reports whether the config uses plugin workload identity federation.
+func (c awsRootConfig) workloadIdentity() bool {
+	return c.RoleARN != "" || c.IdentityTokenAudience != "" || c.IdentityTokenTTL != ""
+}
+
+// validateAWSRootConfig checks the credentials of an AWS secrets engine config/root with an STS
+// GetCallerIdentity call before they are written to Vault.
+// Configs without static credentials are skipped, as Vault resolves those from its own environment,
+// and so are workload identity federation configs, as only Vault can obtain their identity token.
+// The check can be disabled with skip_preflight on the config/root item.
+func validateAWSRootConfig(ctx context.Context, configPath string, data map[string]interface{}) error {
+	rootConfig := newAWSRootConfig(data)
+	if rootConfig.workloadIdentity() {
+		slog.Debug("workload identity federation configured, skipping AWS pre-flight check", "section", SectionSecrets, "path", configPath)
+		return nil
+	}
+	if rootConfig.AccessKey == "" {
+		slog.Debug("no static credentials, skipping AWS pre-flight check", "section", SectionSecrets, "path", configPath)
+		return nil
+	}
+
+	region := rootConfig.Region
+	if region == "" {
+		region = awsPreflightRegion
+	}
+
+	stsOptions := func(o *sts.Options) {
+		if rootConfig.STSEndpoint != "" {
+			o.BaseEndpoint = aws.String(rootConfig.STSEndpoint)
+		}
+	}
+
+	cfg := aws.Config{
+		Region:      region,
+		Credentials: credentials.NewStaticCredentialsProvider(rootConfig.AccessKey, rootConfig.SecretKey, rootConfig.SessionToken),
+	}
+
+	identity, err := sts.NewFromConfig(cfg, stsOptions).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Wrapf(awsPreflightError(err), "AWS pre-flight check of %s failed", configPath)
+	}
+
+	slog.Info("AWS credentials are valid", "section", SectionSecrets, "path", configPath, "arn", aws.ToString(identity.Arn))
+
+	return nil
+}
+
+// awsPreflightError turns IAM and STS API errors into an error carrying only their code and message.
+func awsPreflightError(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return errors.Errorf("%s: %s", apiErr.ErrorCode(), apiErr.ErrorMessage())
+	}
+
+	return err
+}
@@ -0,0 +1,40 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAWSRootConfigSkipsWorkloadIdentity(t *testing.T) {
+	// An unreachable STS endpoint fails the check if it's not skipped
+	configs := []map[string]interface{}{
+		{"role_arn": "arn:aws:iam::123456789012:role/vault", "identity_token_audience": "vault", "sts_endpoint": "http://127.0.0.1:1"},
+		{"access_key": "AKIA", "secret_key": "secret", "identity_token_ttl": "1h", "sts_endpoint": "http://127.0.0.1:1"},
+		{"region": "eu-west-1"},
+	}
+
+	for _, config := range configs {
+		assert.NoError(t, validateAWSRootConfig(context.Background(), "aws/config/root", config))
+	}
+
+	err := validateAWSRootConfig(context.Background(), "aws/config/root", map[string]interface{}{
+		"access_key": "AKIA", "secret_key": "secret", "sts_endpoint": "http://127.0.0.1:1",
+	})
+	assert.Error(t, err)
+}
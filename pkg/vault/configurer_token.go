@@ -0,0 +1,299 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+)
+
+// ConfigurerPolicyName is the name of the policy of the configurer token, it is never purged.
+const ConfigurerPolicyName = "bank-vaults-configurer"
+
+const (
+	keyConfigurerToken        = "vault-configurer-token"
+	keyConfigurerPolicyDigest = "vault-configurer-policy-digest"
+
+	// the configurer token is periodic: it lives as long as configure renews it
+	configurerTokenPeriod = 24 * time.Hour
+)
+
+var (
+	capabilitiesCRUD  = []string{"create", "read", "update", "delete", "list"}
+	capabilitiesMount = []string{"create", "read", "update", "delete", "sudo"}
+)
+
+// ConfigurerPolicy returns the ACL policy granting what configure needs to apply the external configs,
+// and nothing else: the policy of the configurer token. Set license if configure manages the license.
+func ConfigurerPolicy(configs []map[string]interface{}, license bool) (string, error) {
+	paths := map[string][]string{}
+	for _, config := range configs {
+		loadedConfig, err := (&vault{externalConfig: &ExternalConfig{}}).loadExternalConfig(config)
+		if err != nil {
+			return "", err
+		}
+		grantConfigurerPaths(paths, loadedConfig, license)
+	}
+
+	return formatPolicy(paths), nil
+}
+
+// configurerPolicy returns the policy granting what configure needs to apply the config, and nothing else.
+func configurerPolicy(config *ExternalConfig, license bool) string {
+	paths := map[string][]string{}
+	grantConfigurerPaths(paths, config, license)
+
+	return formatPolicy(paths)
+}
+
+// grantConfigurerPaths adds the capabilities configure needs to apply the config to paths.
+func grantConfigurerPaths(paths map[string][]string, config *ExternalConfig, license bool) {
+	grant := func(path string, capabilities []string) {
+		for _, capability := range capabilities {
+			if !slices.Contains(paths[path], capability) {
+				paths[path] = append(paths[path], capability)
+			}
+		}
+	}
+	grant("auth/token/lookup-self", []string{"read"})
+	grant("auth/token/renew-self", []string{"update"})
+	grant("sys/mounts", []string{"read"})
+	grant("sys/auth", []string{"read"})
+	grant("sys/policies/acl", []string{"list"})
+	grant("sys/audit", []string{"read", "sudo"})
+	grant("sys/plugins/catalog", []string{"read"})
+
+	purges := func(excluded bool) bool {
+		return config.PurgeUnmanagedConfig.Enabled && !excluded
+	}
+	exclude := config.PurgeUnmanagedConfig.Exclude
+
+	if license {
+		grant("sys/license", []string{"read", "update"})
+		grant("sys/license/status", []string{"read"})
+	}
+
+	for _, audit := range config.Audit {
+		grant("sys/audit/"+mountPath(audit.Path, audit.Type), capabilitiesMount)
+	}
+	if purges(exclude.Audit) {
+		grant("sys/audit/*", capabilitiesMount)
+	}
+
+	for _, plugin := range config.Plugins {
+		grant(fmt.Sprintf("sys/plugins/catalog/%s/%s", plugin.Type, plugin.Name), capabilitiesMount)
+	}
+	if purges(exclude.Plugins) {
+		grant("sys/plugins/catalog/*", capabilitiesMount)
+	}
+
+	for _, auth := range config.Auth {
+		path := mountPath(auth.Path, auth.Type)
+		grant("sys/auth/"+path, capabilitiesMount)
+		grant("sys/auth/"+path+"/tune", []string{"read", "update", "sudo"})
+		grant("auth/"+path+"/*", capabilitiesCRUD)
+	}
+	if purges(exclude.Auth) {
+		grant("sys/auth/*", capabilitiesMount)
+	}
+
+	if len(config.Groups) > 0 || len(config.GroupAliases) > 0 || config.DefaultGroup.Enabled {
+		grant("identity/*", capabilitiesCRUD)
+	}
+	if purges(exclude.Groups) || purges(exclude.GroupAliases) {
+		grant("identity/*", capabilitiesCRUD)
+	}
+
+	for _, policy := range config.Policies {
+		grant("sys/policies/acl/"+policy.Name, capabilitiesCRUD)
+	}
+	if purges(exclude.Policies) {
+		grant("sys/policies/acl/*", capabilitiesCRUD)
+	}
+
+	for _, secretEngine := range config.Secrets {
+		path := mountPath(secretEngine.Path, secretEngine.Type)
+		grant("sys/mounts/"+path, capabilitiesMount)
+		grant("sys/mounts/"+path+"/tune", []string{"read", "update"})
+		grant(path+"/*", capabilitiesCRUD)
+	}
+	if purges(exclude.Secrets) {
+		grant("sys/mounts/*", capabilitiesMount)
+	}
+
+	for _, startupSecret := range config.StartupSecrets {
+		if startupSecret.Source != nil {
+			grant(strings.Trim(startupSecret.Path, "/")+"/*", []string{"create", "read", "update"})
+		}
+		grant(strings.Trim(startupSecret.Path, "/"), []string{"create", "read", "update"})
+	}
+}
+
+func formatPolicy(paths map[string][]string) string {
+	var policy strings.Builder
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		capabilities := make([]string, 0, len(paths[path]))
+		for _, capability := range paths[path] {
+			capabilities = append(capabilities, fmt.Sprintf("%q", capability))
+		}
+		fmt.Fprintf(&policy, "path %q {\n  capabilities = [%s]\n}\n\n", path, strings.Join(capabilities, ", "))
+	}
+
+	return policy.String()
+}
+
+func mountPath(path, mountType string) string {
+	if path == "" {
+		path = mountType
+	}
+
+	return strings.Trim(path, "/")
+}
+
+func policyDigest(policy string) string {
+	digest := sha256.Sum256([]byte(policy))
+	return hex.EncodeToString(digest[:])
+}
+
+// configurerLogin sets the configurer token on the client. The token is minted with the root token
+// the first time, and minted again whenever the config needs a different policy.
+func (v *vault) configurerLogin(ctx context.Context, config *ExternalConfig) error {
+	policy := configurerPolicy(config, v.config.License != "" || v.config.LicenseKVKey != "")
+	digest := policyDigest(policy)
+
+	storedDigest, err := v.keyStore.Get(ctx, keyConfigurerPolicyDigest)
+	if err != nil && !isNotFoundError(err) {
+		return errors.Wrapf(err, "unable to get key '%s'", keyConfigurerPolicyDigest)
+	}
+
+	if string(storedDigest) == digest {
+		token, err := v.keyStore.Get(ctx, keyConfigurerToken)
+		if err != nil && !isNotFoundError(err) {
+			return errors.Wrapf(err, "unable to get key '%s'", keyConfigurerToken)
+		}
+		if len(token) > 0 {
+			v.cl.SetToken(string(token))
+			secmem.Wipe(token)
+
+			// The token expires if configure doesn't run for a whole period
+			_, err := v.cl.Auth().Token().LookupSelfWithContext(ctx)
+			if err == nil {
+				return nil
+			}
+			v.log().Warn("stored configurer token is invalid", "error", err)
+		}
+	}
+
+	v.log().Info("minting configurer token", "policy", ConfigurerPolicyName)
+
+	if err := v.login(ctx); err != nil {
+		return err
+	}
+	defer v.revokeRootToken(ctx, v.cl.Token())
+
+	return v.mintConfigurerToken(ctx, policy, digest)
+}
+
+// mintConfigurerToken writes the configurer policy and replaces the stored configurer token
+// with a new one, then logs in with it. The client must be logged in with the root token.
+func (v *vault) mintConfigurerToken(ctx context.Context, policy, digest string) error {
+	if err := v.cl.Sys().PutPolicyWithContext(ctx, ConfigurerPolicyName, policy); err != nil {
+		return errors.Wrap(err, "error writing configurer policy")
+	}
+
+	secret, err := v.cl.Auth().Token().CreateOrphanWithContext(ctx, &api.TokenCreateRequest{
+		Policies:        []string{ConfigurerPolicyName},
+		DisplayName:     ConfigurerPolicyName,
+		Period:          configurerTokenPeriod.String(),
+		NoDefaultPolicy: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error creating configurer token")
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return errors.New("no token in configurer token creation response")
+	}
+	token := secret.Auth.ClientToken
+
+	previousToken, err := v.keyStore.Get(ctx, keyConfigurerToken)
+	if err != nil && !isNotFoundError(err) {
+		return errors.Wrapf(err, "unable to get key '%s'", keyConfigurerToken)
+	}
+	defer secmem.Wipe(previousToken)
+
+	// The new token is lost if storing it is aborted by a shutdown
+	ctx = context.WithoutCancel(ctx)
+
+	tokenData := []byte(token)
+	err = v.keyStore.Set(ctx, keyConfigurerToken, tokenData)
+	secmem.Wipe(tokenData)
+	if err != nil {
+		if rErr := v.cl.Auth().Token().RevokeOrphanWithContext(ctx, token); rErr != nil {
+			v.log().Warn("error revoking unstored configurer token", "error", rErr)
+		}
+
+		return errors.Wrapf(err, "error storing configurer token in key '%s'", keyConfigurerToken)
+	}
+	if err := v.keyStore.Set(ctx, keyConfigurerPolicyDigest, []byte(digest)); err != nil {
+		return errors.Wrapf(err, "error storing configurer policy digest in key '%s'", keyConfigurerPolicyDigest)
+	}
+
+	if len(previousToken) > 0 {
+		if err := v.cl.Auth().Token().RevokeOrphanWithContext(ctx, string(previousToken)); err != nil {
+			v.log().Warn("error revoking previous configurer token", "error", err)
+		}
+	}
+
+	v.cl.SetToken(token)
+	v.log().Info("minted configurer token", "policy", ConfigurerPolicyName)
+
+	return nil
+}
+
+// initConfigurerToken mints the configurer token right after init, with the policy of an empty config,
+// if Vault is already unsealed (auto-unseal). Otherwise it is minted by the first configure run.
+func (v *vault) initConfigurerToken(ctx context.Context, rootToken string) error {
+	if sealed, err := v.Sealed(); err != nil || sealed {
+		v.log().Info("vault is sealed, the configurer token is minted by the first configure run")
+		return nil
+	}
+
+	v.cl.SetToken(rootToken)
+	defer v.cl.SetToken("")
+
+	policy := configurerPolicy(&ExternalConfig{}, v.config.License != "" || v.config.LicenseKVKey != "")
+
+	return v.mintConfigurerToken(ctx, policy, policyDigest(policy))
+}
+
+// usesRootToken tells whether configure logs in with the root token, which the configurer token replaces.
+func (v *vault) usesRootToken() bool {
+	return v.config.Token == "" &&
+		v.config.TokenFile == "" &&
+		v.config.KubernetesAuth.Role == "" &&
+		v.config.AppRoleAuth.Credentials == nil &&
+		!v.config.CertAuth.Enabled
+}
@@ -0,0 +1,131 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurerPolicy(t *testing.T) {
+	config := &ExternalConfig{
+		Auth:     []AuthMethod{{Type: "kubernetes"}},
+		Secrets:  []SecretsEngine{{Type: "kv", Path: "/team-a/"}},
+		Policies: []Policy{{Name: "allow_secrets"}},
+	}
+
+	policy := configurerPolicy(config, false)
+	assert.Contains(t, policy, "path \"sys/auth/kubernetes\" {\n  capabilities = [\"create\", \"read\", \"update\", \"delete\", \"sudo\"]\n}")
+	assert.Contains(t, policy, `path "auth/kubernetes/*"`)
+	assert.Contains(t, policy, `path "sys/mounts/team-a"`)
+	assert.Contains(t, policy, `path "team-a/*"`)
+	assert.Contains(t, policy, `path "sys/policies/acl/allow_secrets"`)
+	assert.NotContains(t, policy, `path "sys/mounts/*"`)
+	assert.NotContains(t, policy, `path "sys/license"`)
+	assert.Equal(t, policy, configurerPolicy(config, false), "the policy should be stable")
+
+	config.PurgeUnmanagedConfig.Enabled = true
+	config.PurgeUnmanagedConfig.Exclude.Auth = true
+	policy = configurerPolicy(config, true)
+	assert.Contains(t, policy, `path "sys/mounts/*"`)
+	assert.NotContains(t, policy, `path "sys/auth/*"`)
+	assert.Contains(t, policy, `path "sys/license"`)
+}
+
+// newConfigurerTokenVault returns a vault talking to a fake Vault minting configurer tokens, and the
+// tokens of the requests it received by path.
+func newConfigurerTokenVault(t *testing.T, store *memKV) (*vault, map[string][]string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	requests := map[string][]string{}
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.Path] = append(requests[r.URL.Path], r.Header.Get("X-Vault-Token"))
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/auth/token/create-orphan":
+			w.Write([]byte(`{"auth":{"client_token":"configurer-2"}}`)) //nolint:errcheck
+		case "/v1/auth/token/lookup-self":
+			if r.Header.Get("X-Vault-Token") == "expired" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	v.keyStore = store
+	v.config = &Config{StoreRootToken: true, ConfigurerToken: true}
+
+	return v, requests
+}
+
+func TestConfigurerLoginMints(t *testing.T) {
+	ctx := context.Background()
+	store := &memKV{}
+	require.NoError(t, store.Set(ctx, keyRootToken, []byte("root")))
+	require.NoError(t, store.Set(ctx, keyConfigurerToken, []byte("configurer-1")))
+	require.NoError(t, store.Set(ctx, keyConfigurerPolicyDigest, []byte("outdated")))
+
+	v, requests := newConfigurerTokenVault(t, store)
+
+	require.NoError(t, v.configurerLogin(ctx, &ExternalConfig{Auth: []AuthMethod{{Type: "kubernetes"}}}))
+	assert.Equal(t, "configurer-2", v.cl.Token())
+	assert.Equal(t, []string{"root"}, requests["/v1/sys/policies/acl/"+ConfigurerPolicyName])
+	assert.Equal(t, []string{"root"}, requests["/v1/auth/token/create-orphan"])
+	assert.Equal(t, []string{"root"}, requests["/v1/auth/token/revoke-orphan"], "the previous token should be revoked")
+
+	token, err := store.Get(ctx, keyConfigurerToken)
+	require.NoError(t, err)
+	assert.Equal(t, "configurer-2", string(token))
+}
+
+func TestConfigurerLoginReusesToken(t *testing.T) {
+	ctx := context.Background()
+	config := &ExternalConfig{Auth: []AuthMethod{{Type: "kubernetes"}}}
+	store := &memKV{}
+	require.NoError(t, store.Set(ctx, keyConfigurerToken, []byte("configurer-1")))
+	require.NoError(t, store.Set(ctx, keyConfigurerPolicyDigest, []byte(policyDigest(configurerPolicy(config, false)))))
+
+	v, requests := newConfigurerTokenVault(t, store)
+
+	require.NoError(t, v.configurerLogin(ctx, config))
+	assert.Equal(t, "configurer-1", v.cl.Token())
+	assert.Empty(t, requests["/v1/auth/token/create-orphan"])
+}
+
+func TestConfigurerLoginReplacesExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	config := &ExternalConfig{}
+	store := &memKV{}
+	require.NoError(t, store.Set(ctx, keyRootToken, []byte("root")))
+	require.NoError(t, store.Set(ctx, keyConfigurerToken, []byte("expired")))
+	require.NoError(t, store.Set(ctx, keyConfigurerPolicyDigest, []byte(policyDigest(configurerPolicy(config, false)))))
+
+	v, requests := newConfigurerTokenVault(t, store)
+
+	require.NoError(t, v.configurerLogin(ctx, config))
+	assert.Equal(t, "configurer-2", v.cl.Token())
+	assert.Equal(t, []string{"root"}, requests["/v1/auth/token/create-orphan"])
+}
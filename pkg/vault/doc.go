@@ -0,0 +1,51 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package vault is the engine behind the init, unseal and configure commands of bank-vaults,
+// for controllers which manage Vault themselves instead of running the CLI.
+//
+// New wraps a Vault API client and a key store holding the unseal keys, typically a kv backend
+// of the kv package, into a Vault. Its Config has the settings of the command line flags, the
+// zero value of each field being the default of the flag:
+//
+//	v, err := vault.New(ctx, store, client, vault.Config{
+//		SecretShares:    5,
+//		SecretThreshold: 3,
+//		ContinueOnError: true,
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	defer v.Close()
+//
+// Configure takes the external config as a map, in the layout of the YAML config of the configure
+// command: the auth, secrets, policies, groups, group-aliases, audit, plugins and startupSecrets
+// sections, and purgeUnmanagedConfig to remove the resources they no longer list. ValidateConfig
+// checks such a config without a Vault, Verify reports its drift from Vault and Report tells what
+// the last run applied, skipped, purged and failed:
+//
+//	if err := v.Configure(ctx, config); err != nil && !vault.IsPartialFailure(err) {
+//		return err
+//	}
+//	for section, report := range v.Report().Sections {
+//		for path, failure := range report.Failed {
+//			log.Printf("%s %s: %s", section, path, failure)
+//		}
+//	}
+//
+// ParseConfig decodes such a config into an ExternalConfig, whose section types (AuthMethod, SecretsEngine,
+// Policy, Group and the rest) document the fields of each section.
+//
+// RegisterReconciler adds custom sections to the external config, configured by a Reconciler.
+package vault
@@ -0,0 +1,83 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+)
+
+// Categories of the errors of configure runs, so auth problems can be told apart from availability problems.
+const (
+	ErrorPermissionDenied    = "permission_denied"
+	ErrorSealed              = "sealed"
+	ErrorConnection          = "connection"
+	ErrorServer              = "server_error"
+	ErrorOverwriteProhibited = "overwrite_prohibited"
+	ErrorClient              = "client_error"
+	ErrorOther               = "other"
+)
+
+// failedItemsError is the error of a run with items which failed in continue-on-error mode.
+type failedItemsError []string
+
+func (e failedItemsError) Error() string {
+	return fmt.Sprintf("%d config items failed:\n%s", len(e), strings.Join(e, "\n"))
+}
+
+// IsPartialFailure tells whether a configure run only failed because of the config items skipped
+// in continue-on-error mode, the rest of the config was applied.
+func IsPartialFailure(err error) bool {
+	var failedItems failedItemsError
+
+	return errors.As(err, &failedItems)
+}
+
+// errorCategory classifies an error of a Vault API call.
+func errorCategory(err error) string {
+	if isOverwriteProhibitedError(err) {
+		return ErrorOverwriteProhibited
+	}
+
+	var respErr *api.ResponseError
+	if errors.As(err, &respErr) {
+		switch {
+		case respErr.StatusCode == http.StatusForbidden:
+			return ErrorPermissionDenied
+		case respErr.StatusCode == http.StatusServiceUnavailable && strings.Contains(err.Error(), "Vault is sealed"):
+			return ErrorSealed
+		case respErr.StatusCode >= http.StatusInternalServerError:
+			return ErrorServer
+		case respErr.StatusCode >= http.StatusBadRequest:
+			return ErrorClient
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorConnection
+	}
+
+	if strings.Contains(err.Error(), "permission denied") {
+		return ErrorPermissionDenied
+	}
+
+	return ErrorOther
+}
@@ -0,0 +1,83 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	emperror "emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCategory(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		category string
+	}{
+		"permission denied": {
+			err:      &api.ResponseError{StatusCode: 403, Errors: []string{"permission denied"}},
+			category: ErrorPermissionDenied,
+		},
+		"sealed": {
+			err:      &api.ResponseError{StatusCode: 503, Errors: []string{"Vault is sealed"}},
+			category: ErrorSealed,
+		},
+		"server error": {
+			err:      emperror.Wrap(&api.ResponseError{StatusCode: 500}, "error mounting secret engine"),
+			category: ErrorServer,
+		},
+		"client error": {
+			err:      &api.ResponseError{StatusCode: 400, Errors: []string{"invalid options"}},
+			category: ErrorClient,
+		},
+		"connection refused": {
+			err:      emperror.Wrap(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, "error listing mounts"),
+			category: ErrorConnection,
+		},
+		"overwrite prohibited": {
+			err:      errors.New("existing roles, delete them before reconfiguring"),
+			category: ErrorOverwriteProhibited,
+		},
+		"other": {
+			err:      errors.New("boom"),
+			category: ErrorOther,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.category, errorCategory(test.err))
+		})
+	}
+}
+
+func TestIsPartialFailure(t *testing.T) {
+	assert.True(t, IsPartialFailure(emperror.Wrap(failedItemsError{"secrets kv: boom"}, "vault target eu")))
+	assert.False(t, IsPartialFailure(errors.New("boom")))
+	assert.False(t, IsPartialFailure(nil))
+}
+
+func TestReportErrorCategories(t *testing.T) {
+	r := newReport()
+
+	r.failed(SectionAuth, "kubernetes", &api.ResponseError{StatusCode: 403})
+	r.failed(SectionSecrets, "kv", &api.ResponseError{StatusCode: 403})
+	r.finish(failedItemsError(r.failures()))
+
+	assert.Equal(t, map[string]int{ErrorPermissionDenied: 2}, r.ErrorCategories)
+}
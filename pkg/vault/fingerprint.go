@@ -0,0 +1,218 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+)
+
+// keyConfigFingerprint is the key store entry holding the fingerprint of the last successfully applied config
+const keyConfigFingerprint = "vault-config-fingerprint"
+
+// normalizeConfig converts the nested `map[interface{}]interface{}` values some parsers produce
+// into `map[string]interface{}` so the config can be marshaled into a stable JSON form.
+func normalizeConfig(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			normalized[fmt.Sprint(k)] = normalizeConfig(v)
+		}
+		return normalized
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			normalized[k] = normalizeConfig(v)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(value))
+		for i, v := range value {
+			normalized[i] = normalizeConfig(v)
+		}
+		return normalized
+	default:
+		return value
+	}
+}
+
+// canonicalJSON marshals a config value into its stable JSON form.
+func canonicalJSON(value interface{}) ([]byte, error) {
+	// encoding/json sorts map keys, which makes the output deterministic
+	return json.Marshal(normalizeConfig(value))
+}
+
+// configHash returns a stable hash of the rendered external config.
+func configHash(config map[string]interface{}) (string, error) {
+	data, err := canonicalJSON(config)
+	if err != nil {
+		return "", errors.Wrap(err, "error marshaling config for hashing")
+	}
+
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// configFingerprint returns a stable hash of the rendered external config, the digest of the secret
+// inputs of the run and the current mount table (secret engines and auth methods), so rotated Secrets,
+// license changes and out-of-band changes to Vault mounts also invalidate the fingerprint.
+func configFingerprint(config map[string]interface{}, secretsDigest string, mounts, auths map[string]*api.MountOutput) (string, error) {
+	data, err := canonicalJSON(config)
+	if err != nil {
+		return "", errors.Wrap(err, "error marshaling config for fingerprinting")
+	}
+
+	h := sha256.New()
+	h.Write(data)
+	fmt.Fprintf(h, "\x00%s", secretsDigest)
+
+	for _, table := range []map[string]*api.MountOutput{mounts, auths} {
+		for _, path := range slices.Sorted(maps.Keys(table)) {
+			fmt.Fprintf(h, "\x00%s\x00%s\x00%s", path, table[path].Type, table[path].Accessor)
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// secretKeyRefs returns the Kubernetes Secret references anywhere in the config.
+func secretKeyRefs(value interface{}) ([]secretKeyRef, error) {
+	ref, ok, err := asSecretKeyRef(value)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return []secretKeyRef{ref}, nil
+	}
+
+	var refs []secretKeyRef
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, field := range slices.Sorted(maps.Keys(value)) {
+			nested, err := secretKeyRefs(value[field])
+			if err != nil {
+				return nil, errors.Wrapf(err, "error parsing field %s", field)
+			}
+			refs = append(refs, nested...)
+		}
+	case []interface{}:
+		for _, item := range value {
+			nested, err := secretKeyRefs(item)
+			if err != nil {
+				return nil, err
+			}
+			refs = append(refs, nested...)
+		}
+	}
+
+	return refs, nil
+}
+
+// secretInputsDigest returns the keyed digest of the inputs of a configure run which are not part of the
+// config: the values of the Kubernetes Secrets it references and the license, or an empty string if there are none.
+func (v *vault) secretInputsDigest(ctx context.Context, config map[string]interface{}) (string, error) {
+	refs, err := secretKeyRefs(normalizeConfig(config))
+	if err != nil {
+		return "", err
+	}
+
+	var license string
+	if v.config != nil {
+		if license, err = v.license(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	if len(refs) == 0 && license == "" {
+		return "", nil
+	}
+
+	if len(refs) > 0 && (v.config == nil || v.config.SecretResolver == nil) {
+		return "", errors.Errorf("config references secret %s, but no secret resolver is configured", refs[0].Name)
+	}
+
+	values := make([]string, 0, 4*len(refs)+1)
+	values = append(values, license)
+	for _, ref := range refs {
+		value, err := v.config.SecretResolver.SecretValue(ctx, ref.Namespace, ref.Name, ref.Key)
+		if err != nil {
+			return "", errors.Wrapf(err, "error resolving secret %s", ref.Name)
+		}
+		values = append(values, ref.Namespace, ref.Name, ref.Key, value)
+	}
+
+	return v.secretDigest(ctx, values...)
+}
+
+// currentConfigFingerprint computes the fingerprint of the given config against the live mount table.
+func (v *vault) currentConfigFingerprint(ctx context.Context, config map[string]interface{}) (string, error) {
+	secretsDigest, err := v.secretInputsDigest(ctx, config)
+	if err != nil {
+		return "", errors.Wrap(err, "error resolving secret inputs for fingerprinting")
+	}
+
+	mounts, err := v.listMounts()
+	if err != nil {
+		return "", errors.Wrap(err, "error listing mounts for fingerprinting")
+	}
+
+	auths, err := v.listAuth()
+	if err != nil {
+		return "", errors.Wrap(err, "error listing auth methods for fingerprinting")
+	}
+
+	return configFingerprint(config, secretsDigest, mounts, auths)
+}
+
+// configUnchanged reports whether the given fingerprint matches the one stored after the last successful apply.
+// Only the last one counts: reverting to an earlier config has to be applied again.
+func (v *vault) configUnchanged(ctx context.Context, fingerprint string) (bool, error) {
+	stored, err := v.keyStore.Get(ctx, v.stateKey(keyConfigFingerprint))
+	if err != nil {
+		if isNotFoundError(err) {
+			return false, nil
+		}
+
+		return false, errors.Wrapf(err, "unable to get key '%s'", v.stateKey(keyConfigFingerprint))
+	}
+
+	return strings.TrimSpace(string(stored)) == fingerprint, nil
+}
+
+// storeConfigFingerprint persists the fingerprint of the applied config, computed against the mount table
+// as it looks after the apply, so the next identical run can be skipped.
+func (v *vault) storeConfigFingerprint(ctx context.Context, config map[string]interface{}) error {
+	fingerprint, err := v.currentConfigFingerprint(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	if err := v.keyStore.Set(ctx, v.stateKey(keyConfigFingerprint), []byte(fingerprint)); err != nil {
+		return errors.Wrapf(err, "error storing key '%s'", v.stateKey(keyConfigFingerprint))
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bank-vaults/bank-vaults/pkg/kv"
+)
+
+// memKV is an in-memory key store.
+type memKV struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func (m *memKV) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	value, ok := m.data[key]
+	if !ok {
+		return nil, kv.NewNotFoundError("key not found: %s", key)
+	}
+
+	return bytes.Clone(value), nil
+}
+
+func (m *memKV) Set(_ context.Context, key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.data == nil {
+		m.data = map[string][]byte{}
+	}
+	m.data[key] = bytes.Clone(value)
+
+	return nil
+}
+
+// newMountsVault returns a vault talking to a fake server with an empty mount table.
+func newMountsVault(t *testing.T, config *Config) *vault {
+	t.Helper()
+
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+	}))
+	v.config = config
+
+	return v
+}
+
+func TestConfigUnchangedOnlyMatchesLastApply(t *testing.T) {
+	ctx := context.Background()
+	v := newMountsVault(t, &Config{})
+
+	configA := map[string]interface{}{"policies": []interface{}{map[string]interface{}{"name": "a"}}}
+	configB := map[string]interface{}{"policies": []interface{}{map[string]interface{}{"name": "b"}}}
+
+	fingerprintA, err := v.currentConfigFingerprint(ctx, configA)
+	require.NoError(t, err)
+
+	require.NoError(t, v.storeConfigFingerprint(ctx, configA))
+	unchanged, err := v.configUnchanged(ctx, fingerprintA)
+	require.NoError(t, err)
+	assert.True(t, unchanged)
+
+	// Reverting from B to A has to be applied again
+	require.NoError(t, v.storeConfigFingerprint(ctx, configB))
+	unchanged, err = v.configUnchanged(ctx, fingerprintA)
+	require.NoError(t, err)
+	assert.False(t, unchanged)
+}
+
+func TestConfigFingerprintCoversSecretRefs(t *testing.T) {
+	ctx := context.Background()
+	resolver := staticSecretResolver{"/mysql-root/password": "s3cr3t"}
+	v := newMountsVault(t, &Config{SecretResolver: resolver})
+
+	config := map[string]interface{}{
+		"secrets": []interface{}{map[string]interface{}{
+			"type": "database",
+			"configuration": map[string]interface{}{
+				"config": []interface{}{map[string]interface{}{
+					"name": "mysql",
+					"password": map[string]interface{}{
+						"secretKeyRef": map[string]interface{}{"name": "mysql-root", "key": "password"},
+					},
+				}},
+			},
+		}},
+	}
+
+	before, err := v.currentConfigFingerprint(ctx, config)
+	require.NoError(t, err)
+
+	again, err := v.currentConfigFingerprint(ctx, config)
+	require.NoError(t, err)
+	assert.Equal(t, before, again)
+
+	resolver["/mysql-root/password"] = "rotated"
+	after, err := v.currentConfigFingerprint(ctx, config)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+}
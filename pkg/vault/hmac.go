@@ -0,0 +1,71 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"emperror.dev/errors"
+)
+
+// keyHMAC is the key store entry holding the random key the digests of secret values are keyed with,
+// so the digests persisted by the configurer can't be used to guess low-entropy secrets offline.
+const keyHMAC = "vault-hmac-key"
+
+// hmacKey returns the HMAC key from the key store, generating it on first use.
+// It is shared by the targets sharing the key store, the first one generates it.
+func (v *vault) hmacKey(ctx context.Context) ([]byte, error) {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	key, err := v.keyStore.Get(ctx, keyHMAC)
+	if err == nil {
+		return key, nil
+	}
+	if !isNotFoundError(err) {
+		return nil, errors.Wrapf(err, "unable to get key '%s'", keyHMAC)
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Wrap(err, "error generating hmac key")
+	}
+
+	if err := v.keyStore.Set(ctx, keyHMAC, key); err != nil {
+		return nil, errors.Wrapf(err, "error storing key '%s'", keyHMAC)
+	}
+
+	return key, nil
+}
+
+// secretDigest returns the keyed digest of secret values.
+func (v *vault) secretDigest(ctx context.Context, values ...string) (string, error) {
+	key, err := v.hmacKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(sha256.New, key)
+	for _, value := range values {
+		mac.Write([]byte(value))
+		mac.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
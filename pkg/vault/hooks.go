@@ -0,0 +1,185 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Stages of the configure runs the hooks are run at.
+const (
+	HookStagePreApply  = "pre-apply"
+	HookStagePostApply = "post-apply"
+	HookStagePurge     = "purge"
+)
+
+// DefaultHookTimeout limits a hook run if Hooks has no timeout.
+const DefaultHookTimeout = 30 * time.Second
+
+// HookEvent is the input of a hook, it is sent as JSON.
+type HookEvent struct {
+	Stage string    `json:"stage"`
+	Time  time.Time `json:"time"`
+	// the resource removed, at the purge stage
+	Section string `json:"section,omitempty"`
+	Path    string `json:"path,omitempty"`
+	// the report of the run so far, the complete one at the post-apply stage
+	Report *Report `json:"report"`
+}
+
+// Hook integrates an external system with the configure runs, e.g. opens a change ticket or invalidates a cache.
+type Hook interface {
+	Run(ctx context.Context, event HookEvent) error
+}
+
+// Hooks are run before and after each configure run, and after each unmanaged resource it purges.
+// A failing pre-apply hook aborts the run, the errors of the others are only logged.
+type Hooks struct {
+	PreApply  []Hook
+	PostApply []Hook
+	Purge     []Hook
+	// time limit of each hook, DefaultHookTimeout if 0
+	Timeout time.Duration
+}
+
+type execHook struct {
+	command []string
+}
+
+// NewExecHook returns a Hook running the command with the event on its standard input.
+// The command fails the hook if it exits with a non-zero status.
+func NewExecHook(command ...string) Hook {
+	return &execHook{command: command}
+}
+
+func (h *execHook) Run(ctx context.Context, event HookEvent) error {
+	if len(h.command) == 0 {
+		return errors.New("hook command is empty")
+	}
+
+	input, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling hook event")
+	}
+
+	cmd := exec.CommandContext(ctx, h.command[0], h.command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "hook %s failed: %s", h.command[0], strings.TrimSpace(output.String()))
+	}
+
+	return nil
+}
+
+type httpHook struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPHook returns a Hook posting the event to the URL. A response with an error status fails the hook.
+func NewHTTPHook(url string, headers map[string]string) Hook {
+	return &httpHook{url: url, headers: headers, client: http.DefaultClient}
+}
+
+func (h *httpHook) Run(ctx context.Context, event HookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling hook event")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "error creating hook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range h.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "error calling hook %s", h.url)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.Errorf("hook %s returned unexpected status code: %d", h.url, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// stage returns the hooks of a stage.
+func (h Hooks) stage(stage string) []Hook {
+	switch stage {
+	case HookStagePreApply:
+		return h.PreApply
+	case HookStagePostApply:
+		return h.PostApply
+	case HookStagePurge:
+		return h.Purge
+	default:
+		return nil
+	}
+}
+
+// runHooks runs the hooks of the stage of the event one after the other, returning all their errors.
+func (v *vault) runHooks(ctx context.Context, event HookEvent) error {
+	if v.config == nil {
+		return nil
+	}
+	hooks := v.config.Hooks.stage(event.Stage)
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	timeout := v.config.Hooks.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	event.Time = time.Now()
+	if v.report != nil {
+		event.Report = v.report.snapshot()
+	}
+
+	var errs error
+	for _, hook := range hooks {
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+		errs = errors.Append(errs, hook.Run(hookCtx, event))
+		cancel()
+	}
+
+	return errs
+}
+
+// runLoggedHooks runs the hooks of a stage which can't fail the run, only logging their errors.
+func (v *vault) runLoggedHooks(ctx context.Context, event HookEvent) {
+	if err := v.runHooks(ctx, event); err != nil {
+		v.log().Error("error running hooks", "stage", event.Stage, "error", err)
+	}
+}
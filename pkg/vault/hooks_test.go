@@ -0,0 +1,135 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHook records the events it received, failing with its error.
+type recordingHook struct {
+	mu     sync.Mutex
+	events []HookEvent
+	err    error
+}
+
+func (h *recordingHook) Run(_ context.Context, event HookEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+
+	return h.err
+}
+
+func newHooksVault(t *testing.T, hooks Hooks) *vault {
+	t.Helper()
+
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	v.config = &Config{Token: "admin", Hooks: hooks}
+
+	return v
+}
+
+func TestConfigureRunsHooks(t *testing.T) {
+	pre, post := &recordingHook{}, &recordingHook{err: errors.New("post-apply hook failed")}
+	v := newHooksVault(t, Hooks{PreApply: []Hook{pre}, PostApply: []Hook{post}})
+
+	require.NoError(t, v.Configure(context.Background(), map[string]interface{}{}), "post-apply hooks don't fail the run")
+
+	require.Len(t, pre.events, 1)
+	assert.Equal(t, HookStagePreApply, pre.events[0].Stage)
+	require.Len(t, post.events, 1)
+	assert.Equal(t, HookStagePostApply, post.events[0].Stage)
+	assert.Equal(t, v.Report().ConfigHash, post.events[0].Report.ConfigHash)
+	assert.NotZero(t, post.events[0].Report.Duration, "post-apply hooks get the finished report")
+}
+
+func TestConfigureAbortedByPreApplyHook(t *testing.T) {
+	pre, post := &recordingHook{err: errors.New("change freeze")}, &recordingHook{}
+	v := newHooksVault(t, Hooks{PreApply: []Hook{pre}, PostApply: []Hook{post}})
+
+	err := v.Configure(context.Background(), map[string]interface{}{})
+	require.ErrorContains(t, err, "change freeze")
+	assert.Empty(t, post.events)
+	assert.Contains(t, v.Report().Error, "change freeze")
+}
+
+func TestPurgeRunsHooks(t *testing.T) {
+	purge := &recordingHook{}
+	v := newHooksVault(t, Hooks{Purge: []Hook{purge}})
+
+	v.resourcePurged(context.Background(), SectionPolicies, "old-policy")
+
+	require.Len(t, purge.events, 1)
+	assert.Equal(t, HookStagePurge, purge.events[0].Stage)
+	assert.Equal(t, SectionPolicies, purge.events[0].Section)
+	assert.Equal(t, "old-policy", purge.events[0].Path)
+}
+
+func TestHTTPHook(t *testing.T) {
+	var event map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		if event["stage"] == HookStagePreApply {
+			w.WriteHeader(http.StatusConflict)
+		}
+	}))
+	defer srv.Close()
+
+	hook := NewHTTPHook(srv.URL, map[string]string{"Authorization": "Bearer secret"})
+	report := newReport()
+	report.created(SectionPolicies, "admin")
+
+	require.NoError(t, hook.Run(context.Background(), HookEvent{Stage: HookStagePostApply, Report: report}))
+	assert.Equal(t, []interface{}{"admin"}, event["report"].(map[string]interface{})["sections"].(map[string]interface{})[SectionPolicies].(map[string]interface{})["created"])
+
+	assert.ErrorContains(t, hook.Run(context.Background(), HookEvent{Stage: HookStagePreApply, Report: report}), "409")
+}
+
+func TestExecHook(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "event.json")
+
+	hook := NewExecHook("sh", "-c", "cat > "+output)
+	require.NoError(t, hook.Run(context.Background(), HookEvent{Stage: HookStagePurge, Section: SectionSecrets, Path: "kv/"}))
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	var event HookEvent
+	require.NoError(t, json.Unmarshal(data, &event))
+	assert.Equal(t, HookStagePurge, event.Stage)
+	assert.Equal(t, "kv/", event.Path)
+
+	err = NewExecHook("sh", "-c", "echo no ticket; exit 1").Run(context.Background(), HookEvent{Stage: HookStagePreApply})
+	assert.ErrorContains(t, err, "no ticket")
+}
@@ -0,0 +1,517 @@
+// Copyright © 2022 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/spf13/cast"
+)
+
+// Group is an identity group of the groups section, internal or external.
+type Group struct {
+	Name     string                 `mapstructure:"name"`
+	Type     string                 `mapstructure:"type"`
+	Policies []string               `mapstructure:"policies"`
+	Metadata map[string]interface{} `mapstructure:"metadata"`
+}
+
+// DefaultGroup is an internal group containing every entity of the managed auth methods,
+// used to attach a common set of policies to all of them.
+type DefaultGroup struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Name     string   `mapstructure:"name"`
+	Policies []string `mapstructure:"policies"`
+}
+
+const defaultGroupName = "default-entities"
+
+func (g DefaultGroup) name() string {
+	if g.Name == "" {
+		return defaultGroupName
+	}
+
+	return g.Name
+}
+
+// GroupAlias is an alias of the group-aliases section, linking an external group to a group of an auth method.
+type GroupAlias struct {
+	Name      string `mapstructure:"name"`
+	MountPath string `mapstructure:"mountpath"`
+	Group     string `mapstructure:"group"`
+}
+
+//
+// Common functions.
+
+func readVaultGroup(group string, client *api.Client) (secret *api.Secret, err error) {
+	secret, err = client.Logical().Read(fmt.Sprintf("identity/group/name/%s", group))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read group %s by name", group)
+	}
+	if secret == nil {
+		// No Data returned, Group does not exist
+		return nil, nil
+	}
+
+	return secret, nil
+}
+
+func readVaultGroupAlias(id string, client *api.Client) (secret *api.Secret, err error) {
+	secret, err = client.Logical().Read(fmt.Sprintf("identity/group-alias/id/%s", id))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read group alias by id %s", id)
+	}
+	if secret == nil {
+		// No Data returned, Group does not exist
+		return nil, nil
+	}
+
+	return secret, nil
+}
+
+func (v *vault) authMountAccessor(path string) (accessor string, err error) {
+	path = strings.TrimRight(path, "/") + "/"
+	mounts, err := v.listAuth()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read auth mounts from vault")
+	}
+	if mounts[path] == nil {
+		return "", errors.Errorf("auth mount path %s does not exist in vault", path)
+	}
+
+	return mounts[path].Accessor, nil
+}
+
+func getVaultGroupID(group string, client *api.Client) (id string, err error) {
+	g, err := readVaultGroup(group, client)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading group %s", group)
+	}
+	if g == nil {
+		return "", errors.Errorf("group %s does not exist", group)
+	}
+
+	id, ok := g.Data["id"].(string)
+	if !ok || id == "" {
+		return "", errors.Errorf("group %s has no id", group)
+	}
+
+	return id, nil
+}
+
+func getVaultGroupAliasName(aliasID string, client *api.Client) (id string, err error) {
+	alias, err := readVaultGroupAlias(aliasID, client)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading group alias %s", aliasID)
+	}
+	if alias == nil {
+		return "", errors.Errorf("group alias %s does not exist", aliasID)
+	}
+
+	return alias.Data["name"].(string), nil
+}
+
+func getVaultGroupAliasMount(aliasID string, client *api.Client) (id string, err error) {
+	alias, err := readVaultGroupAlias(aliasID, client)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading group alias %s", aliasID)
+	}
+	if alias == nil {
+		return "", errors.Errorf("group alias %s does not exist", aliasID)
+	}
+
+	return alias.Data["mount_accessor"].(string), nil
+}
+
+func findVaultGroupAliasIDFromNameAndMount(name string, accessor string, client *api.Client) (id string, err error) {
+	aliases, err := client.Logical().List("identity/group-alias/id")
+	if err != nil {
+		return "", errors.Wrap(err, "error listing group aliases")
+	}
+	if aliases == nil {
+		return "", nil
+	}
+
+	for _, alias := range aliases.Data["keys"].([]interface{}) {
+		aliasName, err := getVaultGroupAliasName(cast.ToString(alias), client)
+		if err != nil {
+			return "", errors.Wrapf(err, "error fetching name for alias id: %s err", alias)
+		}
+
+		aliasMount, err := getVaultGroupAliasMount(cast.ToString(alias), client)
+		if err != nil {
+			return "", errors.Wrapf(err, "error fetching mount for alias id: %s err", alias)
+		}
+
+		if aliasName == name && aliasMount == accessor {
+			return cast.ToString(alias), nil
+		}
+	}
+
+	// Did not find any alias matching Name and MountPath
+	return "", nil
+}
+
+//
+// Groups.
+
+func (v *vault) getExistingGroups() (map[string]bool, error) {
+	existinGroups := make(map[string]bool)
+
+	existingGroupsList, err := v.cl.Logical().ReadWithData("identity/group/name", map[string][]string{"list": {"true"}})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve list of groups")
+	}
+
+	if existingGroupsList == nil {
+		v.log().Debug("vault has no groups", "section", SectionGroups)
+		return nil, nil
+	}
+
+	existingGroupsNames := cast.ToStringSlice(existingGroupsList.Data["keys"])
+	for _, existingGroupName := range existingGroupsNames {
+		existinGroups[existingGroupName] = true
+	}
+
+	return existinGroups, nil
+}
+
+func getUnmanagedGroups(existingGroups map[string]bool, managedGroups []Group) map[string]bool {
+	for _, managedGroup := range managedGroups {
+		delete(existingGroups, managedGroup.Name)
+	}
+
+	return existingGroups
+}
+
+func (v *vault) addManagedGroups(managedGroups []Group) error {
+	for _, group := range managedGroups {
+		if err := v.itemFailed(SectionGroups, group.Name, v.addManagedGroup(group)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *vault) addManagedGroup(group Group) error {
+	g, err := readVaultGroup(group.Name, v.cl)
+	if err != nil {
+		return errors.Wrap(err, "error reading group")
+	}
+
+	// Currently does not support specifying members directly in the group config
+	// Use group aliases for that
+	if group.Type != "external" {
+		return errors.Errorf("only external groups are supported for now")
+	}
+
+	config := map[string]interface{}{
+		"name":     group.Name,
+		"type":     group.Type,
+		"policies": group.Policies,
+		"metadata": group.Metadata,
+	}
+
+	if g == nil {
+		v.log().Info("adding group", "section", SectionGroups, "path", group.Name)
+		_, err = v.writeWithWarningCheck("identity/group", config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create group %s", group.Name)
+		}
+		v.report.created(SectionGroups, group.Name)
+	} else {
+		v.log().Info("tuning already existing group", "section", SectionGroups, "path", group.Name)
+		_, err = v.writeWithWarningCheck(fmt.Sprintf("identity/group/name/%s", group.Name), config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to tune group %s", group.Name)
+		}
+		v.report.updated(SectionGroups, group.Name)
+	}
+
+	return nil
+}
+
+func (v *vault) removeUnmanagedGroups(ctx context.Context, managedGroups []Group) error {
+	if !v.externalConfig.PurgeUnmanagedConfig.Enabled || v.externalConfig.PurgeUnmanagedConfig.Exclude.Groups {
+		v.log().Debug("purge config is disabled, no unmanaged groups will be removed", "section", SectionGroups)
+		return nil
+	}
+
+	existingGroups, err := v.getExistingGroups()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get existing groups from vault")
+	}
+
+	unmanagedGroups := getUnmanagedGroups(existingGroups, managedGroups)
+	for unmanagedGroupName := range unmanagedGroups {
+		v.log().Info("removing group", "section", SectionGroups, "path", unmanagedGroupName)
+		_, err := v.cl.Logical().Delete("identity/group/name/" + unmanagedGroupName)
+		if err != nil {
+			if err := v.itemFailed(SectionGroups, unmanagedGroupName, errors.Wrapf(err, "error removing group %s from vault", unmanagedGroupName)); err != nil {
+				return err
+			}
+
+			continue
+		}
+		v.resourcePurged(ctx, SectionGroups, unmanagedGroupName)
+	}
+
+	return nil
+}
+
+//
+// Group Aliases.
+
+func (v *vault) addManagedGroupAliases(managedGroupAliases []GroupAlias) error {
+	// Group Aliases for External Groups might require to have the same Name when on different Mount/Path combinations
+	// external groups can only have ONE alias so we need to make sure not to overwrite any
+	for _, groupAlias := range managedGroupAliases {
+		if err := v.itemFailed(SectionGroups, "alias/"+groupAlias.Name, v.addManagedGroupAlias(groupAlias)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *vault) addManagedGroupAlias(groupAlias GroupAlias) error {
+	accessor, err := v.authMountAccessor(groupAlias.MountPath)
+	if err != nil {
+		return errors.Wrapf(err, "error getting mount accessor for %s", groupAlias.MountPath)
+	}
+
+	id, err := getVaultGroupID(groupAlias.Group, v.cl)
+	if err != nil {
+		return errors.Wrapf(err, "error getting canonical_id for group %s", groupAlias.Group)
+	}
+
+	config := map[string]interface{}{
+		"name":           groupAlias.Name,
+		"mount_accessor": accessor,
+		"canonical_id":   id,
+	}
+
+	// Find a matching alias for NAME and MOUNT
+	ga, err := findVaultGroupAliasIDFromNameAndMount(groupAlias.Name, accessor, v.cl)
+	if err != nil {
+		return errors.Wrapf(err, "error finding group-alias %s", groupAlias.Name)
+	}
+
+	if ga == "" {
+		v.log().Info("adding group-alias", "section", SectionGroups, "path", groupAlias.Name, "accessor", accessor)
+		_, err = v.writeWithWarningCheck("identity/group-alias", config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create group-alias %s", groupAlias.Name)
+		}
+		v.report.created(SectionGroups, "alias/"+groupAlias.Name)
+	} else {
+		v.log().Info("tuning already existing group-alias", "section", SectionGroups, "path", groupAlias.Name, "accessor", accessor, "id", ga)
+		_, err = v.writeWithWarningCheck(fmt.Sprintf("identity/group-alias/id/%s", ga), config)
+		if err != nil {
+			return errors.Wrapf(err, "failed to tune group-alias %s", ga)
+		}
+		v.report.updated(SectionGroups, "alias/"+groupAlias.Name)
+	}
+
+	return nil
+}
+
+func (v *vault) getExistingGroupAliases() (map[string]string, error) {
+	existingGroupAliases := make(map[string]string)
+
+	existingGroupAliasesRaw, err := v.cl.Logical().ReadWithData(
+		"identity/group-alias/id", map[string][]string{"list": {"true"}})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve list of group-alias")
+	}
+
+	if existingGroupAliasesRaw == nil {
+		v.log().Debug("vault has no group-aliases", "section", SectionGroups)
+		return nil, nil
+	}
+
+	existingGroupAliasesData := cast.ToStringMap(existingGroupAliasesRaw.Data["key_info"])
+	for existingGroupAliasID, existingGroupAliasRaw := range existingGroupAliasesData {
+		existingGroupAlias := cast.ToStringMapString(existingGroupAliasRaw)
+		existingGroupAliases[existingGroupAlias["name"]] = existingGroupAliasID
+	}
+
+	return existingGroupAliases, nil
+}
+
+func getUnmanagedGroupAliases(existingGroupAliases map[string]string, managedGroupAliases []GroupAlias) map[string]string {
+	for _, managedGroupAlias := range managedGroupAliases {
+		delete(existingGroupAliases, managedGroupAlias.Name)
+	}
+
+	return existingGroupAliases
+}
+
+func (v *vault) removeUnmanagedGroupAliases(ctx context.Context, managedGroupAliases []GroupAlias) error {
+	if !v.externalConfig.PurgeUnmanagedConfig.Enabled || v.externalConfig.PurgeUnmanagedConfig.Exclude.GroupAliases {
+		v.log().Debug("purge config is disabled, no unmanaged group-alias will be removed", "section", SectionGroups)
+		return nil
+	}
+
+	existingGroupAliases, err := v.getExistingGroupAliases()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get existing group-alias from vault")
+	}
+	unmanagedGroupAliases := getUnmanagedGroupAliases(existingGroupAliases, managedGroupAliases)
+
+	v.log().Info("removing group-aliases", "section", SectionGroups, "count", len(unmanagedGroupAliases))
+	for unmanagedGroupAliasName, unmanagedGroupAliasID := range unmanagedGroupAliases {
+		_, err := v.cl.Logical().Delete("identity/group-alias/id/" + unmanagedGroupAliasID)
+		if err != nil {
+			err = errors.Wrapf(err, "error removing group-alias %s with ID %s from vault", unmanagedGroupAliasName, unmanagedGroupAliasID)
+			if err := v.itemFailed(SectionGroups, "alias/"+unmanagedGroupAliasName, err); err != nil {
+				return err
+			}
+
+			continue
+		}
+		v.resourcePurged(ctx, SectionGroups, "alias/"+unmanagedGroupAliasName)
+	}
+
+	return nil
+}
+
+//
+// Default group.
+
+// managedGroups returns the groups managed by the config, including the default group if it's enabled.
+func (v *vault) managedGroups() []Group {
+	managedGroups := slices.Clone(v.externalConfig.Groups)
+	if v.externalConfig.DefaultGroup.Enabled {
+		managedGroups = append(managedGroups, Group{
+			Name:     v.externalConfig.DefaultGroup.name(),
+			Type:     "internal",
+			Policies: v.externalConfig.DefaultGroup.Policies,
+		})
+	}
+
+	return managedGroups
+}
+
+// getManagedAuthEntityIDs returns the IDs of the entities having an alias on any of the managed auth methods.
+func (v *vault) getManagedAuthEntityIDs() ([]string, error) {
+	existingAuths, err := v.getExistingAuthMethods()
+	if err != nil {
+		return nil, err
+	}
+
+	managedAccessors := make(map[string]bool)
+	for _, authMethod := range initAuthConfig(v.externalConfig.Auth) {
+		if mount := existingAuths[strings.Trim(authMethod.Path, "/")]; mount != nil {
+			managedAccessors[mount.Accessor] = true
+		}
+	}
+
+	// The key info of the alias list holds the mount and entity of every alias, so a single request
+	// is enough instead of reading every entity
+	aliasList, err := v.cl.Logical().List("identity/entity-alias/id")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list entity aliases")
+	}
+	if aliasList == nil {
+		return nil, nil
+	}
+
+	entityIDs := make(map[string]bool)
+	for _, aliasInfo := range cast.ToStringMap(aliasList.Data["key_info"]) {
+		alias := cast.ToStringMap(aliasInfo)
+		if managedAccessors[cast.ToString(alias["mount_accessor"])] {
+			entityIDs[cast.ToString(alias["canonical_id"])] = true
+		}
+	}
+
+	return slices.Sorted(maps.Keys(entityIDs)), nil
+}
+
+func (v *vault) configureDefaultGroup() error {
+	defaultGroup := v.externalConfig.DefaultGroup
+	if !defaultGroup.Enabled {
+		return nil
+	}
+
+	entityIDs, err := v.getManagedAuthEntityIDs()
+	if err != nil {
+		return errors.Wrap(err, "error getting entities of managed auth methods")
+	}
+
+	g, err := readVaultGroup(defaultGroup.name(), v.cl)
+	if err != nil {
+		return errors.Wrap(err, "error reading group")
+	}
+
+	config := map[string]interface{}{
+		"name":              defaultGroup.name(),
+		"type":              "internal",
+		"policies":          defaultGroup.Policies,
+		"member_entity_ids": entityIDs,
+	}
+
+	v.log().Info("updating default group", "section", SectionGroups, "path", defaultGroup.name(), "entities", len(entityIDs))
+	_, err = v.writeWithWarningCheck(fmt.Sprintf("identity/group/name/%s", defaultGroup.name()), config)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write default group %s", defaultGroup.name())
+	}
+
+	if g == nil {
+		v.report.created(SectionGroups, defaultGroup.name())
+	} else {
+		v.report.updated(SectionGroups, defaultGroup.name())
+	}
+
+	return nil
+}
+
+//
+// Configure groups and group-aliases.
+
+func (v *vault) configureIdentityGroups(ctx context.Context) error {
+	managedGroups := v.externalConfig.Groups
+	managedGroupAliases := v.externalConfig.GroupAliases
+
+	if err := v.addManagedGroups(managedGroups); err != nil {
+		return errors.Wrap(err, "error while adding groups")
+	}
+
+	if err := v.addManagedGroupAliases(managedGroupAliases); err != nil {
+		return errors.Wrap(err, "error while adding groups aliases")
+	}
+
+	if err := v.itemFailed(SectionGroups, v.externalConfig.DefaultGroup.name(), v.configureDefaultGroup()); err != nil {
+		return errors.Wrap(err, "error while configuring default group")
+	}
+
+	if err := v.removeUnmanagedGroups(ctx, v.managedGroups()); err != nil {
+		return errors.Wrap(err, "error while removing groups")
+	}
+
+	if err := v.removeUnmanagedGroupAliases(ctx, managedGroupAliases); err != nil {
+		return errors.Wrap(err, "error while removing group aliases")
+	}
+
+	return nil
+}
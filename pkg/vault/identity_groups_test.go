@@ -0,0 +1,79 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigureDefaultGroup(t *testing.T) {
+	var written map[string]interface{}
+	var entityReads int
+
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var data interface{}
+		switch {
+		case r.URL.Path == "/v1/sys/auth":
+			data = map[string]interface{}{
+				"userpass/": map[string]interface{}{"type": "userpass", "accessor": "auth_userpass_1"},
+				"github/":   map[string]interface{}{"type": "github", "accessor": "auth_github_2"},
+			}
+		case r.URL.Path == "/v1/identity/entity-alias/id":
+			data = map[string]interface{}{
+				"keys": []string{"a-1", "a-2", "a-3", "a-4"},
+				"key_info": map[string]interface{}{
+					"a-1": map[string]interface{}{"canonical_id": "e-2", "mount_accessor": "auth_userpass_1"},
+					"a-2": map[string]interface{}{"canonical_id": "e-1", "mount_accessor": "auth_userpass_1"},
+					"a-3": map[string]interface{}{"canonical_id": "e-3", "mount_accessor": "auth_github_2"},
+					"a-4": map[string]interface{}{"canonical_id": "e-1", "mount_accessor": "auth_userpass_1"},
+				},
+			}
+		case strings.HasPrefix(r.URL.Path, "/v1/identity/entity/"):
+			entityReads++
+			http.NotFound(w, r)
+			return
+		case r.URL.Path == "/v1/identity/group/name/default-entities" && r.Method == http.MethodGet:
+			// Vault answers reads of missing paths without a body, a text body fails parsing the secret
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.URL.Path == "/v1/identity/group/name/default-entities":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&written))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data}) //nolint:errcheck
+	}))
+	v.externalConfig = &ExternalConfig{
+		Auth:         []AuthMethod{{Type: "userpass"}},
+		DefaultGroup: DefaultGroup{Enabled: true, Policies: []string{"default-entities"}},
+	}
+
+	require.NoError(t, v.configureDefaultGroup())
+	assert.Zero(t, entityReads, "entities are not read one by one")
+	assert.Equal(t, []interface{}{"e-1", "e-2"}, written["member_entity_ids"])
+	assert.Equal(t, "internal", written["type"])
+	assert.Equal(t, []string{defaultGroupName}, v.report.Sections[SectionGroups].Created)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"fmt"
+	"os"
+
+	"emperror.dev/errors"
+)
+
+// inClusterCACertFile is the CA certificate of the Kubernetes API server mounted into pods.
+const inClusterCACertFile = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+
+// completeKubernetesSecretsEngineConfig fills the defaults of the Kubernetes secrets engine configs in place:
+// the API server address and CA certificate of the cluster the configurer runs in, and the generated role
+// rules of roles, which can be given as structured YAML instead of a string.
+func completeKubernetesSecretsEngineConfig(configOption string, data map[string]interface{}) error {
+	switch configOption {
+	case "config":
+		host := os.Getenv("KUBERNETES_SERVICE_HOST")
+		if host == "" {
+			// Not running inside Kubernetes
+			return nil
+		}
+
+		if _, ok := data["kubernetes_host"]; !ok {
+			data["kubernetes_host"] = fmt.Sprint("https://", host)
+		}
+
+		if _, ok := data["kubernetes_ca_cert"]; !ok {
+			caCert, err := os.ReadFile(inClusterCACertFile)
+			if err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "error reading in-cluster CA certificate")
+			}
+			if err == nil {
+				data["kubernetes_ca_cert"] = string(caCert)
+			}
+		}
+
+	case "roles":
+		rules, ok := data["generated_role_rules"]
+		if !ok {
+			return nil
+		}
+		if _, ok := rules.(string); ok {
+			return nil
+		}
+
+		rulesJSON, err := canonicalJSON(rules)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling generated_role_rules")
+		}
+		data["generated_role_rules"] = string(rulesJSON)
+	}
+
+	return nil
+}
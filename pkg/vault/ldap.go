@@ -0,0 +1,66 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cast"
+
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+)
+
+// isLDAPStaticRole reports whether the secret engine config is a static role of the LDAP secrets engine.
+func isLDAPStaticRole(secretEngineType, configOption string) bool {
+	return (secretEngineType == "ldap" || secretEngineType == "openldap") && configOption == "static-role"
+}
+
+// rotateLDAPStaticRole rotates the password of an LDAP static role when its last rotation by Vault
+// is older than maxAge. It's only checked when the config is applied, so it's no schedule:
+// the periodic rotation is done by Vault itself based on the rotation_period of the role.
+func (v *vault) rotateLDAPStaticRole(ctx context.Context, path, name string, maxAge time.Duration) error {
+	credPath := fmt.Sprintf("%s/static-cred/%s", path, name)
+	cred, err := v.cl.Logical().ReadWithContext(ctx, credPath)
+	if err != nil {
+		return errors.Wrapf(err, "error reading %s", credPath)
+	}
+
+	if cred != nil && cred.Data != nil {
+		lastRotation, err := time.Parse(time.RFC3339Nano, cast.ToString(cred.Data["last_vault_rotation"]))
+		if err == nil && time.Since(lastRotation) < maxAge {
+			v.log().Debug("LDAP static role was rotated recently, not rotating it yet", "section", SectionSecrets, "path", credPath, "last_rotation", lastRotation)
+			return nil
+		}
+	}
+
+	rotatePath := fmt.Sprintf("%s/rotate-role/%s", path, name)
+	v.log().Info("rotating LDAP static role", "section", SectionSecrets, "path", rotatePath)
+	if _, err := v.writeWithWarningCheck(rotatePath, nil); err != nil {
+		return errors.Wrapf(err, "error rotating LDAP static role %s", name)
+	}
+	v.report.updated(SectionSecrets, rotatePath)
+
+	v.sendNotification(ctx, notify.Event{
+		Type:    notify.EventCredentialsRotated,
+		Message: fmt.Sprintf("rotated LDAP static role %s", name),
+		Section: SectionSecrets,
+		Path:    rotatePath,
+	})
+
+	return nil
+}
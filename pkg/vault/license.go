@@ -0,0 +1,147 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cast"
+)
+
+// license returns the Vault Enterprise license to apply, read from the key store if a key is configured.
+func (v *vault) license(ctx context.Context) (string, error) {
+	if v.config.LicenseKVKey == "" {
+		return strings.TrimSpace(v.config.License), nil
+	}
+
+	license, err := v.keyStore.Get(ctx, v.config.LicenseKVKey)
+	if err != nil {
+		return "", errors.Wrapf(err, "error reading license from key store key %s", v.config.LicenseKVKey)
+	}
+
+	return strings.TrimSpace(string(license)), nil
+}
+
+// keyLicenseDigest is the key store entry holding the digest of the license last written to Vault
+const keyLicenseDigest = "vault-license-digest"
+
+// configureLicense applies the configured Vault Enterprise license and records its expiration time.
+// Vault 1.11+ only autoloads its license, it is written with the legacy API of older versions only,
+// and only if it differs from the one written last.
+func (v *vault) configureLicense(ctx context.Context) error {
+	license, err := v.license(ctx)
+	if err != nil {
+		return v.itemFailed(SectionLicense, "sys/license", err)
+	}
+
+	if license == "" {
+		return nil
+	}
+
+	details, autoloaded, err := v.licenseStatus(ctx)
+	if err != nil {
+		return v.itemFailed(SectionLicense, "sys/license", err)
+	}
+
+	if autoloaded {
+		v.log().Warn("vault autoloads its enterprise license, the configured license is not applied")
+		return v.itemFailed(SectionLicense, "sys/license", v.recordLicenseExpiry(details))
+	}
+
+	digest, err := v.secretDigest(ctx, license)
+	if err != nil {
+		return v.itemFailed(SectionLicense, "sys/license", err)
+	}
+
+	applied, err := v.keyStore.Get(ctx, v.stateKey(keyLicenseDigest))
+	if err != nil && !isNotFoundError(err) {
+		return v.itemFailed(SectionLicense, "sys/license", errors.Wrapf(err, "unable to get key '%s'", v.stateKey(keyLicenseDigest)))
+	}
+
+	if string(applied) != digest {
+		v.log().Info("applying vault enterprise license")
+		if err := v.writeLicense(ctx, license, digest); err != nil {
+			return v.itemFailed(SectionLicense, "sys/license", err)
+		}
+		v.report.updated(SectionLicense, "sys/license")
+
+		if details, _, err = v.licenseStatus(ctx); err != nil {
+			return v.itemFailed(SectionLicense, "sys/license", err)
+		}
+	}
+
+	return v.itemFailed(SectionLicense, "sys/license", v.recordLicenseExpiry(details))
+}
+
+// licenseStatus returns the details of the license in use and whether Vault autoloads it.
+func (v *vault) licenseStatus(ctx context.Context) (map[string]interface{}, bool, error) {
+	status, err := v.cl.Logical().ReadWithContext(ctx, "sys/license/status")
+	if err != nil {
+		return nil, false, errors.Wrap(err, "error reading vault enterprise license status")
+	}
+	if status == nil {
+		return nil, false, nil
+	}
+
+	// Vault 1.8+ nests the details of the license in use under "autoloaded"
+	if autoloaded := cast.ToStringMap(status.Data["autoloaded"]); len(autoloaded) > 0 {
+		return autoloaded, true, nil
+	}
+
+	return status.Data, cast.ToBool(status.Data["autoloading_used"]), nil
+}
+
+// writeLicense writes the license with the legacy license API and remembers its digest.
+func (v *vault) writeLicense(ctx context.Context, license, digest string) error {
+	if _, err := v.cl.Logical().WriteWithContext(ctx, "sys/license", map[string]interface{}{"text": license}); err != nil {
+		return errors.Wrap(err, "error applying vault enterprise license")
+	}
+	v.recordWrite(AuditOperationWrite, "sys/license", map[string]interface{}{"text": license})
+
+	if err := v.keyStore.Set(ctx, v.stateKey(keyLicenseDigest), []byte(digest)); err != nil {
+		return errors.Wrapf(err, "error storing key '%s'", v.stateKey(keyLicenseDigest))
+	}
+
+	return nil
+}
+
+func (v *vault) recordLicenseExpiry(details map[string]interface{}) error {
+	expirationTime := cast.ToString(details["expiration_time"])
+	if expirationTime == "" {
+		return nil
+	}
+
+	expiry, err := time.Parse(time.RFC3339, expirationTime)
+	if err != nil {
+		return errors.Wrap(err, "error parsing vault enterprise license expiration time")
+	}
+
+	v.licenseExpiry.Store(expiry.Unix())
+
+	return nil
+}
+
+// LicenseExpiry returns the expiration time of the last applied license, or the zero time if none was applied.
+func (v *vault) LicenseExpiry() time.Time {
+	expiry := v.licenseExpiry.Load()
+	if expiry == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(expiry, 0)
+}
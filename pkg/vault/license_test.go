@@ -0,0 +1,81 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLicenseVault returns a vault talking to a fake license API answering with the given status,
+// and a counter of the license writes it received.
+func newLicenseVault(t *testing.T, status string, writeStatus int, config *Config) (*vault, *int) {
+	t.Helper()
+
+	writes := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/sys/license/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(status)) //nolint:errcheck
+	})
+	mux.HandleFunc("/v1/sys/license", func(w http.ResponseWriter, _ *http.Request) {
+		writes++
+		w.WriteHeader(writeStatus)
+	})
+	v := newTestVault(t, mux)
+	v.config = config
+
+	return v, &writes
+}
+
+func TestConfigureLicenseLegacyWritesOnlyChanges(t *testing.T) {
+	ctx := context.Background()
+	v, writes := newLicenseVault(t, `{"data":{"expiration_time":"2027-01-02T15:04:05Z"}}`, http.StatusNoContent, &Config{License: "license-1"})
+
+	require.NoError(t, v.configureLicense(ctx))
+	assert.Equal(t, 1, *writes)
+	assert.Equal(t, time.Date(2027, 1, 2, 15, 4, 5, 0, time.UTC), v.LicenseExpiry().UTC())
+
+	require.NoError(t, v.configureLicense(ctx))
+	assert.Equal(t, 1, *writes, "an unchanged license is not written again")
+
+	v.config.License = "license-2"
+	require.NoError(t, v.configureLicense(ctx))
+	assert.Equal(t, 2, *writes)
+}
+
+func TestConfigureLicenseAutoloaded(t *testing.T) {
+	v, writes := newLicenseVault(t, `{"data":{"autoloading_used":true,"autoloaded":{"expiration_time":"2027-01-02T15:04:05Z"}}}`, http.StatusNotFound, &Config{License: "license-1"})
+
+	require.NoError(t, v.configureLicense(context.Background()))
+	assert.Equal(t, 0, *writes)
+	assert.False(t, v.LicenseExpiry().IsZero())
+}
+
+func TestConfigureLicenseContinueOnError(t *testing.T) {
+	v, writes := newLicenseVault(t, `{"data":{}}`, http.StatusBadRequest, &Config{License: "license-1", ContinueOnError: true})
+
+	require.NoError(t, v.configureLicense(context.Background()))
+	assert.Equal(t, 1, *writes)
+	assert.Contains(t, v.report.Sections[SectionLicense].Failed, "sys/license")
+
+	v.config.ContinueOnError = false
+	assert.Error(t, v.configureLicense(context.Background()))
+}
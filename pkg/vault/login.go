@@ -0,0 +1,252 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cast"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+)
+
+// Defaults of the Kubernetes, AppRole and TLS certificate auth logins.
+const (
+	DefaultKubernetesAuthPath      = "kubernetes"
+	DefaultServiceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	DefaultAppRoleAuthPath         = "approle"
+	DefaultCertAuthPath            = "cert"
+)
+
+// credentialsLogin logs in with the token or the auth method of the config, it tells if either is set.
+func (v *vault) credentialsLogin(ctx context.Context) (bool, error) {
+	switch {
+	case v.config.Token != "":
+		v.cl.SetToken(v.config.Token)
+		return true, nil
+	case v.config.TokenFile != "":
+		return true, v.tokenFileLogin()
+	case v.config.KubernetesAuth.Role != "":
+		return true, v.kubernetesLogin(ctx)
+	case v.config.AppRoleAuth.Credentials != nil:
+		return true, v.appRoleLogin(ctx)
+	case v.config.CertAuth.Enabled:
+		return true, v.certLogin(ctx)
+	default:
+		return false, nil
+	}
+}
+
+// tokenFileLogin uses the token of the token file.
+func (v *vault) tokenFileLogin() error {
+	token, err := os.ReadFile(v.config.TokenFile)
+	if err != nil {
+		return errors.Wrap(err, "error reading token file")
+	}
+
+	token = bytes.TrimSpace(token)
+	if len(token) == 0 {
+		return errors.Errorf("token file %s is empty", v.config.TokenFile)
+	}
+
+	v.cl.SetToken(string(token))
+
+	return nil
+}
+
+// KubernetesAuth configures configure to log in with the Kubernetes auth method instead of the root token.
+type KubernetesAuth struct {
+	// Vault role to log in as, the login is disabled if empty
+	Role string
+	// mount path of the Kubernetes auth method, DefaultKubernetesAuthPath if empty
+	Path string
+	// projected service account token, DefaultServiceAccountTokenFile if empty
+	TokenFile string
+}
+
+// kubernetesLogin logs in with the service account token of the configurer.
+// The token file is read at every login, since projected tokens are rotated by the kubelet.
+func (v *vault) kubernetesLogin(ctx context.Context) error {
+	auth := v.config.KubernetesAuth
+
+	path := auth.Path
+	if path == "" {
+		path = DefaultKubernetesAuthPath
+	}
+	tokenFile := auth.TokenFile
+	if tokenFile == "" {
+		tokenFile = DefaultServiceAccountTokenFile
+	}
+
+	jwt, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return errors.Wrap(err, "error reading service account token")
+	}
+	defer secmem.Track(strings.TrimSpace(string(jwt)))()
+
+	loginPath := "auth/" + strings.Trim(path, "/") + "/login"
+	secret, err := v.cl.Logical().WriteWithContext(ctx, loginPath, map[string]interface{}{
+		"role": auth.Role,
+		"jwt":  strings.TrimSpace(string(jwt)),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "error logging in with kubernetes auth role %s", auth.Role)
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return errors.Errorf("no token in kubernetes auth login response of role %s", auth.Role)
+	}
+
+	v.log().Debug("logged in with kubernetes auth", "role", auth.Role, "path", path)
+	v.cl.SetToken(secret.Auth.ClientToken)
+
+	return nil
+}
+
+// AppRoleCredentials reads the role_id and secret_id configure logs in with, and stores the rotated secret_ids.
+type AppRoleCredentials interface {
+	Credentials(ctx context.Context) (roleID string, secretID string, err error)
+	StoreSecretID(ctx context.Context, secretID string) error
+}
+
+// AppRoleAuth configures configure to log in with the AppRole auth method instead of the root token.
+type AppRoleAuth struct {
+	// read at every login, so secret_ids rotated by others are picked up, the login is disabled if nil
+	Credentials AppRoleCredentials
+	// mount path of the AppRole auth method, DefaultAppRoleAuthPath if empty
+	Path string
+	// if set, a new secret_id of this role is generated after every login and stored
+	// with the credentials, the secret_id logged in with is destroyed afterwards
+	RotateSecretIDRole string
+}
+
+// appRoleLogin logs in with the role_id and secret_id of the configurer and rotates the secret_id if enabled.
+func (v *vault) appRoleLogin(ctx context.Context) error {
+	auth := v.config.AppRoleAuth
+
+	path := strings.Trim(auth.Path, "/")
+	if path == "" {
+		path = DefaultAppRoleAuthPath
+	}
+
+	roleID, secretID, err := auth.Credentials.Credentials(ctx)
+	if err != nil {
+		return errors.Wrap(err, "error reading approle credentials")
+	}
+	defer secmem.Track(secretID)()
+
+	secret, err := v.cl.Logical().WriteWithContext(ctx, "auth/"+path+"/login", map[string]interface{}{
+		"role_id":   roleID,
+		"secret_id": secretID,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error logging in with approle auth")
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return errors.New("no token in approle auth login response")
+	}
+
+	v.log().Debug("logged in with approle auth", "path", path)
+	v.cl.SetToken(secret.Auth.ClientToken)
+
+	if auth.RotateSecretIDRole != "" {
+		// The current secret_id stays valid if the rotation fails, so the next run can retry it
+		if err := v.rotateSecretID(ctx, path, auth.RotateSecretIDRole, secretID); err != nil {
+			v.log().Warn("error rotating the approle secret_id", "role", auth.RotateSecretIDRole, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// rotateSecretID generates a new secret_id for the role, stores it and destroys the old one.
+func (v *vault) rotateSecretID(ctx context.Context, path, role, oldSecretID string) error {
+	rolePath := "auth/" + path + "/role/" + role
+
+	secret, err := v.cl.Logical().WriteWithContext(ctx, rolePath+"/secret-id", nil)
+	if err != nil {
+		return errors.Wrap(err, "error generating secret_id")
+	}
+	if secret == nil || secret.Data["secret_id"] == nil {
+		return errors.New("no secret_id in response")
+	}
+	newSecretID := cast.ToString(secret.Data["secret_id"])
+	defer secmem.Track(newSecretID)()
+
+	if err := v.config.AppRoleAuth.Credentials.StoreSecretID(ctx, newSecretID); err != nil {
+		// Don't leave the unused secret_id behind
+		if _, destroyErr := v.cl.Logical().WriteWithContext(ctx, rolePath+"/secret-id/destroy", map[string]interface{}{"secret_id": newSecretID}); destroyErr != nil {
+			v.log().Warn("error destroying the unstored secret_id", "role", role, "error", destroyErr)
+		}
+
+		return errors.Wrap(err, "error storing secret_id")
+	}
+
+	if _, err := v.cl.Logical().WriteWithContext(ctx, rolePath+"/secret-id/destroy", map[string]interface{}{"secret_id": oldSecretID}); err != nil {
+		return errors.Wrap(err, "error destroying previous secret_id")
+	}
+
+	v.log().Info("rotated the approle secret_id", "role", role)
+	v.sendNotification(ctx, notify.Event{
+		Type:    notify.EventCredentialsRotated,
+		Message: fmt.Sprintf("rotated the approle secret_id of role %s", role),
+		Path:    rolePath,
+	})
+
+	return nil
+}
+
+// CertAuth configures configure to log in with the TLS certificate auth method instead of the root token,
+// presenting the client certificate of the Vault client.
+type CertAuth struct {
+	Enabled bool
+	// mount path of the TLS certificate auth method, DefaultCertAuthPath if empty
+	Path string
+	// certificate role to log in as, all the roles matching the client certificate are tried if empty
+	Role string
+}
+
+// certLogin logs in with the client certificate the Vault client presents.
+func (v *vault) certLogin(ctx context.Context) error {
+	auth := v.config.CertAuth
+
+	path := strings.Trim(auth.Path, "/")
+	if path == "" {
+		path = DefaultCertAuthPath
+	}
+
+	var data map[string]interface{}
+	if auth.Role != "" {
+		data = map[string]interface{}{"name": auth.Role}
+	}
+
+	secret, err := v.cl.Logical().WriteWithContext(ctx, "auth/"+path+"/login", data)
+	if err != nil {
+		return errors.Wrap(err, "error logging in with cert auth")
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return errors.New("no token in cert auth login response")
+	}
+
+	v.log().Debug("logged in with cert auth", "role", auth.Role, "path", path)
+	v.cl.SetToken(secret.Auth.ClientToken)
+
+	return nil
+}
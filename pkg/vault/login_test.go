@@ -0,0 +1,218 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKubernetesLogin(t *testing.T) {
+	var login map[string]interface{}
+	cl := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/k8s-prod/login" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": "configurer-token"},
+		})
+	})).cl
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("sa-jwt\n"), 0o600))
+
+	ctx := context.Background()
+	v, err := New(ctx, nil, cl, Config{
+		KubernetesAuth: KubernetesAuth{Role: "bank-vaults", Path: "/k8s-prod/", TokenFile: tokenFile},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, v.(*vault).login(ctx))
+	assert.Equal(t, "configurer-token", v.(*vault).cl.Token())
+	assert.Equal(t, map[string]interface{}{"role": "bank-vaults", "jwt": "sa-jwt"}, login)
+}
+
+func TestKubernetesLoginMissingTokenFile(t *testing.T) {
+	cl, err := api.NewClient(api.DefaultConfig())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	v, err := New(ctx, nil, cl, Config{
+		KubernetesAuth: KubernetesAuth{Role: "bank-vaults", TokenFile: filepath.Join(t.TempDir(), "missing")},
+	})
+	require.NoError(t, err)
+
+	assert.ErrorContains(t, v.(*vault).login(ctx), "error reading service account token")
+}
+
+type memAppRoleCredentials struct {
+	roleID   string
+	secretID string
+	storeErr error
+}
+
+func (c *memAppRoleCredentials) Credentials(context.Context) (string, string, error) {
+	return c.roleID, c.secretID, nil
+}
+
+func (c *memAppRoleCredentials) StoreSecretID(_ context.Context, secretID string) error {
+	if c.storeErr != nil {
+		return c.storeErr
+	}
+	c.secretID = secretID
+
+	return nil
+}
+
+func TestAppRoleLoginRotatesSecretID(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]map[string]interface{}{}
+	cl := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		mu.Lock()
+		requests[r.URL.Path] = body
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"auth": map[string]interface{}{"client_token": "configurer-token"},
+			})
+		case "/v1/auth/approle/role/bank-vaults/secret-id":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"secret_id": "new-secret-id"},
+			})
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})).cl
+
+	credentials := &memAppRoleCredentials{roleID: "role-id", secretID: "old-secret-id"}
+
+	ctx := context.Background()
+	v, err := New(ctx, nil, cl, Config{
+		AppRoleAuth: AppRoleAuth{Credentials: credentials, RotateSecretIDRole: "bank-vaults"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, v.(*vault).login(ctx))
+	assert.Equal(t, "configurer-token", v.(*vault).cl.Token())
+	assert.Equal(t, "new-secret-id", credentials.secretID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]interface{}{"role_id": "role-id", "secret_id": "old-secret-id"}, requests["/v1/auth/approle/login"])
+	assert.Equal(t, map[string]interface{}{"secret_id": "old-secret-id"}, requests["/v1/auth/approle/role/bank-vaults/secret-id/destroy"])
+}
+
+func TestAppRoleLoginKeepsSecretIDIfStoreFails(t *testing.T) {
+	var mu sync.Mutex
+	var destroyed []interface{}
+	cl := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/v1/auth/approle/login":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"auth": map[string]interface{}{"client_token": "configurer-token"},
+			})
+		case "/v1/auth/approle/role/bank-vaults/secret-id":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": map[string]interface{}{"secret_id": "new-secret-id"},
+			})
+		case "/v1/auth/approle/role/bank-vaults/secret-id/destroy":
+			mu.Lock()
+			destroyed = append(destroyed, body["secret_id"])
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})).cl
+
+	credentials := &memAppRoleCredentials{roleID: "role-id", secretID: "old-secret-id", storeErr: assert.AnError}
+
+	ctx := context.Background()
+	v, err := New(ctx, nil, cl, Config{
+		AppRoleAuth: AppRoleAuth{Credentials: credentials, RotateSecretIDRole: "bank-vaults"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, v.(*vault).login(ctx))
+	assert.Equal(t, "old-secret-id", credentials.secretID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []interface{}{"new-secret-id"}, destroyed)
+}
+
+func TestCertLogin(t *testing.T) {
+	var login map[string]interface{}
+	cl := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/cert/login" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&login)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": "configurer-token"},
+		})
+	})).cl
+
+	ctx := context.Background()
+	v, err := New(ctx, nil, cl, Config{
+		CertAuth: CertAuth{Enabled: true, Role: "bank-vaults"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, v.(*vault).login(ctx))
+	assert.Equal(t, "configurer-token", v.(*vault).cl.Token())
+	assert.Equal(t, map[string]interface{}{"name": "bank-vaults"}, login)
+}
+
+func TestTokenFileLogin(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "sink")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("agent-token\n"), 0o600))
+
+	cl, err := api.NewClient(api.DefaultConfig())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	v, err := New(ctx, nil, cl, Config{TokenFile: tokenFile})
+	require.NoError(t, err)
+
+	require.NoError(t, v.(*vault).login(ctx))
+	assert.Equal(t, "agent-token", v.(*vault).cl.Token())
+
+	// The agent renewed the token
+	require.NoError(t, os.WriteFile(tokenFile, []byte("renewed-token"), 0o600))
+	require.NoError(t, v.(*vault).login(ctx))
+	assert.Equal(t, "renewed-token", v.(*vault).cl.Token())
+
+	require.NoError(t, os.WriteFile(tokenFile, nil, 0o600))
+	assert.ErrorContains(t, v.(*vault).login(ctx), "is empty")
+}
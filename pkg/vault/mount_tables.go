@@ -0,0 +1,92 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"sync"
+
+	"github.com/hashicorp/vault/api"
+)
+
+// mountTables caches the secrets engine and auth method mount tables during a configure run,
+// so the sections checking them don't list them again and again. The mounts and unmounts
+// of the run invalidate them, tunes only change the config of a mount, which isn't read from them.
+type mountTables struct {
+	mu     sync.Mutex
+	mounts map[string]*api.MountOutput
+	auths  map[string]*api.MountOutput
+}
+
+// listMounts returns the secrets engines mounted in Vault by path, the map mustn't be modified.
+func (v *vault) listMounts() (map[string]*api.MountOutput, error) {
+	tables := v.mountTables
+	if tables == nil {
+		return v.cl.Sys().ListMounts()
+	}
+
+	tables.mu.Lock()
+	defer tables.mu.Unlock()
+
+	if tables.mounts == nil {
+		mounts, err := v.cl.Sys().ListMounts()
+		if err != nil {
+			return nil, err
+		}
+		tables.mounts = mounts
+	}
+
+	return tables.mounts, nil
+}
+
+// listAuth returns the auth methods enabled in Vault by path, the map mustn't be modified.
+func (v *vault) listAuth() (map[string]*api.MountOutput, error) {
+	tables := v.mountTables
+	if tables == nil {
+		return v.cl.Sys().ListAuth()
+	}
+
+	tables.mu.Lock()
+	defer tables.mu.Unlock()
+
+	if tables.auths == nil {
+		auths, err := v.cl.Sys().ListAuth()
+		if err != nil {
+			return nil, err
+		}
+		tables.auths = auths
+	}
+
+	return tables.auths, nil
+}
+
+// mountsChanged invalidates the cached secrets engine mount table.
+func (v *vault) mountsChanged() {
+	if tables := v.mountTables; tables != nil {
+		tables.mu.Lock()
+		defer tables.mu.Unlock()
+
+		tables.mounts = nil
+	}
+}
+
+// authsChanged invalidates the cached auth method mount table.
+func (v *vault) authsChanged() {
+	if tables := v.mountTables; tables != nil {
+		tables.mu.Lock()
+		defer tables.mu.Unlock()
+
+		tables.auths = nil
+	}
+}
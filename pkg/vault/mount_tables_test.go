@@ -0,0 +1,100 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMountTables(t *testing.T) {
+	var listed atomic.Int32
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/sys/mounts" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		listed.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":{"kv/":{"type":"kv"}}}`))
+	}))
+
+	// Outside of a configure run every check lists the mounts
+	exists, err := v.mountExists("kv")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	_, err = v.mountExists("kv")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), listed.Load())
+
+	v.mountTables = &mountTables{}
+	for _, path := range []string{"kv", "secret", "kv"} {
+		_, err := v.mountExists(path)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(3), listed.Load())
+
+	// A mount invalidates the cached table, the auth table is cached separately
+	v.mountsChanged()
+	v.authsChanged()
+	exists, err = v.mountExists("kv")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, int32(4), listed.Load())
+}
+
+func TestConfigureListsMountTablesOnce(t *testing.T) {
+	var listedMounts, listedAuths atomic.Int32
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/v1/")
+		switch {
+		case r.Method == http.MethodGet && path == "sys/mounts":
+			listedMounts.Add(1)
+			_, _ = w.Write([]byte(`{"data":{"secret/":{"type":"kv"},"cubbyhole/":{"type":"cubbyhole"}}}`))
+		case r.Method == http.MethodGet && path == "sys/auth":
+			listedAuths.Add(1)
+			_, _ = w.Write([]byte(`{"data":{"token/":{"type":"token"},"approle/":{"type":"approle"}}}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	v.config.Token = "root"
+
+	config := map[string]interface{}{
+		"auth": []interface{}{
+			map[string]interface{}{"type": "approle", "options": map[string]interface{}{"default_lease_ttl": "1h"}},
+			map[string]interface{}{"type": "userpass", "options": map[string]interface{}{"default_lease_ttl": "1h"}},
+		},
+		"secrets": []interface{}{
+			map[string]interface{}{"type": "kv", "path": "secret", "options": map[string]interface{}{"version": "2"}},
+			map[string]interface{}{"type": "kv", "path": "apps", "options": map[string]interface{}{"version": "2"}},
+		},
+	}
+	require.NoError(t, v.Configure(context.Background(), config))
+
+	// The tables are listed again only after the mounts of the run, not after the tunes
+	assert.Equal(t, int32(1), listedMounts.Load())
+	assert.Equal(t, int32(2), listedAuths.Load(), "the auth methods are listed again after enabling userpass")
+}
@@ -15,7 +15,9 @@
 package vault
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"log/slog"
@@ -23,12 +25,17 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"emperror.dev/errors"
 	uuid "github.com/hashicorp/go-uuid"
 	"github.com/hashicorp/vault/api"
 	"github.com/mitchellh/mapstructure"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
 )
 
 const (
@@ -42,16 +49,39 @@ const (
 // Vault is an interface that can be used to attempt to perform actions against
 // a Vault server.
 type Vault interface {
+	// Init initializes Vault and stores the unseal keys and the root token in the key store
 	Init(ctx context.Context) error
+	Initialized() (bool, error)
+	// RaftInitialized reports whether the unseal keys of the raft cluster are in the key store already
 	RaftInitialized(ctx context.Context) (bool, error)
 	RaftJoin(leaderAddress string) error
 	Sealed() (bool, error)
 	Active() (bool, error)
+	// Unseal unseals Vault with the unseal keys read from the key store
 	Unseal(ctx context.Context) error
 	Leader() (bool, error)
 	LeaderAddress() (string, error)
+	// Configure applies an external config, as read from the YAML of the configure command,
+	// purging the unmanaged resources its purgeUnmanagedConfig section selects
 	Configure(ctx context.Context, config map[string]interface{}) error
+	// Report returns the outcome of the last Configure run
+	Report() *Report
+	LicenseExpiry() time.Time
+	TokenExpiry() time.Time
+	// ManageToken keeps the token Configure logs in with valid between the runs, until the context is done
+	ManageToken(ctx context.Context, interval time.Duration)
+	// Verify compares an external config with Vault without changing anything
+	Verify(ctx context.Context, config map[string]interface{}) ([]Drift, error)
+	CreateToken(ctx context.Context, policies []string, ttl time.Duration) (string, error)
+	// SaveSnapshot streams a raft snapshot of Vault into the key store under the key
+	SaveSnapshot(ctx context.Context, key string, chunkSize int) (*SnapshotManifest, error)
+	// RestoreSnapshot streams the raft snapshot stored under the key into Vault
+	RestoreSnapshot(ctx context.Context, key string, force bool) (*SnapshotManifest, error)
+	RevokeInitRootToken(ctx context.Context)
+	Close()
 }
+
+// KVService stores the unseal keys and the root token, any kv backend of the kv package implements it.
 type KVService interface {
 	Set(ctx context.Context, key string, value []byte) error
 	Get(ctx context.Context, key string) ([]byte, error)
@@ -68,12 +98,72 @@ type Config struct {
 	InitRootToken string
 	// should the root token be stored in the keyStore
 	StoreRootToken bool
+	// if set, root tokens are never persisted: the one of init is only kept in memory until its first use
+	// and every root token is revoked after use, later ones are generated from the stored unseal keys
+	RevokeRootToken bool
 
-	// should the KV backend be tested first to validate access rights
+	// should the KV backend be tested first to validate access rights and that values round-trip through its encryption
 	PreFlightChecks bool
+
+	// should configure be skipped when neither the config nor the Vault mount table changed since the last apply
+	SkipUnchanged bool
+
+	// suffixed to the key store entries of the configure state, the applied license, the rotated credentials
+	// and the config fingerprint, so the targets sharing a key store keep theirs apart
+	StateKeySuffix string
+
+	// notifies external systems about failed applies, purges and credential rotations
+	Notifier notify.Notifier
+
+	// records every write performed by configure in an append-only log
+	AuditTrail AuditTrail
+
+	// run before and after each configure run and on each purge, with the apply report as input
+	Hooks Hooks
+
+	// Vault Enterprise license to apply during configure, read from the keyStore if LicenseKVKey is set
+	License      string
+	LicenseKVKey string
+
+	// how failing requests are retried, overridable per config section in the external config
+	Retry RetryPolicy
+	// caps the retries of all Vault requests, shared with the Vault clients, retries are not capped if nil
+	RetryBudget *RetryBudget
+
+	// if set, a token scoped to the config is minted with the root token and configure uses it instead,
+	// it is minted again whenever the config needs a different policy
+	ConfigurerToken bool
+
+	// if set, configure uses this token instead of the root token
+	Token string
+	// if set, configure uses the token in this file instead of the root token, e.g. the sink of a Vault Agent,
+	// it is read at every login so renewed tokens are picked up
+	TokenFile string
+
+	// if its role is set, configure logs in with the Kubernetes auth method instead of using the root token
+	KubernetesAuth KubernetesAuth
+
+	// if its credentials are set, configure logs in with the AppRole auth method instead of using the root token
+	AppRoleAuth AppRoleAuth
+
+	// if enabled, configure logs in with the TLS certificate auth method instead of using the root token
+	CertAuth CertAuth
+
+	// should failing config items be skipped and reported at the end instead of aborting the run
+	ContinueOnError bool
+
+	// how many policies, auth roles and startup secrets are applied at once, one by one if less than 2
+	Concurrency int
+
+	// reads the Kubernetes Secrets referenced by secret engine config values
+	SecretResolver SecretResolver
+
+	// names of the fields masked in the logged config payloads, DefaultRedactedFields if nil
+	RedactFields []string
 }
 
-type purgeUnmanagedConfig struct {
+// PurgeUnmanagedConfig selects the resources removed from Vault when the external config no longer lists them.
+type PurgeUnmanagedConfig struct {
 	Enabled bool `mapstructure:"enabled"`
 	Exclude struct {
 		Audit        bool `mapstructure:"audit"`
@@ -83,25 +173,37 @@ type purgeUnmanagedConfig struct {
 		Plugins      bool `mapstructure:"plugins"`
 		Policies     bool `mapstructure:"policies"`
 		Secrets      bool `mapstructure:"secrets"`
+		// custom sections of registered reconcilers
+		Sections []string `mapstructure:"sections"`
 	} `mapstructure:"exclude"`
 }
 
-type externalConfig struct {
-	PurgeUnmanagedConfig purgeUnmanagedConfig `mapstructure:"purgeUnmanagedConfig"`
-	Audit                []audit              `mapstructure:"audit"`
-	Auth                 []auth               `mapstructure:"auth"`
-	Groups               []group              `mapstructure:"groups"`
-	GroupAliases         []groupAlias         `mapstructure:"group-aliases"`
-	Plugins              []plugin             `mapstructure:"plugins"`
-	Policies             []policy             `mapstructure:"policies"`
-	Secrets              []secretEngine       `mapstructure:"secrets"`
-	StartupSecrets       []startupSecret      `mapstructure:"startupSecrets"`
+// ExternalConfig is the typed form of the external config Configure applies, see ParseConfig.
+type ExternalConfig struct {
+	PurgeUnmanagedConfig PurgeUnmanagedConfig   `mapstructure:"purgeUnmanagedConfig"`
+	Audit                []AuditDevice          `mapstructure:"audit"`
+	Auth                 []AuthMethod           `mapstructure:"auth"`
+	Groups               []Group                `mapstructure:"groups"`
+	DefaultGroup         DefaultGroup           `mapstructure:"defaultGroup"`
+	GroupAliases         []GroupAlias           `mapstructure:"group-aliases"`
+	Plugins              []Plugin               `mapstructure:"plugins"`
+	Policies             []Policy               `mapstructure:"policies"`
+	Secrets              []SecretsEngine        `mapstructure:"secrets"`
+	StartupSecrets       []StartupSecret        `mapstructure:"startupSecrets"`
+	Retry                map[string]RetryPolicy `mapstructure:"retry"`
+	// free-form label of the config generation, reported with the config hash
+	Version string `mapstructure:"version"`
+
+	// sections of the registered reconcilers, by name
+	custom map[string]interface{}
 }
 
 type kvTester struct {
 	Service KVService
 }
 
+// Test checks the key store can store and read back a value, through its encryption if it has any,
+// so the keys about to be stored are recoverable.
 func (t kvTester) Test(ctx context.Context, key string) error {
 	_, err := t.Service.Get(ctx, key)
 	if err != nil {
@@ -110,7 +212,25 @@ func (t kvTester) Test(ctx context.Context, key string) error {
 		}
 	}
 
-	return t.Service.Set(ctx, key, []byte(key))
+	canary := make([]byte, 32)
+	if _, err := rand.Read(canary); err != nil {
+		return errors.Wrap(err, "error generating canary value")
+	}
+
+	if err := t.Service.Set(ctx, key, canary); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	value, err := t.Service.Get(ctx, key)
+	if err != nil {
+		return errors.Wrap(err, "error reading back canary value")
+	}
+
+	if !bytes.Equal(value, canary) {
+		return errors.Errorf("the value read back from key '%s' doesn't match the one stored, the key store can't decrypt what it encrypts", key)
+	}
+
+	return nil
 }
 
 var _ Vault = &vault{}
@@ -122,8 +242,23 @@ type vault struct {
 	keyStore       KVService
 	cl             *api.Client
 	config         *Config
-	externalConfig *externalConfig
+	externalConfig *ExternalConfig
 	rotateCache    map[string]bool
+	report         *Report
+	configHash     string
+	licenseExpiry  atomic.Int64
+	tokenExpiry    atomic.Int64
+	spanCtx        atomic.Value
+	// root token of init waiting for its first use, if root tokens are revoked after use
+	initRootToken *secmem.Buffer
+	// token kept between the configure runs by ManageToken, guarded by tokenMu like the runs
+	tokenMu      sync.Mutex
+	manageToken  bool
+	managedToken string
+	// stops redacting the tokens of the client from the logs
+	untrackTokens func()
+	// mount tables cached during a configure run
+	mountTables *mountTables
 }
 
 // New returns a new vault Vault, or an error.
@@ -131,15 +266,39 @@ func New(ctx context.Context, k KVService, cl *api.Client, config Config) (Vault
 	if config.SecretShares < config.SecretThreshold {
 		return nil, errors.Errorf("the secret threshold can't be bigger than the shares [%d < %d]", config.SecretShares, config.SecretThreshold)
 	}
+	if config.RevokeRootToken && (config.StoreRootToken || config.InitRootToken != "") {
+		return nil, errors.New("revoking the root token after use excludes storing it or setting the init root token")
+	}
 
-	return &vault{
+	v := &vault{
 		ctx:            ctx,
 		keyStore:       k,
-		cl:             cl,
 		config:         &config,
 		rotateCache:    map[string]bool{},
-		externalConfig: &externalConfig{},
-	}, nil
+		externalConfig: &ExternalConfig{},
+		report:         newReport(),
+	}
+	v.cl = cl.WithRequestCallbacks(v.injectTraceContext)
+
+	// The client token changes with every login, it is looked up when a record is logged
+	v.untrackTokens = secmem.TrackSource(func() []string {
+		return []string{v.cl.Token(), v.config.Token, v.config.InitRootToken}
+	})
+
+	// The callbacks are registered on a copy of the client, the audit trail has to write
+	// with the copy configure logs in with
+	if auditTrail, ok := config.AuditTrail.(*vaultAuditTrail); ok {
+		v.config.AuditTrail = auditTrail.withClient(v.cl)
+	}
+
+	return v, nil
+}
+
+// Close releases the Vault helper, it isn't used afterwards.
+func (v *vault) Close() {
+	if v.untrackTokens != nil {
+		v.untrackTokens()
+	}
 }
 
 func (v *vault) Sealed() (bool, error) {
@@ -202,9 +361,11 @@ func (v *vault) Unseal(ctx context.Context) error {
 		if err != nil {
 			return errors.Wrapf(err, "unable to get key '%s'", keyUnsealForID(i))
 		}
+		key := secmem.NewBuffer(k)
 
 		slog.Debug("sending unseal request to vault...")
-		resp, err := v.cl.Sys().Unseal(string(k))
+		resp, err := v.cl.Sys().Unseal(key.String())
+		key.Destroy()
 		if err != nil {
 			return errors.Wrap(err, "fail to send unseal request to vault")
 		}
@@ -242,11 +403,29 @@ func (v *vault) keyStoreSet(ctx context.Context, key string, val []byte) error {
 	return errors.Wrapf(err, "error setting key '%s'", key)
 }
 
+// keyStoreSetSecret stores key material, wiping the copy handed to the key store afterwards.
+func (v *vault) keyStoreSetSecret(ctx context.Context, key string, val string) error {
+	data := []byte(val)
+	defer secmem.Wipe(data)
+
+	return v.keyStoreSet(ctx, key, data)
+}
+
+// Initialized reports whether Vault is initialized.
+func (v *vault) Initialized() (bool, error) {
+	initialized, err := v.cl.Sys().InitStatus()
+	if err != nil {
+		return false, errors.Wrap(err, "error testing if vault is initialized")
+	}
+
+	return initialized, nil
+}
+
 // Init initializes Vault if is not initialized already
 func (v *vault) Init(ctx context.Context) error {
-	initialized, err := v.cl.Sys().InitStatus()
+	initialized, err := v.Initialized()
 	if err != nil {
-		return errors.Wrap(err, "error testing if vault is initialized")
+		return err
 	}
 	if initialized {
 		slog.Info("vault is already initialized")
@@ -304,8 +483,15 @@ func (v *vault) Init(ctx context.Context) error {
 		return errors.Wrap(err, "error initializing vault")
 	}
 
+	// The keys exist only in this response, storing them can't be aborted by a shutdown
+	ctx = context.WithoutCancel(ctx)
+
+	for _, k := range append(append([]string{resp.RootToken}, resp.Keys...), resp.RecoveryKeys...) {
+		defer secmem.Track(k)()
+	}
+
 	for i, k := range resp.Keys {
-		err := v.keyStoreSet(ctx, keyUnsealForID(i), []byte(k))
+		err := v.keyStoreSetSecret(ctx, keyUnsealForID(i), k)
 		if err != nil {
 			return errors.Wrapf(err, "error storing unseal key '%s'", keyUnsealForID(i))
 		}
@@ -313,7 +499,7 @@ func (v *vault) Init(ctx context.Context) error {
 	}
 
 	for i, k := range resp.RecoveryKeys {
-		err := v.keyStoreSet(ctx, keyRecoveryForID(i), []byte(k))
+		err := v.keyStoreSetSecret(ctx, keyRecoveryForID(i), k)
 		if err != nil {
 			return errors.Wrapf(err, "error storing recovery key '%s'", keyRecoveryForID(i))
 		}
@@ -361,14 +547,23 @@ func (v *vault) Init(ctx context.Context) error {
 	}
 
 	if v.config.StoreRootToken {
-		if err = v.keyStoreSet(ctx, keyRootToken, []byte(resp.RootToken)); err != nil {
+		if err = v.keyStoreSetSecret(ctx, keyRootToken, resp.RootToken); err != nil {
 			return errors.Wrapf(err, "error storing root token '%s' in key'%s'", rootToken, keyRootToken)
 		}
 		slog.With(slog.String("key", keyRootToken)).Info("root token stored in key store")
+	} else if v.config.RevokeRootToken {
+		v.initRootToken = secmem.NewBuffer([]byte(rootToken))
+		slog.Info("root token not stored, it is revoked after its first use")
 	} else if v.config.InitRootToken == "" {
 		slog.With(slog.String("root-token", resp.RootToken)).Warn("won't store root token in key store, this token grants full privileges to vault, so keep this secret")
 	}
 
+	if v.config.ConfigurerToken {
+		if err := v.initConfigurerToken(ctx, rootToken); err != nil {
+			return errors.Wrap(err, "error minting configurer token")
+		}
+	}
+
 	return nil
 }
 
@@ -385,6 +580,8 @@ func (v *vault) RaftInitialized(ctx context.Context) (bool, error) {
 			return false, errors.Wrapf(err, "unable to get key '%s'", keyRootToken)
 		}
 
+		defer secmem.Wipe(rootToken)
+
 		if len(rootToken) > 0 {
 			return true, nil
 		}
@@ -402,6 +599,7 @@ func (v *vault) RaftInitialized(ctx context.Context) (bool, error) {
 			if len(unsealKey) == 0 {
 				return false, errors.Wrapf(err, "empty file for key '%s'", keyUnsealForID(i))
 			}
+			secmem.Wipe(unsealKey)
 		}
 		return true, nil
 	}
@@ -459,17 +657,95 @@ func (v *vault) RaftJoin(leaderAPIAddr string) error {
 	return errors.New("vault hasn't joined raft cluster")
 }
 
+// Configure applies the external config to Vault and records the outcome in a Report.
 func (v *vault) Configure(ctx context.Context, config map[string]interface{}) error {
+	ctx, span := tracer.Start(ctx, "configure")
+	defer span.End()
+
+	v.report = newReport()
+	if err := v.runHooks(ctx, HookEvent{Stage: HookStagePreApply}); err != nil {
+		err = errors.Wrap(err, "error running pre-apply hooks")
+		v.report.finish(err)
+		endSpan(span, err)
+		return err
+	}
+
+	v.mountTables = &mountTables{}
+	err := v.configure(ctx, config)
+	v.mountTables = nil
+	v.report.finish(err)
+	endSpan(span, err)
+	v.log().LogAttrs(ctx, slog.LevelInfo, "configure run summary", v.report.summary()...)
+	v.runLoggedHooks(ctx, HookEvent{Stage: HookStagePostApply})
+
+	if err != nil {
+		v.sendNotification(ctx, notify.Event{
+			Type:    notify.EventConfigureFailed,
+			Message: err.Error(),
+		})
+	}
+
+	return err
+}
+
+func (v *vault) sendNotification(ctx context.Context, event notify.Event) {
+	if v.config == nil {
+		return
+	}
+
+	notify.Send(ctx, v.config.Notifier, event)
+}
+
+// log returns the logger of the Vault instance, so the logs of parallel targets can be told apart.
+func (v *vault) log() *slog.Logger {
+	if v.cl == nil {
+		return slog.Default()
+	}
+
+	return slog.Default().With("vault", v.cl.Address())
+}
+
+// resourcePurged records an unmanaged resource removed from Vault.
+func (v *vault) resourcePurged(ctx context.Context, section, path string) {
+	v.report.purged(section, path)
+	v.recordWrite(AuditOperationDelete, path, nil)
+	v.sendNotification(ctx, notify.Event{
+		Type:    notify.EventPurged,
+		Message: fmt.Sprintf("removed unmanaged %s resource", section),
+		Section: section,
+		Path:    path,
+	})
+	v.runLoggedHooks(ctx, HookEvent{Stage: HookStagePurge, Section: section, Path: path})
+}
+
+// Report returns the report of the last configure run.
+func (v *vault) Report() *Report {
+	return v.report
+}
+
+// login sets the root token on the client, either read from the key store or generated from the unseal keys.
+func (v *vault) login(ctx context.Context) error {
 	var rootToken []byte
+	defer func() { secmem.Wipe(rootToken) }()
+
+	if ok, err := v.credentialsLogin(ctx); ok {
+		return err
+	}
 
 	slog.Debug("retrieving key from kms service...")
 
 	if v.config.StoreRootToken {
-		rootToken, err := v.keyStore.Get(ctx, keyRootToken)
+		storedRootToken, err := v.keyStore.Get(ctx, keyRootToken)
 		if err != nil {
 			return errors.Wrapf(err, "unable to get key '%s'", keyRootToken)
 		}
+		rootToken = storedRootToken
 		v.cl.SetToken(string(rootToken))
+	} else if v.initRootToken != nil {
+		// The root token of init is used once instead of generating one
+		v.cl.SetToken(v.initRootToken.String())
+		v.initRootToken.Destroy()
+		v.initRootToken = nil
 	} else {
 		var otp string
 		var nonce string
@@ -510,7 +786,9 @@ func (v *vault) Configure(ctx context.Context, config map[string]interface{}) er
 			if err != nil {
 				return errors.Wrapf(err, "unable to get key '%s'", keyID)
 			}
-			res, err := v.cl.Sys().GenerateRootUpdate(string(k), nonce)
+			key := secmem.NewBuffer(k)
+			res, err := v.cl.Sys().GenerateRootUpdate(key.String(), nonce)
+			key.Destroy()
 			if err != nil {
 				return errors.Wrapf(err, "unable to update generate-root token process with key %s", keyID)
 			}
@@ -526,6 +804,7 @@ func (v *vault) Configure(ctx context.Context, config map[string]interface{}) er
 					}
 
 					uuidToken, err := uuid.FormatUUID(tokenBytes)
+					secmem.Wipe(tokenBytes)
 					if err != nil {
 						return errors.Wrapf(err, "error formatting base64 encoded root token")
 					}
@@ -537,7 +816,9 @@ func (v *vault) Configure(ctx context.Context, config map[string]interface{}) er
 						return errors.Wrapf(err, "error decoding base64 encoded root token")
 					}
 
+					encodedTokenBytes := tokenBytes
 					tokenBytes, err = XORBytes(tokenBytes, []byte(otp))
+					secmem.Wipe(encodedTokenBytes)
 					if err != nil {
 						return errors.Wrapf(err, "error xoring encoded root token")
 					}
@@ -556,15 +837,21 @@ func (v *vault) Configure(ctx context.Context, config map[string]interface{}) er
 		}
 	}
 
-	// Clear the token and GC it
-	defer runtime.GC()
-	defer v.cl.SetToken("")
-	defer func() { rootToken = nil }()
+	return nil
+}
+
+// loadExternalConfig merges the given config into a copy of the current external config.
+// ParseConfig decodes an external config, in the layout Configure takes, into its typed form. The sections of
+// the registered reconcilers are left to them.
+func ParseConfig(config map[string]interface{}) (*ExternalConfig, error) {
+	return (&vault{}).loadExternalConfig(config)
+}
 
+func (v *vault) loadExternalConfig(config map[string]interface{}) (*ExternalConfig, error) {
 	// Deep copy current vault externalConfig
-	var loadedConfig externalConfig
+	var loadedConfig ExternalConfig
 	if err := mapstructure.Decode(v.externalConfig, &loadedConfig); err != nil {
-		return errors.Wrap(err, "error while copying externalConfig")
+		return nil, errors.Wrap(err, "error while copying externalConfig")
 	}
 
 	// Load and merge config from input
@@ -573,47 +860,133 @@ func (v *vault) Configure(ctx context.Context, config map[string]interface{}) er
 		// in Vault if the purge config is enabled.
 		ErrorUnused:      true,
 		WeaklyTypedInput: true,
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
 		Result:           &loadedConfig,
 	})
 	if err != nil {
-		return errors.Wrap(err, "error creating externalConfig decoder")
+		return nil, errors.Wrap(err, "error creating externalConfig decoder")
 	}
 
+	// The custom sections are decoded by their reconcilers
+	config, custom := splitCustomSections(config)
 	if err = decoder.Decode(config); err != nil {
-		return errors.Wrap(err, "error decoding externalConfig")
+		return nil, errors.Wrap(err, "error decoding externalConfig")
+	}
+	loadedConfig.custom = custom
+
+	if err = validateRetryOverrides(loadedConfig.Retry); err != nil {
+		return nil, errors.Wrap(err, "error decoding externalConfig")
+	}
+
+	return &loadedConfig, nil
+}
+
+func (v *vault) configure(ctx context.Context, config map[string]interface{}) error {
+	loadedConfig, err := v.loadExternalConfig(config)
+	if err != nil {
+		return err
+	}
+
+	// The configurer token is scoped to the loaded config
+	configurerToken := v.config.ConfigurerToken && v.usesRootToken()
+	v.tokenMu.Lock()
+	defer v.tokenMu.Unlock()
+	switch {
+	case configurerToken:
+		err = v.configurerLogin(ctx, loadedConfig)
+	case v.managedToken != "":
+		v.cl.SetToken(v.managedToken)
+	default:
+		err = v.login(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	if v.manageToken && v.tokenManageable() {
+		v.managedToken = v.cl.Token()
+	}
+	v.checkToken(ctx)
+
+	// Clear the token and GC it
+	defer runtime.GC()
+	defer v.cl.SetToken("")
+	if !configurerToken {
+		defer v.revokeRootToken(ctx, v.cl.Token())
 	}
 
 	// Update vault externalConfig with loaded data
-	v.externalConfig = &loadedConfig
+	v.externalConfig = loadedConfig
+
+	if v.configHash, err = configHash(config); err != nil {
+		return errors.Wrap(err, "error hashing config")
+	}
+	v.report.ConfigHash = v.configHash
+	v.report.ConfigVersion = loadedConfig.Version
+
+	if v.config.SkipUnchanged {
+		fingerprint, err := v.currentConfigFingerprint(ctx, config)
+		if err != nil {
+			return errors.Wrap(err, "error fingerprinting config")
+		}
+
+		unchanged, err := v.configUnchanged(ctx, fingerprint)
+		if err != nil {
+			return errors.Wrap(err, "error checking config fingerprint")
+		}
+
+		if unchanged {
+			v.log().Info("config and mount table unchanged since last apply, skipping configuration")
+			v.report.Skipped = true
+			return nil
+		}
+	}
+
+	if err = v.traceSection(ctx, SectionLicense, v.configureLicense); err != nil {
+		return errors.Wrap(err, "error configuring license for vault")
+	}
 
-	if err = v.configureAuditDevices(); err != nil {
+	if err = v.traceSection(ctx, SectionAudit, v.configureAuditDevices); err != nil {
 		return errors.Wrap(err, "error configuring audit devices for vault")
 	}
 
-	if err = v.configurePlugins(); err != nil {
+	if err = v.traceSection(ctx, SectionPlugins, v.configurePlugins); err != nil {
 		return errors.Wrap(err, "error configuring plugins for vault")
 	}
 
-	if err = v.configureAuthMethods(); err != nil {
+	if err = v.traceSection(ctx, SectionAuth, v.configureAuthMethods); err != nil {
 		return errors.Wrap(err, "error configuring auth methods for vault")
 	}
 
-	if err = v.configureIdentityGroups(); err != nil {
+	if err = v.traceSection(ctx, SectionGroups, v.configureIdentityGroups); err != nil {
 		return errors.Wrap(err, "error writing groups configurations for vault")
 	}
 
-	if err = v.configurePolicies(); err != nil {
+	if err = v.traceSection(ctx, SectionPolicies, v.configurePolicies); err != nil {
 		return errors.Wrap(err, "error configuring policies for vault")
 	}
 
-	if err = v.configureSecretsEngines(ctx); err != nil {
+	if err = v.traceSection(ctx, SectionSecrets, v.configureSecretsEngines); err != nil {
 		return errors.Wrap(err, "error configuring secret engines for vault")
 	}
 
-	if err = v.configureStartupSecrets(ctx); err != nil {
+	if err = v.traceSection(ctx, SectionStartupSecrets, v.configureStartupSecrets); err != nil {
 		return errors.Wrap(err, "error writing startup secrets to vault")
 	}
 
+	if err = v.configureCustomSections(ctx); err != nil {
+		return err
+	}
+
+	if failures := v.report.failures(); len(failures) > 0 {
+		return failedItemsError(failures)
+	}
+
+	if v.config.SkipUnchanged {
+		if err = v.storeConfigFingerprint(ctx, config); err != nil {
+			return errors.Wrap(err, "error storing config fingerprint")
+		}
+	}
+
 	return err
 }
 
@@ -622,10 +995,11 @@ func (v *vault) writeWithWarningCheck(path string, data map[string]interface{})
 	if err != nil {
 		return nil, err
 	}
+	v.recordWrite(AuditOperationWrite, path, data)
 
 	if sec != nil {
 		for _, warning := range sec.Warnings {
-			slog.Warn(warning)
+			v.log().Warn(warning, "path", path)
 		}
 	}
 
@@ -684,6 +1058,17 @@ func XORBase64(a, b string) ([]byte, error) {
 	return XORBytes(aBytes, bBytes)
 }
 
+// InitOutputKeys returns the keys the output of Vault init is stored under: the unseal
+// and recovery keys and the root token.
+func InitOutputKeys(config Config) []string {
+	keys := make([]string, 0, 2*config.SecretShares+1)
+	for i := 0; i < config.SecretShares; i++ {
+		keys = append(keys, keyUnsealForID(i), keyRecoveryForID(i))
+	}
+
+	return append(keys, keyRootToken)
+}
+
 func keyUnsealForID(i int) string {
 	return fmt.Sprint("vault-unseal-", i)
 }
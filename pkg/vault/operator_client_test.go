@@ -0,0 +1,78 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestVault returns a vault with an in-memory key store, talking to a fake Vault served by handler.
+func newTestVault(t *testing.T, handler http.Handler) *vault {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cfg := api.DefaultConfig()
+	cfg.Address = srv.URL
+	cl, err := api.NewClient(cfg)
+	require.NoError(t, err)
+	cl.ClearToken()
+
+	return &vault{
+		ctx:            context.Background(),
+		cl:             cl,
+		keyStore:       &memKV{},
+		config:         &Config{},
+		externalConfig: &ExternalConfig{},
+		rotateCache:    map[string]bool{},
+		report:         newReport(),
+	}
+}
+
+// corruptingKV returns values other than the ones it stored, like a key store decrypting with the wrong key.
+type corruptingKV struct {
+	memKV
+}
+
+func (c *corruptingKV) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.memKV.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	value[0] ^= 0xff
+
+	return value, nil
+}
+
+func TestKVTesterRoundTrip(t *testing.T) {
+	ctx := context.Background()
+
+	store := &memKV{}
+	require.NoError(t, kvTester{Service: store}.Test(ctx, keyTestField))
+	value, err := store.Get(ctx, keyTestField)
+	require.NoError(t, err)
+	assert.Len(t, value, 32)
+
+	err = kvTester{Service: &corruptingKV{}}.Test(ctx, keyTestField)
+	assert.ErrorContains(t, err, "doesn't match")
+}
@@ -15,8 +15,7 @@
 package vault
 
 import (
-	"fmt"
-	"log/slog"
+	"context"
 
 	"emperror.dev/errors"
 	"github.com/hashicorp/vault/api"
@@ -47,7 +46,8 @@ var builtinPlugins = map[string]map[string]bool{
 	},
 }
 
-type plugin struct {
+// Plugin is a plugin of the plugins section, registered in the plugin catalog.
+type Plugin struct {
 	Name    string `mapstructure:"plugin_name"`
 	Type    string `mapstructure:"type"`
 	Command string `mapstructure:"command"`
@@ -78,7 +78,7 @@ func (v *vault) getExistingPlugins() (map[string]map[string]bool, error) {
 					Type: existingPluginType,
 				}
 
-				slog.Debug(fmt.Sprintf("check if %s/%s is a builtin plugin or not", existingPluginType, existingPluginName))
+				v.log().Debug("checking if plugin is builtin", "section", SectionPlugins, "path", existingPluginName, "type", existingPluginType)
 				existingPlugin, err := v.cl.Sys().GetPlugin(&input)
 				if err != nil {
 					return nil, errors.Wrapf(err, "failed to retrieve plugin %s/%s", existingPluginType, existingPluginName)
@@ -95,7 +95,7 @@ func (v *vault) getExistingPlugins() (map[string]map[string]bool, error) {
 
 // getUnmanagedPlugins gets unmanaged plugins by comparing what's already in Vault
 // and what's in the externalConfig.
-func getUnmanagedPlugins(existingPlugins map[string]map[string]bool, managedPlugins []plugin) map[string]map[string]bool {
+func getUnmanagedPlugins(existingPlugins map[string]map[string]bool, managedPlugins []Plugin) map[string]map[string]bool {
 	for _, managedPlugin := range managedPlugins {
 		delete(existingPlugins[managedPlugin.Type], managedPlugin.Name)
 	}
@@ -103,33 +103,43 @@ func getUnmanagedPlugins(existingPlugins map[string]map[string]bool, managedPlug
 	return existingPlugins
 }
 
-func (v *vault) addManagedPlugins(managedPlugins []plugin) error {
+func (v *vault) addManagedPlugins(managedPlugins []Plugin) error {
 	for _, plugin := range managedPlugins {
-		pluginType, err := api.ParsePluginType(plugin.Type)
-		if err != nil {
-			return errors.Wrap(err, "error parsing type for plugin")
+		if err := v.itemFailed(SectionPlugins, plugin.Type+"/"+plugin.Name, v.addManagedPlugin(plugin)); err != nil {
+			return err
 		}
+	}
 
-		input := api.RegisterPluginInput{
-			Name:    plugin.Name,
-			Command: plugin.Command,
-			SHA256:  plugin.SHA256,
-			Type:    pluginType,
-		}
+	return nil
+}
 
-		slog.Info(fmt.Sprintf("adding plugin %s (%s)", plugin.Name, plugin.Type))
-		slog.Debug(fmt.Sprintf("plugin input %#v", input))
-		if err = v.cl.Sys().RegisterPlugin(&input); err != nil {
-			return errors.Wrapf(err, "error adding plugin %s/%s in vault", plugin.Type, plugin.Name)
-		}
+func (v *vault) addManagedPlugin(plugin Plugin) error {
+	pluginType, err := api.ParsePluginType(plugin.Type)
+	if err != nil {
+		return errors.Wrap(err, "error parsing type for plugin")
 	}
 
+	input := api.RegisterPluginInput{
+		Name:    plugin.Name,
+		Command: plugin.Command,
+		SHA256:  plugin.SHA256,
+		Type:    pluginType,
+	}
+
+	v.log().Info("adding plugin", "section", SectionPlugins, "path", plugin.Name, "type", plugin.Type)
+	v.log().Debug("plugin input", "section", SectionPlugins, "path", plugin.Name, "input", v.redact(input))
+	if err = v.cl.Sys().RegisterPlugin(&input); err != nil {
+		return errors.Wrapf(err, "error adding plugin %s/%s in vault", plugin.Type, plugin.Name)
+	}
+	v.report.updated(SectionPlugins, plugin.Type+"/"+plugin.Name)
+	v.recordWrite(AuditOperationWrite, "sys/plugins/catalog/"+plugin.Type+"/"+plugin.Name, map[string]interface{}{"command": plugin.Command, "sha256": plugin.SHA256})
+
 	return nil
 }
 
-func (v *vault) removeUnmanagedPlugins(managedPlugins []plugin) error {
+func (v *vault) removeUnmanagedPlugins(ctx context.Context, managedPlugins []Plugin) error {
 	if !v.externalConfig.PurgeUnmanagedConfig.Enabled || v.externalConfig.PurgeUnmanagedConfig.Exclude.Plugins {
-		slog.Debug("purge config is disabled, no unmanaged plugins will be removed")
+		v.log().Debug("purge config is disabled, no unmanaged plugins will be removed", "section", SectionPlugins)
 		return nil
 	}
 
@@ -148,24 +158,30 @@ func (v *vault) removeUnmanagedPlugins(managedPlugins []plugin) error {
 				Type: pluginType,
 			}
 
-			slog.Info(fmt.Sprintf("removing plugin %s (%s)", existingPluginName, existingPluginType))
+			v.log().Info("removing plugin", "section", SectionPlugins, "path", existingPluginName, "type", existingPluginType)
 			if err := v.cl.Sys().DeregisterPlugin(&input); err != nil {
-				return errors.Wrapf(err, "error removing plugin %s/%s in vault", existingPluginType, existingPluginName)
+				err = errors.Wrapf(err, "error removing plugin %s/%s in vault", existingPluginType, existingPluginName)
+				if err := v.itemFailed(SectionPlugins, existingPluginType+"/"+existingPluginName, err); err != nil {
+					return err
+				}
+
+				continue
 			}
+			v.resourcePurged(ctx, SectionPlugins, existingPluginType+"/"+existingPluginName)
 		}
 	}
 
 	return nil
 }
 
-func (v *vault) configurePlugins() error {
+func (v *vault) configurePlugins(ctx context.Context) error {
 	managedPlugins := v.externalConfig.Plugins
 
 	if err := v.addManagedPlugins(managedPlugins); err != nil {
 		return errors.Wrap(err, "error while adding plugins")
 	}
 
-	if err := v.removeUnmanagedPlugins(managedPlugins); err != nil {
+	if err := v.removeUnmanagedPlugins(ctx, managedPlugins); err != nil {
 		return errors.Wrap(err, "error while removing plugins")
 	}
 
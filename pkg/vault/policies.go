@@ -0,0 +1,399 @@
+// Copyright © 2021 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	hclPrinter "github.com/hashicorp/hcl/hcl/printer"
+	"github.com/hashicorp/vault/api"
+)
+
+// Policy is an ACL policy of the policies section, its rules extended with the control groups.
+type Policy struct {
+	Name           string         `mapstructure:"name"`
+	Rules          string         `mapstructure:"rules"`
+	ControlGroups  []ControlGroup `mapstructure:"controlGroups"`
+	RulesFormatted string
+}
+
+// ControlGroup requires approvals for requests on a path (Vault Enterprise only).
+type ControlGroup struct {
+	Path         string               `mapstructure:"path"`
+	Capabilities []string             `mapstructure:"capabilities"`
+	TTL          string               `mapstructure:"ttl"`
+	Factors      []ControlGroupFactor `mapstructure:"factors"`
+}
+
+// ControlGroupFactor is an approval of a control group by the members of identity groups.
+type ControlGroupFactor struct {
+	Name       string   `mapstructure:"name"`
+	GroupNames []string `mapstructure:"groupNames"`
+	Approvals  int      `mapstructure:"approvals"`
+}
+
+// renderControlGroups renders the control groups as HCL path rules.
+func renderControlGroups(controlGroups []ControlGroup) (string, error) {
+	var rules strings.Builder
+	for _, cg := range controlGroups {
+		if cg.Path == "" {
+			return "", errors.New("control group path must be set")
+		}
+		if len(cg.Factors) == 0 {
+			return "", errors.Errorf("control group of path %s must have at least one factor", cg.Path)
+		}
+
+		capabilities := make([]string, 0, len(cg.Capabilities))
+		for _, capability := range cg.Capabilities {
+			capabilities = append(capabilities, fmt.Sprintf("%q", capability))
+		}
+
+		fmt.Fprintf(&rules, "\npath %q {\n", cg.Path)
+		fmt.Fprintf(&rules, "  capabilities = [%s]\n", strings.Join(capabilities, ", "))
+		rules.WriteString("  control_group = {\n")
+		if cg.TTL != "" {
+			fmt.Fprintf(&rules, "    ttl = %q\n", cg.TTL)
+		}
+		for _, factor := range cg.Factors {
+			if factor.Name == "" || len(factor.GroupNames) == 0 || factor.Approvals < 1 {
+				return "", errors.Errorf("control group factor of path %s must have a name, group names and at least one approval", cg.Path)
+			}
+
+			groupNames := make([]string, 0, len(factor.GroupNames))
+			for _, groupName := range factor.GroupNames {
+				groupNames = append(groupNames, fmt.Sprintf("%q", groupName))
+			}
+
+			fmt.Fprintf(&rules, "    factor %q {\n", factor.Name)
+			rules.WriteString("      identity {\n")
+			fmt.Fprintf(&rules, "        group_names = [%s]\n", strings.Join(groupNames, ", "))
+			fmt.Fprintf(&rules, "        approvals = %d\n", factor.Approvals)
+			rules.WriteString("      }\n    }\n")
+		}
+		rules.WriteString("  }\n}\n")
+	}
+
+	return rules.String(), nil
+}
+
+func initPoliciesConfig(policiesConfig []Policy, mounts map[string]*api.MountOutput) ([]Policy, error) {
+	// Sort mount paths by length (longest first) to avoid substring collisions
+	// e.g., "kubernetes_cluster" should be processed before "kubernetes"
+	mountPaths := slices.Collect(maps.Keys(mounts))
+	slices.SortFunc(mountPaths, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	for i := range policiesConfig {
+		policy := &policiesConfig[i]
+
+		// Append the control groups to the rules
+		if len(policy.ControlGroups) > 0 {
+			if strings.HasPrefix(strings.TrimSpace(policy.Rules), "{") {
+				return nil, fmt.Errorf("control groups of %s policy can't be combined with JSON rules", policy.Name)
+			}
+
+			controlGroupRules, err := renderControlGroups(policy.ControlGroups)
+			if err != nil {
+				return nil, fmt.Errorf("rendering %s policy control groups: %w", policy.Name, err)
+			}
+			policy.Rules += controlGroupRules
+		}
+
+		// Replace alias name and accessor placeholders
+		for _, mountPath := range mountPaths {
+			mountName := strings.TrimSuffix(mountPath, "/")
+			aliasName := fmt.Sprintf("{{identity.entity.aliases.%s.name}}", mounts[mountPath].Accessor)
+			policy.Rules = strings.ReplaceAll(policy.Rules, "__alias_name__"+mountName, aliasName)
+			policy.Rules = strings.ReplaceAll(policy.Rules, "__accessor__"+mountName, mounts[mountPath].Accessor)
+		}
+
+		// Format as HCL, falling back to original if it's valid JSON
+		formatted, err := hclPrinter.Format([]byte(policy.Rules))
+		if err != nil {
+			if _, parseErr := hcl.Parse(policy.Rules); parseErr != nil {
+				return nil, fmt.Errorf("parsing %s policy rules: %w", policy.Name, parseErr)
+			}
+			slog.Debug("could not HCL-format policy rules (may be JSON)",
+				"policy", policy.Name,
+				"error", err)
+			formatted = []byte(policy.Rules)
+		}
+		policy.RulesFormatted = string(formatted)
+
+		if err := validatePolicyRules(policy.Rules); err != nil {
+			return nil, fmt.Errorf("validating %s policy rules: %w", policy.Name, err)
+		}
+	}
+
+	return policiesConfig, nil
+}
+
+// identityPlaceholder matches the __entity_id__<name> and __group_id__<name> placeholders in policy rules.
+var identityPlaceholder = regexp.MustCompile(`__(entity|group)_id__([\w.@-]+)`)
+
+// resolveIdentityPlaceholders replaces the identity placeholders in the policy rules with the IDs
+// of the named entities and groups, so policies can reference them by name.
+func (v *vault) resolveIdentityPlaceholders(policiesConfig []Policy) error {
+	for i := range policiesConfig {
+		policy := &policiesConfig[i]
+
+		var err error
+		policy.Rules = identityPlaceholder.ReplaceAllStringFunc(policy.Rules, func(placeholder string) string {
+			match := identityPlaceholder.FindStringSubmatch(placeholder)
+
+			var id string
+			var lookupErr error
+			switch match[1] {
+			case "entity":
+				id, lookupErr = getVaultEntityID(match[2], v.cl)
+			case "group":
+				id, lookupErr = getVaultGroupID(match[2], v.cl)
+			}
+			err = errors.Append(err, lookupErr)
+
+			return id
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error resolving identity placeholders of %s policy", policy.Name)
+		}
+	}
+
+	return nil
+}
+
+func getVaultEntityID(entity string, client *api.Client) (string, error) {
+	secret, err := client.Logical().Read("identity/entity/name/" + entity)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read entity %s by name", entity)
+	}
+	if secret == nil {
+		return "", errors.Errorf("entity %s does not exist", entity)
+	}
+
+	id, ok := secret.Data["id"].(string)
+	if !ok || id == "" {
+		return "", errors.Errorf("entity %s has no id", entity)
+	}
+
+	return id, nil
+}
+
+// policyCapabilities are the capabilities Vault understands in path rules.
+var policyCapabilities = []string{"create", "read", "update", "patch", "delete", "list", "sudo", "deny", "subscribe", "recover"}
+
+// validatePolicyRules checks the path rules of a policy locally, since Vault accepts
+// unknown capabilities and malformed globs, resulting in policies which silently grant nothing.
+func validatePolicyRules(rules string) error {
+	file, err := hcl.Parse(rules)
+	if err != nil {
+		return err
+	}
+
+	list, ok := file.Node.(*ast.ObjectList)
+	if !ok {
+		return nil
+	}
+
+	for _, item := range list.Items {
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value() != "path" {
+			continue
+		}
+
+		// Both `path "x" {...}` and JSON style `{"path": {"x": {...}}}` are accepted
+		if len(item.Keys) > 1 {
+			if err := validatePolicyPath(item.Keys[1], item.Val); err != nil {
+				return err
+			}
+			continue
+		}
+
+		paths, ok := item.Val.(*ast.ObjectType)
+		if !ok {
+			return fmt.Errorf("line %d: path rule must be an object", item.Pos().Line)
+		}
+		for _, pathItem := range paths.List.Items {
+			if err := validatePolicyPath(pathItem.Keys[0], pathItem.Val); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validatePolicyPath(key *ast.ObjectKey, val ast.Node) error {
+	line := key.Pos().Line
+
+	path, ok := key.Token.Value().(string)
+	if !ok || path == "" {
+		return fmt.Errorf("line %d: path must be a non-empty string", line)
+	}
+
+	if i := strings.Index(path, "*"); i >= 0 && i != len(path)-1 {
+		return fmt.Errorf("line %d: path %q may only contain '*' as its last character", line, path)
+	}
+
+	for _, segment := range strings.Split(path, "/") {
+		if strings.Contains(segment, "+") && segment != "+" {
+			return fmt.Errorf("line %d: path %q may only contain '+' as a whole path segment", line, path)
+		}
+	}
+
+	body, ok := val.(*ast.ObjectType)
+	if !ok {
+		return fmt.Errorf("line %d: rules of path %q must be an object", line, path)
+	}
+
+	for _, item := range body.List.Items {
+		if len(item.Keys) == 0 || item.Keys[0].Token.Value() != "capabilities" {
+			continue
+		}
+
+		capabilities, ok := item.Val.(*ast.ListType)
+		if !ok {
+			return fmt.Errorf("line %d: capabilities of path %q must be a list", item.Pos().Line, path)
+		}
+
+		for _, node := range capabilities.List {
+			literal, ok := node.(*ast.LiteralType)
+			if !ok {
+				return fmt.Errorf("line %d: capabilities of path %q must be strings", node.Pos().Line, path)
+			}
+
+			capability, _ := literal.Token.Value().(string)
+			if !slices.Contains(policyCapabilities, capability) {
+				return fmt.Errorf("line %d: unknown capability %q for path %q", literal.Pos().Line, capability, path)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *vault) addManagedPolicies(managedPolicies []Policy) error {
+	return applyItems(v, SectionPolicies, managedPolicies, func(policy Policy) string { return policy.Name }, func(policy Policy) error {
+		v.log().Info("adding policy", "section", SectionPolicies, "path", policy.Name)
+		if err := v.cl.Sys().PutPolicy(policy.Name, policy.RulesFormatted); err != nil {
+			return errors.Wrapf(err, "error putting %s policy into vault", policy.Name)
+		}
+		v.report.updated(SectionPolicies, policy.Name)
+		v.recordWrite(AuditOperationWrite, "sys/policies/acl/"+policy.Name, map[string]interface{}{"policy": policy.RulesFormatted})
+
+		return nil
+	})
+}
+
+// getExistingPolicies gets all policies that are already in Vault.
+// The existing policies are in a map to make it easy to delete easily from it with "O(n)" complexity.
+func (v *vault) getExistingPolicies() (map[string]bool, error) {
+	existingPolicies := make(map[string]bool)
+
+	existingPoliciesList, err := v.cl.Sys().ListPolicies()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list existing policies")
+	}
+
+	for _, existingPolicy := range existingPoliciesList {
+		existingPolicies[existingPolicy] = true
+	}
+
+	return existingPolicies, nil
+}
+
+// builtInPolicies are policies Vault provides out of the box and refuses to
+// delete via the API. Attempting to delete them returns HTTP 400.
+// "default-ceiling" was introduced in Vault 2.0.
+var builtInPolicies = []string{"root", "default", "default-ceiling"}
+
+// getUnmanagedPolicies gets unmanaged policies by comparing what's already in Vault and what's in the externalConfig.
+func (v *vault) getUnmanagedPolicies(managedPolicies []Policy) map[string]bool {
+	unmanagedPolicies, _ := v.getExistingPolicies()
+
+	// Vault doesn't allow removing built-in policies.
+	for _, builtIn := range builtInPolicies {
+		delete(unmanagedPolicies, builtIn)
+	}
+
+	// The policy of the token configure runs with isn't part of the config
+	delete(unmanagedPolicies, ConfigurerPolicyName)
+
+	// Remove managed polices form the items since the reset will be removed.
+	for _, managedPolicy := range managedPolicies {
+		delete(unmanagedPolicies, managedPolicy.Name)
+	}
+
+	return unmanagedPolicies
+}
+
+func (v *vault) removeUnmanagedPolicies(ctx context.Context, managedPolicies []Policy) error {
+	unmanagedPolicies := v.getUnmanagedPolicies(managedPolicies)
+	v.report.unmanaged(SectionPolicies, slices.Sorted(maps.Keys(unmanagedPolicies)))
+
+	if !v.externalConfig.PurgeUnmanagedConfig.Enabled || v.externalConfig.PurgeUnmanagedConfig.Exclude.Policies {
+		v.log().Debug("purge config is disabled, no unmanaged policies will be removed", "section", SectionPolicies)
+		return nil
+	}
+
+	for policyName := range unmanagedPolicies {
+		v.log().Info("removing policy", "section", SectionPolicies, "path", policyName)
+		if err := v.cl.Sys().DeletePolicy(policyName); err != nil {
+			if err := v.itemFailed(SectionPolicies, policyName, errors.Wrapf(err, "error deleting %s policy from vault", policyName)); err != nil {
+				return err
+			}
+
+			continue
+		}
+		v.resourcePurged(ctx, SectionPolicies, policyName)
+	}
+	return nil
+}
+
+func (v *vault) configurePolicies(ctx context.Context) error {
+	auths, err := v.listAuth()
+	if err != nil {
+		return errors.Wrap(err, "error while getting list of auth engines")
+	}
+
+	// The rules are resolved and rendered in a copy, the config is kept for the next runs
+	policies := slices.Clone(v.externalConfig.Policies)
+	if err := v.resolveIdentityPlaceholders(policies); err != nil {
+		return errors.Wrap(err, "error while initializing policies config")
+	}
+
+	managedPolicies, err := initPoliciesConfig(policies, auths)
+	if err != nil {
+		return errors.Wrap(err, "error while initializing policies config")
+	}
+
+	if err := v.addManagedPolicies(managedPolicies); err != nil {
+		return errors.Wrap(err, "error while adding policies")
+	}
+
+	if err := v.removeUnmanagedPolicies(ctx, managedPolicies); err != nil {
+		return errors.Wrap(err, "error while removing policies")
+	}
+
+	return nil
+}
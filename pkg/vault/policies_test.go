@@ -0,0 +1,376 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitPoliciesConfig_SubstringCollision(t *testing.T) {
+	tests := []struct {
+		name           string
+		policies       []Policy
+		mounts         map[string]*api.MountOutput
+		expectedRules  string
+		description    string
+	}{
+		{
+			name: "multiple accessors with prefix collision",
+			policies: []Policy{
+				{
+					Name: "test-policy",
+					Rules: `path "secret/data/__accessor__kubernetes_cluster/*" {
+  capabilities = ["read"]
+}
+path "secret/data/__accessor__kubernetes/*" {
+  capabilities = ["read"]
+}`,
+				},
+			},
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+				"kubernetes_cluster/": {
+					Accessor: "auth_kubernetes_cluster_67890",
+				},
+			},
+			expectedRules: `path "secret/data/auth_kubernetes_cluster_67890/*" {
+  capabilities = ["read"]
+}
+path "secret/data/auth_kubernetes_12345/*" {
+  capabilities = ["read"]
+}`,
+			description: "Should replace longer path first to avoid substring collision",
+		},
+		{
+			name: "single mount",
+			policies: []Policy{
+				{
+					Name:  "single-mount",
+					Rules: `path "auth/__accessor__kubernetes/role" { capabilities = ["read"] }`,
+				},
+			},
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+			},
+			expectedRules: `path "auth/auth_kubernetes_12345/role" { capabilities = ["read"] }`,
+			description:   "Should work correctly with single mount",
+		},
+		{
+			name: "alias name placeholder",
+			policies: []Policy{
+				{
+					Name:  "alias-name",
+					Rules: `path "secret/data/__alias_name__kubernetes/*" { capabilities = ["read"] }`,
+				},
+			},
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+			},
+			expectedRules: `path "secret/data/{{identity.entity.aliases.auth_kubernetes_12345.name}}/*" { capabilities = ["read"] }`,
+			description:   "Should expand alias name placeholder to an identity template",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := initPoliciesConfig(tt.policies, tt.mounts)
+			assert.NoError(t, err, tt.description)
+			assert.Equal(t, tt.expectedRules, result[0].Rules, tt.description)
+
+			// Verify RulesFormatted is set and contains replaced accessors
+			assert.NotEmpty(t, result[0].RulesFormatted, "RulesFormatted should be set")
+			assert.NotContains(t, result[0].RulesFormatted, "__accessor__", "RulesFormatted should not contain placeholders")
+		})
+	}
+}
+
+func TestInitPoliciesConfig_RulesFormatted(t *testing.T) {
+	tests := []struct {
+		name        string
+		policies    []Policy
+		mounts      map[string]*api.MountOutput
+		description string
+	}{
+		{
+			name: "multiline HCL formatting",
+			policies: []Policy{
+				{
+					Name: "multiline-policy",
+					Rules: `path "secret/data/__accessor__kubernetes/*" {
+  capabilities = ["read", "list"]
+}`,
+				},
+			},
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+			},
+			description: "Should handle multiline HCL formatting",
+		},
+		{
+			name: "JSON policy rules (not HCL)",
+			policies: []Policy{
+				{
+					Name:  "json-policy",
+					Rules: `{"path": {"auth/__accessor__kubernetes/role": {"capabilities": ["read"]}}}`,
+				},
+			},
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+			},
+			description: "Should handle JSON-formatted policies (HCL formatter fails but parsing succeeds)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := initPoliciesConfig(tt.policies, tt.mounts)
+			assert.NoError(t, err, tt.description)
+
+			// Verify RulesFormatted is populated
+			assert.NotEmpty(t, result[0].RulesFormatted, "RulesFormatted should be set")
+
+			// Verify it's valid (can be parsed as HCL or JSON)
+			_, err = hcl.Parse(result[0].RulesFormatted)
+			assert.NoError(t, err, "RulesFormatted should be valid HCL or JSON")
+		})
+	}
+}
+
+func TestInitPoliciesConfig_InvalidRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		policies      []Policy
+		mounts        map[string]*api.MountOutput
+		expectedError string
+		description   string
+	}{
+		{
+			name: "invalid HCL syntax",
+			policies: []Policy{
+				{
+					Name:  "invalid-policy",
+					Rules: `path "auth/kubernetes/role" { this is not valid HCL }`,
+				},
+			},
+			mounts:        map[string]*api.MountOutput{},
+			expectedError: "parsing invalid-policy policy rules",
+			description:   "Should return error for invalid HCL syntax",
+		},
+		{
+			name: "completely malformed rules",
+			policies: []Policy{
+				{
+					Name:  "malformed-policy",
+					Rules: `{{{{{ not valid at all`,
+				},
+			},
+			mounts:        map[string]*api.MountOutput{},
+			expectedError: "parsing malformed-policy policy rules",
+			description:   "Should return error for completely malformed rules",
+		},
+		{
+			name: "empty rules",
+			policies: []Policy{
+				{
+					Name:  "empty-policy",
+					Rules: ``,
+				},
+			},
+			mounts:        map[string]*api.MountOutput{},
+			expectedError: "",
+			description:   "Should handle empty rules without error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := initPoliciesConfig(tt.policies, tt.mounts)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err, tt.description)
+				assert.Contains(t, err.Error(), tt.expectedError, tt.description)
+				assert.Nil(t, result, "Result should be nil on error")
+			} else {
+				assert.NoError(t, err, tt.description)
+				assert.NotNil(t, result, "Result should not be nil")
+			}
+		})
+	}
+}
+
+func TestValidatePolicyRules(t *testing.T) {
+	tests := []struct {
+		name          string
+		rules         string
+		expectedError string
+	}{
+		{
+			name: "valid HCL",
+			rules: `path "secret/data/+/config" {
+  capabilities = ["read", "list"]
+}
+path "secret/*" {
+  capabilities = ["deny"]
+}`,
+		},
+		{
+			name:  "valid JSON",
+			rules: `{"path": {"secret/*": {"capabilities": ["read"]}}}`,
+		},
+		{
+			name: "unknown capability",
+			rules: `path "secret/*" {
+  capabilities = ["read",
+    "wirte"]
+}`,
+			expectedError: `line 3: unknown capability "wirte" for path "secret/*"`,
+		},
+		{
+			name:          "glob in the middle",
+			rules:         `path "secret/*/config" { capabilities = ["read"] }`,
+			expectedError: `line 1: path "secret/*/config" may only contain '*' as its last character`,
+		},
+		{
+			name:          "partial segment wildcard",
+			rules:         `path "secret/foo+/config" { capabilities = ["read"] }`,
+			expectedError: `line 1: path "secret/foo+/config" may only contain '+' as a whole path segment`,
+		},
+		{
+			name:          "capabilities not a list",
+			rules:         `path "secret/*" { capabilities = "read" }`,
+			expectedError: `line 1: capabilities of path "secret/*" must be a list`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePolicyRules(tt.rules)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestInitPoliciesConfig_ControlGroups(t *testing.T) {
+	policies := []Policy{
+		{
+			Name:  "control-group",
+			Rules: `path "secret/data/dev/*" { capabilities = ["read"] }`,
+			ControlGroups: []ControlGroup{
+				{
+					Path:         "secret/data/prod/*",
+					Capabilities: []string{"read"},
+					TTL:          "4h",
+					Factors: []ControlGroupFactor{
+						{Name: "managers", GroupNames: []string{"managers"}, Approvals: 2},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := initPoliciesConfig(policies, map[string]*api.MountOutput{})
+	assert.NoError(t, err)
+
+	file, err := hcl.Parse(result[0].RulesFormatted)
+	assert.NoError(t, err)
+	assert.NotNil(t, file)
+	assert.Contains(t, result[0].RulesFormatted, `factor "managers"`)
+	assert.Contains(t, result[0].RulesFormatted, `group_names = ["managers"]`)
+
+	_, err = initPoliciesConfig([]Policy{{
+		Name:          "invalid-control-group",
+		ControlGroups: []ControlGroup{{Path: "secret/*"}},
+	}}, map[string]*api.MountOutput{})
+	assert.EqualError(t, err, "rendering invalid-control-group policy control groups: control group of path secret/* must have at least one factor")
+}
+
+func TestConfigurePoliciesKeepsConfig(t *testing.T) {
+	var written []string
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/sys/auth":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+		case r.URL.Path == "/v1/sys/policies/acl/prod":
+			var body map[string]string
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			written = append(written, body["policy"])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	rules := `path "secret/*" { capabilities = ["read"] }`
+	v.externalConfig.Policies = []Policy{{
+		Name:  "prod",
+		Rules: rules,
+		ControlGroups: []ControlGroup{{
+			Path:         "secret/data/prod/*",
+			Capabilities: []string{"read"},
+			Factors:      []ControlGroupFactor{{Name: "managers", GroupNames: []string{"managers"}, Approvals: 1}},
+		}},
+	}}
+
+	// The control groups are rendered once per run, not appended to the config again and again
+	require.NoError(t, v.configurePolicies(context.Background()))
+	require.NoError(t, v.configurePolicies(context.Background()))
+	require.Len(t, written, 2)
+	assert.Equal(t, written[0], written[1])
+	assert.Equal(t, 1, strings.Count(written[1], `factor "managers"`))
+	assert.Equal(t, rules, v.externalConfig.Policies[0].Rules)
+}
+
+func TestResolveIdentityPlaceholders(t *testing.T) {
+	v := newVerifyVault(t, map[string]interface{}{
+		"/v1/identity/entity/name/alice": map[string]interface{}{"id": "e-1"},
+		"/v1/identity/group/name/admins": map[string]interface{}{"id": "g-1"},
+		"/v1/identity/entity/name/bob":   map[string]interface{}{"name": "bob"},
+	}, nil)
+
+	policies := []Policy{{Name: "owners", Rules: `path "secret/__entity_id__alice/*" { capabilities = ["read"] }
+path "secret/__group_id__admins/*" { capabilities = ["read"] }`}}
+	require.NoError(t, v.resolveIdentityPlaceholders(policies))
+	assert.Equal(t, `path "secret/e-1/*" { capabilities = ["read"] }
+path "secret/g-1/*" { capabilities = ["read"] }`, policies[0].Rules)
+
+	// A lookup without an id is an error, not a panic
+	policies = []Policy{{Name: "bob", Rules: `path "secret/__entity_id__bob/*" { capabilities = ["read"] }`}}
+	assert.NotPanics(t, func() {
+		assert.Error(t, v.resolveIdentityPlaceholders(policies))
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+)
+
+// Reconciler configures a custom section of the external config, e.g. the resources of a
+// company-specific plugin. The resources are keyed by their path, which identifies them in the report.
+type Reconciler interface {
+	// Desired returns the resources the section of the external config describes
+	Desired(ctx context.Context, section interface{}) (map[string]interface{}, error)
+	// Existing returns the resources in Vault, to compare with the desired ones
+	Existing(ctx context.Context, client *api.Client) (map[string]interface{}, error)
+	// Apply creates or updates a desired resource which differs from the existing one
+	Apply(ctx context.Context, client *api.Client, path string, resource interface{}) error
+	// Purge removes an existing resource which isn't desired, if the purge config allows it
+	Purge(ctx context.Context, client *api.Client, path string) error
+}
+
+var (
+	reconcilersMu sync.RWMutex
+	reconcilers   = map[string]Reconciler{}
+)
+
+// RegisterReconciler makes configure reconcile the section of the external config with the reconciler,
+// after the built-in sections. Sections are reconciled in the order of their names, the configurer token
+// doesn't cover them. It panics if the section is a built-in one or registered twice.
+func RegisterReconciler(section string, reconciler Reconciler) {
+	reconcilersMu.Lock()
+	defer reconcilersMu.Unlock()
+
+	if reconciler == nil {
+		panic("vault: reconciler of section " + section + " is nil")
+	}
+	if slices.Contains(builtinSections(), section) {
+		panic("vault: section " + section + " is a built-in one")
+	}
+	if _, ok := reconcilers[section]; ok {
+		panic("vault: reconciler registered twice for section " + section)
+	}
+	reconcilers[section] = reconciler
+}
+
+// registeredReconcilers returns a copy of the reconcilers by section.
+func registeredReconcilers() map[string]Reconciler {
+	reconcilersMu.RLock()
+	defer reconcilersMu.RUnlock()
+
+	return maps.Clone(reconcilers)
+}
+
+// builtinSections returns the keys of the external config which aren't custom sections.
+func builtinSections() []string {
+	t := reflect.TypeFor[ExternalConfig]()
+	sections := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("mapstructure"), ","); tag != "" {
+			sections = append(sections, tag)
+		}
+	}
+
+	return sections
+}
+
+// splitCustomSections returns the config without the sections of the registered reconcilers, and those sections.
+func splitCustomSections(config map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	registered := registeredReconcilers()
+
+	builtin := make(map[string]interface{}, len(config))
+	custom := map[string]interface{}{}
+	for key, value := range config {
+		if _, ok := registered[key]; ok {
+			custom[key] = value
+		} else {
+			builtin[key] = value
+		}
+	}
+
+	return builtin, custom
+}
+
+// configureCustomSections reconciles the custom sections of the config with their reconcilers.
+func (v *vault) configureCustomSections(ctx context.Context) error {
+	registered := registeredReconcilers()
+	for _, section := range slices.Sorted(maps.Keys(v.externalConfig.custom)) {
+		err := v.traceSection(ctx, section, func(ctx context.Context) error {
+			return v.reconcileSection(ctx, section, registered[section])
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error configuring %s for vault", section)
+		}
+	}
+
+	return nil
+}
+
+// reconcileSection applies the desired resources of a custom section which differ from the existing ones,
+// and purges the existing ones which aren't desired.
+func (v *vault) reconcileSection(ctx context.Context, section string, reconciler Reconciler) error {
+	desired, err := reconciler.Desired(ctx, v.externalConfig.custom[section])
+	if err != nil {
+		return errors.Wrap(err, "error reading the desired resources")
+	}
+
+	existing, err := reconciler.Existing(ctx, v.cl)
+	if err != nil {
+		return errors.Wrap(err, "error reading the existing resources")
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(desired)) {
+		current, exists := existing[path]
+		if exists && reflect.DeepEqual(current, desired[path]) {
+			v.report.skipped(section, path)
+			continue
+		}
+
+		v.log().Info("applying resource", "section", section, "path", path)
+		if err := reconciler.Apply(ctx, v.cl, path, desired[path]); err != nil {
+			if err := v.itemFailed(section, path, errors.Wrapf(err, "error applying %s", path)); err != nil {
+				return err
+			}
+
+			continue
+		}
+		if exists {
+			v.report.updated(section, path)
+		} else {
+			v.report.created(section, path)
+		}
+		v.recordWrite(AuditOperationWrite, path, nil)
+	}
+
+	unmanaged := unmanagedPaths(desired, existing)
+	v.report.unmanaged(section, unmanaged)
+
+	if !v.purges(slices.Contains(v.externalConfig.PurgeUnmanagedConfig.Exclude.Sections, section)) {
+		v.log().Debug("purge config is disabled, no unmanaged resources will be removed", "section", section)
+		return nil
+	}
+
+	for _, path := range unmanaged {
+		v.log().Info("removing unmanaged resource", "section", section, "path", path)
+		if err := reconciler.Purge(ctx, v.cl, path); err != nil {
+			if err := v.itemFailed(section, path, errors.Wrapf(err, "error removing %s", path)); err != nil {
+				return err
+			}
+
+			continue
+		}
+		v.resourcePurged(ctx, section, path)
+	}
+
+	return nil
+}
+
+// verifyCustomSections compares the custom sections of the config with Vault.
+func (v *vault) verifyCustomSections(ctx context.Context) ([]Drift, error) {
+	registered := registeredReconcilers()
+
+	var drifts []Drift
+	for _, section := range slices.Sorted(maps.Keys(v.externalConfig.custom)) {
+		reconciler := registered[section]
+
+		desired, err := reconciler.Desired(ctx, v.externalConfig.custom[section])
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading the desired resources of %s", section)
+		}
+		existing, err := reconciler.Existing(ctx, v.cl)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading the existing resources of %s", section)
+		}
+
+		for _, path := range slices.Sorted(maps.Keys(desired)) {
+			current, exists := existing[path]
+			switch {
+			case !exists:
+				drifts = append(drifts, Drift{Section: section, Path: path, Reason: DriftMissing})
+			case !reflect.DeepEqual(current, desired[path]):
+				drifts = append(drifts, Drift{Section: section, Path: path, Reason: DriftChanged})
+			}
+		}
+
+		if v.purges(slices.Contains(v.externalConfig.PurgeUnmanagedConfig.Exclude.Sections, section)) {
+			drifts = append(drifts, unmanagedDrifts(section, unmanagedPaths(desired, existing))...)
+		}
+	}
+
+	return drifts, nil
+}
+
+// unmanagedPaths returns the sorted paths of the existing resources which aren't desired.
+func unmanagedPaths(desired, existing map[string]interface{}) []string {
+	var paths []string
+	for path := range existing {
+		if _, ok := desired[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+
+	return paths
+}
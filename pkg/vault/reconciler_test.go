@@ -0,0 +1,122 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/spf13/cast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// widgetReconciler keeps its resources in memory instead of Vault.
+type widgetReconciler struct {
+	widgets map[string]interface{}
+}
+
+func (r *widgetReconciler) Desired(_ context.Context, section interface{}) (map[string]interface{}, error) {
+	desired := map[string]interface{}{}
+	for _, widget := range cast.ToSlice(section) {
+		widget := cast.ToStringMap(widget)
+		name := cast.ToString(widget["name"])
+		if name == "" {
+			return nil, errors.New("widget name is required")
+		}
+		desired["widgets/"+name] = cast.ToString(widget["color"])
+	}
+
+	return desired, nil
+}
+
+func (r *widgetReconciler) Existing(context.Context, *api.Client) (map[string]interface{}, error) {
+	existing := map[string]interface{}{}
+	for path, widget := range r.widgets {
+		existing[path] = widget
+	}
+
+	return existing, nil
+}
+
+func (r *widgetReconciler) Apply(_ context.Context, _ *api.Client, path string, resource interface{}) error {
+	r.widgets[path] = resource
+	return nil
+}
+
+func (r *widgetReconciler) Purge(_ context.Context, _ *api.Client, path string) error {
+	delete(r.widgets, path)
+	return nil
+}
+
+func TestReconciler(t *testing.T) {
+	reconciler := &widgetReconciler{widgets: map[string]interface{}{
+		"widgets/blue":    "blue",
+		"widgets/green":   "red",
+		"widgets/orphan":  "black",
+		"widgets/ignored": "white",
+	}}
+	RegisterReconciler("widgets", reconciler)
+
+	assert.Panics(t, func() { RegisterReconciler("widgets", reconciler) })
+	assert.Panics(t, func() { RegisterReconciler(SectionPolicies, reconciler) })
+	assert.Panics(t, func() { RegisterReconciler("purgeUnmanagedConfig", reconciler) })
+
+	config := map[string]interface{}{
+		"purgeUnmanagedConfig": map[string]interface{}{"enabled": true},
+		"widgets": []interface{}{
+			map[string]interface{}{"name": "blue", "color": "blue"},
+			map[string]interface{}{"name": "green", "color": "green"},
+			map[string]interface{}{"name": "red", "color": "red"},
+		},
+	}
+	assert.Empty(t, ValidateConfig(config))
+	assert.Len(t, ValidateConfig(map[string]interface{}{"widgets": []interface{}{map[string]interface{}{"color": "red"}}}), 1)
+
+	v := newTestVault(t, http.NotFoundHandler())
+	loadedConfig, err := v.loadExternalConfig(config)
+	require.NoError(t, err)
+	v.externalConfig = loadedConfig
+
+	drifts, err := v.verifyCustomSections(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []Drift{
+		{Section: "widgets", Path: "widgets/green", Reason: DriftChanged},
+		{Section: "widgets", Path: "widgets/red", Reason: DriftMissing},
+		{Section: "widgets", Path: "widgets/ignored", Reason: DriftUnmanaged},
+		{Section: "widgets", Path: "widgets/orphan", Reason: DriftUnmanaged},
+	}, drifts)
+
+	require.NoError(t, v.configureCustomSections(context.Background()))
+
+	assert.Equal(t, map[string]interface{}{"widgets/blue": "blue", "widgets/green": "green", "widgets/red": "red"}, reconciler.widgets)
+	section := v.report.Sections["widgets"]
+	assert.Equal(t, []string{"widgets/blue"}, section.Skipped)
+	assert.Equal(t, []string{"widgets/green"}, section.Updated)
+	assert.Equal(t, []string{"widgets/red"}, section.Created)
+	assert.Equal(t, []string{"widgets/ignored", "widgets/orphan"}, section.Purged)
+
+	// Excluded sections are not purged
+	reconciler.widgets["widgets/orphan"] = "black"
+	v.externalConfig.PurgeUnmanagedConfig.Exclude.Sections = []string{"widgets"}
+	v.report = newReport()
+	require.NoError(t, v.configureCustomSections(context.Background()))
+	assert.Equal(t, []string{"widgets/orphan"}, v.report.Sections["widgets"].Unmanaged)
+	assert.Empty(t, v.report.Sections["widgets"].Purged)
+	assert.Contains(t, reconciler.widgets, "widgets/orphan")
+}
@@ -0,0 +1,103 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+const redactedValue = "<redacted>"
+
+// DefaultRedactedFields are the names of the fields redacted from the logged config payloads by default.
+var DefaultRedactedFields = []string{
+	"password",
+	"bindpass",
+	"secret",
+	"secret_key",
+	"secret_id",
+	"client_secret",
+	"private_key",
+	"pem_bundle",
+	"credentials",
+	"token",
+	"session_token",
+	"hmac_key",
+}
+
+// redactedPayload logs a config payload with the values of the sensitive fields masked.
+// The payload is only walked if the log record is actually written.
+type redactedPayload struct {
+	value  interface{}
+	fields []string
+}
+
+// redact returns the payload to log in place of value, masking the fields of the denylist.
+func (v *vault) redact(value interface{}) slog.LogValuer {
+	fields := DefaultRedactedFields
+	if v.config != nil && v.config.RedactFields != nil {
+		fields = v.config.RedactFields
+	}
+
+	return RedactedPayload(value, fields)
+}
+
+// RedactedPayload returns the payload to log in place of value, masking the values of the given fields.
+func RedactedPayload(value interface{}, fields []string) slog.LogValuer {
+	return redactedPayload{value: value, fields: fields}
+}
+
+func (p redactedPayload) LogValue() slog.Value {
+	// Round-trip through JSON to walk structs (e.g. the API inputs) like maps
+	data, err := json.Marshal(p.value)
+	if err != nil {
+		return slog.StringValue(redactedValue)
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return slog.StringValue(redactedValue)
+	}
+
+	return slog.AnyValue(redactFields(generic, p.fields))
+}
+
+// redactFields masks the values of the fields of the denylist, matched case-insensitively, in nested maps and slices.
+func redactFields(value interface{}, fields []string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(value))
+		for k, v := range value {
+			if slices.ContainsFunc(fields, func(field string) bool { return strings.EqualFold(field, k) }) {
+				redacted[k] = redactedValue
+			} else {
+				redacted[k] = redactFields(v, fields)
+			}
+		}
+
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(value))
+		for i, v := range value {
+			redacted[i] = redactFields(v, fields)
+		}
+
+		return redacted
+	default:
+		return value
+	}
+}
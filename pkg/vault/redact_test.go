@@ -0,0 +1,55 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactConfigPayload(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	v := &vault{config: &Config{}}
+	input := api.MountInput{
+		Type: "database",
+		Options: map[string]string{
+			"version":  "2",
+			"Password": "hunter2",
+		},
+		Config: api.MountConfigInput{
+			Options: map[string]string{"client_secret": "s3cr3t"},
+		},
+	}
+	logger.Debug("secret engine input", "input", v.redact(input))
+
+	assert.Contains(t, buf.String(), `"version":"2"`)
+	assert.Contains(t, buf.String(), `"Password":"<redacted>"`)
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), "s3cr3t")
+}
+
+func TestRedactCustomFields(t *testing.T) {
+	v := &vault{config: &Config{RedactFields: []string{"api_key"}}}
+
+	value := v.redact(map[string]interface{}{"api_key": "key", "password": "visible"}).LogValue().Any()
+
+	assert.Equal(t, map[string]interface{}{"api_key": redactedValue, "password": "visible"}, value)
+}
@@ -0,0 +1,298 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Config sections, used to group the resources in the apply report.
+const (
+	SectionLicense        = "license"
+	SectionAudit          = "audit"
+	SectionPlugins        = "plugins"
+	SectionAuth           = "auth"
+	SectionGroups         = "groups"
+	SectionPolicies       = "policies"
+	SectionSecrets        = "secrets"
+	SectionStartupSecrets = "startupSecrets"
+)
+
+// ReportSection holds what happened to the resources of a single config section during a configure run.
+type ReportSection struct {
+	Created []string `json:"created,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Skipped []string `json:"skipped,omitempty"`
+	Purged  []string `json:"purged,omitempty"`
+	// resources in Vault which are not in the config, whether they are purged or not
+	Unmanaged []string `json:"unmanaged,omitempty"`
+	// errors of the items skipped in continue-on-error mode, by path
+	Failed   map[string]string `json:"failed,omitempty"`
+	Duration time.Duration     `json:"duration"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// Report is a machine-readable summary of a single configure run.
+type Report struct {
+	StartTime time.Time                 `json:"startTime"`
+	Duration  time.Duration             `json:"duration"`
+	Skipped   bool                      `json:"skipped"`
+	Sections  map[string]*ReportSection `json:"sections"`
+	Error     string                    `json:"error,omitempty"`
+
+	// identity of the applied config
+	ConfigHash    string `json:"configHash,omitempty"`
+	ConfigVersion string `json:"configVersion,omitempty"`
+
+	// result of renewing the token configure logged in with, empty if it wasn't due
+	TokenRenewal string `json:"tokenRenewal,omitempty"`
+
+	// number of the errors of the run (the failed items and the error aborting it), by category
+	ErrorCategories map[string]int `json:"errorCategories,omitempty"`
+
+	// the first error of the run, a failed item or the error aborting it
+	firstError string
+
+	mu sync.Mutex
+}
+
+func newReport() *Report {
+	return &Report{
+		StartTime: time.Now(),
+		Sections:  map[string]*ReportSection{},
+	}
+}
+
+// snapshot returns a copy of the report, which can be read while the run goes on, e.g. by the hooks.
+func (r *Report) snapshot() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sections := make(map[string]*ReportSection, len(r.Sections))
+	for name, section := range r.Sections {
+		sections[name] = &ReportSection{
+			Created:   slices.Clone(section.Created),
+			Updated:   slices.Clone(section.Updated),
+			Skipped:   slices.Clone(section.Skipped),
+			Purged:    slices.Clone(section.Purged),
+			Unmanaged: slices.Clone(section.Unmanaged),
+			Failed:    maps.Clone(section.Failed),
+			Duration:  section.Duration,
+			Error:     section.Error,
+		}
+	}
+
+	return &Report{
+		StartTime:       r.StartTime,
+		Duration:        r.Duration,
+		Skipped:         r.Skipped,
+		Sections:        sections,
+		Error:           r.Error,
+		ConfigHash:      r.ConfigHash,
+		ConfigVersion:   r.ConfigVersion,
+		TokenRenewal:    r.TokenRenewal,
+		ErrorCategories: maps.Clone(r.ErrorCategories),
+		firstError:      r.firstError,
+	}
+}
+
+func (r *Report) section(name string) *ReportSection {
+	s, ok := r.Sections[name]
+	if !ok {
+		s = &ReportSection{}
+		r.Sections[name] = s
+	}
+
+	return s
+}
+
+func (r *Report) created(section, path string) {
+	r.record(section, func(s *ReportSection) { s.Created = append(s.Created, path) })
+}
+
+func (r *Report) updated(section, path string) {
+	r.record(section, func(s *ReportSection) { s.Updated = append(s.Updated, path) })
+}
+
+func (r *Report) skipped(section, path string) {
+	r.record(section, func(s *ReportSection) { s.Skipped = append(s.Skipped, path) })
+}
+
+func (r *Report) purged(section, path string) {
+	r.record(section, func(s *ReportSection) { s.Purged = append(s.Purged, path) })
+}
+
+func (r *Report) unmanaged(section string, paths []string) {
+	r.record(section, func(s *ReportSection) { s.Unmanaged = paths })
+}
+
+func (r *Report) failed(section, path string, err error) {
+	r.record(section, func(s *ReportSection) {
+		if s.Failed == nil {
+			s.Failed = map[string]string{}
+		}
+		s.Failed[path] = err.Error()
+		r.countError(err)
+		if r.firstError == "" {
+			r.firstError = fmt.Sprintf("%s %s: %s", section, path, err.Error())
+		}
+	})
+}
+
+// countError counts an error by category, the caller must hold the lock.
+func (r *Report) countError(err error) {
+	if r.ErrorCategories == nil {
+		r.ErrorCategories = map[string]int{}
+	}
+	r.ErrorCategories[errorCategory(err)]++
+}
+
+func (r *Report) tokenRenewal(result string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.TokenRenewal = result
+}
+
+// failures lists the items which failed in continue-on-error mode.
+func (r *Report) failures() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var failures []string
+	for _, name := range slices.Sorted(maps.Keys(r.Sections)) {
+		section := r.Sections[name]
+		for _, path := range slices.Sorted(maps.Keys(section.Failed)) {
+			failures = append(failures, fmt.Sprintf("%s %s: %s", name, path, section.Failed[path]))
+		}
+	}
+
+	return failures
+}
+
+// itemFailed returns the error of a single config item to abort the run, or records it
+// and returns nil to skip the item when continue-on-error is enabled.
+func (v *vault) itemFailed(section, path string, err error) error {
+	if err == nil || v.config == nil || !v.config.ContinueOnError {
+		return err
+	}
+
+	v.log().Error("skipping failed config item", "section", section, "path", path, "error", err)
+	v.report.failed(section, path, err)
+
+	return nil
+}
+
+// record updates a section of the report, it is a no-op on a nil report.
+func (r *Report) record(section string, fn func(s *ReportSection)) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	fn(r.section(section))
+}
+
+// track runs the configuration of a section, recording its duration and error.
+func (r *Report) track(section string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+
+	r.record(section, func(s *ReportSection) {
+		s.Duration = time.Since(start)
+		if err != nil {
+			s.Error = err.Error()
+		}
+	})
+
+	return err
+}
+
+func (r *Report) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Duration = time.Since(r.StartTime)
+	if err != nil {
+		r.Error = err.Error()
+		// The failed items were counted already
+		if !IsPartialFailure(err) {
+			r.countError(err)
+		}
+		if r.firstError == "" {
+			r.firstError = err.Error()
+		}
+	}
+}
+
+// summary returns the attributes of the single summary log record of a run, so log-based
+// alerts and dashboards don't have to piece the outcome of a run together from its logs.
+func (r *Report) summary() []slog.Attr {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := "success"
+	switch {
+	case r.Error != "":
+		result = "failure"
+	case r.Skipped:
+		result = "skipped"
+	}
+
+	var created, updated, skipped, purged, unmanaged, failed int
+	durations := make([]slog.Attr, 0, len(r.Sections))
+	for _, name := range slices.Sorted(maps.Keys(r.Sections)) {
+		section := r.Sections[name]
+		created += len(section.Created)
+		updated += len(section.Updated)
+		skipped += len(section.Skipped)
+		purged += len(section.Purged)
+		unmanaged += len(section.Unmanaged)
+		failed += len(section.Failed)
+		durations = append(durations, slog.Duration(name, section.Duration))
+	}
+
+	attrs := []slog.Attr{
+		slog.String("result", result),
+		slog.Duration("duration", r.Duration),
+		slog.Int("created", created),
+		slog.Int("updated", updated),
+		slog.Int("skipped", skipped),
+		slog.Int("purged", purged),
+		slog.Int("unmanaged", unmanaged),
+		slog.Int("failed", failed),
+		slog.Attr{Key: "sectionDurations", Value: slog.GroupValue(durations...)},
+	}
+	if r.ConfigHash != "" {
+		attrs = append(attrs, slog.String("configHash", r.ConfigHash))
+	}
+	if r.firstError != "" {
+		attrs = append(attrs, slog.String("firstError", r.firstError))
+	}
+
+	return attrs
+}
@@ -0,0 +1,174 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReport(t *testing.T) {
+	r := newReport()
+
+	r.created(SectionSecrets, "kv")
+	r.updated(SectionSecrets, "kv/config")
+	r.skipped(SectionAudit, "file")
+	r.purged(SectionPolicies, "old")
+
+	err := r.track(SectionAuth, func() error { return errors.New("boom") })
+	assert.EqualError(t, err, "boom")
+
+	r.finish(err)
+
+	assert.Equal(t, []string{"kv"}, r.Sections[SectionSecrets].Created)
+	assert.Equal(t, []string{"kv/config"}, r.Sections[SectionSecrets].Updated)
+	assert.Equal(t, []string{"file"}, r.Sections[SectionAudit].Skipped)
+	assert.Equal(t, []string{"old"}, r.Sections[SectionPolicies].Purged)
+	assert.Equal(t, "boom", r.Sections[SectionAuth].Error)
+	assert.Equal(t, "boom", r.Error)
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"created":["kv"]`)
+}
+
+func TestReport_Nil(t *testing.T) {
+	var r *Report
+
+	assert.NotPanics(t, func() {
+		r.created(SectionSecrets, "kv")
+		assert.NoError(t, r.track(SectionSecrets, func() error { return nil }))
+	})
+}
+
+func TestReport_ItemFailed(t *testing.T) {
+	v := &vault{config: &Config{ContinueOnError: true}, report: newReport()}
+
+	assert.NoError(t, v.itemFailed(SectionSecrets, "database", errors.New("connection refused")))
+	assert.NoError(t, v.itemFailed(SectionPolicies, "admin", nil))
+	assert.Equal(t, []string{"secrets database: connection refused"}, v.report.failures())
+
+	v.config.ContinueOnError = false
+	assert.EqualError(t, v.itemFailed(SectionSecrets, "database", errors.New("connection refused")), "connection refused")
+}
+
+func TestReport_Summary(t *testing.T) {
+	r := newReport()
+	r.created(SectionPolicies, "admin")
+	r.created(SectionPolicies, "reader")
+	r.updated(SectionAudit, "file")
+	r.failed(SectionSecrets, "database", errors.New("connection refused"))
+	r.failed(SectionSecrets, "aws", errors.New("permission denied"))
+	r.finish(failedItemsError(r.failures()))
+
+	summary := map[string]string{}
+	for _, attr := range r.summary() {
+		summary[attr.Key] = attr.Value.String()
+	}
+
+	assert.Equal(t, "failure", summary["result"])
+	assert.Equal(t, "2", summary["created"])
+	assert.Equal(t, "1", summary["updated"])
+	assert.Equal(t, "2", summary["failed"])
+	assert.Equal(t, "secrets database: connection refused", summary["firstError"])
+	assert.Contains(t, summary, "sectionDurations")
+}
+
+func TestReport_ItemFailedAuditDevice(t *testing.T) {
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/sys/audit":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+		case "/v1/sys/audit/broken":
+			http.Error(w, `{"errors":["invalid options"]}`, http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	v.config = &Config{ContinueOnError: true}
+	v.externalConfig = &ExternalConfig{Audit: []AuditDevice{
+		{Type: "file", Path: "broken"},
+		{Type: "file", Path: "file"},
+	}}
+
+	require.NoError(t, v.configureAuditDevices(context.Background()))
+	assert.Contains(t, v.report.Sections[SectionAudit].Failed, "broken")
+	assert.Equal(t, []string{"file"}, v.report.Sections[SectionAudit].Created)
+}
+
+func TestReport_ItemFailedSecretsEngineConfig(t *testing.T) {
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/sys/mounts":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"data":{"database/":{"type":"database"}}}`)) //nolint:errcheck
+		case r.URL.Path == "/v1/database/config/broken":
+			http.Error(w, `{"errors":["connection refused"]}`, http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	v.config = &Config{ContinueOnError: true}
+	engine := SecretsEngine{Type: "database", Path: "database", Configuration: map[string]interface{}{
+		"config": []interface{}{
+			map[string]interface{}{"name": "broken", "plugin_name": "postgresql-database-plugin"},
+			map[string]interface{}{"name": "mysql", "plugin_name": "mysql-database-plugin"},
+		},
+		"roles": "not a list",
+	}}
+
+	// The failed items are skipped, the rest of the engine is configured
+	require.NoError(t, v.addManagedSecretsEngines(context.Background(), []SecretsEngine{engine}, nil))
+	assert.Contains(t, v.report.Sections[SectionSecrets].Failed, "database/config/broken")
+	assert.Contains(t, v.report.Sections[SectionSecrets].Failed, "database/roles")
+	assert.Contains(t, v.report.Sections[SectionSecrets].Updated, "database/config/mysql")
+	assert.NotContains(t, v.report.Sections[SectionSecrets].Failed, "database")
+
+	v.config.ContinueOnError = false
+	delete(engine.Configuration, "roles")
+	assert.ErrorContains(t, v.addManagedSecretsEngines(context.Background(), []SecretsEngine{engine}, nil), "error configuring database/config/broken config")
+}
+
+func TestReport_ItemFailedPurge(t *testing.T) {
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/broken") {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	v.config = &Config{ContinueOnError: true}
+	v.externalConfig.PurgeUnmanagedConfig.Enabled = true
+
+	require.NoError(t, v.removeUnmanagedSecretsEngines(context.Background(), map[string]bool{"broken": true, "kv": true}))
+	assert.Contains(t, v.report.Sections[SectionSecrets].Failed, "broken")
+	assert.Equal(t, []string{"kv"}, v.report.Sections[SectionSecrets].Purged)
+
+	require.NoError(t, v.removeUnmanagedAuthMethods(context.Background(), map[string]*api.MountOutput{"broken": {}, "github": {}}))
+	assert.Contains(t, v.report.Sections[SectionAuth].Failed, "broken")
+	assert.Equal(t, []string{"github"}, v.report.Sections[SectionAuth].Purged)
+
+	v.config.ContinueOnError = false
+	assert.ErrorContains(t, v.removeUnmanagedSecretsEngines(context.Background(), map[string]bool{"broken": true}), "error unmounting broken secret engine")
+}
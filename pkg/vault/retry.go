@@ -0,0 +1,185 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/jpillora/backoff"
+)
+
+// retrySections are the config sections whose Vault writes are retried, only they accept a retry policy override.
+var retrySections = []string{SectionAuth, SectionSecrets}
+
+// RetryPolicy configures how failing Vault requests are retried.
+type RetryPolicy struct {
+	// maximum number of attempts, 0 retries until the backoff reaches MaxBackoff
+	Attempts   int           `mapstructure:"attempts"`
+	MinBackoff time.Duration `mapstructure:"minBackoff"`
+	MaxBackoff time.Duration `mapstructure:"maxBackoff"`
+	// overall time limit of the retries, 0 means no limit
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	budget *RetryBudget
+}
+
+// DefaultRetryPolicy is used when no retry policy is configured.
+var DefaultRetryPolicy = RetryPolicy{
+	MinBackoff: 500 * time.Millisecond,
+	MaxBackoff: 60 * time.Second,
+}
+
+// merge returns the policy with the non-zero fields of the override applied.
+func (p RetryPolicy) merge(override RetryPolicy) RetryPolicy {
+	if override.Attempts != 0 {
+		p.Attempts = override.Attempts
+	}
+	if override.MinBackoff != 0 {
+		p.MinBackoff = override.MinBackoff
+	}
+	if override.MaxBackoff != 0 {
+		p.MaxBackoff = override.MaxBackoff
+	}
+	if override.Timeout != 0 {
+		p.Timeout = override.Timeout
+	}
+
+	return p
+}
+
+// validateRetryOverrides rejects retry policy overrides of sections which are not retried,
+// so they are not silently ignored.
+func validateRetryOverrides(overrides map[string]RetryPolicy) error {
+	for _, section := range slices.Sorted(maps.Keys(overrides)) {
+		if !slices.Contains(retrySections, section) {
+			return errors.Errorf("retry policy of section '%s' is not supported, only %v can be overridden", section, retrySections)
+		}
+	}
+
+	return nil
+}
+
+// retryPolicy returns the retry policy of a config section: the global policy overridden by the section's one.
+func (v *vault) retryPolicy(section string) RetryPolicy {
+	policy := DefaultRetryPolicy
+	if v.config != nil {
+		policy = policy.merge(v.config.Retry)
+		policy.budget = v.config.RetryBudget
+	}
+	if v.externalConfig != nil {
+		policy = policy.merge(v.externalConfig.Retry[section])
+	}
+
+	return policy
+}
+
+// retry calls fn until it succeeds or the policy gives up, returning the last error. The retries are logged to log.
+func (p RetryPolicy) retry(ctx context.Context, log *slog.Logger, description string, fn func() error) error {
+	b := &backoff.Backoff{
+		Min:    p.MinBackoff,
+		Max:    p.MaxBackoff,
+		Factor: 2,
+		Jitter: false,
+	}
+
+	var deadline time.Time
+	if p.Timeout > 0 {
+		deadline = time.Now().Add(p.Timeout)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil {
+			return nil
+		}
+
+		d := b.Duration()
+		switch {
+		case p.Attempts > 0 && attempt >= p.Attempts,
+			p.Attempts == 0 && d == b.Max,
+			!deadline.IsZero() && time.Now().Add(d).After(deadline):
+			return err
+		}
+		if !p.budget.Withdraw() {
+			log.Warn("request failed, not trying again since the retry budget is exhausted", "request", description, "error", err)
+			return err
+		}
+
+		log.Info("request failed, waiting before trying again", "request", description, "error", err, "backoff", d)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(d):
+		}
+	}
+}
+
+// RetryBudget caps the retries of the Vault requests to a ratio of the requests sent, so the retries of
+// a large reconcile cannot pile up on an overloaded Vault. It is safe for concurrent use, a nil budget
+// allows every retry.
+type RetryBudget struct {
+	mu       sync.Mutex
+	ratio    float64
+	capacity float64
+	balance  float64
+}
+
+// NewRetryBudget returns a budget allowing ratio retries per request sent, and bursts of at most
+// reserve retries, which are available from the start.
+func NewRetryBudget(ratio float64, reserve int) *RetryBudget {
+	capacity := max(float64(reserve), 1)
+
+	return &RetryBudget{
+		ratio:    ratio,
+		capacity: capacity,
+		balance:  float64(reserve),
+	}
+}
+
+// Deposit records a request sent to Vault, earning ratio retries.
+func (b *RetryBudget) Deposit() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.balance = min(b.balance+b.ratio, b.capacity)
+}
+
+// Withdraw reports whether a failed request may be retried, taking the retry from the budget if so.
+func (b *RetryBudget) Withdraw() bool {
+	if b == nil {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.balance < 1 {
+		return false
+	}
+	b.balance--
+
+	return true
+}
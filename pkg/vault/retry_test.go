@@ -0,0 +1,98 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	v := &vault{
+		config: &Config{Retry: RetryPolicy{Attempts: 5}},
+		externalConfig: &ExternalConfig{
+			Retry: map[string]RetryPolicy{SectionSecrets: {Attempts: 3, MinBackoff: time.Millisecond}},
+		},
+	}
+
+	assert.Equal(t, RetryPolicy{Attempts: 5, MinBackoff: 500 * time.Millisecond, MaxBackoff: 60 * time.Second}, v.retryPolicy(SectionAuth))
+
+	policy := v.retryPolicy(SectionSecrets)
+	assert.Equal(t, RetryPolicy{Attempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 60 * time.Second}, policy)
+
+	var attempts int
+	err := policy.retry(context.Background(), v.log(), "testing", func() error {
+		attempts++
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = policy.retry(context.Background(), v.log(), "testing", func() error {
+		attempts++
+		if attempts < 2 {
+			return errors.New("boom")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestRetryBudget(t *testing.T) {
+	budget := NewRetryBudget(0.5, 2)
+
+	assert.True(t, budget.Withdraw())
+	assert.True(t, budget.Withdraw())
+	assert.False(t, budget.Withdraw())
+
+	budget.Deposit()
+	assert.False(t, budget.Withdraw())
+	budget.Deposit()
+	assert.True(t, budget.Withdraw())
+
+	// The reserve caps the retries saved up
+	for range 10 {
+		budget.Deposit()
+	}
+	assert.True(t, budget.Withdraw())
+	assert.True(t, budget.Withdraw())
+	assert.False(t, budget.Withdraw())
+
+	var unlimited *RetryBudget
+	unlimited.Deposit()
+	assert.True(t, unlimited.Withdraw())
+
+	v := &vault{config: &Config{Retry: RetryPolicy{Attempts: 5, MinBackoff: time.Millisecond}, RetryBudget: NewRetryBudget(0, 1)}}
+
+	var attempts int
+	err := v.retryPolicy(SectionAuth).retry(context.Background(), v.log(), "testing", func() error {
+		attempts++
+		return errors.New("boom")
+	})
+	assert.EqualError(t, err, "boom")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestValidateRetryOverrides(t *testing.T) {
+	assert.NoError(t, validateRetryOverrides(map[string]RetryPolicy{SectionAuth: {Attempts: 2}, SectionSecrets: {Attempts: 3}}))
+	assert.Error(t, validateRetryOverrides(map[string]RetryPolicy{SectionPolicies: {Attempts: 2}}))
+	assert.Error(t, validateRetryOverrides(map[string]RetryPolicy{"secret": {Attempts: 2}}))
+}
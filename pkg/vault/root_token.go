@@ -0,0 +1,55 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import "context"
+
+// revokeRootToken revokes a root token after use when root tokens are never kept, it was either the
+// one of init or generated from the stored unseal keys. Other tokens configure logs in with are kept.
+func (v *vault) revokeRootToken(ctx context.Context, token string) {
+	if !v.config.RevokeRootToken || !v.usesRootToken() || token == "" {
+		return
+	}
+
+	cl, err := v.cl.Clone()
+	if err != nil {
+		v.log().Error("error cloning client to revoke the root token, it stays valid", "error", err)
+		return
+	}
+	cl.SetToken(token)
+
+	// The token stays valid if revoking it is aborted by a shutdown
+	if err := cl.Auth().Token().RevokeSelfWithContext(context.WithoutCancel(ctx), ""); err != nil {
+		v.log().Error("error revoking the root token, it stays valid", "error", err)
+		return
+	}
+
+	v.log().Info("revoked the root token after use")
+}
+
+// RevokeInitRootToken revokes the root token of init if nothing used it yet, it is kept while Vault is
+// sealed. Processes which never configure call it, configure revokes the token after its first use.
+func (v *vault) RevokeInitRootToken(ctx context.Context) {
+	if v.initRootToken == nil {
+		return
+	}
+	if sealed, err := v.Sealed(); err != nil || sealed {
+		return
+	}
+
+	v.revokeRootToken(ctx, v.initRootToken.String())
+	v.initRootToken.Destroy()
+	v.initRootToken = nil
+}
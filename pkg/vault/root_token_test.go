@@ -0,0 +1,112 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newInitVault returns a vault talking to a fake uninitialized Vault with a single unseal key, and the
+// paths and tokens of the requests it received in order.
+func newInitVault(t *testing.T) (*vault, func() []string) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []string
+	sealed := true
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, r.URL.Path+" "+r.Header.Get("X-Vault-Token"))
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v1/sys/init" && r.Method == http.MethodGet:
+			w.Write([]byte(`{"initialized":false}`)) //nolint:errcheck
+		case r.URL.Path == "/v1/sys/init":
+			w.Write([]byte(`{"keys":["unseal-key"],"root_token":"init-root"}`)) //nolint:errcheck
+		case r.URL.Path == "/v1/sys/seal-status":
+			fmt.Fprintf(w, `{"sealed":%t}`, sealed)
+		case r.URL.Path == "/v1/sys/unseal":
+			sealed = false
+			w.Write([]byte(`{"sealed":false}`)) //nolint:errcheck
+		case r.Method == http.MethodGet:
+			w.Write([]byte(`{"data":{}}`)) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	v.config = &Config{SecretShares: 1, SecretThreshold: 1, RevokeRootToken: true}
+
+	return v, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return slices.Clone(requests)
+	}
+}
+
+func TestConfigureRevokesInitRootTokenAfterUse(t *testing.T) {
+	ctx := context.Background()
+	v, requests := newInitVault(t)
+
+	require.NoError(t, v.Init(ctx))
+	require.NoError(t, v.Unseal(ctx))
+	assert.NotContains(t, requests(), "/v1/auth/token/revoke-self init-root", "the root token of init waits for configure")
+
+	require.NoError(t, v.Configure(ctx, map[string]interface{}{}))
+	assert.Contains(t, requests(), "/v1/auth/token/lookup-self init-root", "configure used the root token of init")
+	assert.Equal(t, "/v1/auth/token/revoke-self init-root", requests()[len(requests())-1])
+	assert.Nil(t, v.initRootToken, "the root token of init is only used once")
+
+	_, err := v.keyStore.Get(ctx, keyRootToken)
+	assert.True(t, isNotFoundError(err), "the root token is never stored")
+}
+
+func TestRevokeInitRootTokenWaitsForUnseal(t *testing.T) {
+	ctx := context.Background()
+	v, requests := newInitVault(t)
+
+	require.NoError(t, v.Init(ctx))
+	v.RevokeInitRootToken(ctx)
+	assert.NotNil(t, v.initRootToken, "the root token of init is kept while Vault is sealed")
+
+	require.NoError(t, v.Unseal(ctx))
+	v.RevokeInitRootToken(ctx)
+	assert.Nil(t, v.initRootToken)
+	assert.Contains(t, requests(), "/v1/auth/token/revoke-self init-root")
+}
+
+func TestRevokeRootTokenKeepsOtherTokens(t *testing.T) {
+	ctx := context.Background()
+
+	v, requests := newConfigurerTokenVault(t, &memKV{})
+	v.config = &Config{RevokeRootToken: true, Token: "admin"}
+
+	v.revokeRootToken(ctx, "admin")
+	assert.Empty(t, requests["/v1/auth/token/revoke-self"])
+
+	v.config = &Config{}
+	v.revokeRootToken(ctx, "root")
+	assert.Empty(t, requests["/v1/auth/token/revoke-self"], "root tokens are only revoked if enabled")
+}
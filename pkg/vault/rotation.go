@@ -0,0 +1,129 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+
+	"emperror.dev/errors"
+)
+
+// keyRotationState is the key store entry holding the keyed digests of the credentials
+// which were rotated by Vault, keyed by their config path.
+const keyRotationState = "vault-rotation-state"
+
+// rootRotatable reports whether the root credentials of the given secret engine config can be rotated.
+func rootRotatable(secretEngineType, configOption string) bool {
+	switch secretEngineType {
+	case "aws":
+		return configOption == "config/root"
+	case "azure", "database", "gcp", "ldap", "openldap":
+		return configOption == "config"
+	default:
+		return false
+	}
+}
+
+// credentialsRotatable reports whether the credentials of the given secret engine config can be rotated,
+// either the root credentials or the service account key of a GCP roleset.
+func credentialsRotatable(secretEngineType, configOption string) bool {
+	return rootRotatable(secretEngineType, configOption) || (secretEngineType == "gcp" && configOption == "roleset")
+}
+
+// credentialsDigest returns the keyed digest of a secret engine config holding credentials.
+// A plain hash would allow guessing low-entropy credentials offline from the key store.
+func (v *vault) credentialsDigest(ctx context.Context, config map[string]interface{}) (string, error) {
+	data, err := canonicalJSON(config)
+	if err != nil {
+		return "", errors.Wrap(err, "error marshaling config for hashing")
+	}
+
+	return v.secretDigest(ctx, string(data))
+}
+
+// rotationState returns the digests of the rotated credentials by config path. The state of a target
+// sharing the key store starts out from the one stored before it was kept per target.
+func (v *vault) rotationState(ctx context.Context) (map[string]string, error) {
+	state := map[string]string{}
+
+	key := v.stateKey(keyRotationState)
+	stored, err := v.keyStore.Get(ctx, key)
+	if err != nil && isNotFoundError(err) && key != keyRotationState {
+		key = keyRotationState
+		stored, err = v.keyStore.Get(ctx, key)
+	}
+	if err != nil {
+		if isNotFoundError(err) {
+			return state, nil
+		}
+
+		return nil, errors.Wrapf(err, "unable to get key '%s'", key)
+	}
+
+	if err := json.Unmarshal(stored, &state); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling key '%s'", key)
+	}
+
+	return state, nil
+}
+
+// credentialsRotated reports whether the given root credentials were already rotated away,
+// so they must not be written to Vault again.
+func (v *vault) credentialsRotated(ctx context.Context, configPath, credentialsHash string) (bool, error) {
+	rotatedHash, ok, err := v.rotatedCredentials(ctx, configPath)
+	if err != nil {
+		return false, err
+	}
+
+	return ok && rotatedHash == credentialsHash, nil
+}
+
+// rotatedCredentials returns the digest of the root credentials Vault last rotated away for the config path,
+// and whether any were.
+func (v *vault) rotatedCredentials(ctx context.Context, configPath string) (string, bool, error) {
+	state, err := v.rotationState(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	credentialsHash, ok := state[configPath]
+
+	return credentialsHash, ok, nil
+}
+
+// storeRotatedCredentials records that the given root credentials were rotated.
+func (v *vault) storeRotatedCredentials(ctx context.Context, configPath, credentialsHash string) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := v.rotationState(ctx)
+	if err != nil {
+		return err
+	}
+
+	state[configPath] = credentialsHash
+
+	stored, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling key '%s'", v.stateKey(keyRotationState))
+	}
+
+	if err := v.keyStore.Set(ctx, v.stateKey(keyRotationState), stored); err != nil {
+		return errors.Wrapf(err, "error storing key '%s'", v.stateKey(keyRotationState))
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cast"
+)
+
+// secretKeyRefField is the key of config values referencing a key of a Kubernetes Secret:
+//
+//	password:
+//	  secretKeyRef:
+//	    name: mysql-root
+//	    key: password
+const secretKeyRefField = "secretKeyRef"
+
+// SecretResolver reads the values of the Kubernetes Secrets referenced by the config.
+type SecretResolver interface {
+	// SecretValue returns the value of the key of a Secret, the namespace is optional
+	SecretValue(ctx context.Context, namespace, name, key string) (string, error)
+}
+
+type secretKeyRef struct {
+	Namespace string
+	Name      string
+	Key       string
+}
+
+// asSecretKeyRef returns the Secret reference of a config value, if it is one.
+func asSecretKeyRef(value interface{}) (secretKeyRef, bool, error) {
+	m, err := cast.ToStringMapE(value)
+	if err != nil || len(m) != 1 {
+		return secretKeyRef{}, false, nil
+	}
+
+	raw, ok := m[secretKeyRefField]
+	if !ok {
+		return secretKeyRef{}, false, nil
+	}
+
+	fields, err := cast.ToStringMapStringE(raw)
+	if err != nil {
+		return secretKeyRef{}, false, errors.Wrapf(err, "invalid %s", secretKeyRefField)
+	}
+
+	ref := secretKeyRef{Namespace: fields["namespace"], Name: fields["name"], Key: fields["key"]}
+	if ref.Name == "" || ref.Key == "" {
+		return secretKeyRef{}, false, errors.Errorf("%s needs a name and a key", secretKeyRefField)
+	}
+
+	return ref, true, nil
+}
+
+// resolveSecretRefs replaces the Kubernetes Secret references in the config data with the values
+// of the referenced keys in place, so the credentials don't have to appear in the external config.
+func (v *vault) resolveSecretRefs(ctx context.Context, data map[string]interface{}) error {
+	for field, value := range data {
+		ref, ok, err := asSecretKeyRef(value)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing field %s", field)
+		}
+
+		if !ok {
+			if nested, isMap := value.(map[string]interface{}); isMap {
+				if err := v.resolveSecretRefs(ctx, nested); err != nil {
+					return err
+				}
+			}
+
+			continue
+		}
+
+		if v.config == nil || v.config.SecretResolver == nil {
+			return errors.Errorf("field %s references secret %s, but no secret resolver is configured", field, ref.Name)
+		}
+
+		secretValue, err := v.config.SecretResolver.SecretValue(ctx, ref.Namespace, ref.Name, ref.Key)
+		if err != nil {
+			return errors.Wrapf(err, "error resolving field %s from secret %s", field, ref.Name)
+		}
+		data[field] = secretValue
+	}
+
+	return nil
+}
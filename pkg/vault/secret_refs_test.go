@@ -0,0 +1,74 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticSecretResolver map[string]string
+
+func (r staticSecretResolver) SecretValue(_ context.Context, namespace, name, key string) (string, error) {
+	value, ok := r[namespace+"/"+name+"/"+key]
+	if !ok {
+		return "", errors.New("not found")
+	}
+
+	return value, nil
+}
+
+func TestResolveSecretRefs(t *testing.T) {
+	v := &vault{config: &Config{SecretResolver: staticSecretResolver{
+		"/mysql-root/password":       "s3cr3t",
+		"other/credentials/json-key": "{}",
+	}}}
+
+	data := map[string]interface{}{
+		"username": "root",
+		"password": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{"name": "mysql-root", "key": "password"},
+		},
+		"nested": map[string]interface{}{
+			"credentials": map[interface{}]interface{}{
+				"secretKeyRef": map[interface{}]interface{}{"namespace": "other", "name": "credentials", "key": "json-key"},
+			},
+		},
+	}
+
+	require.NoError(t, v.resolveSecretRefs(context.Background(), data))
+
+	assert.Equal(t, map[string]interface{}{
+		"username": "root",
+		"password": "s3cr3t",
+		"nested":   map[string]interface{}{"credentials": "{}"},
+	}, data)
+}
+
+func TestResolveSecretRefs_NoResolver(t *testing.T) {
+	v := &vault{}
+
+	err := v.resolveSecretRefs(context.Background(), map[string]interface{}{
+		"password": map[string]interface{}{
+			"secretKeyRef": map[string]interface{}{"name": "mysql-root", "key": "password"},
+		},
+	})
+
+	assert.Error(t, err)
+}
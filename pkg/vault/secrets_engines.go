@@ -0,0 +1,580 @@
+// Copyright © 2022 Banzai Cloud
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"emperror.dev/errors"
+	vaultpkg "github.com/bank-vaults/vault-sdk/vault"
+	"github.com/hashicorp/vault/api"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/cast"
+
+	"github.com/bank-vaults/bank-vaults/pkg/notify"
+)
+
+func isOverwriteProhibitedError(err error) bool {
+	return strings.Contains(err.Error(), "delete them before reconfiguring")
+}
+
+// This object is used to easily find fields in secret engines that contain potentially templated expressions
+type secretEngineTemplatedConfig struct {
+	AllowedDomains []string               `mapstructure:"allowed_domains"`
+	Other          map[string]interface{} `mapstructure:",remain"`
+}
+
+// SecretsEngine is a secrets engine of the secrets section, mounted at its path, the type if empty, with the
+// configuration written below it by endpoint.
+type SecretsEngine struct {
+	Path          string                 `mapstructure:"path"`
+	Type          string                 `mapstructure:"type"`
+	Description   string                 `mapstructure:"description"`
+	Configuration map[string]interface{} `mapstructure:"configuration"`
+	Config        map[string]interface{} `mapstructure:"config"`
+	Options       map[string]string      `mapstructure:"options"`
+	PluginName    string                 `mapstructure:"plugin_name"`
+	Local         bool                   `mapstructure:"local"`
+	SealWrap      bool                   `mapstructure:"seal_wrap"`
+	MaxVersions   *int                   `mapstructure:"max_versions"`
+}
+
+func replaceAccessor(input string, mounts map[string]*api.MountOutput) string {
+	// Sort mount paths by length (longest first) to avoid substring collisions
+	// e.g., "__accessor__kubernetes_cluster" should be replaced before "__accessor__kubernetes"
+	mountPaths := slices.Collect(maps.Keys(mounts))
+	slices.SortFunc(mountPaths, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	result := input
+	for _, mountPath := range mountPaths {
+		placeholder := fmt.Sprintf("__accessor__%s", strings.TrimSuffix(mountPath, "/"))
+		if strings.Contains(result, placeholder) {
+			slog.Info("replaced __accessor__ placeholder",
+				"placeholder", placeholder,
+				"accessor", mounts[mountPath].Accessor)
+			result = strings.ReplaceAll(result, placeholder, mounts[mountPath].Accessor)
+		}
+	}
+
+	return result
+}
+
+func initSecretsEnginesConfig(configs []SecretsEngine) []SecretsEngine {
+	for index, config := range configs {
+		if config.Path == "" {
+			configs[index].Path = config.Type
+		}
+
+		configs[index].Path = strings.Trim(configs[index].Path, "/")
+	}
+
+	return configs
+}
+
+func (se *SecretsEngine) getMountConfigInput() (api.MountConfigInput, error) {
+	var mountConfigInput api.MountConfigInput
+	if err := mapstructure.Decode(se.Config, &mountConfigInput); err != nil {
+		return mountConfigInput, errors.Wrap(err, "error parsing config for secret engine")
+	}
+
+	// Bank-Vaults supported options outside config to be used options in the mount request
+	// so for now, to preserve backward compatibility we overwrite the options inside config
+	// with the options outside.
+	mountConfigInput.Options = se.Options
+
+	return mountConfigInput, nil
+}
+
+func (v *vault) mountExists(path string) (bool, error) {
+	mounts, err := v.listMounts()
+	if err != nil {
+		return false, errors.Wrap(err, "error reading mounts from vault")
+	}
+	v.log().Debug("already existing mounts", "section", SectionSecrets, "mounts", v.redact(mounts))
+
+	return mounts[path+"/"] != nil, nil
+}
+
+func (v *vault) rotateSecretEngineCredentials(ctx context.Context, secretEngineType, path, configOption, name, configPath, credentialsHash string) error {
+	var rotatePath string
+	switch secretEngineType {
+	case "aws":
+		rotatePath = fmt.Sprintf("%s/config/rotate-root", path)
+	case "azure":
+		rotatePath = fmt.Sprintf("%s/rotate-root", path)
+	case "gcp":
+		if configOption == "roleset" {
+			rotatePath = fmt.Sprintf("%s/roleset/%s/rotate", path, name)
+		} else {
+			rotatePath = fmt.Sprintf("%s/config/rotate-root", path)
+		}
+	case "database":
+		rotatePath = fmt.Sprintf("%s/rotate-root/%s", path, name)
+	case "ldap", "openldap":
+		rotatePath = fmt.Sprintf("%s/rotate-root", path)
+	default:
+		return errors.Errorf("secret engine type '%s' doesn't support credential rotation", secretEngineType)
+	}
+
+	rotated, err := v.credentialsRotated(ctx, configPath, credentialsHash)
+	if err != nil {
+		return err
+	}
+
+	// Credentials replacing the rotated ones are rotated again
+	rotateKey := rotatePath + "@" + credentialsHash
+	if _, ok := v.rotateCache[rotateKey]; !ok && !rotated {
+		v.log().Info("doing credential rotation", "section", SectionSecrets, "path", rotatePath)
+
+		_, err := v.writeWithWarningCheck(rotatePath, nil)
+		if err != nil {
+			return errors.Wrapf(err, "error rotating credentials for '%s' config in vault", configPath)
+		}
+
+		v.log().Info("credential got rotated", "section", SectionSecrets, "path", rotatePath)
+		v.sendNotification(ctx, notify.Event{
+			Type:    notify.EventCredentialsRotated,
+			Message: fmt.Sprintf("rotated %s secret engine root credentials", secretEngineType),
+			Section: SectionSecrets,
+			Path:    rotatePath,
+		})
+
+		v.rotateCache[rotateKey] = true
+
+		// Remember the rotated credentials, so they are not written again after a restart
+		if err := v.storeRotatedCredentials(ctx, configPath, credentialsHash); err != nil {
+			return err
+		}
+	} else {
+		v.log().Info("credentials were rotated previously", "section", SectionSecrets, "path", rotatePath)
+	}
+
+	return nil
+}
+
+// NOTE: Maybe we could convert "getExisting*" and "getUnmanaged*" methods to generic functions
+// since probably they will be the same for all config types.
+
+// getExistingSecretsEngines gets all secrets engines that are already in Vault.
+func (v *vault) getExistingSecretsEngines() (map[string]bool, error) {
+	existingSecretsEngines := make(map[string]bool)
+
+	existingSecretsEnginesList, err := v.listMounts()
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to list existing secrets engines")
+	}
+
+	for existingSecretEnginePath := range existingSecretsEnginesList {
+		existingSecretsEngines[strings.Trim(existingSecretEnginePath, "/")] = true
+	}
+
+	return existingSecretsEngines, nil
+}
+
+// builtInSecretsEngines are mounts Vault provides out of the box and refuses
+// to unmount via the API. Attempting to unmount them returns HTTP 400.
+// "agent-registry" was introduced in Vault 2.0.
+var builtInSecretsEngines = []string{"sys", "identity", "cubbyhole", "agent-registry"}
+
+// getUnmanagedSecretsEngines gets unmanaged secrets engines by comparing what's already in Vault
+// and what's in the externalConfig.
+func (v *vault) getUnmanagedSecretsEngines(managedSecretsEngines []SecretsEngine) map[string]bool {
+	unmanagedSecretsEngines, _ := v.getExistingSecretsEngines()
+
+	// Ignore system mounts that Vault refuses to unmount.
+	for _, builtIn := range builtInSecretsEngines {
+		delete(unmanagedSecretsEngines, builtIn)
+	}
+
+	// Remove managed secret engine form the items since the reset will be removed.
+	for _, managedSecretEngine := range managedSecretsEngines {
+		delete(unmanagedSecretsEngines, managedSecretEngine.Path)
+	}
+
+	return unmanagedSecretsEngines
+}
+
+// isSecretEngineConfigEndpoint reports whether the config option is a config endpoint of the secret engine
+// (e.g. `config`, `config/root` or `cache-config`), which is a singleton for most engines, so no name is required.
+func isSecretEngineConfigEndpoint(configOption string) bool {
+	return configOption == "config" || strings.HasPrefix(configOption, "config/") || strings.HasSuffix(configOption, "-config")
+}
+
+// secretEngineConfigPath returns the Vault path of a secret engine config entry. The template of the
+// `config_path` override can refer to the mount path, config option and entry name as {{path}}, {{option}} and {{name}}.
+func secretEngineConfigPath(path, configOption string, name interface{}, nameInPath bool, template string) string {
+	if template != "" {
+		return strings.NewReplacer(
+			"{{path}}", path,
+			"{{option}}", configOption,
+			"{{name}}", cast.ToString(name),
+		).Replace(template)
+	}
+
+	if name != nil && nameInPath {
+		return fmt.Sprintf("%s/%s/%s", path, configOption, name)
+	}
+
+	return fmt.Sprintf("%s/%s", path, configOption)
+}
+
+func (v *vault) addManagedSecretsEngines(ctx context.Context, managedSecretsEngines []SecretsEngine, mounts map[string]*api.MountOutput) error {
+	retryPolicy := v.retryPolicy(SectionSecrets)
+
+	for _, secretEngine := range managedSecretsEngines {
+		if err := v.itemFailed(SectionSecrets, secretEngine.Path, v.addManagedSecretsEngine(ctx, secretEngine, mounts, retryPolicy)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *vault) addManagedSecretsEngine(ctx context.Context, secretEngine SecretsEngine, mounts map[string]*api.MountOutput, retryPolicy RetryPolicy) error {
+	mountExists, err := v.mountExists(secretEngine.Path)
+	if err != nil {
+		return err
+	}
+
+	mountConfigInput, err := secretEngine.getMountConfigInput()
+	if err != nil {
+		return err
+	}
+
+	if !mountExists {
+		// Mount the secret engine if it's not already there.
+		mountInput := api.MountInput{
+			Type:        secretEngine.Type,
+			Description: secretEngine.Description,
+			PluginName:  secretEngine.PluginName,
+			Config:      mountConfigInput,
+			Options:     mountConfigInput.Options, // options needs to be sent here first time
+			Local:       secretEngine.Local,
+			SealWrap:    secretEngine.SealWrap,
+		}
+
+		v.log().Info("adding secret engine", "section", SectionSecrets, "path", secretEngine.Path, "type", secretEngine.Type)
+		v.log().Debug("secret engine input", "section", SectionSecrets, "path", secretEngine.Path, "input", v.redact(mountInput))
+		err = retryPolicy.retry(ctx, v.log(), fmt.Sprintf("mounting %s into vault", secretEngine.Path), func() error {
+			return v.cl.Sys().Mount(secretEngine.Path, &mountInput)
+		})
+		v.mountsChanged()
+		if err != nil {
+			return errors.Wrapf(err, "error mounting %s into vault after several attempts", secretEngine.Path)
+		}
+		v.report.created(SectionSecrets, secretEngine.Path)
+		v.recordWrite(AuditOperationMount, "sys/mounts/"+secretEngine.Path, secretEngine.Config)
+	} else {
+		// If the secret engine is already mounted, only update its config in place.
+		v.log().Info("tuning already existing secret engine", "section", SectionSecrets, "path", secretEngine.Path)
+		err = retryPolicy.retry(ctx, v.log(), fmt.Sprintf("tuning %s", secretEngine.Path), func() error {
+			return v.cl.Sys().TuneMountAllowNilWithContext(ctx, secretEngine.Path, convertToTuneMountConfigInput(mountConfigInput))
+		})
+		if err != nil {
+			return errors.Wrapf(err, "error tuning %s in vault after several attempts", secretEngine.Path)
+		}
+		v.report.updated(SectionSecrets, secretEngine.Path)
+		v.recordWrite(AuditOperationTune, "sys/mounts/"+secretEngine.Path+"/tune", secretEngine.Config)
+	}
+
+	// Configuration of the Secret Engine in a very generic manner, YAML config file should have the proper format
+	for configOption, configData := range secretEngine.Configuration {
+		configData, err := cast.ToSliceE(configData)
+		if err != nil {
+			if err := v.itemFailed(SectionSecrets, secretEngine.Path+"/"+configOption, errors.Wrap(err, "error converting config data for secret engine")); err != nil {
+				return err
+			}
+
+			continue
+		}
+		for _, subConfigDataRaw := range configData {
+			configPath, err := v.configureSecretsEngine(ctx, secretEngine, configOption, subConfigDataRaw, mounts, mountExists)
+			if configPath == "" {
+				configPath = secretEngine.Path + "/" + configOption
+			}
+			if err := v.itemFailed(SectionSecrets, configPath, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// configureSecretsEngine writes a single configuration item of a secrets engine, it returns the path
+// of the item once it is known for reporting its failure.
+func (v *vault) configureSecretsEngine(ctx context.Context, secretEngine SecretsEngine, configOption string, subConfigDataRaw interface{}, mounts map[string]*api.MountOutput, mountExists bool) (string, error) {
+	var configPath string
+	var subConfigData map[string]interface{}
+
+	// If subConfigDataRaw has fields that are supported for templated policies,
+	// it will be cast successfully into secretEngineTemplatedConfig
+	var pkiRole secretEngineTemplatedConfig
+	err := mapstructure.Decode(subConfigDataRaw, &pkiRole)
+	if err == nil {
+		templatedDomains := []string{}
+		for _, domain := range pkiRole.AllowedDomains {
+			templatedDomains = append(templatedDomains, replaceAccessor(domain, mounts))
+		}
+		pkiRole.AllowedDomains = templatedDomains
+		subConfigData = pkiRole.Other
+		subConfigData["allowed_domains"] = pkiRole.AllowedDomains
+	} else {
+		// If the object could not be cast into a secretEngineTemplatedConfig,
+		// subConfigData will just be initialized from the subConfigDataRaw
+		subConfigData, err = cast.ToStringMapE(subConfigDataRaw)
+		if err != nil {
+			return configPath, errors.Wrap(err, "error converting sub config data for secret engine")
+		}
+	}
+
+	// Entries need a name, except the config endpoints. A given name is part of the config path,
+	// `name_required` and `config_path` override this for engines (e.g. custom plugins) with other paths
+	nameRequired := !isSecretEngineConfigEndpoint(configOption)
+	nameInPath := true
+	if _, ok := subConfigData["name_required"]; ok {
+		nameRequired = cast.ToBool(subConfigData["name_required"])
+		nameInPath = nameRequired
+		// Delete the name_required key from the map, so we don't push it to vault
+		delete(subConfigData, "name_required")
+	}
+
+	configPathTemplate := cast.ToString(subConfigData["config_path"])
+	// Delete the config_path key from the map, so we don't push it to vault
+	delete(subConfigData, "config_path")
+
+	name, ok := subConfigData["name"]
+	if !ok && nameRequired && (configPathTemplate == "" || strings.Contains(configPathTemplate, "{{name}}")) {
+		return configPath, errors.Errorf("error finding sub config data name for secret engine: %s/%s", secretEngine.Path, configOption)
+	}
+
+	// config data can have a child dict. But it will cause:
+	// `json: unsupported type: map[interface {}]interface {}`
+	// So check and replace by `map[string]interface{}` before using it.
+	for k, v := range subConfigData {
+		if val, ok := v.(map[interface{}]interface{}); ok {
+			subConfigData[k] = cast.ToStringMap(val)
+		}
+	}
+
+	if err := v.resolveSecretRefs(ctx, subConfigData); err != nil {
+		return configPath, errors.Wrapf(err, "error resolving secret references of %s/%s config", secretEngine.Path, configOption)
+	}
+
+	if secretEngine.Type == "kubernetes" {
+		if err := completeKubernetesSecretsEngineConfig(configOption, subConfigData); err != nil {
+			return configPath, errors.Wrapf(err, "error completing %s/%s config", secretEngine.Path, configOption)
+		}
+	}
+
+	configPath = secretEngineConfigPath(secretEngine.Path, configOption, name, nameInPath, configPathTemplate)
+
+	// Control if the configs should be updated or just Created once and skipped later on
+	// This is a workaround to secrets backend like GCP that will destroy and recreate secrets at every iteration
+	createOnly := cast.ToBool(subConfigData["create_only"])
+	// Delete the create_only key from the map, so we don't push it to vault
+	delete(subConfigData, "create_only")
+
+	rotate := cast.ToBool(subConfigData["rotate"])
+	// Delete the rotate key from the map, so we don't push it to vault
+	delete(subConfigData, "rotate")
+
+	saveTo := cast.ToString(subConfigData["save_to"])
+	// Delete the rotate key from the map, so we don't push it to vault
+	delete(subConfigData, "save_to")
+
+	skipPreflight := cast.ToBool(subConfigData["skip_preflight"])
+	// Delete the skip_preflight key from the map, so we don't push it to vault
+	delete(subConfigData, "skip_preflight")
+
+	rotateIfOlderThan, err := cast.ToDurationE(subConfigData["rotate_if_older_than"])
+	if err != nil {
+		return configPath, errors.Wrapf(err, "error parsing rotate_if_older_than of %s", configPath)
+	}
+	// Delete the rotate_if_older_than key from the map, so we don't push it to vault
+	delete(subConfigData, "rotate_if_older_than")
+
+	// Digest the credentials before they are written, the rotation state is keyed by them
+	rotatable := rotate && credentialsRotatable(secretEngine.Type, configOption)
+	var credentialsHash, rotatedHash string
+	var rotationRecorded bool
+	if rotatable {
+		credentialsHash, err = v.credentialsDigest(ctx, subConfigData)
+		if err != nil {
+			return configPath, err
+		}
+		rotatedHash, rotationRecorded, err = v.rotatedCredentials(ctx, configPath)
+		if err != nil {
+			return configPath, err
+		}
+	}
+
+	shouldUpdate := true
+	if (createOnly || rotate) && mountExists {
+		secretExists := false
+		if configOption == "root/generate" { // the pki generate call is a different beast
+			req := v.cl.NewRequest("GET", fmt.Sprintf("/v1/%s/ca", secretEngine.Path))
+			resp, err := v.cl.RawRequestWithContext(ctx, req) //nolint
+			if resp != nil {
+				defer func() {
+					if err := resp.Body.Close(); err != nil {
+						v.log().Error("error closing response body", "section", SectionSecrets, "error", err)
+					}
+				}()
+			}
+			if err != nil {
+				return configPath, errors.Wrapf(err, "failed to check pki CA")
+			}
+			if resp.StatusCode == http.StatusOK {
+				secretExists = true
+			}
+		} else {
+			secret, err := v.cl.Logical().Read(configPath)
+			if err != nil {
+				return configPath, errors.Wrapf(err, "error reading configPath %s", configPath)
+			}
+			if secret != nil && secret.Data != nil {
+				secretExists = true
+			}
+		}
+
+		// create_only never updates the config, rotate only with credentials replacing the ones Vault rotated
+		if !createOnly && rotationRecorded && rotatedHash != credentialsHash {
+			secretExists = false
+		}
+
+		if secretExists {
+			reason := "rotate"
+			if createOnly {
+				reason = "create_only"
+			}
+			v.log().Info("config already exists and will not be updated", "section", SectionSecrets, "path", configPath, "reason", reason)
+			v.report.skipped(SectionSecrets, configPath)
+			shouldUpdate = false
+		}
+	}
+
+	if shouldUpdate && rotatable && rotationRecorded && rotatedHash == credentialsHash {
+		v.log().Warn("the credentials were rotated by vault already, provide new ones to reconfigure it", "section", SectionSecrets, "path", configPath)
+		v.report.skipped(SectionSecrets, configPath)
+		shouldUpdate = false
+	}
+
+	if shouldUpdate && secretEngine.Type == "aws" && configOption == "config/root" && !skipPreflight {
+		// A bad key would otherwise only fail at the first credential issuance
+		if err := validateAWSRootConfig(ctx, configPath, subConfigData); err != nil {
+			return configPath, err
+		}
+	}
+
+	if shouldUpdate {
+		sec, err := v.writeWithWarningCheck(configPath, subConfigData)
+		if err != nil {
+			if isOverwriteProhibitedError(err) {
+				v.log().Info("can't reconfigure config, please delete it manually", "section", SectionSecrets, "path", configPath)
+				v.report.skipped(SectionSecrets, configPath)
+
+				return configPath, nil
+			}
+			return configPath, errors.Wrapf(err, "error configuring %s config in vault", configPath)
+		}
+		v.report.updated(SectionSecrets, configPath)
+
+		if saveTo != "" {
+			_, err = v.writeWithWarningCheck(saveTo, vaultpkg.NewData(0, sec.Data))
+			if err != nil {
+				return configPath, errors.Wrapf(err, "error saving secret in vault to %s", saveTo)
+			}
+		}
+	}
+
+	// For secret engines where the root credentials are rotatable we don't want to reconfigure again
+	// with the old credentials, because that would cause access denied issues. Currently these are:
+	// - AWS
+	// - Azure
+	// - Database
+	// - GCP (root config and rolesets)
+	// - LDAP
+	// A config kept by create_only is rotated once, later changes of it are ignored like the config
+	if rotatable && mountExists && (shouldUpdate || !rotationRecorded) {
+		nameStr := ""
+		if name != nil {
+			nameStr = name.(string)
+		}
+		err = v.rotateSecretEngineCredentials(ctx, secretEngine.Type, secretEngine.Path, configOption, nameStr, configPath, credentialsHash)
+		if err != nil {
+			return configPath, errors.Wrapf(err, "error rotating credentials for '%s' config in vault", configPath)
+		}
+	}
+
+	if rotateIfOlderThan > 0 && isLDAPStaticRole(secretEngine.Type, configOption) {
+		if err := v.rotateLDAPStaticRole(ctx, secretEngine.Path, cast.ToString(name), rotateIfOlderThan); err != nil {
+			return configPath, err
+		}
+	}
+
+	return configPath, nil
+}
+
+func (v *vault) removeUnmanagedSecretsEngines(ctx context.Context, unmanagedSecretsEngines map[string]bool) error {
+	if len(unmanagedSecretsEngines) == 0 || !v.externalConfig.PurgeUnmanagedConfig.Enabled ||
+		v.externalConfig.PurgeUnmanagedConfig.Exclude.Secrets {
+		return nil
+	}
+
+	for secretEnginePath := range unmanagedSecretsEngines {
+		v.log().Info("removing secret engine", "section", SectionSecrets, "path", secretEnginePath)
+		err := v.cl.Sys().Unmount(secretEnginePath)
+		v.mountsChanged()
+		if err != nil {
+			if err := v.itemFailed(SectionSecrets, secretEnginePath, errors.Wrapf(err, "error unmounting %s secret engine from vault", secretEnginePath)); err != nil {
+				return err
+			}
+
+			continue
+		}
+		v.resourcePurged(ctx, SectionSecrets, secretEnginePath)
+	}
+
+	return nil
+}
+
+func (v *vault) configureSecretsEngines(ctx context.Context) error {
+	auths, err := v.listAuth()
+	if err != nil {
+		return errors.Wrap(err, "error while getting list of auth engines for secret engine configuration")
+	}
+	managedSecretsEngines := initSecretsEnginesConfig(v.externalConfig.Secrets)
+	unmanagedSecretsEngines := v.getUnmanagedSecretsEngines(managedSecretsEngines)
+	v.report.unmanaged(SectionSecrets, slices.Sorted(maps.Keys(unmanagedSecretsEngines)))
+
+	if err := v.addManagedSecretsEngines(ctx, managedSecretsEngines, auths); err != nil {
+		return errors.Wrap(err, "error adding secrets engines")
+	}
+
+	if err := v.removeUnmanagedSecretsEngines(ctx, unmanagedSecretsEngines); err != nil {
+		return errors.Wrap(err, "error removing secrets engines")
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/vault/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceAccessor_SubstringCollision(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		mounts      map[string]*api.MountOutput
+		expected    string
+		description string
+	}{
+		{
+			name:  "longer path with prefix collision",
+			input: "__accessor__kubernetes_cluster",
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+				"kubernetes_cluster/": {
+					Accessor: "auth_kubernetes_cluster_67890",
+				},
+			},
+			expected:    "auth_kubernetes_cluster_67890",
+			description: "Should replace longer path first to avoid substring collision",
+		},
+		{
+			name:  "shorter path with prefix collision",
+			input: "__accessor__kubernetes",
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+				"kubernetes_cluster/": {
+					Accessor: "auth_kubernetes_cluster_67890",
+				},
+			},
+			expected:    "auth_kubernetes_12345",
+			description: "Should correctly replace shorter path when it's the target",
+		},
+		{
+			name:  "no matching accessor",
+			input: "__accessor__nonexistent",
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+			},
+			expected:    "__accessor__nonexistent",
+			description: "Should return input unchanged when no match found",
+		},
+		{
+			name:  "single mount",
+			input: "__accessor__kubernetes",
+			mounts: map[string]*api.MountOutput{
+				"kubernetes/": {
+					Accessor: "auth_kubernetes_12345",
+				},
+			},
+			expected:    "auth_kubernetes_12345",
+			description: "Should work correctly with single mount",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := replaceAccessor(tt.input, tt.mounts)
+			assert.Equal(t, tt.expected, result, tt.description)
+		})
+	}
+}
+
+func TestRootRotatable(t *testing.T) {
+	assert.True(t, rootRotatable("aws", "config/root"))
+	assert.True(t, rootRotatable("database", "config"))
+	assert.True(t, rootRotatable("gcp", "config"))
+	assert.True(t, rootRotatable("azure", "config"))
+	assert.False(t, rootRotatable("gcp", "roleset"))
+	assert.False(t, rootRotatable("aws", "roles"))
+	assert.False(t, rootRotatable("pki", "config"))
+
+	assert.True(t, credentialsRotatable("gcp", "roleset"))
+	assert.True(t, credentialsRotatable("aws", "config/root"))
+	assert.False(t, credentialsRotatable("aws", "roles"))
+}
+
+func TestRotateSecretEngineCredentialsPath(t *testing.T) {
+	tests := []struct {
+		engine, configOption, name string
+		rotatePath                 string
+	}{
+		{engine: "aws", configOption: "config/root", rotatePath: "aws/config/rotate-root"},
+		{engine: "azure", configOption: "config", rotatePath: "azure/rotate-root"},
+		{engine: "gcp", configOption: "config", rotatePath: "gcp/config/rotate-root"},
+		{engine: "gcp", configOption: "roleset", name: "ci", rotatePath: "gcp/roleset/ci/rotate"},
+		{engine: "database", configOption: "config", name: "postgres", rotatePath: "database/rotate-root/postgres"},
+		{engine: "ldap", configOption: "config", rotatePath: "ldap/rotate-root"},
+		{engine: "openldap", configOption: "config", rotatePath: "openldap/rotate-root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rotatePath, func(t *testing.T) {
+			var written []string
+			v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				written = append(written, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/v1/"))
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			configPath := tt.engine + "/" + tt.configOption
+			require.NoError(t, v.rotateSecretEngineCredentials(context.Background(), tt.engine, tt.engine, tt.configOption, tt.name, configPath, "digest"))
+			assert.Equal(t, []string{"PUT " + tt.rotatePath}, written)
+		})
+	}
+
+	v := newTestVault(t, http.NotFoundHandler())
+	assert.ErrorContains(t, v.rotateSecretEngineCredentials(context.Background(), "pki", "pki", "config", "", "pki/config", "digest"), "doesn't support credential rotation")
+}
+
+func TestAzureRootRotation(t *testing.T) {
+	var mu sync.Mutex
+	var written []string
+	configured := false
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		path := strings.TrimPrefix(r.URL.Path, "/v1/")
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "sys/mounts":
+			_, _ = w.Write([]byte(`{"data":{"azure/":{"type":"azure"}}}`))
+		case r.Method == http.MethodGet && path == "azure/config":
+			if !configured {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":{"client_id":"app"}}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			if path == "azure/config" {
+				configured = true
+			}
+			written = append(written, path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+
+	apply := func(config map[string]interface{}) []string {
+		written = nil
+		engine := SecretsEngine{Type: "azure", Path: "azure", Configuration: map[string]interface{}{"config": []interface{}{config}}}
+		require.NoError(t, v.addManagedSecretsEngine(context.Background(), engine, nil, RetryPolicy{}))
+
+		return written
+	}
+
+	// The new config is written and its root credentials rotated at the path of the Azure engine
+	assert.Equal(t, []string{"sys/mounts/azure/tune", "azure/config", "azure/rotate-root"},
+		apply(map[string]interface{}{"client_id": "app", "client_secret": "first", "rotate": true}))
+
+	// The rotated credentials are neither written nor rotated again
+	assert.Equal(t, []string{"sys/mounts/azure/tune"},
+		apply(map[string]interface{}{"client_id": "app", "client_secret": "first", "rotate": true}))
+
+	// With rotate new credentials replace the rotated ones and are rotated in turn
+	assert.Equal(t, []string{"sys/mounts/azure/tune", "azure/config", "azure/rotate-root"},
+		apply(map[string]interface{}{"client_id": "app", "client_secret": "second", "rotate": true}))
+
+	// With create_only the existing config is kept, whatever credentials are given
+	assert.Equal(t, []string{"sys/mounts/azure/tune"},
+		apply(map[string]interface{}{"client_id": "app", "client_secret": "third", "rotate": true, "create_only": true}))
+}
+
+func TestCredentialsDigestIsKeyed(t *testing.T) {
+	config := map[string]interface{}{"access_key": "AKIA", "secret_key": "secret"}
+
+	digest := func(store *memKV) string {
+		d, err := (&vault{keyStore: store}).credentialsDigest(context.Background(), config)
+		require.NoError(t, err)
+
+		return d
+	}
+
+	store := &memKV{}
+	first := digest(store)
+	assert.Equal(t, first, digest(store), "the digest should be stable for the same key")
+	assert.NotEqual(t, first, digest(&memKV{}), "the digest should depend on the stored key")
+
+	plain, err := configHash(config)
+	require.NoError(t, err)
+	assert.NotEqual(t, plain, first)
+}
+
+func TestCompleteKubernetesSecretsEngineConfig_GeneratedRoleRules(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "list-pods",
+		"generated_role_rules": map[interface{}]interface{}{
+			"rules": []interface{}{
+				map[interface{}]interface{}{
+					"apiGroups": []interface{}{""},
+					"resources": []interface{}{"pods"},
+					"verbs":     []interface{}{"list"},
+				},
+			},
+		},
+	}
+
+	err := completeKubernetesSecretsEngineConfig("roles", data)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"rules":[{"apiGroups":[""],"resources":["pods"],"verbs":["list"]}]}`, data["generated_role_rules"].(string))
+}
+
+func TestIsSecretEngineConfigEndpoint(t *testing.T) {
+	assert.True(t, isSecretEngineConfigEndpoint("config"))
+	assert.True(t, isSecretEngineConfigEndpoint("config/root"))
+	assert.True(t, isSecretEngineConfigEndpoint("cache-config"))
+	assert.False(t, isSecretEngineConfigEndpoint("roles"))
+	assert.False(t, isSecretEngineConfigEndpoint("static-role"))
+}
+
+func TestSecretEngineConfigPath(t *testing.T) {
+	assert.Equal(t, "database/roles/pipeline", secretEngineConfigPath("database", "roles", "pipeline", true, ""))
+	assert.Equal(t, "gcp/config", secretEngineConfigPath("gcp", "config", nil, true, ""))
+	assert.Equal(t, "custom/settings", secretEngineConfigPath("custom", "settings", "main", false, ""))
+	assert.Equal(t, "custom/v2/main/settings", secretEngineConfigPath("custom", "settings", "main", true, "{{path}}/v2/{{name}}/{{option}}"))
+}
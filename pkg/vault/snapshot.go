@@ -0,0 +1,200 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// DefaultSnapshotKey is the key raft snapshots are stored under in the key store.
+const DefaultSnapshotKey = "vault-raft-snapshot"
+
+// DefaultSnapshotChunkSize is the size of the values a raft snapshot is stored in, small enough
+// for every KMS backend to encrypt it directly, e.g. AWS KMS encrypts at most 4 KiB.
+const DefaultSnapshotChunkSize = 4 * 1024
+
+// SnapshotManifest describes a raft snapshot stored in the key store, it is stored under the key of the
+// snapshot after its chunks.
+type SnapshotManifest struct {
+	Created   time.Time `json:"created"`
+	Size      int64     `json:"size"`
+	Chunks    int       `json:"chunks"`
+	ChunkSize int       `json:"chunkSize"`
+	SHA256    string    `json:"sha256"`
+}
+
+// snapshotChunkKey returns the key a chunk of a raft snapshot is stored under.
+func snapshotChunkKey(key string, chunk int) string {
+	return fmt.Sprintf("%s-chunk-%d", key, chunk)
+}
+
+// SaveSnapshot logs in like configure does and streams a raft snapshot of Vault into the key store, in chunks
+// of the size stored under the key. A snapshot saved under the same key before is overwritten, it can't be
+// restored anymore if saving fails halfway.
+func (v *vault) SaveSnapshot(ctx context.Context, key string, chunkSize int) (*SnapshotManifest, error) {
+	if chunkSize <= 0 {
+		return nil, errors.Errorf("invalid snapshot chunk size: %d", chunkSize)
+	}
+
+	if err := v.login(ctx); err != nil {
+		return nil, err
+	}
+	defer v.cl.SetToken("")
+	defer v.revokeRootToken(ctx, v.cl.Token())
+
+	reader, writer := io.Pipe()
+	// Unblocks the snapshot request if storing a chunk fails
+	defer reader.Close()
+	go func() {
+		writer.CloseWithError(v.cl.Sys().RaftSnapshotWithContext(ctx, writer))
+	}()
+
+	manifest := &SnapshotManifest{Created: time.Now().UTC(), ChunkSize: chunkSize}
+	digest := sha256.New()
+	chunk := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(reader, chunk)
+		if n > 0 {
+			digest.Write(chunk[:n])
+			if err := v.keyStore.Set(ctx, snapshotChunkKey(key, manifest.Chunks), chunk[:n]); err != nil {
+				return nil, errors.Wrapf(err, "error storing chunk %d of the raft snapshot", manifest.Chunks)
+			}
+			manifest.Chunks++
+			manifest.Size += int64(n)
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "error taking raft snapshot")
+		}
+	}
+	manifest.SHA256 = hex.EncodeToString(digest.Sum(nil))
+
+	data, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling raft snapshot manifest")
+	}
+	if err := v.keyStore.Set(ctx, key, data); err != nil {
+		return nil, errors.Wrap(err, "error storing raft snapshot manifest")
+	}
+
+	v.log().Info("raft snapshot saved", "key", key, "size", manifest.Size, "chunks", manifest.Chunks)
+
+	return manifest, nil
+}
+
+// RestoreSnapshot logs in like configure does and streams the raft snapshot stored under the key into Vault,
+// force restores a snapshot of another cluster. The snapshot is verified against its manifest before its last
+// chunk is sent, so Vault never installs a corrupted or incomplete one.
+func (v *vault) RestoreSnapshot(ctx context.Context, key string, force bool) (*SnapshotManifest, error) {
+	manifest, err := v.snapshotManifest(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.login(ctx); err != nil {
+		return nil, err
+	}
+	defer v.cl.SetToken("")
+	defer v.revokeRootToken(ctx, v.cl.Token())
+
+	reader, writer := io.Pipe()
+	streamed := make(chan error, 1)
+	go func() {
+		err := v.streamSnapshot(ctx, key, manifest, writer)
+		writer.CloseWithError(err)
+		streamed <- err
+	}()
+
+	err = v.cl.Sys().RaftSnapshotRestoreWithContext(ctx, reader, force)
+	// Unblocks streaming if the request failed before reading the whole snapshot
+	reader.Close()
+	if streamErr := <-streamed; streamErr != nil && !errors.Is(streamErr, io.ErrClosedPipe) {
+		return nil, streamErr
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error restoring raft snapshot")
+	}
+
+	v.log().Info("raft snapshot restored", "key", key, "created", manifest.Created, "size", manifest.Size)
+
+	return manifest, nil
+}
+
+// snapshotManifest reads the manifest of the raft snapshot stored under the key.
+func (v *vault) snapshotManifest(ctx context.Context, key string) (*SnapshotManifest, error) {
+	data, err := v.keyStore.Get(ctx, key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading raft snapshot manifest '%s'", key)
+	}
+
+	var manifest SnapshotManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "error parsing raft snapshot manifest '%s'", key)
+	}
+	if manifest.Chunks <= 0 || manifest.SHA256 == "" {
+		return nil, errors.Errorf("raft snapshot manifest '%s' is incomplete", key)
+	}
+
+	return &manifest, nil
+}
+
+// streamSnapshot writes the chunks of a raft snapshot to w, checking its size and checksum before the last one.
+func (v *vault) streamSnapshot(ctx context.Context, key string, manifest *SnapshotManifest, w io.Writer) error {
+	digest := sha256.New()
+	var size int64
+	for i := range manifest.Chunks {
+		chunk, err := v.keyStore.Get(ctx, snapshotChunkKey(key, i))
+		if err != nil {
+			return errors.Wrapf(err, "error reading chunk %d of the raft snapshot", i)
+		}
+		digest.Write(chunk)
+		size += int64(len(chunk))
+
+		if i == manifest.Chunks-1 {
+			if err := verifySnapshot(manifest, size, digest); err != nil {
+				return err
+			}
+		}
+
+		if _, err := w.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// verifySnapshot checks the size and checksum of a snapshot read from the key store against its manifest.
+func verifySnapshot(manifest *SnapshotManifest, size int64, digest hash.Hash) error {
+	if size != manifest.Size {
+		return errors.Errorf("raft snapshot is %d bytes instead of %d, it was overwritten or saving it failed", size, manifest.Size)
+	}
+	if sum := hex.EncodeToString(digest.Sum(nil)); sum != manifest.SHA256 {
+		return errors.Errorf("raft snapshot checksum %s doesn't match %s, it was overwritten or saving it failed", sum, manifest.SHA256)
+	}
+
+	return nil
+}
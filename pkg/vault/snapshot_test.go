@@ -0,0 +1,118 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSnapshot returns a raft snapshot archive, which the Vault API client checks for a sealed checksum file.
+func testSnapshot(t *testing.T) []byte {
+	t.Helper()
+
+	state := make([]byte, 10000)
+	_, err := rand.Read(state)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{{"state.bin", state}, {"SHA256SUMS.sealed", []byte("sealed")}} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0o600, Size: int64(len(file.data))}))
+		_, err := tw.Write(file.data)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	return buf.Bytes()
+}
+
+func newSnapshotVault(t *testing.T, snapshot []byte) (*vault, func() (string, []byte)) {
+	t.Helper()
+
+	var restorePath string
+	var restored []byte
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "admin", r.Header.Get("X-Vault-Token"))
+		if r.Method == http.MethodGet {
+			w.Write(snapshot) //nolint:errcheck
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		restorePath, restored = r.URL.Path, body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	v.config = &Config{Token: "admin"}
+
+	return v, func() (string, []byte) { return restorePath, restored }
+}
+
+func TestSnapshotSaveRestore(t *testing.T) {
+	ctx := context.Background()
+	snapshot := testSnapshot(t)
+	v, restored := newSnapshotVault(t, snapshot)
+
+	manifest, err := v.SaveSnapshot(ctx, DefaultSnapshotKey, 1024)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(snapshot)), manifest.Size)
+	assert.Equal(t, (len(snapshot)+1023)/1024, manifest.Chunks)
+
+	chunk, err := v.keyStore.Get(ctx, snapshotChunkKey(DefaultSnapshotKey, 0))
+	require.NoError(t, err)
+	assert.Equal(t, snapshot[:1024], chunk)
+
+	_, err = v.RestoreSnapshot(ctx, DefaultSnapshotKey, true)
+	require.NoError(t, err)
+	path, body := restored()
+	assert.Equal(t, "/v1/sys/storage/raft/snapshot-force", path)
+	assert.Equal(t, snapshot, body)
+}
+
+func TestSnapshotRestoreCorrupted(t *testing.T) {
+	ctx := context.Background()
+	snapshot := testSnapshot(t)
+	v, restored := newSnapshotVault(t, snapshot)
+
+	_, err := v.SaveSnapshot(ctx, DefaultSnapshotKey, 1024)
+	require.NoError(t, err)
+	require.NoError(t, v.keyStore.Set(ctx, snapshotChunkKey(DefaultSnapshotKey, 1), make([]byte, 1024)))
+
+	_, err = v.RestoreSnapshot(ctx, DefaultSnapshotKey, false)
+	require.ErrorContains(t, err, "checksum")
+	path, _ := restored()
+	assert.Empty(t, path, "vault didn't accept the corrupted snapshot")
+
+	_, err = v.RestoreSnapshot(ctx, "missing", false)
+	assert.ErrorContains(t, err, "error reading raft snapshot manifest")
+}
@@ -0,0 +1,201 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// StartupSecretSource seeds a KV mount with the secrets of a local directory, tarball or JSON dump,
+// keeping their path hierarchy below the path of the startup secret. Exactly one of them is set.
+type StartupSecretSource struct {
+	// every directory holding files is a secret, with a key per file named after it
+	Directory string `mapstructure:"directory"`
+	// a tarball, gzipped or not, laid out like the directory
+	Archive string `mapstructure:"archive"`
+	// a JSON object with the data of the secrets by their relative path
+	JSON string `mapstructure:"json"`
+}
+
+// secretTree holds the data of secrets by their path relative to the source.
+type secretTree map[string]map[string]interface{}
+
+// add sets a key of the secret of the directory of the file, the hidden files and directories
+// like the ..data link of Kubernetes volumes are left out.
+func (t secretTree) add(file string, value []byte) {
+	for _, name := range strings.Split(file, "/") {
+		if strings.HasPrefix(name, ".") {
+			return
+		}
+	}
+
+	dir, key := path.Split(file)
+	dir = strings.TrimSuffix(dir, "/")
+	if t[dir] == nil {
+		t[dir] = map[string]interface{}{}
+	}
+	t[dir][key] = string(value)
+}
+
+// read returns the secrets of the source.
+func (s StartupSecretSource) read() (secretTree, error) {
+	switch {
+	case s.Directory != "":
+		return readSecretDirectory(os.DirFS(s.Directory))
+	case s.Archive != "":
+		return readSecretArchive(s.Archive)
+	case s.JSON != "":
+		return readSecretDump(s.JSON)
+	}
+
+	return nil, errors.New("startup secret source needs a directory, an archive or a json dump")
+}
+
+func readSecretDirectory(fsys fs.FS) (secretTree, error) {
+	tree := secretTree{}
+	err := fs.WalkDir(fsys, ".", func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if file != "." && strings.HasPrefix(entry.Name(), ".") {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		// The files of Kubernetes volumes are links
+		info, err := fs.Stat(fsys, file)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		value, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		tree.add(file, value)
+
+		return nil
+	})
+
+	return tree, errors.Wrap(err, "error reading secrets directory")
+}
+
+func readSecretArchive(file string) (secretTree, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "error opening secrets archive")
+	}
+	defer f.Close()
+
+	var reader io.Reader = bufio.NewReader(f)
+	if magic, _ := reader.(*bufio.Reader).Peek(2); len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrap(err, "error decompressing secrets archive")
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	tree := secretTree{}
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return tree, nil
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading secrets archive")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if !fs.ValidPath(name) {
+			return nil, errors.Errorf("secrets archive entry %s is out of the archive", header.Name)
+		}
+
+		value, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading secrets archive entry %s", header.Name)
+		}
+		tree.add(name, value)
+	}
+}
+
+func readSecretDump(file string) (secretTree, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading secrets dump")
+	}
+
+	var tree secretTree
+	if err := json.Unmarshal(content, &tree); err != nil {
+		return nil, errors.Wrap(err, "error parsing secrets dump")
+	}
+
+	cleaned := make(secretTree, len(tree))
+	for secretPath, data := range tree {
+		cleaned[strings.Trim(secretPath, "/")] = data
+	}
+
+	return cleaned, nil
+}
+
+// expandStartupSecrets replaces the startup secrets with a source by a 'kv' startup secret
+// per secret of the source, below their path.
+func expandStartupSecrets(startupSecrets []StartupSecret) ([]StartupSecret, error) {
+	expanded := make([]StartupSecret, 0, len(startupSecrets))
+	for _, startupSecret := range startupSecrets {
+		if startupSecret.Source == nil {
+			expanded = append(expanded, startupSecret)
+			continue
+		}
+
+		tree, err := startupSecret.Source.read()
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading the source of startup secret %s", startupSecret.Path)
+		}
+
+		for _, secretPath := range slices.Sorted(maps.Keys(tree)) {
+			secret := startupSecret
+			secret.Source = nil
+			secret.Path = strings.TrimSuffix(path.Join(startupSecret.Path, secretPath), "/")
+			secret.Data.Data = tree[secretPath]
+			expanded = append(expanded, secret)
+		}
+	}
+
+	return expanded, nil
+}
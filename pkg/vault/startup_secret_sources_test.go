@@ -0,0 +1,88 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var seededSecrets = secretTree{
+	"app":    {"token": "t0ken"},
+	"app/db": {"user": "admin", "password": "s3cret"},
+}
+
+func TestReadSecretDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "app", "db"), 0o700))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "..data"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "token"), []byte("t0ken"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "app", "db", "user"), []byte("admin"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "..data", "password"), []byte("s3cret"), 0o600))
+	// Kubernetes volumes link the files to the hidden ..data directory
+	require.NoError(t, os.Symlink(filepath.Join("..", "..", "..data", "password"), filepath.Join(dir, "app", "db", "password")))
+
+	tree, err := StartupSecretSource{Directory: dir}.read()
+	require.NoError(t, err)
+	assert.Equal(t, seededSecrets, tree)
+}
+
+func TestReadSecretArchive(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "secrets.tar.gz")
+	f, err := os.Create(archive)
+	require.NoError(t, err)
+	gzipWriter := gzip.NewWriter(f)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range map[string]string{"./app/token": "t0ken", "app/db/user": "admin", "app/db/password": "s3cret", "app/.hidden": "x"} {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o600, Size: int64(len(content))}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	require.NoError(t, f.Close())
+
+	tree, err := StartupSecretSource{Archive: archive}.read()
+	require.NoError(t, err)
+	assert.Equal(t, seededSecrets, tree)
+}
+
+func TestExpandStartupSecrets(t *testing.T) {
+	dump := filepath.Join(t.TempDir(), "secrets.json")
+	require.NoError(t, os.WriteFile(dump, []byte(`{"/app/": {"token": "t0ken"}, "app/db": {"user": "admin", "password": "s3cret"}}`), 0o600))
+
+	seeded := StartupSecret{Type: "kv", Path: "secret/data/migrated", Source: &StartupSecretSource{JSON: dump}}
+	seeded.Data.Options = map[string]interface{}{"cas": 0}
+	single := StartupSecret{Type: "pki", Path: "pki/config/ca"}
+
+	expanded, err := expandStartupSecrets([]StartupSecret{single, seeded})
+	require.NoError(t, err)
+	require.Len(t, expanded, 3)
+	assert.Equal(t, single, expanded[0])
+	assert.Equal(t, "secret/data/migrated/app", expanded[1].Path)
+	assert.Equal(t, map[string]interface{}{"token": "t0ken"}, expanded[1].Data.Data)
+	assert.Equal(t, "secret/data/migrated/app/db", expanded[2].Path)
+	assert.Equal(t, map[string]interface{}{"cas": 0}, expanded[2].Data.Options)
+	assert.Nil(t, expanded[2].Source)
+
+	_, err = expandStartupSecrets([]StartupSecret{{Type: "kv", Path: "secret", Source: &StartupSecretSource{}}})
+	assert.Error(t, err)
+}
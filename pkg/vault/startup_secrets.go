@@ -16,8 +16,6 @@ package vault
 
 import (
 	"context"
-	"fmt"
-	"log/slog"
 	"os"
 	"strings"
 
@@ -28,7 +26,8 @@ import (
 	crconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
 )
 
-type startupSecret struct {
+// StartupSecret is a secret of the startupSecrets section, written once with its data or seeded from a source.
+type StartupSecret struct {
 	Type        string `mapstructure:"type"`
 	Path        string `mapstructure:"path"`
 	MaxVersions *int   `mapstructure:"max_versions"`
@@ -37,6 +36,8 @@ type startupSecret struct {
 		Options      map[string]interface{}   `mapstructure:"options,omitempty"`
 		SecretKeyRef []map[string]interface{} `mapstructure:"secretKeyRef"`
 	} `mapstructure:"data"`
+	// seeds the secrets of a directory, tarball or JSON dump below the path instead of the data
+	Source *StartupSecretSource `mapstructure:"source"`
 }
 
 func getOrDefaultSecretData(ctx context.Context, m interface{}) (map[string]interface{}, error) {
@@ -77,8 +78,8 @@ func getOrDefaultSecretData(ctx context.Context, m interface{}) (map[string]inte
 // is a boundary-aware prefix of secretPath. A boundary means the secretPath
 // either equals the engine path or continues with a '/' separator, preventing
 // "secret" from matching "secret2/...".
-func matchKVEngine(secretPath string, secretEngines []secretEngine) *secretEngine {
-	var best *secretEngine
+func matchKVEngine(secretPath string, secretEngines []SecretsEngine) *SecretsEngine {
+	var best *SecretsEngine
 	for i := range secretEngines {
 		e := &secretEngines[i]
 		if e.Type != "kv" {
@@ -94,7 +95,7 @@ func matchKVEngine(secretPath string, secretEngines []secretEngine) *secretEngin
 	return best
 }
 
-func vaultKVVersion(secretPath string, secretEngines []secretEngine) string {
+func vaultKVVersion(secretPath string, secretEngines []SecretsEngine) string {
 	if e := matchKVEngine(secretPath, secretEngines); e != nil {
 		return e.Options["version"]
 	}
@@ -102,14 +103,14 @@ func vaultKVVersion(secretPath string, secretEngines []secretEngine) string {
 }
 
 // vaultKVMaxVersions returns the max_versions configured on the secret engine for the given path.
-func vaultKVMaxVersions(secretPath string, secretEngines []secretEngine) *int {
+func vaultKVMaxVersions(secretPath string, secretEngines []SecretsEngine) *int {
 	if e := matchKVEngine(secretPath, secretEngines); e != nil {
 		return e.MaxVersions
 	}
 	return nil
 }
 
-func readStartupSecret(ctx context.Context, startupSecret startupSecret, secretEngines []secretEngine) (string, map[string]interface{}, error) {
+func readStartupSecret(ctx context.Context, startupSecret StartupSecret, secretEngines []SecretsEngine) (string, map[string]interface{}, error) {
 	if len(startupSecret.Data.Data) > 0 && len(startupSecret.Data.SecretKeyRef) > 0 {
 		return "", nil, errors.New("the startup secret data source should be either 'data' or 'secretKeyRef'." +
 			"They are mutually exclusive and cannot be used together")
@@ -152,28 +153,30 @@ func generateCertPayload(data interface{}) (map[string]interface{}, error) {
 }
 
 func (v *vault) configureStartupSecrets(ctx context.Context) error {
-	managedStartupSecrets := v.externalConfig.StartupSecrets
+	managedStartupSecrets, err := expandStartupSecrets(v.externalConfig.StartupSecrets)
+	if err != nil {
+		return err
+	}
+
 	for _, startupSecret := range managedStartupSecrets {
+		if startupSecret.Type != "kv" && startupSecret.Type != "pki" {
+			return errors.Errorf("'%s' startup secret type is not supported, only 'kv' or 'pki'", startupSecret.Type)
+		}
+	}
+
+	return applyItems(v, SectionStartupSecrets, managedStartupSecrets, func(startupSecret StartupSecret) string { return startupSecret.Path }, func(startupSecret StartupSecret) error {
 		var err error
-		switch startupSecret.Type {
-		case "kv":
+		if startupSecret.Type == "kv" {
 			err = v.handleKVSecret(ctx, startupSecret)
-
-		case "pki":
+		} else {
 			err = v.handlePKISecret(ctx, startupSecret)
-
-		default:
-			return errors.Errorf("'%s' startup secret type is not supported, only 'kv' or 'pki'", startupSecret.Type)
 		}
-		if err != nil {
-			return errors.Wrap(err, "error handling startup secret")
-		}
-	}
 
-	return nil
+		return errors.Wrap(err, "error handling startup secret")
+	})
 }
 
-func (v *vault) handleKVSecret(ctx context.Context, startupSecret startupSecret) error {
+func (v *vault) handleKVSecret(ctx context.Context, startupSecret StartupSecret) error {
 	path, data, err := readStartupSecret(ctx, startupSecret, v.externalConfig.Secrets)
 	if err != nil {
 		return errors.Wrap(err, "unable to read 'kv' startup secret")
@@ -207,7 +210,7 @@ func (v *vault) handleKVSecret(ctx context.Context, startupSecret startupSecret)
 		metadataData := map[string]interface{}{
 			"max_versions": *maxVersions,
 		}
-		slog.Info(fmt.Sprintf("setting max_versions=%d for secret %s", *maxVersions, path))
+		v.log().Info("setting max_versions of secret", "section", SectionStartupSecrets, "path", path, "max_versions", *maxVersions)
 		if _, err := v.writeWithWarningCheck(metadataPath, metadataData); err != nil {
 			return errors.Wrapf(err, "error setting max_versions for secret '%s'", path)
 		}
@@ -217,11 +220,12 @@ func (v *vault) handleKVSecret(ctx context.Context, startupSecret startupSecret)
 	if err != nil {
 		return errors.Wrapf(err, "error writing data for startup 'kv' secret '%s'", path)
 	}
+	v.report.updated(SectionStartupSecrets, path)
 
 	return nil
 }
 
-func (v *vault) handlePKISecret(ctx context.Context, startupSecret startupSecret) error {
+func (v *vault) handlePKISecret(ctx context.Context, startupSecret StartupSecret) error {
 	path, data, err := readStartupSecret(ctx, startupSecret, v.externalConfig.Secrets)
 	if err != nil {
 		return errors.Wrap(err, "unable to read 'pki' startup secret")
@@ -236,6 +240,7 @@ func (v *vault) handlePKISecret(ctx context.Context, startupSecret startupSecret
 	if err != nil {
 		return errors.Wrapf(err, "error writing data for startup 'pki' secret '%s'", path)
 	}
+	v.report.updated(SectionStartupSecrets, path)
 
 	return nil
 }
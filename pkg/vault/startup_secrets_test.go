@@ -18,11 +18,9 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
-	"net/http/httptest"
 	"sync"
 	"testing"
 
-	"github.com/hashicorp/vault/api"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,7 +30,7 @@ func intPtr(i int) *int {
 }
 
 func TestVaultKVMaxVersions(t *testing.T) {
-	engines := []secretEngine{
+	engines := []SecretsEngine{
 		{Path: "staging/kv", Type: "kv", MaxVersions: intPtr(10)},
 		{Path: "staging/kv/team", Type: "kv", MaxVersions: intPtr(5)},
 		{Path: "secret", Type: "kv", MaxVersions: intPtr(15)},
@@ -91,7 +89,7 @@ func TestVaultKVMaxVersions(t *testing.T) {
 }
 
 func TestVaultKVVersion(t *testing.T) {
-	engines := []secretEngine{
+	engines := []SecretsEngine{
 		{Path: "staging/kv", Type: "kv", Options: map[string]string{"version": "2"}},
 		{Path: "staging/kv/team", Type: "kv", Options: map[string]string{"version": "2"}},
 		{Path: "secret", Type: "kv", Options: map[string]string{"version": "2"}},
@@ -155,8 +153,8 @@ type writeRecord struct {
 	Data map[string]interface{}
 }
 
-// newFakeVaultServer returns an httptest.Server that records writes and a slice to inspect them.
-func newFakeVaultServer(t *testing.T) (*httptest.Server, *[]writeRecord, *sync.Mutex) {
+// newFakeVaultHandler returns a fake Vault handler that records writes and a slice to inspect them.
+func newFakeVaultHandler(t *testing.T) (http.Handler, *[]writeRecord, *sync.Mutex) {
 	t.Helper()
 	var writes []writeRecord
 	var mu sync.Mutex
@@ -181,27 +179,11 @@ func newFakeVaultServer(t *testing.T) (*httptest.Server, *[]writeRecord, *sync.M
 		json.NewEncoder(w).Encode(map[string]interface{}{}) //nolint:errcheck
 	})
 
-	srv := httptest.NewServer(mux)
-	return srv, &writes, &mu
-}
-
-func newTestVault(t *testing.T, serverURL string, secrets []secretEngine) *vault {
-	t.Helper()
-	cfg := api.DefaultConfig()
-	cfg.Address = serverURL
-	cl, err := api.NewClient(cfg)
-	require.NoError(t, err)
-
-	return &vault{
-		cl: cl,
-		externalConfig: &externalConfig{
-			Secrets: secrets,
-		},
-	}
+	return mux, &writes, &mu
 }
 
 func TestHandleKVSecret_MaxVersionsOverride(t *testing.T) {
-	engines := []secretEngine{
+	engines := []SecretsEngine{
 		{
 			Path:        "staging/kv",
 			Type:        "kv",
@@ -212,7 +194,7 @@ func TestHandleKVSecret_MaxVersionsOverride(t *testing.T) {
 
 	tests := []struct {
 		name                 string
-		startupSecret        startupSecret
+		startupSecret        StartupSecret
 		expectMetadataWrite  bool
 		expectedMetadataPath string
 		expectedDataPath     string
@@ -220,7 +202,7 @@ func TestHandleKVSecret_MaxVersionsOverride(t *testing.T) {
 	}{
 		{
 			name: "startup secret overrides engine max_versions",
-			startupSecret: startupSecret{
+			startupSecret: StartupSecret{
 				Type:        "kv",
 				Path:        "staging/kv/data/app1",
 				MaxVersions: intPtr(20),
@@ -239,7 +221,7 @@ func TestHandleKVSecret_MaxVersionsOverride(t *testing.T) {
 		},
 		{
 			name: "uses engine default when startup has no max_versions",
-			startupSecret: startupSecret{
+			startupSecret: StartupSecret{
 				Type: "kv",
 				Path: "staging/kv/data/app2",
 				Data: struct {
@@ -259,10 +241,9 @@ func TestHandleKVSecret_MaxVersionsOverride(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			srv, writes, mu := newFakeVaultServer(t)
-			defer srv.Close()
-
-			v := newTestVault(t, srv.URL, engines)
+			handler, writes, mu := newFakeVaultHandler(t)
+			v := newTestVault(t, handler)
+			v.externalConfig.Secrets = engines
 
 			err := v.handleKVSecret(context.Background(), tt.startupSecret)
 			require.NoError(t, err)
@@ -286,7 +267,7 @@ func TestHandleKVSecret_MaxVersionsOverride(t *testing.T) {
 }
 
 func TestHandleKVSecret_NoMaxVersions(t *testing.T) {
-	engines := []secretEngine{
+	engines := []SecretsEngine{
 		{
 			Path:    "staging/kv",
 			Type:    "kv",
@@ -295,7 +276,7 @@ func TestHandleKVSecret_NoMaxVersions(t *testing.T) {
 		},
 	}
 
-	secret := startupSecret{
+	secret := StartupSecret{
 		Type: "kv",
 		Path: "staging/kv/data/app1",
 		// No MaxVersions set on startup secret
@@ -308,10 +289,9 @@ func TestHandleKVSecret_NoMaxVersions(t *testing.T) {
 		},
 	}
 
-	srv, writes, mu := newFakeVaultServer(t)
-	defer srv.Close()
-
-	v := newTestVault(t, srv.URL, engines)
+	handler, writes, mu := newFakeVaultHandler(t)
+	v := newTestVault(t, handler)
+	v.externalConfig.Secrets = engines
 
 	err := v.handleKVSecret(context.Background(), secret)
 	require.NoError(t, err)
@@ -324,7 +304,7 @@ func TestHandleKVSecret_NoMaxVersions(t *testing.T) {
 }
 
 func TestHandleKVSecret_KVv1RejectsMaxVersions(t *testing.T) {
-	engines := []secretEngine{
+	engines := []SecretsEngine{
 		{
 			Path:    "legacy/kv",
 			Type:    "kv",
@@ -332,7 +312,7 @@ func TestHandleKVSecret_KVv1RejectsMaxVersions(t *testing.T) {
 		},
 	}
 
-	secret := startupSecret{
+	secret := StartupSecret{
 		Type:        "kv",
 		Path:        "legacy/kv/app1",
 		MaxVersions: intPtr(5),
@@ -345,10 +325,9 @@ func TestHandleKVSecret_KVv1RejectsMaxVersions(t *testing.T) {
 		},
 	}
 
-	srv, _, _ := newFakeVaultServer(t)
-	defer srv.Close()
-
-	v := newTestVault(t, srv.URL, engines)
+	handler, _, _ := newFakeVaultHandler(t)
+	v := newTestVault(t, handler)
+	v.externalConfig.Secrets = engines
 
 	err := v.handleKVSecret(context.Background(), secret)
 	require.Error(t, err)
@@ -356,7 +335,7 @@ func TestHandleKVSecret_KVv1RejectsMaxVersions(t *testing.T) {
 }
 
 func TestHandleKVSecret_MissingDataSegmentRejectsMaxVersions(t *testing.T) {
-	engines := []secretEngine{
+	engines := []SecretsEngine{
 		{
 			Path:    "staging/kv",
 			Type:    "kv",
@@ -364,7 +343,7 @@ func TestHandleKVSecret_MissingDataSegmentRejectsMaxVersions(t *testing.T) {
 		},
 	}
 
-	secret := startupSecret{
+	secret := StartupSecret{
 		Type:        "kv",
 		Path:        "staging/kv/app1", // missing /data/ segment
 		MaxVersions: intPtr(5),
@@ -377,10 +356,9 @@ func TestHandleKVSecret_MissingDataSegmentRejectsMaxVersions(t *testing.T) {
 		},
 	}
 
-	srv, _, _ := newFakeVaultServer(t)
-	defer srv.Close()
-
-	v := newTestVault(t, srv.URL, engines)
+	handler, _, _ := newFakeVaultHandler(t)
+	v := newTestVault(t, handler)
+	v.externalConfig.Secrets = engines
 
 	err := v.handleKVSecret(context.Background(), secret)
 	require.Error(t, err)
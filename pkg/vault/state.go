@@ -0,0 +1,34 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"fmt"
+	"sync"
+)
+
+// stateMu serializes the read-modify-writes of the configure state in the key store,
+// which the helpers of the targets configured in parallel share.
+var stateMu sync.Mutex
+
+// stateKey returns the key store entry of the configure state under the given key,
+// suffixed with the name of the target if it shares the key store with others.
+func (v *vault) stateKey(key string) string {
+	if v.config == nil || v.config.StateKeySuffix == "" {
+		return key
+	}
+
+	return fmt.Sprintf("%s-%s", key, v.config.StateKeySuffix)
+}
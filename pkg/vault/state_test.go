@@ -0,0 +1,72 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateKeysPerTarget(t *testing.T) {
+	ctx := context.Background()
+	store := &memKV{}
+	require.NoError(t, store.Set(ctx, keyRotationState, []byte(`{"aws/config/root":"legacy"}`)))
+
+	eu := &vault{keyStore: store, config: &Config{StateKeySuffix: "eu"}}
+	us := &vault{keyStore: store, config: &Config{StateKeySuffix: "us"}}
+
+	// The targets configured in parallel generate a single hmac key and don't lose each other's rotations
+	var wg sync.WaitGroup
+	keys := make([][]byte, 20)
+	for i := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			target := eu
+			if i%2 == 1 {
+				target = us
+			}
+
+			key, err := target.hmacKey(ctx)
+			assert.NoError(t, err)
+			keys[i] = key
+			assert.NoError(t, target.storeRotatedCredentials(ctx, fmt.Sprintf("database%d/config", i), "digest"))
+		}()
+	}
+	wg.Wait()
+
+	for _, key := range keys {
+		assert.Equal(t, keys[0], key)
+	}
+
+	euState, err := eu.rotationState(ctx)
+	require.NoError(t, err)
+	usState, err := us.rotationState(ctx)
+	require.NoError(t, err)
+	assert.Len(t, euState, 11)
+	assert.Len(t, usState, 11)
+	assert.Equal(t, "legacy", euState["aws/config/root"], "the state stored before it was kept per target is carried over")
+	assert.Contains(t, euState, "database0/config")
+	assert.NotContains(t, euState, "database1/config")
+
+	assert.Equal(t, "vault-config-fingerprint-eu", eu.stateKey(keyConfigFingerprint))
+	assert.Equal(t, keyLicenseDigest, (&vault{config: &Config{}}).stateKey(keyLicenseDigest))
+}
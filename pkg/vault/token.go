@@ -0,0 +1,121 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"github.com/spf13/cast"
+)
+
+// Results of renewing the configurer token, as recorded in the report.
+const (
+	TokenRenewed     = "renewed"
+	TokenRenewFailed = "failed"
+)
+
+// checkToken looks up the token configure logged in with and renews it once less than half of its
+// TTL is left, so an expiring token shows up in the metrics before the runs fail with 403s.
+// Errors are only logged, a token which can't be looked up fails the run at its first request anyway.
+func (v *vault) checkToken(ctx context.Context) {
+	token, err := v.cl.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		v.log().Warn("error looking up the token", "error", err)
+		return
+	}
+
+	ttl, err := token.TokenTTL()
+	if err != nil {
+		v.log().Warn("error reading the ttl of the token", "error", err)
+		return
+	}
+
+	// Tokens without a TTL (e.g. root tokens) never expire
+	if ttl == 0 {
+		v.tokenExpiry.Store(0)
+		return
+	}
+
+	renewable, _ := token.TokenIsRenewable()
+	creationTTL := time.Duration(cast.ToInt64(token.Data["creation_ttl"])) * time.Second
+	if renewable && ttl < creationTTL/2 {
+		ttl, err = v.renewToken(ctx)
+		if err != nil {
+			v.log().Warn("error renewing the token", "error", err)
+			v.report.tokenRenewal(TokenRenewFailed)
+			ttl, _ = token.TokenTTL()
+		} else {
+			v.log().Info("renewed the token", "ttl", ttl)
+			v.report.tokenRenewal(TokenRenewed)
+		}
+	}
+
+	v.tokenExpiry.Store(time.Now().Add(ttl).Unix())
+}
+
+// renewToken renews the token configure logged in with and returns its new TTL.
+func (v *vault) renewToken(ctx context.Context) (time.Duration, error) {
+	secret, err := v.cl.Auth().Token().RenewSelfWithContext(ctx, 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "error renewing token")
+	}
+	if secret == nil || secret.Auth == nil {
+		return 0, errors.New("no auth data in token renewal response")
+	}
+
+	return time.Duration(secret.Auth.LeaseDuration) * time.Second, nil
+}
+
+// TokenExpiry returns the expiration time of the token configure logged in with at the last run,
+// or the zero time if it never expires or wasn't looked up yet.
+func (v *vault) TokenExpiry() time.Time {
+	expiry := v.tokenExpiry.Load()
+	if expiry == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(expiry, 0)
+}
+
+// CreateToken logs in like configure does and creates an orphan token with the policies, which can
+// be handed out instead of the root token. The token doesn't expire if the ttl is 0.
+func (v *vault) CreateToken(ctx context.Context, policies []string, ttl time.Duration) (string, error) {
+	if err := v.login(ctx); err != nil {
+		return "", err
+	}
+	defer v.cl.SetToken("")
+	defer v.revokeRootToken(ctx, v.cl.Token())
+
+	request := &api.TokenCreateRequest{
+		Policies:    policies,
+		DisplayName: "bank-vaults",
+	}
+	if ttl > 0 {
+		request.TTL = ttl.String()
+	}
+
+	secret, err := v.cl.Auth().Token().CreateOrphanWithContext(ctx, request)
+	if err != nil {
+		return "", errors.Wrap(err, "error creating token")
+	}
+	if secret == nil || secret.Auth == nil || secret.Auth.ClientToken == "" {
+		return "", errors.New("no token in token creation response")
+	}
+
+	return secret.Auth.ClientToken, nil
+}
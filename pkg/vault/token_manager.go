@@ -0,0 +1,172 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cast"
+)
+
+// ManageToken keeps the token configure logs in with valid between the runs until the context is done:
+// it is checked at every interval and renewed once less than half of its TTL is left, logged in again
+// if renewing it fails, and revoked when the context is done. Only the tokens of auth method logins and
+// the configurer token are managed, the configurer token is stored for the next start instead of revoked.
+func (v *vault) ManageToken(ctx context.Context, interval time.Duration) {
+	v.tokenMu.Lock()
+	v.manageToken = true
+	v.tokenMu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Revoking the token must not be aborted by the shutdown
+			v.releaseManagedToken(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			v.refreshManagedToken(ctx)
+		}
+	}
+}
+
+// tokenManageable tells if the token configure logs in with can be kept between the runs. Root tokens
+// never expire, static tokens are renewed by whoever hands them out.
+func (v *vault) tokenManageable() bool {
+	if v.usesRootToken() {
+		return v.config.ConfigurerToken
+	}
+
+	return v.config.Token == "" && v.config.TokenFile == ""
+}
+
+func (v *vault) refreshManagedToken(ctx context.Context) {
+	v.tokenMu.Lock()
+	defer v.tokenMu.Unlock()
+
+	if v.managedToken == "" {
+		return
+	}
+
+	ttl, err := v.renewManagedToken(ctx)
+	if err == nil {
+		if ttl == 0 {
+			v.tokenExpiry.Store(0)
+		} else {
+			v.tokenExpiry.Store(time.Now().Add(ttl).Unix())
+		}
+
+		return
+	}
+
+	v.log().Warn("error renewing the managed token, logging in again", "error", err)
+	v.managedToken = ""
+	if err := v.managedLogin(ctx); err != nil {
+		v.log().Warn("error logging in again, the next run logs in instead", "error", err)
+	}
+}
+
+// renewManagedToken looks up the managed token, renews it once less than half of its TTL is left
+// and returns its TTL.
+func (v *vault) renewManagedToken(ctx context.Context) (time.Duration, error) {
+	cl, err := v.cl.Clone()
+	if err != nil {
+		return 0, errors.Wrap(err, "error cloning client")
+	}
+	cl.SetToken(v.managedToken)
+
+	token, err := cl.Auth().Token().LookupSelfWithContext(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "error looking up token")
+	}
+
+	ttl, err := token.TokenTTL()
+	if err != nil {
+		return 0, errors.Wrap(err, "error reading token ttl")
+	}
+
+	creationTTL := time.Duration(cast.ToInt64(token.Data["creation_ttl"])) * time.Second
+	if ttl == 0 || ttl >= creationTTL/2 {
+		return ttl, nil
+	}
+
+	if renewable, _ := token.TokenIsRenewable(); !renewable {
+		return 0, errors.New("token is not renewable")
+	}
+
+	secret, err := cl.Auth().Token().RenewSelfWithContext(ctx, 0)
+	if err != nil {
+		return 0, errors.Wrap(err, "error renewing token")
+	}
+	if secret == nil || secret.Auth == nil {
+		return 0, errors.New("no auth data in token renewal response")
+	}
+
+	ttl = time.Duration(secret.Auth.LeaseDuration) * time.Second
+	v.log().Info("renewed the managed token", "ttl", ttl)
+
+	return ttl, nil
+}
+
+// managedLogin logs in like the last configure run did and keeps the token for the next runs.
+func (v *vault) managedLogin(ctx context.Context) error {
+	defer v.cl.SetToken("")
+
+	var err error
+	if v.usesRootToken() {
+		err = v.configurerLogin(ctx, v.externalConfig)
+	} else {
+		err = v.login(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	v.managedToken = v.cl.Token()
+	v.log().Info("logged in again with a new managed token")
+
+	return nil
+}
+
+// releaseManagedToken stops managing the token and revokes it, unless it is the stored configurer token.
+func (v *vault) releaseManagedToken(ctx context.Context) {
+	v.tokenMu.Lock()
+	defer v.tokenMu.Unlock()
+
+	token := v.managedToken
+	v.managedToken = ""
+	v.manageToken = false
+	if token == "" || v.usesRootToken() {
+		return
+	}
+
+	cl, err := v.cl.Clone()
+	if err != nil {
+		v.log().Error("error cloning client to revoke the managed token, it stays valid", "error", err)
+		return
+	}
+	cl.SetToken(token)
+
+	if err := cl.Auth().Token().RevokeSelfWithContext(ctx, ""); err != nil {
+		v.log().Error("error revoking the managed token, it stays valid until it expires", "error", err)
+		return
+	}
+
+	v.log().Info("revoked the managed token")
+}
@@ -0,0 +1,140 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// managedTokenVault is a fake Vault handing out a new token at every approle login, and recording
+// the renewed and revoked tokens.
+type managedTokenVault struct {
+	mu          sync.Mutex
+	logins      int
+	lookup      string
+	renewStatus int
+	renewed     []string
+	revoked     []string
+}
+
+func newManagedTokenVault(t *testing.T, fake *managedTokenVault) *vault {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, _ *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.logins++
+		fmt.Fprintf(w, `{"auth":{"client_token":"login-%d","lease_duration":3600,"renewable":true}}`, fake.logins)
+	})
+	mux.HandleFunc("/v1/auth/token/lookup-self", func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(fake.lookup)) //nolint:errcheck
+	})
+	mux.HandleFunc("/v1/auth/token/renew-self", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.renewed = append(fake.renewed, r.Header.Get("X-Vault-Token"))
+		w.WriteHeader(fake.renewStatus)
+		if fake.renewStatus == http.StatusOK {
+			w.Write([]byte(`{"auth":{"client_token":"token","lease_duration":3600,"renewable":true}}`)) //nolint:errcheck
+		}
+	})
+	mux.HandleFunc("/v1/auth/token/revoke-self", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.revoked = append(fake.revoked, r.Header.Get("X-Vault-Token"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+	v := newTestVault(t, mux)
+	v.config = &Config{AppRoleAuth: AppRoleAuth{Credentials: &memAppRoleCredentials{roleID: "role-id", secretID: "secret-id"}}}
+
+	return v
+}
+
+func TestRefreshManagedTokenRenewsAfterHalfTTL(t *testing.T) {
+	fake := &managedTokenVault{lookup: `{"data":{"ttl":600,"creation_ttl":3600,"renewable":true}}`, renewStatus: http.StatusOK}
+	v := newManagedTokenVault(t, fake)
+	v.managedToken = "managed"
+
+	v.refreshManagedToken(context.Background())
+
+	assert.Equal(t, []string{"managed"}, fake.renewed)
+	assert.Equal(t, "managed", v.managedToken)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), v.TokenExpiry(), 5*time.Second)
+}
+
+func TestRefreshManagedTokenKeepsFreshToken(t *testing.T) {
+	fake := &managedTokenVault{lookup: `{"data":{"ttl":3000,"creation_ttl":3600,"renewable":true}}`, renewStatus: http.StatusOK}
+	v := newManagedTokenVault(t, fake)
+	v.managedToken = "managed"
+
+	v.refreshManagedToken(context.Background())
+
+	assert.Empty(t, fake.renewed)
+	assert.Equal(t, "managed", v.managedToken)
+}
+
+func TestRefreshManagedTokenLogsInAgainWhenRenewalFails(t *testing.T) {
+	fake := &managedTokenVault{lookup: `{"data":{"ttl":600,"creation_ttl":3600,"renewable":true}}`, renewStatus: http.StatusForbidden}
+	v := newManagedTokenVault(t, fake)
+	v.managedToken = "managed"
+
+	v.refreshManagedToken(context.Background())
+
+	assert.Equal(t, 1, fake.logins)
+	assert.Equal(t, "login-1", v.managedToken)
+	assert.Empty(t, v.cl.Token())
+}
+
+func TestManageTokenRevokesOnShutdown(t *testing.T) {
+	fake := &managedTokenVault{}
+	v := newManagedTokenVault(t, fake)
+	v.managedToken = "managed"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	v.ManageToken(ctx, time.Hour)
+
+	assert.Equal(t, []string{"managed"}, fake.revoked)
+	assert.Empty(t, v.managedToken)
+	assert.False(t, v.manageToken)
+}
+
+func TestManageTokenKeepsConfigurerToken(t *testing.T) {
+	fake := &managedTokenVault{}
+	v := newManagedTokenVault(t, fake)
+	v.config = &Config{ConfigurerToken: true}
+	v.managedToken = "configurer"
+
+	v.releaseManagedToken(context.Background())
+
+	assert.Empty(t, fake.revoked)
+	assert.Empty(t, v.managedToken)
+}
+
+func TestTokenManageable(t *testing.T) {
+	assert.False(t, (&vault{config: &Config{}}).tokenManageable())
+	assert.True(t, (&vault{config: &Config{ConfigurerToken: true}}).tokenManageable())
+	assert.False(t, (&vault{config: &Config{Token: "token"}}).tokenManageable())
+	assert.False(t, (&vault{config: &Config{TokenFile: "token", CertAuth: CertAuth{Enabled: true}}}).tokenManageable())
+	assert.True(t, (&vault{config: &Config{CertAuth: CertAuth{Enabled: true}}}).tokenManageable())
+}
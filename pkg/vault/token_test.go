@@ -0,0 +1,106 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenVault returns a vault talking to a fake token API answering the lookups with the given token,
+// and a counter of the renewals it received.
+func newTokenVault(t *testing.T, lookup string, renewStatus int) (*vault, *int) {
+	t.Helper()
+
+	renewals := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/token/lookup-self", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(lookup)) //nolint:errcheck
+	})
+	mux.HandleFunc("/v1/auth/token/renew-self", func(w http.ResponseWriter, _ *http.Request) {
+		renewals++
+		w.WriteHeader(renewStatus)
+		if renewStatus == http.StatusOK {
+			w.Write([]byte(`{"auth":{"client_token":"token","lease_duration":3600,"renewable":true}}`)) //nolint:errcheck
+		}
+	})
+	return newTestVault(t, mux), &renewals
+}
+
+func TestCheckTokenRenewsAfterHalfTTL(t *testing.T) {
+	v, renewals := newTokenVault(t, `{"data":{"ttl":600,"creation_ttl":3600,"renewable":true}}`, http.StatusOK)
+
+	v.checkToken(context.Background())
+
+	assert.Equal(t, 1, *renewals)
+	assert.Equal(t, TokenRenewed, v.report.TokenRenewal)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), v.TokenExpiry(), 5*time.Second)
+}
+
+func TestCheckTokenNotDue(t *testing.T) {
+	v, renewals := newTokenVault(t, `{"data":{"ttl":3000,"creation_ttl":3600,"renewable":true}}`, http.StatusOK)
+
+	v.checkToken(context.Background())
+
+	assert.Equal(t, 0, *renewals)
+	assert.Empty(t, v.report.TokenRenewal)
+	assert.WithinDuration(t, time.Now().Add(3000*time.Second), v.TokenExpiry(), 5*time.Second)
+}
+
+func TestCheckTokenRenewalFailure(t *testing.T) {
+	v, renewals := newTokenVault(t, `{"data":{"ttl":600,"creation_ttl":3600,"renewable":true}}`, http.StatusForbidden)
+
+	v.checkToken(context.Background())
+
+	assert.Equal(t, 1, *renewals)
+	assert.Equal(t, TokenRenewFailed, v.report.TokenRenewal)
+	assert.WithinDuration(t, time.Now().Add(600*time.Second), v.TokenExpiry(), 5*time.Second)
+}
+
+func TestCheckTokenWithoutTTL(t *testing.T) {
+	v, renewals := newTokenVault(t, `{"data":{"ttl":0,"creation_ttl":0,"renewable":false}}`, http.StatusOK)
+
+	v.checkToken(context.Background())
+
+	assert.Equal(t, 0, *renewals)
+	assert.True(t, v.TokenExpiry().IsZero())
+}
+
+func TestCreateToken(t *testing.T) {
+	var request map[string]interface{}
+	var token string
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/auth/token/create-orphan", r.URL.Path)
+		token = r.Header.Get("X-Vault-Token")
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		w.Write([]byte(`{"auth":{"client_token":"app-token"}}`)) //nolint:errcheck
+	}))
+	v.config = &Config{Token: "configurer-token"}
+
+	created, err := v.CreateToken(context.Background(), []string{"app"}, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, "app-token", created)
+	assert.Equal(t, "configurer-token", token)
+	assert.Equal(t, []interface{}{"app"}, request["policies"])
+	assert.Equal(t, "1h0m0s", request["ttl"])
+	assert.Empty(t, v.cl.Token())
+}
@@ -0,0 +1,78 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/hashicorp/vault/api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/bank-vaults/bank-vaults/pkg/vault"
+
+var tracer = otel.Tracer(tracerName)
+
+// spanContext holds the context of the span the Vault API calls are made in.
+type spanContext struct {
+	context.Context
+}
+
+// traceSection runs the config section in a span and tracks it in the report. Most Vault API calls
+// don't take a context, so the span is also stored for the request callback to propagate it.
+func (v *vault) traceSection(ctx context.Context, section string, fn func(ctx context.Context) error) error {
+	// A shutdown aborts the run between the sections, the section in progress is finished
+	if err := ctx.Err(); err != nil {
+		return errors.Wrap(err, "configure run aborted")
+	}
+
+	ctx, span := tracer.Start(ctx, "configure "+section, trace.WithAttributes(attribute.String("vault.config.section", section)))
+	defer span.End()
+
+	v.spanCtx.Store(spanContext{ctx})
+	defer v.spanCtx.Store(spanContext{context.Background()})
+
+	err := v.report.track(section, func() error { return fn(ctx) })
+	endSpan(span, err)
+
+	return err
+}
+
+// injectTraceContext is a Vault client request callback propagating the current section span
+// in the request headers, so the API calls made without a context are traced as its children.
+func (v *vault) injectTraceContext(req *api.Request) {
+	current, ok := v.spanCtx.Load().(spanContext)
+	if !ok {
+		return
+	}
+
+	if req.Headers == nil {
+		req.Headers = http.Header{}
+	}
+	otel.GetTextMapPropagator().Inject(current, propagation.HeaderCarrier(req.Headers))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
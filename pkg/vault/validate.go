@@ -0,0 +1,145 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"emperror.dev/errors"
+)
+
+// ValidateConfig checks an external config without contacting Vault: it decodes it like configure does,
+// parses and lints the policy rules and looks for duplicate paths and names. All the problems found are returned.
+func ValidateConfig(config map[string]interface{}) []error {
+	loadedConfig, err := (&vault{}).loadExternalConfig(config)
+	if err != nil {
+		return []error{err}
+	}
+
+	var errs []error
+	problem := func(format string, args ...interface{}) {
+		errs = append(errs, errors.Errorf(format, args...))
+	}
+
+	audits := map[string]bool{}
+	for i, audit := range loadedConfig.Audit {
+		if audit.Type == "" {
+			problem("audit[%d]: type is required", i)
+			continue
+		}
+		path := mountPath(audit.Path, audit.Type) + "/"
+		if audits[path] {
+			problem("audit[%d]: duplicate audit device path %s", i, path)
+		}
+		audits[path] = true
+	}
+
+	auths := map[string]bool{}
+	for i, auth := range loadedConfig.Auth {
+		if auth.Type == "" {
+			problem("auth[%d]: type is required", i)
+			continue
+		}
+		path := mountPath(auth.Path, auth.Type) + "/"
+		if auths[path] {
+			problem("auth[%d]: duplicate auth method path %s", i, path)
+		}
+		auths[path] = true
+	}
+
+	secrets := map[string]bool{}
+	for i, secretEngine := range loadedConfig.Secrets {
+		if secretEngine.Type == "" {
+			problem("secrets[%d]: type is required", i)
+			continue
+		}
+		path := mountPath(secretEngine.Path, secretEngine.Type) + "/"
+		if secrets[path] {
+			problem("secrets[%d]: duplicate secret engine path %s", i, path)
+		}
+		secrets[path] = true
+	}
+
+	plugins := map[string]bool{}
+	for i, plugin := range loadedConfig.Plugins {
+		key := plugin.Type + "/" + plugin.Name
+		if plugins[key] {
+			problem("plugins[%d]: duplicate %s plugin %s", i, plugin.Type, plugin.Name)
+		}
+		plugins[key] = true
+	}
+
+	policies := map[string]bool{}
+	for i, policyConfig := range loadedConfig.Policies {
+		if policyConfig.Name == "" {
+			problem("policies[%d]: name is required", i)
+			continue
+		}
+		if policies[policyConfig.Name] {
+			problem("policies[%d]: duplicate policy %s", i, policyConfig.Name)
+		}
+		policies[policyConfig.Name] = true
+
+		// Without the mounts the accessor placeholders are left in, they don't break the HCL
+		if _, err := initPoliciesConfig([]Policy{policyConfig}, nil); err != nil {
+			problem("policies[%d]: %s", i, err.Error())
+		}
+	}
+
+	groups := map[string]bool{}
+	for i, group := range loadedConfig.Groups {
+		if groups[group.Name] {
+			problem("groups[%d]: duplicate group %s", i, group.Name)
+		}
+		groups[group.Name] = true
+	}
+
+	startupSecrets := map[string]bool{}
+	for i, startupSecret := range loadedConfig.StartupSecrets {
+		if startupSecrets[startupSecret.Path] {
+			problem("startupSecrets[%d]: duplicate startup secret path %s", i, startupSecret.Path)
+		}
+		startupSecrets[startupSecret.Path] = true
+
+		if source := startupSecret.Source; source != nil {
+			if startupSecret.Type != "kv" {
+				problem("startupSecrets[%d]: a source is only supported by 'kv' startup secrets", i)
+			}
+			if len(startupSecret.Data.Data) > 0 || len(startupSecret.Data.SecretKeyRef) > 0 {
+				problem("startupSecrets[%d]: the source and the data are mutually exclusive", i)
+			}
+			sources := 0
+			for _, file := range []string{source.Directory, source.Archive, source.JSON} {
+				if file != "" {
+					sources++
+				}
+			}
+			if sources != 1 {
+				problem("startupSecrets[%d]: the source needs exactly one of directory, archive or json", i)
+			}
+		}
+	}
+
+	registered := registeredReconcilers()
+	for _, section := range slices.Sorted(maps.Keys(loadedConfig.custom)) {
+		if _, err := registered[section].Desired(context.Background(), loadedConfig.custom[section]); err != nil {
+			problem("%s: %s", section, err)
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,94 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		config := map[string]interface{}{
+			"auth": []interface{}{
+				map[string]interface{}{"type": "kubernetes"},
+				map[string]interface{}{"type": "kubernetes", "path": "kubernetes-eu"},
+			},
+			"secrets": []interface{}{
+				map[string]interface{}{"type": "kv", "path": "secret"},
+			},
+			"policies": []interface{}{
+				map[string]interface{}{"name": "reader", "rules": `path "secret/*" { capabilities = ["read"] }`},
+			},
+		}
+
+		assert.Empty(t, ValidateConfig(config))
+	})
+
+	t.Run("problems", func(t *testing.T) {
+		config := map[string]interface{}{
+			"auth": []interface{}{
+				map[string]interface{}{"type": "kubernetes"},
+				map[string]interface{}{"type": "jwt", "path": "/kubernetes/"},
+			},
+			"secrets": []interface{}{
+				map[string]interface{}{"path": "secret"},
+			},
+			"policies": []interface{}{
+				map[string]interface{}{"name": "reader", "rules": `path "secret/*" { capabilities = ["raed"] }`},
+				map[string]interface{}{"name": "reader", "rules": `path "secret/*" {`},
+			},
+		}
+
+		var problems []string
+		for _, err := range ValidateConfig(config) {
+			problems = append(problems, err.Error())
+		}
+
+		assert.Len(t, problems, 5)
+		assert.Contains(t, problems, "auth[1]: duplicate auth method path kubernetes/")
+		assert.Contains(t, problems, "secrets[0]: type is required")
+		assert.Contains(t, problems, "policies[1]: duplicate policy reader")
+		assert.Contains(t, problems[2], `unknown capability "raed"`)
+		assert.Contains(t, problems[4], "parsing reader policy rules")
+	})
+
+	t.Run("unknown keys", func(t *testing.T) {
+		errs := ValidateConfig(map[string]interface{}{"polices": []interface{}{}})
+
+		assert.Len(t, errs, 1)
+		assert.ErrorContains(t, errs[0], "polices")
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	config, err := ParseConfig(map[string]interface{}{
+		"purgeUnmanagedConfig": map[string]interface{}{"enabled": true},
+		"auth":                 []interface{}{map[string]interface{}{"type": "kubernetes", "path": "kubernetes-eu"}},
+		"secrets":              []interface{}{map[string]interface{}{"type": "kv", "options": map[string]interface{}{"version": 2}}},
+		"policies":             []interface{}{map[string]interface{}{"name": "admin", "rules": `path "*" { capabilities = ["sudo"] }`}},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, config.PurgeUnmanagedConfig.Enabled)
+	assert.Equal(t, []AuthMethod{{Type: "kubernetes", Path: "kubernetes-eu"}}, config.Auth)
+	assert.Equal(t, []SecretsEngine{{Type: "kv", Options: map[string]string{"version": "2"}}}, config.Secrets)
+	assert.Equal(t, "admin", config.Policies[0].Name)
+
+	_, err = ParseConfig(map[string]interface{}{"polices": []interface{}{}})
+	assert.ErrorContains(t, err, "polices")
+}
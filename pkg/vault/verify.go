@@ -0,0 +1,399 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	hclPrinter "github.com/hashicorp/hcl/hcl/printer"
+	"github.com/hashicorp/vault/api"
+	"github.com/spf13/cast"
+
+	"github.com/bank-vaults/bank-vaults/internal/secmem"
+)
+
+// Drift reasons.
+const (
+	DriftMissing   = "missing"
+	DriftUnmanaged = "unmanaged"
+	DriftChanged   = "changed"
+)
+
+// Drift describes a single difference between the external config and the state of Vault.
+type Drift struct {
+	Section string `json:"section"`
+	Path    string `json:"path"`
+	Reason  string `json:"reason"`
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s: %s is %s", d.Section, d.Path, d.Reason)
+}
+
+// Verify compares the given config with the state of Vault without changing anything.
+// Unmanaged resources are only reported when the purge config would remove them.
+// The settings of audit devices and mounts are compared, the contents of secret engine,
+// auth method and startup secret configurations are not, as Vault doesn't return
+// write-only fields like credentials.
+func (v *vault) Verify(ctx context.Context, config map[string]interface{}) ([]Drift, error) {
+	if err := v.verifyLogin(ctx); err != nil {
+		return nil, err
+	}
+	defer v.cl.SetToken("")
+
+	loadedConfig, err := v.loadExternalConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	v.externalConfig = loadedConfig
+
+	var drifts []Drift
+	for _, verify := range []func() ([]Drift, error){
+		v.verifyAuditDevices,
+		v.verifyPlugins,
+		v.verifyAuthMethods,
+		v.verifyGroups,
+		v.verifyPolicies,
+		v.verifySecretsEngines,
+		func() ([]Drift, error) { return v.verifyCustomSections(ctx) },
+	} {
+		d, err := verify()
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, d...)
+	}
+
+	return drifts, nil
+}
+
+// verifyLogin logs in like login, except that it never generates a root token, which would be a write to Vault.
+func (v *vault) verifyLogin(ctx context.Context) error {
+	if ok, err := v.credentialsLogin(ctx); ok {
+		return err
+	}
+
+	if !v.config.StoreRootToken {
+		return errors.New("verifying needs a token: set one, log in with an auth method or store the root token in the key store")
+	}
+
+	rootToken, err := v.keyStore.Get(ctx, keyRootToken)
+	if err != nil {
+		return errors.Wrapf(err, "unable to get key '%s'", keyRootToken)
+	}
+	defer secmem.Wipe(rootToken)
+	v.cl.SetToken(string(rootToken))
+
+	return nil
+}
+
+// settingsDrifted reports whether any of the desired settings differs from the actual ones.
+// Settings Vault doesn't return are not compared.
+func settingsDrifted(desired, actual map[string]interface{}) bool {
+	for key, want := range desired {
+		got, ok := actual[key]
+		if !ok {
+			continue
+		}
+
+		if strings.HasSuffix(key, "_ttl") {
+			wantTTL, wantOK := ttlSeconds(want)
+			gotTTL, gotOK := ttlSeconds(got)
+			if wantOK && gotOK && wantTTL != gotTTL {
+				return true
+			}
+
+			continue
+		}
+
+		switch want.(type) {
+		case []interface{}, []string:
+			if !slices.Equal(cast.ToStringSlice(want), cast.ToStringSlice(got)) {
+				return true
+			}
+		default:
+			if cast.ToString(want) != cast.ToString(got) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ttlSeconds parses a TTL given either as a duration string or as seconds.
+func ttlSeconds(value interface{}) (int64, bool) {
+	if s, ok := value.(string); ok {
+		if d, err := time.ParseDuration(s); err == nil {
+			return int64(d.Seconds()), true
+		}
+	}
+
+	seconds, err := cast.ToInt64E(value)
+
+	return seconds, err == nil
+}
+
+// mountDrifted compares the desired settings of a mount with its tune endpoint.
+func (v *vault) mountDrifted(tunePath string, desired map[string]interface{}) (bool, error) {
+	if len(desired) == 0 {
+		return false, nil
+	}
+
+	actual, err := v.cl.Logical().Read(tunePath)
+	if err != nil {
+		return false, errors.Wrapf(err, "error reading %s", tunePath)
+	}
+	if actual == nil {
+		return false, nil
+	}
+
+	return settingsDrifted(desired, actual.Data), nil
+}
+
+// purges reports whether unmanaged resources of a section would be removed by configure.
+func (v *vault) purges(excluded bool) bool {
+	return v.externalConfig.PurgeUnmanagedConfig.Enabled && !excluded
+}
+
+func unmanagedDrifts(section string, paths []string) []Drift {
+	drifts := make([]Drift, 0, len(paths))
+	for _, path := range paths {
+		drifts = append(drifts, Drift{Section: section, Path: path, Reason: DriftUnmanaged})
+	}
+
+	return drifts
+}
+
+func (v *vault) verifyAuditDevices() ([]Drift, error) {
+	existingAudits, err := v.cl.Sys().ListAudit()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list existing audits")
+	}
+
+	existing := make(map[string]map[string]interface{}, len(existingAudits))
+	for path, auditDevice := range existingAudits {
+		settings := map[string]interface{}{"type": auditDevice.Type, "description": auditDevice.Description}
+		for key, value := range auditDevice.Options {
+			settings[key] = value
+		}
+		existing[strings.Trim(path, "/")] = settings
+	}
+
+	var drifts []Drift
+	managedAudits := initAuditConfig(v.externalConfig.Audit)
+	for _, auditDevice := range managedAudits {
+		actual, ok := existing[auditDevice.Path]
+		if !ok {
+			drifts = append(drifts, Drift{Section: SectionAudit, Path: auditDevice.Path, Reason: DriftMissing})
+			continue
+		}
+
+		desired := map[string]interface{}{"type": auditDevice.Type}
+		if auditDevice.Description != "" {
+			desired["description"] = auditDevice.Description
+		}
+		maps.Copy(desired, auditDevice.Options)
+		if settingsDrifted(desired, actual) {
+			drifts = append(drifts, Drift{Section: SectionAudit, Path: auditDevice.Path, Reason: DriftChanged})
+		}
+	}
+
+	if v.purges(v.externalConfig.PurgeUnmanagedConfig.Exclude.Audit) {
+		drifts = append(drifts, unmanagedDrifts(SectionAudit, slices.Sorted(maps.Keys(v.getUnmanagedAudits(managedAudits))))...)
+	}
+
+	return drifts, nil
+}
+
+func (v *vault) verifyPlugins() ([]Drift, error) {
+	existingPlugins, err := v.getExistingPlugins()
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []Drift
+	for _, plugin := range v.externalConfig.Plugins {
+		if !existingPlugins[plugin.Type][plugin.Name] {
+			drifts = append(drifts, Drift{Section: SectionPlugins, Path: plugin.Type + "/" + plugin.Name, Reason: DriftMissing})
+		}
+	}
+
+	if v.purges(v.externalConfig.PurgeUnmanagedConfig.Exclude.Plugins) {
+		var unmanaged []string
+		for pluginType, pluginNames := range getUnmanagedPlugins(existingPlugins, v.externalConfig.Plugins) {
+			for pluginName := range pluginNames {
+				unmanaged = append(unmanaged, pluginType+"/"+pluginName)
+			}
+		}
+		slices.Sort(unmanaged)
+		drifts = append(drifts, unmanagedDrifts(SectionPlugins, unmanaged)...)
+	}
+
+	return drifts, nil
+}
+
+func (v *vault) verifyAuthMethods() ([]Drift, error) {
+	existingAuths, err := v.getExistingAuthMethods()
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []Drift
+	managedAuths := initAuthConfig(v.externalConfig.Auth)
+	for _, authMethod := range managedAuths {
+		if existingAuths[authMethod.Path] == nil {
+			drifts = append(drifts, Drift{Section: SectionAuth, Path: authMethod.Path, Reason: DriftMissing})
+			continue
+		}
+
+		if existingAuths[authMethod.Path].Type != authMethod.Type {
+			drifts = append(drifts, Drift{Section: SectionAuth, Path: authMethod.Path, Reason: DriftChanged})
+			continue
+		}
+
+		drifted, err := v.mountDrifted(fmt.Sprintf("sys/auth/%s/tune", authMethod.Path), authMethod.Options)
+		if err != nil {
+			return nil, err
+		}
+		if drifted {
+			drifts = append(drifts, Drift{Section: SectionAuth, Path: authMethod.Path, Reason: DriftChanged})
+		}
+	}
+
+	if v.purges(v.externalConfig.PurgeUnmanagedConfig.Exclude.Auth) {
+		drifts = append(drifts, unmanagedDrifts(SectionAuth, slices.Sorted(maps.Keys(v.getUnmanagedAuthMethods(managedAuths))))...)
+	}
+
+	return drifts, nil
+}
+
+func (v *vault) verifyGroups() ([]Drift, error) {
+	existingGroups, err := v.getExistingGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []Drift
+	managedGroups := v.managedGroups()
+	for _, group := range managedGroups {
+		if !existingGroups[group.Name] {
+			drifts = append(drifts, Drift{Section: SectionGroups, Path: group.Name, Reason: DriftMissing})
+		}
+	}
+
+	if v.purges(v.externalConfig.PurgeUnmanagedConfig.Exclude.Groups) {
+		drifts = append(drifts, unmanagedDrifts(SectionGroups, slices.Sorted(maps.Keys(getUnmanagedGroups(existingGroups, managedGroups))))...)
+	}
+
+	return drifts, nil
+}
+
+func (v *vault) verifyPolicies() ([]Drift, error) {
+	auths, err := v.cl.Sys().ListAuth()
+	if err != nil {
+		return nil, errors.Wrap(err, "error while getting list of auth engines")
+	}
+
+	// The rules are resolved and rendered in a copy, the config is kept for the next runs
+	policies := slices.Clone(v.externalConfig.Policies)
+	if err := v.resolveIdentityPlaceholders(policies); err != nil {
+		return nil, errors.Wrap(err, "error while initializing policies config")
+	}
+
+	managedPolicies, err := initPoliciesConfig(policies, auths)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while initializing policies config")
+	}
+
+	var drifts []Drift
+	for _, policy := range managedPolicies {
+		existingRules, err := v.cl.Sys().GetPolicy(policy.Name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading %s policy from vault", policy.Name)
+		}
+
+		if existingRules == "" {
+			drifts = append(drifts, Drift{Section: SectionPolicies, Path: policy.Name, Reason: DriftMissing})
+			continue
+		}
+
+		if formatted, err := hclPrinter.Format([]byte(existingRules)); err == nil {
+			existingRules = string(formatted)
+		}
+		if strings.TrimSpace(existingRules) != strings.TrimSpace(policy.RulesFormatted) {
+			drifts = append(drifts, Drift{Section: SectionPolicies, Path: policy.Name, Reason: DriftChanged})
+		}
+	}
+
+	if v.purges(v.externalConfig.PurgeUnmanagedConfig.Exclude.Policies) {
+		drifts = append(drifts, unmanagedDrifts(SectionPolicies, slices.Sorted(maps.Keys(v.getUnmanagedPolicies(managedPolicies))))...)
+	}
+
+	return drifts, nil
+}
+
+func (v *vault) verifySecretsEngines() ([]Drift, error) {
+	mounts, err := v.cl.Sys().ListMounts()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list existing secrets engines")
+	}
+
+	existing := make(map[string]*api.MountOutput, len(mounts))
+	for path, mount := range mounts {
+		existing[strings.Trim(path, "/")] = mount
+	}
+
+	var drifts []Drift
+	managedSecretsEngines := initSecretsEnginesConfig(v.externalConfig.Secrets)
+	for _, secretEngine := range managedSecretsEngines {
+		mount := existing[secretEngine.Path]
+		if mount == nil {
+			drifts = append(drifts, Drift{Section: SectionSecrets, Path: secretEngine.Path, Reason: DriftMissing})
+			continue
+		}
+
+		// Plugin mounts report the name of the plugin as their type
+		drifted := (secretEngine.PluginName == "" && mount.Type != secretEngine.Type) ||
+			(secretEngine.Description != "" && mount.Description != secretEngine.Description)
+		for key, value := range secretEngine.Options {
+			if mount.Options[key] != value {
+				drifted = true
+			}
+		}
+
+		if !drifted {
+			if drifted, err = v.mountDrifted(fmt.Sprintf("sys/mounts/%s/tune", secretEngine.Path), secretEngine.Config); err != nil {
+				return nil, err
+			}
+		}
+
+		if drifted {
+			drifts = append(drifts, Drift{Section: SectionSecrets, Path: secretEngine.Path, Reason: DriftChanged})
+		}
+	}
+
+	if v.purges(v.externalConfig.PurgeUnmanagedConfig.Exclude.Secrets) {
+		drifts = append(drifts, unmanagedDrifts(SectionSecrets, slices.Sorted(maps.Keys(v.getUnmanagedSecretsEngines(managedSecretsEngines))))...)
+	}
+
+	return drifts, nil
+}
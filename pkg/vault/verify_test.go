@@ -0,0 +1,156 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVerifyVault returns a vault talking to a fake server answering the given paths with their data.
+func newVerifyVault(t *testing.T, responses map[string]interface{}, requests *atomic.Int32) *vault {
+	t.Helper()
+
+	return newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			requests.Add(1)
+		}
+
+		data, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data}) //nolint:errcheck
+	}))
+}
+
+func TestSettingsDrifted(t *testing.T) {
+	actual := map[string]interface{}{
+		"default_lease_ttl":           json.Number("3600"),
+		"audit_non_hmac_request_keys": []interface{}{"a", "b"},
+		"listing_visibility":          "hidden",
+	}
+
+	assert.False(t, settingsDrifted(map[string]interface{}{"default_lease_ttl": "1h"}, actual))
+	assert.False(t, settingsDrifted(map[string]interface{}{"default_lease_ttl": 3600}, actual))
+	assert.True(t, settingsDrifted(map[string]interface{}{"default_lease_ttl": "2h"}, actual))
+	assert.False(t, settingsDrifted(map[string]interface{}{"audit_non_hmac_request_keys": []interface{}{"a", "b"}}, actual))
+	assert.True(t, settingsDrifted(map[string]interface{}{"audit_non_hmac_request_keys": []interface{}{"a"}}, actual))
+	assert.True(t, settingsDrifted(map[string]interface{}{"listing_visibility": "unauth"}, actual))
+	assert.False(t, settingsDrifted(map[string]interface{}{"not_returned": "x"}, actual))
+}
+
+func TestVerifyLoginNeverGeneratesRootToken(t *testing.T) {
+	var requests atomic.Int32
+	v := newVerifyVault(t, nil, &requests)
+
+	assert.Error(t, v.verifyLogin(context.Background()))
+	assert.Zero(t, requests.Load())
+
+	v.config.Token = "configurer-token"
+	require.NoError(t, v.verifyLogin(context.Background()))
+	assert.Equal(t, "configurer-token", v.cl.Token())
+
+	v.config.Token = ""
+	v.config.TokenFile = filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(v.config.TokenFile, []byte("agent-token\n"), 0o600))
+	require.NoError(t, v.verifyLogin(context.Background()))
+	assert.Equal(t, "agent-token", v.cl.Token())
+
+	v.config.TokenFile = ""
+	v.config.StoreRootToken = true
+	require.NoError(t, v.keyStore.Set(context.Background(), keyRootToken, []byte("root-token")))
+	require.NoError(t, v.verifyLogin(context.Background()))
+	assert.Equal(t, "root-token", v.cl.Token())
+	assert.Zero(t, requests.Load())
+}
+
+func TestVerifyLoginWithAuthMethod(t *testing.T) {
+	v := newTestVault(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/auth/approle/login" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"auth": map[string]interface{}{"client_token": "approle-token"},
+		})
+	}))
+	v.config.StoreRootToken = true
+	v.config.AppRoleAuth = AppRoleAuth{Credentials: &memAppRoleCredentials{roleID: "role-id", secretID: "secret-id"}}
+
+	// The auth method is preferred to the stored root token, like when configuring
+	require.NoError(t, v.verifyLogin(context.Background()))
+	assert.Equal(t, "approle-token", v.cl.Token())
+}
+
+func TestVerifySecretsEngines(t *testing.T) {
+	v := newVerifyVault(t, map[string]interface{}{
+		"/v1/sys/mounts": map[string]interface{}{
+			"kv/": map[string]interface{}{"type": "kv", "options": map[string]interface{}{"version": "2"}},
+		},
+		"/v1/sys/mounts/kv/tune": map[string]interface{}{"default_lease_ttl": 3600, "max_lease_ttl": 86400},
+	}, nil)
+
+	v.externalConfig.Secrets = []SecretsEngine{
+		{Path: "kv", Type: "kv", Options: map[string]string{"version": "2"}, Config: map[string]interface{}{"default_lease_ttl": "1h"}},
+		{Path: "pki", Type: "pki"},
+	}
+	drifts, err := v.verifySecretsEngines()
+	require.NoError(t, err)
+	assert.Equal(t, []Drift{{Section: SectionSecrets, Path: "pki", Reason: DriftMissing}}, drifts)
+
+	v.externalConfig.Secrets = []SecretsEngine{
+		{Path: "kv", Type: "kv", Config: map[string]interface{}{"max_lease_ttl": "48h"}},
+	}
+	drifts, err = v.verifySecretsEngines()
+	require.NoError(t, err)
+	assert.Equal(t, []Drift{{Section: SectionSecrets, Path: "kv", Reason: DriftChanged}}, drifts)
+
+	v.externalConfig.Secrets = []SecretsEngine{
+		{Path: "kv", Type: "kv", Options: map[string]string{"version": "1"}},
+	}
+	drifts, err = v.verifySecretsEngines()
+	require.NoError(t, err)
+	assert.Equal(t, []Drift{{Section: SectionSecrets, Path: "kv", Reason: DriftChanged}}, drifts)
+}
+
+func TestVerifyAuditDevices(t *testing.T) {
+	v := newVerifyVault(t, map[string]interface{}{
+		"/v1/sys/audit": map[string]interface{}{
+			"file/": map[string]interface{}{"type": "file", "path": "file/", "options": map[string]interface{}{"file_path": "/vault/audit.log"}},
+		},
+	}, nil)
+
+	v.externalConfig.Audit = []AuditDevice{{Type: "file", Options: map[string]interface{}{"file_path": "/vault/audit.log"}}}
+	drifts, err := v.verifyAuditDevices()
+	require.NoError(t, err)
+	assert.Empty(t, drifts)
+
+	v.externalConfig.Audit = []AuditDevice{{Type: "file", Options: map[string]interface{}{"file_path": "/tmp/audit.log"}}}
+	drifts, err = v.verifyAuditDevices()
+	require.NoError(t, err)
+	assert.Equal(t, []Drift{{Section: SectionAudit, Path: "file", Reason: DriftChanged}}, drifts)
+}
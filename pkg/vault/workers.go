@@ -0,0 +1,93 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// forEachItem calls apply for the items with at most concurrency calls in flight, and returns their errors
+// in the order of the items. Once an item failed and stop is set, the items not started yet are left out.
+func forEachItem[T any](concurrency int, items []T, stop bool, apply func(item T) error) []error {
+	errs := make([]error, len(items))
+	if concurrency <= 1 {
+		for i, item := range items {
+			if errs[i] = apply(item); errs[i] != nil && stop {
+				return errs[:i+1]
+			}
+		}
+
+		return errs
+	}
+
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, concurrency)
+	for i, item := range items {
+		workers <- struct{}{}
+		if stop && failed.Load() {
+			<-workers
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			if errs[i] = apply(item); errs[i] != nil {
+				failed.Store(true)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// firstError returns the first error of the items.
+func firstError(errs []error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyItems applies the items of a config section through the worker pool. The errors are handled in
+// the order of the items like applied one by one: the first one aborts the run, unless continue-on-error
+// skips the failed items.
+func applyItems[T any](v *vault, section string, items []T, path func(item T) string, apply func(item T) error) error {
+	errs := forEachItem(v.concurrency(), items, !v.config.ContinueOnError, apply)
+	for i, err := range errs {
+		if err := v.itemFailed(section, path(items[i]), err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// concurrency returns how many items of a section are applied at once.
+func (v *vault) concurrency() int {
+	if v.config == nil || v.config.Concurrency < 1 {
+		return 1
+	}
+
+	return v.config.Concurrency
+}
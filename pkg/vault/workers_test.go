@@ -0,0 +1,83 @@
+// Copyright © 2026 Bank-Vaults Maintainers
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachItem(t *testing.T) {
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+
+	var inFlight, maxInFlight atomic.Int32
+	errs := forEachItem(4, items, false, func(item int) error {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+
+		if item%5 == 3 {
+			return fmt.Errorf("item %d", item)
+		}
+
+		return nil
+	})
+
+	require.Len(t, errs, len(items))
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(4))
+	assert.EqualError(t, firstError(errs), "item 3")
+	assert.EqualError(t, errs[18], "item 18")
+	assert.NoError(t, errs[19])
+}
+
+func TestForEachItemStops(t *testing.T) {
+	var applied atomic.Int32
+	errs := forEachItem(1, []int{0, 1, 2}, true, func(item int) error {
+		applied.Add(1)
+		if item == 1 {
+			return errors.New("failed")
+		}
+
+		return nil
+	})
+
+	assert.Equal(t, int32(2), applied.Load())
+	assert.EqualError(t, firstError(errs), "failed")
+
+	// The items in flight finish, the ones not started yet are left out
+	applied.Store(0)
+	errs = forEachItem(2, make([]int, 100), true, func(int) error {
+		applied.Add(1)
+		return errors.New("failed")
+	})
+
+	assert.EqualError(t, firstError(errs), "failed")
+	assert.Less(t, applied.Load(), int32(100))
+}